@@ -4,7 +4,6 @@ package main
 
 import (
 	"context"
-	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -21,6 +20,7 @@ import (
 	"search-engine/backend/internal/provider"
 	"search-engine/backend/internal/repository"
 	"search-engine/backend/internal/service"
+	"search-engine/backend/internal/trace"
 	"search-engine/backend/pkg/cache"
 
 	"github.com/gin-gonic/gin"
@@ -33,7 +33,7 @@ import (
 
 // @title           Search Engine API
 // @version         1.0
-// @description     A search engine service that aggregates content from multiple providers, ranks them by relevance score, and provides search, filtering, sorting, and pagination capabilities.
+// @description     A search engine service that aggregates content from multiple providers, ranks them by relevance score, and provides search, filtering, sorting, and pagination capabilities. Add pretty=true to any request for indented JSON.
 // @termsOfService  http://swagger.io/terms/
 
 // @contact.name   API Support
@@ -94,6 +94,9 @@ func main() {
 	// Create HTTP server
 	app.createServer()
 
+	// Reuse raw provider responses across overlapping syncs
+	provider.SetFetchCacheTTL(time.Duration(cfg.Provider.FetchCacheTTLSeconds) * time.Second)
+
 	// Start initial sync from providers in background
 	// This ensures data is available when the server starts
 	go app.syncProvidersOnStartup(cfg)
@@ -135,7 +138,7 @@ func (a *App) initializeCache() error {
 	if !a.config.Redis.Enabled {
 		log.Println("Using in-memory cache")
 		cacheTTL := time.Duration(a.config.Search.CacheTTLSeconds) * time.Second
-		a.cacheInstance = cache.NewInMemoryCache(cacheTTL)
+		a.cacheInstance = cache.NewInMemoryCache(cacheTTL, a.config.Search.CacheMaxEntries)
 		return nil
 	}
 
@@ -153,7 +156,7 @@ func (a *App) initializeCache() error {
 	if err := a.redisClient.Ping(ctx).Err(); err != nil {
 		log.Printf("Warning: Redis connection failed, falling back to in-memory cache: %v", err)
 		cacheTTL := time.Duration(a.config.Search.CacheTTLSeconds) * time.Second
-		a.cacheInstance = cache.NewInMemoryCache(cacheTTL)
+		a.cacheInstance = cache.NewInMemoryCache(cacheTTL, a.config.Search.CacheMaxEntries)
 		a.redisClient = nil
 		return nil
 	}
@@ -167,15 +170,34 @@ func (a *App) initializeCache() error {
 func (a *App) setupMiddleware() {
 	// Global middleware
 	a.router.Use(middleware.LoggerMiddleware())
-	a.router.Use(middleware.CORSMiddleware())
+	a.router.Use(middleware.CORSMiddleware(a.config.CORS.AllowedOrigins))
 	a.router.Use(middleware.SecurityHeadersMiddleware())
 
+	// Response compression (outside the error handler, so error bodies are compressed too)
+	if a.config.Compress.Enabled {
+		a.router.Use(middleware.GzipMiddleware(middleware.GzipConfig{
+			MinSize:   a.config.Compress.MinSizeBytes,
+			SkipPaths: []string{"/metrics"},
+		}))
+	}
+
 	// Error handling middleware (should be early in the chain)
 	a.router.Use(middleware.ErrorHandlerMiddleware())
 
+	// Body size limit (after the error handler, which renders its 413)
+	a.router.Use(middleware.BodyLimitMiddleware(int64(a.config.Server.MaxBodyBytes)))
+
 	// Rate limiting middleware
 	rateLimiter := a.createRateLimiter()
 	a.router.Use(rateLimiter)
+
+	// API key authentication (after rate limiting, so key guessing is throttled too)
+	if a.config.Auth.Enabled {
+		if len(a.config.Auth.APIKeys) == 0 {
+			log.Println("Warning: AUTH_ENABLED is set but AUTH_API_KEYS is empty; every request except health checks will be rejected")
+		}
+		a.router.Use(middleware.APIKeyAuthMiddleware(a.config.Auth.APIKeys, "/health", "/health/live", "/health/ready", "/readyz"))
+	}
 }
 
 // createRateLimiter creates appropriate rate limiter (Redis or in-memory)
@@ -199,13 +221,19 @@ func (a *App) createRateLimiter() gin.HandlerFunc {
 
 	return middleware.NewIPRateLimiterMiddleware(middleware.RateLimiterConfig{
 		RequestsPerMinute: a.config.Rate.RequestsPerMinute,
+		IdleTimeout:       time.Duration(a.config.Rate.IdleTimeoutSeconds) * time.Second,
 	})
 }
 
 // setupRoutes configures all API routes
 func (a *App) setupRoutes() {
-	// Health check endpoint (before rate limiting)
-	a.router.GET("/health", a.healthCheck)
+	// Health check endpoints (before rate limiting)
+	// /health/live is for liveness probes; /health/ready, and /health for
+	// existing clients, check dependencies
+	a.router.GET("/health/live", a.healthLive)
+	a.router.GET("/health/ready", a.healthReady)
+	a.router.GET("/health", a.healthReady)
+	a.router.GET("/readyz", a.readinessCheck)
 
 	// API v1 routes
 	api := a.router.Group("/api/v1")
@@ -219,55 +247,152 @@ func (a *App) setupRoutes() {
 func (a *App) setupAPIRoutes(api *gin.RouterGroup) {
 	// Initialize repositories
 	contentRepo := repository.NewContentRepository(repository.GetDB(), a.config.Search.MinFullTextLength)
+	contentRepo.SetRelevanceWeights(repository.RelevanceWeights{
+		Text:  a.config.Search.RelevanceTextWeight,
+		Score: a.config.Search.RelevanceScoreWeight,
+	})
+	contentRepo.EnableHistory(a.config.History.MaxPerContent)
+	contentRepo.SetTagLimits(model.TagLimits{
+		MaxLength:     a.config.Tags.MaxLength,
+		MaxPerContent: a.config.Tags.MaxPerContent,
+	})
 	providerRepo := repository.NewProviderRepository(repository.GetDB())
+	syncRepo := repository.NewSyncHistoryRepository(repository.GetDB())
+	historyRepo := repository.NewContentHistoryRepository(repository.GetDB(), a.config.History.MaxPerContent)
+	tagRepo := repository.NewContentTagRepository(repository.GetDB(), model.TagLimits{
+		MaxLength:     a.config.Tags.MaxLength,
+		MaxPerContent: a.config.Tags.MaxPerContent,
+	})
 
 	// Initialize services
-	cacheTTL := time.Duration(a.config.Search.CacheTTLSeconds) * time.Second
+	// Each cached feature has its own TTL (all default to the global cache TTL)
+	cacheTTL := time.Duration(a.config.CacheTTL.SearchSeconds) * time.Second
+	statsCacheTTL := time.Duration(a.config.CacheTTL.StatsSeconds) * time.Second
+	suggestCacheTTL := time.Duration(a.config.CacheTTL.SuggestSeconds) * time.Second
 	queryTimeout := time.Duration(a.config.Search.QueryTimeoutSeconds) * time.Second
+	maxQueryTimeout := time.Duration(a.config.Search.MaxQueryTimeoutSeconds) * time.Second
 	simpleQueryTimeout := time.Duration(a.config.Search.SimpleQueryTimeoutSeconds) * time.Second
-	searchService := service.NewSearchService(contentRepo, a.cacheInstance, cacheTTL, queryTimeout, simpleQueryTimeout)
+	searchService := service.NewSearchService(contentRepo, a.cacheInstance, service.SearchServiceOptions{
+		CacheTTL:           cacheTTL,
+		QueryTimeout:       queryTimeout,
+		MaxQueryTimeout:    maxQueryTimeout,
+		SimpleQueryTimeout: simpleQueryTimeout,
+		MaxResultWindow:    a.config.Search.MaxResultWindow,
+		MaxPerPage:         a.config.Search.MaxPerPage,
+		PrefixMatch:        a.config.Search.PrefixMatch,
+		EmptyResultHints:   a.config.Search.EmptyResultHints,
+		HighlightPreTag:    a.config.Search.HighlightPreTag,
+		HighlightPostTag:   a.config.Search.HighlightPostTag,
+		ScoringDegraded:    a.config.Scoring.Degraded,
+	})
 
 	// Initialize handlers
 	searchHandler := handler.NewSearchHandler(searchService)
-	contentHandler := handler.NewContentHandler(contentRepo, simpleQueryTimeout)
-	providerHandler := handler.NewProviderHandler(providerRepo)
-	statsHandler := handler.NewStatsHandler(contentRepo, providerRepo)
+	autocompleteHandler := handler.NewAutocompleteHandler(contentRepo, a.cacheInstance, suggestCacheTTL, simpleQueryTimeout)
+	contentHandler := handler.NewContentHandler(contentRepo, historyRepo, providerRepo, a.config.Scoring, a.cacheInstance, simpleQueryTimeout)
+	providerHandler := handler.NewProviderHandler(providerRepo, contentRepo, syncRepo, a.cacheInstance, a.config.Provider.StaleAfterMinutes, simpleQueryTimeout)
+	statsHandler := handler.NewStatsHandler(contentRepo, providerRepo, syncRepo, a.cacheInstance, statsCacheTTL)
+	adminHandler := handler.NewAdminHandler(a.cacheInstance, searchService)
+	metaHandler := handler.NewMetaHandler()
+	tagHandler := handler.NewTagHandler(tagRepo, simpleQueryTimeout)
+	syncWait := time.Duration(a.config.Provider.SyncWaitSeconds) * time.Second
+	syncTimeout := time.Duration(a.config.Provider.SyncTimeoutSeconds) * time.Second
+	syncHandler := handler.NewSyncHandler(contentRepo, providerRepo, syncRepo, newSyncService(a.config, a.cacheInstance), simpleQueryTimeout, syncWait, syncTimeout)
 
 	// Search endpoints
 	api.GET("/search", searchHandler.Search)
+	api.GET("/search/count", searchHandler.Count)
+	api.GET("/autocomplete", autocompleteHandler.Autocomplete)
+	api.GET("/cache/stats", searchHandler.GetCacheStats)
 
 	// Content endpoints
+	api.POST("/content", middleware.AdminAuthMiddleware(a.config.Admin.APIKey), contentHandler.IngestContent)
 	api.GET("/content/:id", contentHandler.GetContentByID)
+	api.HEAD("/content/:id", contentHandler.ContentExists)
+	api.GET("/content/:id/score", contentHandler.GetContentScore)
+	api.GET("/content/:id/history", contentHandler.GetContentHistory)
+	api.GET("/content/:id/similar", contentHandler.GetSimilarContent)
+	api.PATCH("/content/:id", middleware.AdminAuthMiddleware(a.config.Admin.APIKey), contentHandler.PatchContent)
+	api.DELETE("/content/:id", middleware.AdminAuthMiddleware(a.config.Admin.APIKey), contentHandler.DeleteContent)
 
 	// Provider endpoints
 	api.GET("/providers", providerHandler.GetProviders)
+	api.GET("/providers/status", providerHandler.GetProviderStatuses)
+	api.GET("/providers/:id", providerHandler.GetProvider)
+	api.GET("/providers/:id/sync-history", providerHandler.GetSyncHistory)
+	api.POST("/providers", middleware.AdminAuthMiddleware(a.config.Admin.APIKey), providerHandler.CreateProvider)
+	api.PUT("/providers/:id", middleware.AdminAuthMiddleware(a.config.Admin.APIKey), providerHandler.UpdateProvider)
+	api.DELETE("/providers/:id", middleware.AdminAuthMiddleware(a.config.Admin.APIKey), providerHandler.DeleteProvider)
+
+	// Tag endpoints
+	api.GET("/tags", tagHandler.GetTags)
+
+	// Sync endpoints
+	api.POST("/sync", middleware.AdminAuthMiddleware(a.config.Admin.APIKey), syncHandler.TriggerSync)
 
 	// Statistics endpoints
 	api.GET("/stats", statsHandler.GetStats)
+	api.GET("/stats/providers", statsHandler.GetProviderStats)
+
+	// Metadata endpoints
+	api.GET("/enums", metaHandler.GetEnums)
+
+	// Admin endpoints (require X-Admin-Key; disabled when ADMIN_API_KEY is empty)
+	admin := api.Group("/admin", middleware.AdminAuthMiddleware(a.config.Admin.APIKey))
+	admin.GET("/cache/:key", adminHandler.GetCacheEntry)
+	admin.DELETE("/cache/:key", adminHandler.DeleteCacheEntry)
+	admin.GET("/cache-key/search", adminHandler.GetSearchCacheKey)
+	admin.DELETE("/cache-stats", adminHandler.ResetCacheStats)
+	admin.GET("/sync/last-delta", syncHandler.GetLastSyncDelta)
+	admin.GET("/scoring/degraded", adminHandler.GetScoringDegraded)
+	admin.PUT("/scoring/degraded", adminHandler.SetScoringDegraded)
 }
 
-// healthCheck handles health check requests
-// Returns detailed system status including database and Redis connectivity
+// healthInfo returns the fields every health response starts with
+func (a *App) healthInfo(status string) gin.H {
+	return gin.H{
+		"status":    status,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+		"uptime":    time.Since(a.startTime).String(),
+		"version":   "1.0.0",
+	}
+}
+
+// healthLive handles liveness probe requests
+// Answers 200 whenever the process can serve HTTP; dependencies aren't checked,
+// so an outage of MySQL or Redis doesn't get the pod restarted
+//
+// @Summary     Liveness check
+// @Description Report that the process is running. Never checks dependencies, so it only fails when the server can't answer at all.
+// @Tags        health
+// @Produce     json
+// @Success     200  {object}  map[string]interface{}  "Process is alive"
+// @Router      /health/live [get]
+func (a *App) healthLive(c *gin.Context) {
+	middleware.WriteJSON(c, http.StatusOK, gin.H{
+		"health": a.healthInfo("OK"),
+	})
+}
+
+// healthReady handles readiness health check requests
+// Returns detailed system status including database and Redis connectivity,
+// and 503 when a dependency is down; /health is kept as an alias
 //
-// @Summary     Health check
-// @Description Get detailed system health status including database and Redis connectivity, uptime, and component statistics
+// @Summary     Readiness health check
+// @Description Get detailed system health status including database and Redis connectivity, uptime, and component statistics. Returns 503 when a dependency is unhealthy. /health is an alias kept for existing clients.
 // @Tags        health
 // @Accept      json
 // @Produce     json
 // @Success     200  {object}  map[string]interface{}  "System is healthy"
 // @Success     503  {object}  map[string]interface{}  "System is degraded (some components unhealthy)"
+// @Router      /health/ready [get]
 // @Router      /health [get]
-func (a *App) healthCheck(c *gin.Context) {
+func (a *App) healthReady(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	health := gin.H{
-		"status":     "OK",
-		"timestamp":  time.Now().UTC().Format(time.RFC3339),
-		"uptime":     time.Since(a.startTime).String(),
-		"version":    "1.0.0",
-		"components": gin.H{},
-	}
+	health := a.healthInfo("OK")
+	health["components"] = gin.H{}
 
 	// Check database connectivity
 	dbStatus := gin.H{
@@ -335,11 +460,36 @@ func (a *App) healthCheck(c *gin.Context) {
 		statusCode = http.StatusServiceUnavailable
 	}
 
-	c.JSON(statusCode, gin.H{
+	middleware.WriteJSON(c, statusCode, gin.H{
 		"health": health,
 	})
 }
 
+// readinessCheck handles readiness probe requests
+// Reports not ready while the database is unreachable so load balancers stop routing here
+//
+// @Summary     Readiness check
+// @Description Report whether the instance can serve traffic. Returns 503 while the database is unreachable or recently failed.
+// @Tags        health
+// @Produce     json
+// @Success     200  {object}  map[string]interface{}  "Instance is ready"
+// @Failure     503  {object}  map[string]interface{}  "Instance is not ready"
+// @Router      /readyz [get]
+func (a *App) readinessCheck(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	if err := repository.Ready(ctx); err != nil {
+		middleware.WriteJSON(c, http.StatusServiceUnavailable, gin.H{
+			"status": "not_ready",
+			"reason": err.Error(),
+		})
+		return
+	}
+
+	middleware.WriteJSON(c, http.StatusOK, gin.H{"status": "ready"})
+}
+
 // createServer creates and configures the HTTP server
 func (a *App) createServer() {
 	a.server = &http.Server{
@@ -381,64 +531,46 @@ func (a *App) startServerWithGracefulShutdown() {
 }
 
 // syncProvidersOnStartup syncs data from providers when the server starts
+// Skipped if a sync triggered through the API is already running
 func (a *App) syncProvidersOnStartup(cfg *config.Config) {
 	if os.Getenv("AUTO_SYNC_ON_START") == "false" {
 		return
 	}
 
 	time.Sleep(2 * time.Second)
-	log.Println("Starting initial provider sync...")
-
-	providerRepo := repository.NewProviderRepository(repository.GetDB())
-	contentRepo := repository.NewContentRepository(repository.GetDB(), cfg.Search.MinFullTextLength)
-	tagRepo := repository.NewContentTagRepository(repository.GetDB())
-	manager := provider.NewManager(providerRepo, contentRepo, tagRepo)
-
-	// Ensure providers exist and register them
-	providers := []struct {
-		name, url string
-		format    model.ProviderFormat
-	}{
-		{"provider1", cfg.Provider.Provider1URL, model.ProviderFormatJSON},
-		{"provider2", cfg.Provider.Provider2URL, model.ProviderFormatXML},
-	}
 
-	for _, p := range providers {
-		existing, err := providerRepo.GetByName(p.name)
-		if err != nil && errors.Is(err, repository.ErrProviderNotFound) {
-			if err := providerRepo.Create(&model.Provider{
-				Name:               p.name,
-				URL:                p.url,
-				Format:             p.format,
-				RateLimitPerMinute: 60,
-			}); err != nil {
-				log.Printf("Warning: Failed to create provider %s: %v", p.name, err)
-			}
-		} else if err == nil {
-			existing.URL = p.url
-			existing.Format = p.format
-			existing.RateLimitPerMinute = 60
-			providerRepo.Update(existing)
-		}
-
-		if p.format == model.ProviderFormatJSON {
-			manager.RegisterProvider(provider.NewJSONProvider(p.name, p.url))
-		} else {
-			manager.RegisterProvider(provider.NewXMLProvider(p.name, p.url))
-		}
-	}
-
-	if err := manager.FetchAll(); err != nil {
-		log.Printf("Warning: Failed to fetch from providers: %v", err)
+	release, ok := provider.TryStartSync()
+	if !ok {
+		log.Println("Skipping initial provider sync: a sync is already running")
 		return
 	}
+	defer release()
 
-	// Recalculate scores
-	scoringService := service.NewScoringService(contentRepo)
-	allProviders, _ := providerRepo.GetAll()
-	for _, p := range allProviders {
-		scoringService.RecalculateScoresForProvider(p.ID)
-	}
+	// No request started this sync, so it gets a trace ID of its own
+	// It is bounded like a sync triggered through the API
+	ctx, cancel := context.WithTimeout(trace.WithID(context.Background(), trace.NewID()), time.Duration(cfg.Provider.SyncTimeoutSeconds)*time.Second)
+	defer cancel()
+	trace.Logf(ctx, "Starting initial provider sync...")
+	summary := newSyncService(cfg, a.cacheInstance).Run(ctx)
+	provider.LogSyncReports(ctx, summary.Providers)
 
 	log.Println("Initial provider sync completed")
 }
+
+// newSyncService wires a SyncService with the repositories a sync writes through
+// Syncs record content history, apply the tag limits and invalidate searchCache
+func newSyncService(cfg *config.Config, searchCache cache.Cache) *service.SyncService {
+	contentRepo := repository.NewContentRepository(repository.GetDB(), cfg.Search.MinFullTextLength)
+	contentRepo.EnableHistory(cfg.History.MaxPerContent)
+	contentRepo.SetTagLimits(model.TagLimits{
+		MaxLength:     cfg.Tags.MaxLength,
+		MaxPerContent: cfg.Tags.MaxPerContent,
+	})
+	return service.NewSyncService(
+		repository.NewProviderRepository(repository.GetDB()),
+		contentRepo,
+		repository.NewSyncHistoryRepository(repository.GetDB()),
+		searchCache,
+		cfg,
+	)
+}
@@ -4,17 +4,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math/rand"
+	"os/signal"
 	"search-engine/backend/internal/config"
 	"search-engine/backend/internal/model"
 	"search-engine/backend/internal/repository"
 	"search-engine/backend/internal/service"
+	"syscall"
 	"time"
 )
 
 func main() {
+	// Ctrl-C or SIGTERM stops the score recalculation before its next batch
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	cfg := config.Load()
 	if err := cfg.Validate(); err != nil {
 		log.Fatalf("Config validation failed: %v", err)
@@ -27,7 +34,10 @@ func main() {
 
 	providerRepo := repository.NewProviderRepository(repository.GetDB())
 	contentRepo := repository.NewContentRepository(repository.GetDB(), cfg.Search.MinFullTextLength)
-	tagRepo := repository.NewContentTagRepository(repository.GetDB())
+	tagRepo := repository.NewContentTagRepository(repository.GetDB(), model.TagLimits{
+		MaxLength:     cfg.Tags.MaxLength,
+		MaxPerContent: cfg.Tags.MaxPerContent,
+	})
 
 	// Get providers
 	providers, err := providerRepo.GetAll()
@@ -127,7 +137,7 @@ func main() {
 			"technology",
 		}
 		selectedTags := tags[:rand.Intn(len(tags))+1]
-		if err := tagRepo.CreateBatch(content.ID, selectedTags); err != nil {
+		if _, err := tagRepo.CreateBatch(content.ID, selectedTags); err != nil {
 			log.Printf("Failed to add tags for content %d: %v", content.ID, err)
 		}
 
@@ -141,12 +151,18 @@ func main() {
 
 	// Recalculate scores
 	log.Println("Recalculating scores...")
-	scoringService := service.NewScoringService(contentRepo)
+	scoringService := service.NewScoringService(contentRepo, cfg.Scoring)
 	for _, p := range providers {
-		if err := scoringService.RecalculateScoresForProvider(p.ID); err != nil {
+		if err := scoringService.RecalculateScoresForProvider(ctx, p.ID); err != nil {
+			if ctx.Err() != nil {
+				log.Fatalf("Score recalculation interrupted: %v", err)
+			}
 			log.Printf("Failed to recalculate scores for provider %d: %v", p.ID, err)
 			continue
 		}
 	}
 	log.Println("Score recalculation completed")
+
+	// New content and scores change the results of searches the API has cached
+	service.InvalidateSharedSearchCache(cfg.Redis)
 }
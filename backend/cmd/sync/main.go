@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"log"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"search-engine/backend/internal/config"
 	"search-engine/backend/internal/model"
@@ -12,6 +16,10 @@ import (
 )
 
 func main() {
+	// Ctrl-C or SIGTERM stops the score recalculation before its next batch
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	cfg := config.Load()
 	if err := cfg.Validate(); err != nil {
 		log.Fatalf("Config validation failed: %v", err)
@@ -24,36 +32,58 @@ func main() {
 
 	providerRepo := repository.NewProviderRepository(repository.GetDB())
 	contentRepo := repository.NewContentRepository(repository.GetDB(), cfg.Search.MinFullTextLength)
-	tagRepo := repository.NewContentTagRepository(repository.GetDB())
+	contentRepo.EnableHistory(cfg.History.MaxPerContent)
+	contentRepo.SetTagLimits(model.TagLimits{
+		MaxLength:     cfg.Tags.MaxLength,
+		MaxPerContent: cfg.Tags.MaxPerContent,
+	})
 
-	manager := provider.NewManager(providerRepo, contentRepo, tagRepo)
+	provider.SetFetchCacheTTL(time.Duration(cfg.Provider.FetchCacheTTLSeconds) * time.Second)
+	syncRepo := repository.NewSyncHistoryRepository(repository.GetDB())
+	manager := provider.NewManager(providerRepo, contentRepo, syncRepo)
+	manager.SetFutureDatePolicy(provider.FutureDatePolicyFromConfig(cfg.Provider.FutureDates))
 
-	ensureProvider(providerRepo, &model.Provider{
+	provider1 := ensureProvider(providerRepo, &model.Provider{
 		Name:               "provider1",
 		URL:                cfg.Provider.Provider1URL,
 		Format:             model.ProviderFormatJSON,
 		RateLimitPerMinute: 60,
 	})
 
-	ensureProvider(providerRepo, &model.Provider{
+	provider2 := ensureProvider(providerRepo, &model.Provider{
 		Name:               "provider2",
 		URL:                cfg.Provider.Provider2URL,
 		Format:             model.ProviderFormatXML,
 		RateLimitPerMinute: 60,
 	})
 
-	manager.RegisterProvider(provider.NewJSONProvider("provider1", cfg.Provider.Provider1URL))
-	manager.RegisterProvider(provider.NewXMLProvider("provider2", cfg.Provider.Provider2URL))
+	pagination := provider.PaginationPolicyFromConfig(cfg.Provider.Pagination)
+	manager.RegisterProvider(provider.NewJSONProvider(provider1.Name, provider1.URL, provider.HTTPTimeoutsFromConfig(cfg.Provider.Provider1Timeouts), cfg.Provider.Provider1DateLayouts, provider.RetryPolicyFromConfig(cfg.Provider.Retry), pagination))
+	manager.RegisterProvider(provider.NewXMLProvider(provider2.Name, provider2.URL, provider.HTTPTimeoutsFromConfig(cfg.Provider.Provider2Timeouts), cfg.Provider.Provider2DateLayouts, provider.RetryPolicyFromConfig(cfg.Provider.Retry), pagination))
+	if err := manager.RegisterMappedProviders(provider.HTTPTimeoutsFromConfig(cfg.Provider.MappedTimeouts), provider.RetryPolicyFromConfig(cfg.Provider.Retry)); err != nil {
+		log.Printf("Warning: Failed to register mapped providers: %v", err)
+	}
 
 	log.Println("Fetching data from providers...")
-	if err := manager.FetchAll(); err != nil {
-		log.Fatalf("Failed to fetch providers: %v", err)
+	reports, fetchErr := manager.FetchAll(ctx)
+	provider.LogSyncReports(ctx, reports)
+
+	// A provider that failed doesn't stop the others: their content was saved and
+	// still gets scored below, and the command only exits with an error at the end
+	var partial *provider.MultiProviderError
+	switch {
+	case fetchErr == nil:
+		log.Println("Provider sync completed successfully")
+	case errors.As(fetchErr, &partial):
+		for _, f := range partial.Failures {
+			log.Printf("Provider %s failed to sync: %v", f.Provider, f.Err)
+		}
+	default:
+		log.Fatalf("Failed to fetch providers: %v", fetchErr)
 	}
 
-	log.Println("Provider sync completed successfully")
-
 	// After syncing content, recalculate scores so that search ordering by score is meaningful.
-	scoringService := service.NewScoringService(contentRepo)
+	scoringService := service.NewScoringService(contentRepo, cfg.Scoring)
 
 	providers, err := providerRepo.GetAll()
 	if err != nil {
@@ -61,34 +91,50 @@ func main() {
 	}
 
 	for _, p := range providers {
-		if err := scoringService.RecalculateScoresForProvider(p.ID); err != nil {
+		if partial != nil && partial.Failed(p.Name) {
+			continue
+		}
+		if err := scoringService.RecalculateScoresForProvider(ctx, p.ID); err != nil {
+			if ctx.Err() != nil {
+				log.Fatalf("Score recalculation interrupted: %v", err)
+			}
 			log.Printf("Failed to recalculate scores for provider %d: %v", p.ID, err)
 			continue
 		}
 	}
 
+	// Content and scores changed under every search the API has cached
+	service.InvalidateSharedSearchCache(cfg.Redis)
+
+	if partial != nil {
+		log.Fatalf("Provider sync finished with failures: %v", partial)
+	}
 	log.Println("Score recalculation for all providers completed successfully")
 }
 
-func ensureProvider(repo *repository.ProviderRepository, p *model.Provider) {
+// ensureProvider creates the provider if it is missing and returns the stored row
+// An existing row is returned as stored, keeping edits made through the admin endpoints
+// If another provider already points at the same URL it is reused instead of
+// creating a duplicate that would ingest the same feed twice
+func ensureProvider(repo *repository.ProviderRepository, p *model.Provider) *model.Provider {
 	existing, err := repo.GetByName(p.Name)
-	if err != nil {
-		if errors.Is(err, repository.ErrProviderNotFound) {
-			if err := repo.Create(p); err != nil {
-				log.Fatalf("Failed to create provider %s: %v", p.Name, err)
-			}
-			log.Printf("Created provider %s", p.Name)
-			return
-		}
+	if err == nil {
+		return existing
+	}
+	if !errors.Is(err, repository.ErrProviderNotFound) {
 		log.Fatalf("Failed to get provider %s: %v", p.Name, err)
-		return
 	}
 
-	existing.URL = p.URL
-	existing.Format = p.Format
-	existing.RateLimitPerMinute = p.RateLimitPerMinute
-	if err := repo.Update(existing); err != nil {
-		log.Fatalf("Failed to update provider %s: %v", existing.Name, err)
+	if byURL, err := repo.GetByURL(p.URL); err == nil {
+		if err := model.ValidateProvider(p, byURL); err != nil {
+			log.Fatalf("Failed to register provider %s: %v", p.Name, err)
+		}
+		log.Printf("Provider %s shares its URL with existing provider %s, reusing it", p.Name, byURL.Name)
+		return byURL
+	}
+	if err := repo.Create(p); err != nil {
+		log.Fatalf("Failed to create provider %s: %v", p.Name, err)
 	}
-	log.Printf("Updated provider %s", existing.Name)
+	log.Printf("Created provider %s", p.Name)
+	return p
 }
@@ -23,37 +23,43 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/content/{id}": {
+        "/admin/cache-key/search": {
             "get": {
-                "description": "Get detailed information about a specific content item by its ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Return the cache key that /search would use for the same query parameters. Requires the X-Admin-Key header.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "content"
+                    "admin"
                 ],
-                "summary": "Get content by ID",
+                "summary": "Compute search cache key",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Content ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Same parameters as /search",
+                        "name": "query",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Cache key",
                         "schema": {
-                            "$ref": "#/definitions/model.Content"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid content ID",
+                        "description": "Invalid request parameters",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -61,17 +67,46 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Content not found",
+                    "401": {
+                        "description": "Admin authentication required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/admin/cache-stats": {
+            "delete": {
+                "description": "Zero the search cache hit and miss counters and return the values they had. Requires the X-Admin-Key header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reset search cache statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CacheStats"
+                        }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Admin authentication required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -82,31 +117,85 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
+        "/admin/cache/{key}": {
             "get": {
-                "description": "Check if the server and database are running",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Report whether a cache key exists and its remaining TTL. Requires the X-Admin-Key header.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "admin"
+                ],
+                "summary": "Inspect cache entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cache key (URL-encoded)",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Health check",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CacheEntryStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Admin authentication required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a single cache key. Requires the X-Admin-Key header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Evict cache entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    {
+                        "type": "string",
+                        "description": "Cache key (URL-encoded)",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Key and whether it was deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Admin authentication required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -117,31 +206,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/providers": {
+        "/admin/scoring/degraded": {
             "get": {
-                "description": "Get a list of all content providers",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Report whether score-based ranking is disabled. Requires the X-Admin-Key header.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "providers"
+                    "admin"
+                ],
+                "summary": "Get scoring degraded mode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "Get providers list",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Provider"
-                            }
+                            "$ref": "#/definitions/handler.ScoringDegradedStatus"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Admin authentication required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -150,11 +242,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/search": {
-            "get": {
-                "description": "Search for content with filtering, sorting, and pagination. Results are ranked by relevance score.",
+            },
+            "put": {
+                "description": "Turn degraded mode on or off. While on, searches that would order by score are ordered by published_at DESC and carry a notice. The setting lasts until changed or the server restarts (SCORING_DEGRADED sets the startup value). Requires the X-Admin-Key header.",
                 "consumes": [
                     "application/json"
                 ],
@@ -162,74 +252,83 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "search"
+                    "admin"
                 ],
-                "summary": "Search content",
+                "summary": "Set scoring degraded mode",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search keyword (optional - if empty, returns all content)",
-                        "name": "query",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by content type: video or article",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by provider ID",
-                        "name": "provider_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter results published on/after this date (YYYY-MM-DD)",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter results published on/before this date (YYYY-MM-DD)",
-                        "name": "end_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Items per page (default: 10, max: 100)",
-                        "name": "per_page",
-                        "in": "query"
+                        "description": "Desired state",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ScoringDegradedStatus"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ScoringDegradedStatus"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Sort field: score, published_at, or title (default: score)",
-                        "name": "sort_by",
-                        "in": "query"
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
+                    "401": {
+                        "description": "Admin authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sync/last-delta": {
+            "get": {
+                "description": "For every provider's most recent sync, report how many items were new and how many existing items were updated since the sync started. Updated counts only items whose fields changed: items resent unchanged and score recalculations are not counted. Requires the X-Admin-Key header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get last sync delta",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Sort order: asc or desc (default: desc)",
-                        "name": "sort_order",
-                        "in": "query"
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.SearchResponse"
+                            "$ref": "#/definitions/model.SyncDeltaReport"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request parameters",
+                    "401": {
+                        "description": "Admin authentication required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -249,9 +348,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/stats": {
+        "/autocomplete": {
             "get": {
-                "description": "Get statistics about the search engine including content counts, provider information, and type distribution",
+                "description": "Suggest distinct titles for a partially typed query: titles starting with it first, then titles with words starting with each of its terms, higher scores first. Results are cached for CACHE_TTL_SUGGEST_SECONDS.",
                 "consumes": [
                     "application/json"
                 ],
@@ -259,15 +358,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stats"
+                    "search"
+                ],
+                "summary": "Autocomplete titles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The text typed so far",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Most titles returned (default: 8, max: 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get system statistics",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing q or invalid limit",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -281,154 +406,2816 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.Content": {
-            "type": "object",
-            "properties": {
-                "comments": {
-                    "type": "integer"
-                },
-                "created_at": {
-                    "description": "Timestamps",
-                    "type": "string"
-                },
-                "duration_seconds": {
-                    "type": "integer"
-                },
-                "external_id": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "likes": {
-                    "type": "integer"
-                },
-                "provider": {
-                    "description": "Provider information (optional)",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.Provider"
-                        }
-                    ]
+        },
+        "/cache/stats": {
+            "get": {
+                "description": "Search cache hits, misses and hit ratio since startup or the last reset. Searches with nocache count as neither.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Get search cache statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CacheStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/content": {
+            "post": {
+                "description": "Create or update up to 500 content items. Each item needs the provider_id of an existing provider; an item whose provider and external_id already exist is updated. Scores are computed on save and tags replaced when given; an item and its tags are saved together or not at all. The response lists a created, updated or error result per item, in request order; the status is 200 when every item was saved and 207 when some failed. Requires the X-Admin-Key header.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "Bulk ingest content",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Content items",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Content"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkContentResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Some items failed",
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkContentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid body, empty batch or too many items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Admin authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/content/{id}": {
+            "get": {
+                "description": "Get detailed information about a specific content item by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "Get content by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag order: alpha or insertion (default: alpha)",
+                        "name": "tag_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response; 304 without a body while the item is unchanged",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Content"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version tag derived from updated_at, the same as HEAD returns"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Content unchanged since the ETag in If-None-Match"
+                    },
+                    "400": {
+                        "description": "Invalid content ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Content not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Lookup exceeded its time budget",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a content item and its tags. Deleting invalidates the search cache, so cached search results stop listing it. Requires the X-Admin-Key header.",
+                "tags": [
+                    "content"
+                ],
+                "summary": "Delete content",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Content deleted"
+                    },
+                    "400": {
+                        "description": "Invalid content ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Admin authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Content not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Check whether a content item exists without fetching it. Returns Last-Modified and ETag headers derived from updated_at.",
+                "tags": [
+                    "content"
+                ],
+                "summary": "Check content exists",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Content exists",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version tag derived from updated_at"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the content was last updated"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid content ID"
+                    },
+                    "404": {
+                        "description": "Content not found"
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update selected fields of a content item; omitted fields keep their values. Patchable: title, published_at, views, likes, duration_seconds (videos), reading_time, reactions, comments (articles). The score is recomputed from the result. Requires the X-Admin-Key header.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "Partially update content",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ContentPatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Content"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid content ID or body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Admin authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Content not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/content/{id}/history": {
+            "get": {
+                "description": "Get snapshots of a content item's metrics and score, newest first. A snapshot is recorded when a sync, an edit or a score recalculation changes a metric or moves the score noticeably; retention per item is capped by CONTENT_HISTORY_MAX_PER_ITEM",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "Get content history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum snapshots to return (default: 20, max: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ContentHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid content ID or limit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Content not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/content/{id}/score": {
+            "get": {
+                "description": "Get the base, freshness and engagement components of a content item's score. Freshness is 0 with a \"disabled\" note when SCORING_DISABLE_FRESHNESS is set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "Get content score breakdown",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scoring.ScoreBreakdown"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid content ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Content not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/content/{id}/similar": {
+            "get": {
+                "description": "Get other content items sharing tags with a content item, ranked by the number of shared tags, then score. An item without tags has no similar content",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "content"
+                ],
+                "summary": "Get similar content",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum items to return (default: 10, capped at 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SimilarContentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid content ID or limit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Content not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/enums": {
+            "get": {
+                "description": "Get the valid content types, provider formats, sort fields, sort orders, tag orders, match modes and search periods",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meta"
+                ],
+                "summary": "Get supported enum values",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.EnumsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Get detailed system health status including database and Redis connectivity, uptime, and component statistics. Returns 503 when a dependency is unhealthy. /health is an alias kept for existing clients.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness health check",
+                "responses": {
+                    "200": {
+                        "description": "System is healthy",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "System is degraded (some components unhealthy)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/health/live": {
+            "get": {
+                "description": "Report that the process is running. Never checks dependencies, so it only fails when the server can't answer at all.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness check",
+                "responses": {
+                    "200": {
+                        "description": "Process is alive",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Get detailed system health status including database and Redis connectivity, uptime, and component statistics. Returns 503 when a dependency is unhealthy. /health is an alias kept for existing clients.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness health check",
+                "responses": {
+                    "200": {
+                        "description": "System is healthy",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "System is degraded (some components unhealthy)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/providers": {
+            "get": {
+                "description": "Get a page of content providers ordered by name, with the same pagination fields as search",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Get providers list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default: 20, max: 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProviderListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Register a new content provider. It starts enabled; providers with a field_mapping are fetched by the next sync. Requires the X-Admin-Key header.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Create a provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Provider to create",
+                        "name": "provider",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ProviderCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Provider"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid provider",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Admin authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Name or URL already registered",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/status": {
+            "get": {
+                "description": "Get every provider's enabled state, last fetch, last recorded sync run, whether a sync is running in this API process, and a stale flag. A provider is stale when its last fetch is older than its stale_after_minutes, or PROVIDER_STALE_AFTER_MINUTES when unset; disabled providers are never stale.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Get provider statuses",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ProviderStatus"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/{id}": {
+            "get": {
+                "description": "Get a provider by ID with its content count and last fetch time (null if never fetched)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Get a provider",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProviderDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid provider ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Provider not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace a provider's url, format, rate_limit_per_minute and field_mapping; the name can't be changed. Requires the X-Admin-Key header.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Update a provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New fetch settings",
+                        "name": "provider",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ProviderUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Provider"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid provider ID or settings",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Admin authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Provider not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "URL already registered",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a provider; its content, tags and sync history are deleted with it. Requires the X-Admin-Key header.",
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Delete a provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Provider deleted"
+                    },
+                    "400": {
+                        "description": "Invalid provider ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Admin authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Provider not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/{id}/sync-history": {
+            "get": {
+                "description": "Get a provider's recorded sync runs, newest first: when each started and finished, how many items it fetched, skipped and saved, and the error of failed runs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Get provider sync history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum runs to return (default: 20, max: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SyncHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid provider ID or limit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Provider not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Report whether the instance can serve traffic. Returns 503 while the database is unreachable or recently failed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness check",
+                "responses": {
+                    "200": {
+                        "description": "Instance is ready",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Instance is not ready",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Search for content with filtering, sorting, and pagination. Results are ranked by relevance score.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search content",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search keyword (optional - if empty, returns all content)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by content type: video or article",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by provider ID",
+                        "name": "provider_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter results published on/after this date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter results published on/before this date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date-range preset ending today: last_week, last_month, last_3_months or last_year; start_date and end_date override its ends",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only content created, edited or changed by a sync at or after this RFC 3339 timestamp, e.g. 2024-03-15T10:00:00Z; resyncs that change nothing and score recalculations don't count",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default: 10, max: SEARCH_MAX_PER_PAGE, normally 100; larger values are capped)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous next_cursor; continues after that page without OFFSET (page is ignored). Only with sort_by=score, sort_order=desc and without distinct_titles or group_by_provider",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: score, published_at, title, relevance, views, likes, reactions or comments (default: score); relevance blends the text match (FULLTEXT match score, or for short keywords the number of terms the title contains) with score; engagement sorts list content of the other type last",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: asc or desc (default: desc)",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag order: alpha or insertion (default: alpha)",
+                        "name": "tag_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Query timeout override in milliseconds (clamped to the server maximum)",
+                        "name": "timeout_ms",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Prefix-match keywords so go matches golang (default: server setting, normally true)",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "How keyword terms combine in FULLTEXT mode: any or all (default: any)",
+                        "name": "match_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also match the keyword against tags, ORed with the title match (default: true)",
+                        "name": "include_tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Collapse results with the same title to the highest-scoring one; total then counts titles and collapsed_count the hidden rows",
+                        "name": "distinct_titles",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Bucket results by provider into groups, each with that provider's top per_page matches and its match count; results is then empty. Not with distinct_titles",
+                        "name": "group_by_provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add facets: match counts per type and per provider, over the same filters as the search",
+                        "name": "include_facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add highlighted_title to each result: the HTML-escaped title with query matches wrapped in \u003cmark\u003e tags (SEARCH_HIGHLIGHT_PRE_TAG/POST_TAG)",
+                        "name": "highlight",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "When the keyword matches nothing, return titles with words spelled close to the query terms instead, closest first; the response then has fuzzy=true",
+                        "name": "fuzzy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hide videos with fewer views (articles unaffected)",
+                        "name": "min_views",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hide videos with fewer likes (articles unaffected)",
+                        "name": "min_likes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hide articles with fewer reactions (videos unaffected)",
+                        "name": "min_reactions",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hide articles with fewer comments (videos unaffected)",
+                        "name": "min_comments",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip the cached result and query the database (the fresh result is still cached)",
+                        "name": "nocache",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "no-cache skips the cached result like nocache=true",
+                        "name": "Cache-Control",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response; 304 without a body while the response is unchanged",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SearchResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the response data"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Response unchanged since the ETag in If-None-Match"
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Search query exceeded its time budget",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/search/count": {
+            "get": {
+                "description": "Count the content matching the same filters as /search without fetching rows. If the count times out, total is -1 and timed_out is true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Count search results",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search keyword (optional - if empty, counts all content)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by content type: video or article",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by provider ID",
+                        "name": "provider_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter results published on/after this date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter results published on/before this date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date-range preset ending today: last_week, last_month, last_3_months or last_year; start_date and end_date override its ends",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only content created, edited or changed by a sync at or after this RFC 3339 timestamp, e.g. 2024-03-15T10:00:00Z; resyncs that change nothing and score recalculations don't count",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Query timeout override in milliseconds (clamped to the server maximum)",
+                        "name": "timeout_ms",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Prefix-match keywords so go matches golang (default: server setting, normally true)",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "How keyword terms combine in FULLTEXT mode: any or all (default: any)",
+                        "name": "match_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also match the keyword against tags, ORed with the title match (default: true)",
+                        "name": "include_tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count distinct titles instead of rows",
+                        "name": "distinct_titles",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hide videos with fewer views (articles unaffected)",
+                        "name": "min_views",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hide videos with fewer likes (articles unaffected)",
+                        "name": "min_likes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hide articles with fewer reactions (videos unaffected)",
+                        "name": "min_reactions",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hide articles with fewer comments (videos unaffected)",
+                        "name": "min_comments",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SearchCountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "description": "Get statistics about the search engine including content counts, provider information, each provider's last sync result, and type distribution",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get system statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/providers": {
+            "get": {
+                "description": "Get per-provider statistics (type breakdown, average score, freshness) for a comma-separated list of provider IDs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get statistics for selected providers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated provider IDs (e.g. 1,2,3)",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ProviderStats"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid provider IDs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sync": {
+            "post": {
+                "description": "Fetch from every configured provider, then recalculate scores. Returns 200 with a per-provider summary, or 202 if the sync takes longer than PROVIDER_SYNC_WAIT_SECONDS (it keeps running, for at most PROVIDER_SYNC_TIMEOUT_SECONDS; check /providers/status). Returns 409 while another sync is running. Requires the X-Admin-Key header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Trigger a provider sync",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SyncSummary"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SyncRunningResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Admin authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "A sync is already running",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "List distinct tags with the number of content items carrying each, most used first. Tags differing only in case are counted together.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List tags",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Most tags returned (default: 50, max: 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tags starting with this, for typeahead",
+                        "name": "prefix",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TagCount"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.CacheEntryStatus": {
+            "type": "object",
+            "properties": {
+                "exists": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "ttl_seconds": {
+                    "description": "Seconds until expiry; -1 if the key never expires",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.EnumsResponse": {
+            "type": "object",
+            "properties": {
+                "content_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ContentType"
+                    }
+                },
+                "match_modes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MatchMode"
+                    }
+                },
+                "provider_formats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProviderFormat"
+                    }
+                },
+                "search_periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SearchPeriod"
+                    }
+                },
+                "sort_fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sort_orders": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tag_orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TagOrder"
+                    }
+                }
+            }
+        },
+        "handler.ScoringDegradedStatus": {
+            "type": "object",
+            "properties": {
+                "degraded": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.SyncRunningResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BulkContentItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Content ID of a saved item",
+                    "type": "integer"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "score": {
+                    "description": "Score computed for a saved item",
+                    "type": "number"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.BulkItemStatus"
+                },
+                "tags_dropped": {
+                    "description": "Tags of a saved item rejected by the tag limits",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BulkContentResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BulkContentItemResult"
+                    }
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BulkItemStatus": {
+            "type": "string",
+            "enum": [
+                "created",
+                "updated",
+                "error"
+            ],
+            "x-enum-comments": {
+                "BulkItemCreated": "New item inserted",
+                "BulkItemFailed": "Item rejected or not saved; see Error",
+                "BulkItemUpdated": "Existing item with the same provider and external ID overwritten"
+            },
+            "x-enum-descriptions": [
+                "New item inserted",
+                "Existing item with the same provider and external ID overwritten",
+                "Item rejected or not saved; see Error"
+            ],
+            "x-enum-varnames": [
+                "BulkItemCreated",
+                "BulkItemUpdated",
+                "BulkItemFailed"
+            ]
+        },
+        "model.CacheStats": {
+            "type": "object",
+            "properties": {
+                "hit_ratio": {
+                    "description": "hits / (hits + misses); 0 before any lookup",
+                    "type": "number"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Content": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "Timestamps",
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "type": "integer"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "highlighted_title": {
+                    "description": "HighlightedTitle is the HTML-escaped title with query matches marked\nSet only on search results of requests with highlight=true",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_synced_at": {
+                    "description": "LastSyncedAt is set only when a provider sync writes the row\nUnlike UpdatedAt it doesn't move on internal writes such as score recalcs",
+                    "type": "string"
+                },
+                "likes": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "description": "Provider information (optional)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Provider"
+                        }
+                    ]
+                },
+                "provider_id": {
+                    "type": "integer"
+                },
+                "published_at": {
+                    "description": "Common fields\nAll times are UTC: providers' dates are converted on parse and the DB session runs in UTC",
+                    "type": "string"
+                },
+                "reactions": {
+                    "type": "integer"
+                },
+                "reading_time": {
+                    "description": "Article-specific metrics (NULL for videos)\nThese fields are populated when Type is \"article\"",
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "tags": {
+                    "description": "Related data (loaded separately)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.ContentType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "views": {
+                    "description": "Video-specific metrics (NULL for articles)\nThese fields are populated when Type is \"video\"",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ContentHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "content_id": {
+                    "type": "integer"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ContentSnapshot"
+                    }
+                }
+            }
+        },
+        "model.ContentPatch": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "integer"
+                },
+                "duration_seconds": {
+                    "type": "integer"
+                },
+                "likes": {
+                    "type": "integer"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "reactions": {
+                    "type": "integer"
+                },
+                "reading_time": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ContentSnapshot": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "integer"
+                },
+                "content_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "likes": {
+                    "type": "integer"
+                },
+                "reactions": {
+                    "type": "integer"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ContentType": {
+            "type": "string",
+            "enum": [
+                "video",
+                "article"
+            ],
+            "x-enum-varnames": [
+                "ContentTypeVideo",
+                "ContentTypeArticle"
+            ]
+        },
+        "model.FieldMapping": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "string"
+                },
+                "date_layouts": {
+                    "description": "Tried in order; RFC 3339 when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "default_type": {
+                    "description": "Used when Type is unset or missing from an item",
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "\"MM:SS\" or \"HH:MM:SS\" strings, or a number of seconds",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items_path": {
+                    "type": "string"
+                },
+                "likes": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "reactions": {
+                    "type": "string"
+                },
+                "reading_time": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "An array of strings or a comma-separated string",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MatchMode": {
+            "type": "string",
+            "enum": [
+                "any",
+                "all"
+            ],
+            "x-enum-comments": {
+                "MatchModeAll": "A row matches only if its title contains every term",
+                "MatchModeAny": "A row matches if its title contains any term (default)"
+            },
+            "x-enum-descriptions": [
+                "A row matches if its title contains any term (default)",
+                "A row matches only if its title contains every term"
+            ],
+            "x-enum-varnames": [
+                "MatchModeAny",
+                "MatchModeAll"
+            ]
+        },
+        "model.Provider": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "Disabled providers are skipped by syncs",
+                    "type": "boolean"
+                },
+                "field_mapping": {
+                    "description": "Set for providers synced through a configurable provider",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.FieldMapping"
+                        }
+                    ]
+                },
+                "format": {
+                    "$ref": "#/definitions/model.ProviderFormat"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_fetched_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rate_limit_per_minute": {
+                    "type": "integer"
+                },
+                "stale_after_minutes": {
+                    "description": "Per-provider staleness threshold; server default when unset",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ProviderCreateRequest": {
+            "type": "object",
+            "properties": {
+                "field_mapping": {
+                    "$ref": "#/definitions/model.FieldMapping"
+                },
+                "format": {
+                    "$ref": "#/definitions/model.ProviderFormat"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rate_limit_per_minute": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ProviderDetail": {
+            "type": "object",
+            "properties": {
+                "content_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "Disabled providers are skipped by syncs",
+                    "type": "boolean"
+                },
+                "field_mapping": {
+                    "description": "Set for providers synced through a configurable provider",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.FieldMapping"
+                        }
+                    ]
+                },
+                "format": {
+                    "$ref": "#/definitions/model.ProviderFormat"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_fetched_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rate_limit_per_minute": {
+                    "type": "integer"
+                },
+                "stale_after_minutes": {
+                    "description": "Per-provider staleness threshold; server default when unset",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ProviderFacet": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "provider_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ProviderFormat": {
+            "type": "string",
+            "enum": [
+                "json",
+                "xml"
+            ],
+            "x-enum-varnames": [
+                "ProviderFormatJSON",
+                "ProviderFormatXML"
+            ]
+        },
+        "model.ProviderGroup": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Matching rows of this provider across all pages",
+                    "type": "integer"
+                },
+                "provider_id": {
+                    "type": "integer"
+                },
+                "results": {
+                    "description": "At most per_page rows, in the requested sort order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Content"
+                    }
+                }
+            }
+        },
+        "model.ProviderListResponse": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer"
+                },
+                "per_page": {
+                    "type": "integer"
+                },
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Provider"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ProviderStats": {
+            "type": "object",
+            "properties": {
+                "articles": {
+                    "type": "integer"
+                },
+                "average_score": {
+                    "type": "number"
+                },
+                "last_fetched_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "newest_published_at": {
+                    "type": "string"
+                },
+                "oldest_published_at": {
+                    "type": "string"
+                },
+                "provider_id": {
+                    "type": "integer"
+                },
+                "published_last_month": {
+                    "type": "integer"
+                },
+                "published_last_week": {
+                    "type": "integer"
+                },
+                "total_content": {
+                    "type": "integer"
+                },
+                "videos": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ProviderStatus": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "last_fetched_at": {
+                    "type": "string"
+                },
+                "last_sync": {
+                    "$ref": "#/definitions/model.SyncResult"
+                },
+                "name": {
+                    "type": "string"
                 },
                 "provider_id": {
                     "type": "integer"
                 },
-                "published_at": {
-                    "description": "Common fields",
+                "stale": {
+                    "type": "boolean"
+                },
+                "stale_after_minutes": {
+                    "type": "integer"
+                },
+                "sync_running": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.ProviderSyncReport": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "description": "Skipped because the provider is disabled",
+                    "type": "boolean"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "items_fetched": {
+                    "type": "integer"
+                },
+                "items_skipped": {
+                    "type": "integer"
+                },
+                "items_upserted": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "tags_dropped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ProviderUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "field_mapping": {
+                    "$ref": "#/definitions/model.FieldMapping"
+                },
+                "format": {
+                    "$ref": "#/definitions/model.ProviderFormat"
+                },
+                "rate_limit_per_minute": {
+                    "type": "integer"
+                },
+                "url": {
                     "type": "string"
+                }
+            }
+        },
+        "model.SearchCountResponse": {
+            "type": "object",
+            "properties": {
+                "timed_out": {
+                    "description": "TimedOut is true when the COUNT query exceeded its timeout and Total is unknown",
+                    "type": "boolean"
                 },
-                "reactions": {
-                    "type": "integer"
-                },
-                "reading_time": {
-                    "description": "Article-specific metrics (NULL for videos)\nThese fields are populated when Type is \"article\"",
+                "total": {
+                    "description": "Number of matching results, -1 if the count timed out",
                     "type": "integer"
+                }
+            }
+        },
+        "model.SearchFacets": {
+            "type": "object",
+            "properties": {
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProviderFacet"
+                    }
                 },
-                "score": {
-                    "type": "number"
-                },
-                "tags": {
-                    "description": "Related data (loaded separately)",
+                "types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TypeFacet"
+                    }
+                }
+            }
+        },
+        "model.SearchHint": {
+            "type": "object",
+            "properties": {
+                "active_filters": {
+                    "description": "Filters that narrowed the search",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "title": {
+                "effective_query": {
+                    "description": "Query as sent to the database after sanitizing",
                     "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/model.ContentType"
+                "mode": {
+                    "description": "Matching strategy that was used",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SearchMode"
+                        }
+                    ]
                 },
-                "updated_at": {
+                "suggestion": {
+                    "description": "What to try next",
                     "type": "string"
-                },
-                "views": {
-                    "description": "Video-specific metrics (NULL for articles)\nThese fields are populated when Type is \"video\"",
-                    "type": "integer"
                 }
             }
         },
-        "model.ContentType": {
+        "model.SearchMode": {
             "type": "string",
             "enum": [
-                "video",
-                "article"
+                "none",
+                "fulltext",
+                "like"
+            ],
+            "x-enum-comments": {
+                "SearchModeFullText": "MATCH ... AGAINST in boolean mode",
+                "SearchModeLike": "LIKE fallback for short queries",
+                "SearchModeNone": "No keyword, filters only"
+            },
+            "x-enum-descriptions": [
+                "No keyword, filters only",
+                "MATCH ... AGAINST in boolean mode",
+                "LIKE fallback for short queries"
             ],
             "x-enum-varnames": [
-                "ContentTypeVideo",
-                "ContentTypeArticle"
+                "SearchModeNone",
+                "SearchModeFullText",
+                "SearchModeLike"
             ]
         },
-        "model.Provider": {
+        "model.SearchPeriod": {
+            "type": "string",
+            "enum": [
+                "last_week",
+                "last_month",
+                "last_3_months",
+                "last_year"
+            ],
+            "x-enum-comments": {
+                "PeriodLast3Months": "Since the same day 3 months ago",
+                "PeriodLastMonth": "Since the same day last month",
+                "PeriodLastWeek": "The past 7 days",
+                "PeriodLastYear": "Since the same day last year"
+            },
+            "x-enum-descriptions": [
+                "The past 7 days",
+                "Since the same day last month",
+                "Since the same day 3 months ago",
+                "Since the same day last year"
+            ],
+            "x-enum-varnames": [
+                "PeriodLastWeek",
+                "PeriodLastMonth",
+                "PeriodLast3Months",
+                "PeriodLastYear"
+            ]
+        },
+        "model.SearchResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "collapsed_count": {
+                    "description": "CollapsedCount is how many matching rows distinct_titles hid as duplicates\nTotal then counts distinct titles rather than rows",
+                    "type": "integer"
+                },
+                "facets": {
+                    "description": "Facets counts the matching rows per type and per provider, for filter UIs\nOnly set with include_facets; absent when the facet query timed out",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SearchFacets"
+                        }
+                    ]
+                },
+                "fuzzy": {
+                    "description": "Fuzzy is true when nothing matched the keyword as typed and the results are\nnear spellings found by the fuzzy fallback, ordered closest first",
+                    "type": "boolean"
+                },
+                "groups": {
+                    "description": "Groups holds the results of a group_by_provider search, one per provider with\nrows on the page, ordered by provider ID; Results is then empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProviderGroup"
+                    }
+                },
+                "hint": {
+                    "description": "Hint explains an empty result set; only set when there are no results",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SearchHint"
+                        }
+                    ]
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the page after this one when passed as after\nSet on full pages of score-ordered searches; absent on the last page",
                     "type": "string"
                 },
-                "format": {
-                    "$ref": "#/definitions/model.ProviderFormat"
+                "notice": {
+                    "description": "Notice flags server-side conditions affecting the results, e.g. score ranking being disabled",
+                    "type": "string"
                 },
-                "id": {
+                "page": {
+                    "description": "Current page number",
                     "type": "integer"
                 },
-                "last_fetched_at": {
+                "per_page": {
+                    "description": "Items per page",
+                    "type": "integer"
+                },
+                "result_checksum": {
+                    "description": "ResultChecksum identifies the page's rows and their order: it changes when an item\nis added, removed, reordered or updated, so polling clients can skip unchanged pages",
                     "type": "string"
                 },
-                "name": {
+                "results": {
+                    "description": "Search results",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Content"
+                    }
+                },
+                "suggestions": {
+                    "description": "Suggestions are existing tags spelled close to the query (\"did you mean\")\nOnly set when nothing matched; at most three, closest first",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags_partial": {
+                    "description": "TagsPartial is true when tag loading failed or timed out, so the tags\non the results may be incomplete rather than genuinely absent",
+                    "type": "boolean"
+                },
+                "total": {
+                    "description": "Total number of results; approximate when TotalIsEstimate is set",
+                    "type": "integer"
+                },
+                "total_is_estimate": {
+                    "description": "TotalIsEstimate is true when the COUNT query timed out and Total was estimated\nThe estimate comes from table statistics or the query plan, or is a lower bound\nfrom the rows paged through so far when neither applies",
+                    "type": "boolean"
+                },
+                "total_pages": {
+                    "description": "Total number of pages",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SimilarContent": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "$ref": "#/definitions/model.Content"
+                },
+                "shared_tags": {
+                    "description": "Tags it has in common with the source item",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SimilarContentResponse": {
+            "type": "object",
+            "properties": {
+                "content_id": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SimilarContent"
+                    }
+                }
+            }
+        },
+        "model.SyncDelta": {
+            "type": "object",
+            "properties": {
+                "error_message": {
                     "type": "string"
                 },
-                "rate_limit_per_minute": {
+                "finished_at": {
+                    "type": "string"
+                },
+                "items_fetched": {
                     "type": "integer"
                 },
-                "updated_at": {
+                "new_items": {
+                    "type": "integer"
+                },
+                "provider_id": {
+                    "type": "integer"
+                },
+                "provider_name": {
                     "type": "string"
                 },
-                "url": {
+                "started_at": {
                     "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.SyncStatus"
+                },
+                "updated_items": {
+                    "type": "integer"
                 }
             }
         },
-        "model.ProviderFormat": {
+        "model.SyncDeltaReport": {
+            "type": "object",
+            "properties": {
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SyncDelta"
+                    }
+                },
+                "total_new": {
+                    "type": "integer"
+                },
+                "total_updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SyncHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SyncResult"
+                    }
+                },
+                "provider_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SyncResult": {
+            "type": "object",
+            "properties": {
+                "error_message": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items_fetched": {
+                    "type": "integer"
+                },
+                "items_skipped": {
+                    "description": "Provider items that could not be transformed",
+                    "type": "integer"
+                },
+                "items_upserted": {
+                    "description": "Fetched items saved to the database",
+                    "type": "integer"
+                },
+                "provider_id": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.SyncStatus"
+                },
+                "tags_dropped": {
+                    "description": "Tags rejected by the tag limits",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SyncStatus": {
             "type": "string",
             "enum": [
-                "json",
-                "xml"
+                "success",
+                "failed"
             ],
             "x-enum-varnames": [
-                "ProviderFormatJSON",
-                "ProviderFormatXML"
+                "SyncStatusSuccess",
+                "SyncStatusFailed"
             ]
         },
-        "model.SearchResponse": {
+        "model.SyncSummary": {
             "type": "object",
             "properties": {
-                "page": {
-                    "description": "Current page number",
+                "duration_ms": {
                     "type": "integer"
                 },
-                "per_page": {
-                    "description": "Items per page",
-                    "type": "integer"
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProviderSyncReport"
+                    }
                 },
-                "results": {
-                    "description": "Search results",
+                "score_errors": {
+                    "description": "Providers whose scores failed to recalculate",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Content"
+                        "type": "string"
                     }
                 },
-                "total": {
-                    "description": "Total number of results",
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TagCount": {
+            "type": "object",
+            "properties": {
+                "count": {
                     "type": "integer"
                 },
-                "total_pages": {
-                    "description": "Total number of pages",
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TagOrder": {
+            "type": "string",
+            "enum": [
+                "alpha",
+                "insertion"
+            ],
+            "x-enum-comments": {
+                "TagOrderAlpha": "Alphabetical order (default)",
+                "TagOrderInsertion": "Order the provider supplied them in"
+            },
+            "x-enum-descriptions": [
+                "Alphabetical order (default)",
+                "Order the provider supplied them in"
+            ],
+            "x-enum-varnames": [
+                "TagOrderAlpha",
+                "TagOrderInsertion"
+            ]
+        },
+        "model.TypeFacet": {
+            "type": "object",
+            "properties": {
+                "count": {
                     "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.ContentType"
+                }
+            }
+        },
+        "scoring.ScoreBreakdown": {
+            "type": "object",
+            "properties": {
+                "base_score": {
+                    "type": "number"
+                },
+                "engagement_score": {
+                    "type": "number"
+                },
+                "final_score": {
+                    "type": "number"
+                },
+                "freshness_note": {
+                    "type": "string"
+                },
+                "freshness_score": {
+                    "type": "number"
+                },
+                "type_coefficient": {
+                    "type": "number"
+                },
+                "weighted_base_score": {
+                    "type": "number"
                 }
             }
         }
@@ -442,7 +3229,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/api/v1",
 	Schemes:          []string{"http", "https"},
 	Title:            "Search Engine API",
-	Description:      "A search engine API that aggregates content from multiple providers, ranks them by relevance score, and provides search, filtering, sorting, and pagination capabilities.",
+	Description:      "A search engine service that aggregates content from multiple providers, ranks them by relevance score, and provides search, filtering, sorting, and pagination capabilities. Add pretty=true to any request for indented JSON.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
@@ -3,27 +3,54 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"net"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds all application configuration
 type Config struct {
+	Env      AppEnv // Deployment environment; production refuses insecure defaults
 	Server   ServerConfig
 	Database DatabaseConfig
 	Provider ProviderConfig
 	Search   SearchConfig
+	CacheTTL CacheTTLConfig
+	Scoring  ScoringConfig
+	History  HistoryConfig
+	Tags     TagConfig
+	Admin    AdminConfig
+	Auth     AuthConfig
 	Rate     RateLimitConfig
+	Compress CompressionConfig
+	CORS     CORSConfig
 	Redis    RedisConfig
 }
 
+// AppEnv is the environment the application runs in, read from APP_ENV
+type AppEnv string
+
+const (
+	EnvDevelopment AppEnv = "development" // Insecure defaults are logged as warnings (default)
+	EnvProduction  AppEnv = "production"  // Insecure defaults fail validation
+)
+
+// defaultDBPassword is the development database password, refused in production
+const defaultDBPassword = "password"
+
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
-	Port string
-	Host string
+	Port         string
+	Host         string
+	MaxBodyBytes int // Largest request body accepted (default: 2 MiB, 0 disables the limit)
 }
 
 // DatabaseConfig holds database connection settings
@@ -33,25 +60,154 @@ type DatabaseConfig struct {
 	User     string
 	Password string
 	Name     string
+	// Connection pool sizing; 0 means unlimited for MaxOpenConns and ConnMaxLifetimeSeconds
+	MaxOpenConns           int // Most open connections to the database (default: 25)
+	MaxIdleConns           int // Most idle connections kept ready in the pool (default: 5)
+	ConnMaxLifetimeSeconds int // How long a connection may be reused before it is closed (default: 300)
+	// Startup pings the database until it answers, so the API can start before MySQL is ready
+	ConnectMaxRetries int           // Pings retried after the first one fails; 0 fails at once (default: 5)
+	ConnectRetryDelay time.Duration // Wait before the first retry, doubling each time (default: 1s)
 }
 
 // ProviderConfig holds provider API URLs
 type ProviderConfig struct {
-	Provider1URL string
-	Provider2URL string
+	Provider1URL         string
+	Provider2URL         string
+	Provider1Timeouts    ProviderTimeoutConfig
+	Provider2Timeouts    ProviderTimeoutConfig
+	Provider1DateLayouts []string // Go time layouts tried in order for item dates (default: RFC 3339)
+	Provider2DateLayouts []string // Go time layouts tried in order for item dates (default: 2006-01-02)
+	FetchCacheTTLSeconds int      // How long a raw provider response is reused across fetches (default: 5, 0 disables)
+	StaleAfterMinutes    int      // Default minutes since last fetch before a provider counts as stale (default: 1440, 0 disables)
+	SyncWaitSeconds      int      // How long POST /sync waits for the run before answering 202 (default: 120)
+	SyncTimeoutSeconds   int      // How long a sync run by the API may take before it is cancelled (default: 1800)
+	Retry                ProviderRetryConfig
+	Pagination           ProviderPaginationConfig
+	FutureDates          ProviderFutureDateConfig
+	// MappedTimeouts are shared by every provider synced through a field mapping
+	MappedTimeouts ProviderTimeoutConfig
+}
+
+// ProviderRetryConfig controls retries of failed provider fetches
+// Network errors, 429 and 5xx responses are retried with exponential backoff
+type ProviderRetryConfig struct {
+	MaxAttempts int // Total attempts per fetch including the first (default: 3, 1 disables retries)
+	BaseDelayMs int // Wait before the first retry, doubling each time (default: 500)
+}
+
+// ProviderPaginationConfig controls how paginated provider feeds are followed
+// A feed whose total exceeds per_page * page is fetched again with the next page number
+type ProviderPaginationConfig struct {
+	PageParam string // Query parameter carrying the page number (default: page)
+	MaxPages  int    // Most pages fetched per provider and sync (default: 50)
+}
+
+// FutureDateAction decides what a sync does with an item published too far in the future
+type FutureDateAction string
+
+const (
+	FutureDateClamp  FutureDateAction = "clamp"  // Store the item as published at sync time (default)
+	FutureDateReject FutureDateAction = "reject" // Skip the item like one that failed to transform
+)
+
+// ProviderFutureDateConfig controls provider items whose published_at lies in the future
+// Such dates come from timezone bugs or bad data and would keep the top freshness score for good
+type ProviderFutureDateConfig struct {
+	Action         FutureDateAction // clamp or reject (default: clamp)
+	ToleranceHours int              // How far ahead of now published_at may be before Action applies (default: 24)
+}
+
+// ProviderTimeoutConfig holds the HTTP timeouts for a single provider in seconds
+// Connect bounds dial + TLS handshake, ResponseHeader the wait for headers,
+// Overall the whole request including body read
+type ProviderTimeoutConfig struct {
+	ConnectSeconds        int
+	ResponseHeaderSeconds int
+	OverallSeconds        int
 }
 
 // SearchConfig holds search-related configuration
 type SearchConfig struct {
 	MinFullTextLength         int
 	CacheTTLSeconds           int
-	QueryTimeoutSeconds       int // Timeout for search queries (default: 15)
-	SimpleQueryTimeoutSeconds int // Timeout for simple queries like GetByID (default: 5)
+	CacheMaxEntries           int  // Entry limit of the in-memory cache; least recently used entries are evicted past it (default: 10000)
+	QueryTimeoutSeconds       int  // Timeout for search queries (default: 15)
+	MaxQueryTimeoutSeconds    int  // Upper bound for per-request timeout_ms overrides (default: 60)
+	SimpleQueryTimeoutSeconds int  // Timeout for simple queries like GetByID (default: 5)
+	MaxResultWindow           int  // Maximum page * per_page a single request may reach (default: 10000)
+	MaxPerPage                int  // Largest per_page a search may ask for; larger values are capped (default: 100)
+	PrefixMatch               bool // Default for the prefix search option; FULLTEXT terms prefix-match (default: true)
+	EmptyResultHints          bool // Attach a hint explaining empty result sets (default: true)
+	// highlight=true wraps query matches in result titles with these delimiters
+	HighlightPreTag  string // Inserted before each match (default: <mark>)
+	HighlightPostTag string // Inserted after each match (default: </mark>)
+	// sort_by=relevance ranks keyword searches by match * text weight + score * score weight
+	RelevanceTextWeight  float64 // Weight of the FULLTEXT match score (default: 10)
+	RelevanceScoreWeight float64 // Weight of the stored content score (default: 1)
+}
+
+// CacheTTLConfig holds per-feature cache TTLs in seconds
+// Each one defaults to SearchConfig.CacheTTLSeconds when not set explicitly,
+// so freshness vs load can be tuned per endpoint
+type CacheTTLConfig struct {
+	SearchSeconds   int
+	StatsSeconds    int
+	SuggestSeconds  int
+	TrendingSeconds int
+}
+
+// ScoringConfig holds tunable content scoring settings
+type ScoringConfig struct {
+	DisableFreshness bool // Treat the freshness component as 0 so scores stay stable over time
+	Degraded         bool // Start with score ranking disabled; search orders by published_at (toggle via admin API)
+
+	// Formula coefficients; the zero value means the stock formula
+	Weights ScoringWeights
+
+	// Recalculation retry budget
+	UpdateRetries     int // Extra attempts for a failed score update before giving up on that row (default: 2)
+	MaxUpdateFailures int // Rows allowed to fail before a recalculation reports an error (default: 0)
+}
+
+// HistoryConfig holds content history settings
+type HistoryConfig struct {
+	MaxPerContent int // Snapshots retained per content item (default: 50, 0 disables history)
+}
+
+// TagConfig holds limits applied to provider tags before they are stored
+type TagConfig struct {
+	MaxLength     int // Longest tag kept, in characters (default: 100, the column size)
+	MaxPerContent int // Most tags kept per content item (default: 50, 0 means no limit)
+}
+
+// AdminConfig holds settings for operator-only endpoints
+type AdminConfig struct {
+	APIKey string // Shared key required in X-Admin-Key; admin endpoints are disabled when empty
+}
+
+// AuthConfig holds API key authentication settings
+type AuthConfig struct {
+	Enabled bool     // Require an API key on every endpoint except health probes (default: false)
+	APIKeys []string // Accepted keys; with auth enabled and no keys every request is rejected
 }
 
 // RateLimitConfig holds global rate limiting configuration
 type RateLimitConfig struct {
-	RequestsPerMinute int
+	RequestsPerMinute  int
+	IdleTimeoutSeconds int // In-memory limiter: drop an IP's bucket after this long without requests (default: 600)
+}
+
+// CompressionConfig holds response compression settings
+type CompressionConfig struct {
+	Enabled      bool // Gzip responses for clients that accept it (default: false)
+	MinSizeBytes int  // Smaller responses are sent uncompressed (default: 1024)
+}
+
+// CORSConfig holds cross-origin settings for browser clients
+type CORSConfig struct {
+	// AllowedOrigins may call the API with credentials; "*" admits any other origin
+	// without them (default: http://localhost:3000)
+	AllowedOrigins []string
 }
 
 // RedisConfig holds Redis cache configuration
@@ -69,30 +225,108 @@ func Load() *Config {
 	// This allows running with environment variables set directly
 	_ = godotenv.Load()
 
+	// Global cache TTL, used as the default for every per-feature TTL
+	cacheTTLSeconds := getEnvInt("SEARCH_CACHE_TTL_SECONDS", 60)
+	fetchTimeoutSeconds := getEnvInt("PROVIDER_FETCH_TIMEOUT_SECONDS", 30)
+
 	return &Config{
+		Env: getEnvAppEnv("APP_ENV"),
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:         getEnv("SERVER_PORT", "8080"),
+			Host:         getEnv("SERVER_HOST", "0.0.0.0"),
+			MaxBodyBytes: getEnvInt("SERVER_MAX_BODY_BYTES", 2<<20),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "3306"),
 			User:     getEnv("DB_USER", "root"),
-			Password: getEnv("DB_PASSWORD", "password"),
+			Password: getEnv("DB_PASSWORD", defaultDBPassword),
 			Name:     getEnv("DB_NAME", "search_engine"),
+
+			MaxOpenConns:           getEnvInt("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:           getEnvInt("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetimeSeconds: getEnvInt("DB_CONN_MAX_LIFETIME_SECONDS", 300),
+			ConnectMaxRetries:      getEnvInt("DB_CONNECT_MAX_RETRIES", 5),
+			ConnectRetryDelay:      getEnvDuration("DB_CONNECT_RETRY_DELAY", time.Second),
 		},
 		Provider: ProviderConfig{
-			Provider1URL: getEnv("PROVIDER1_URL", "https://raw.githubusercontent.com/WEG-Technology/mock/refs/heads/main/v2/provider1"),
-			Provider2URL: getEnv("PROVIDER2_URL", "https://raw.githubusercontent.com/WEG-Technology/mock/refs/heads/main/v2/provider2"),
+			Provider1URL:         getEnv("PROVIDER1_URL", "https://raw.githubusercontent.com/WEG-Technology/mock/refs/heads/main/v2/provider1"),
+			Provider2URL:         getEnv("PROVIDER2_URL", "https://raw.githubusercontent.com/WEG-Technology/mock/refs/heads/main/v2/provider2"),
+			Provider1Timeouts:    loadProviderTimeouts("PROVIDER1", fetchTimeoutSeconds),
+			Provider2Timeouts:    loadProviderTimeouts("PROVIDER2", fetchTimeoutSeconds),
+			MappedTimeouts:       loadProviderTimeouts("PROVIDER_MAPPED", fetchTimeoutSeconds),
+			Provider1DateLayouts: getEnvList("PROVIDER1_DATE_LAYOUTS", "|"),
+			Provider2DateLayouts: getEnvList("PROVIDER2_DATE_LAYOUTS", "|"),
+			FetchCacheTTLSeconds: getEnvInt("PROVIDER_FETCH_CACHE_TTL_SECONDS", 5),
+			StaleAfterMinutes:    getEnvInt("PROVIDER_STALE_AFTER_MINUTES", 1440),
+			SyncWaitSeconds:      getEnvInt("PROVIDER_SYNC_WAIT_SECONDS", 120),
+			SyncTimeoutSeconds:   getEnvInt("PROVIDER_SYNC_TIMEOUT_SECONDS", 1800),
+			Retry: ProviderRetryConfig{
+				MaxAttempts: getEnvInt("PROVIDER_FETCH_MAX_ATTEMPTS", 3),
+				BaseDelayMs: getEnvInt("PROVIDER_FETCH_RETRY_BASE_DELAY_MS", 500),
+			},
+			Pagination: ProviderPaginationConfig{
+				PageParam: getEnv("PROVIDER_PAGE_PARAM", "page"),
+				MaxPages:  getEnvInt("PROVIDER_MAX_PAGES", 50),
+			},
+			FutureDates: ProviderFutureDateConfig{
+				Action:         getEnvFutureDateAction("PROVIDER_FUTURE_DATE_ACTION"),
+				ToleranceHours: getEnvInt("PROVIDER_FUTURE_DATE_TOLERANCE_HOURS", 24),
+			},
 		},
 		Search: SearchConfig{
 			MinFullTextLength:         getEnvInt("SEARCH_MIN_FULLTEXT_LENGTH", 3),
-			CacheTTLSeconds:           getEnvInt("SEARCH_CACHE_TTL_SECONDS", 60),
-			QueryTimeoutSeconds:       getEnvInt("SEARCH_QUERY_TIMEOUT_SECONDS", 30),        // Increased to 30s for large datasets
+			CacheTTLSeconds:           cacheTTLSeconds,
+			CacheMaxEntries:           getEnvInt("SEARCH_CACHE_MAX_ENTRIES", 10000),
+			QueryTimeoutSeconds:       getEnvInt("SEARCH_QUERY_TIMEOUT_SECONDS", 30), // Increased to 30s for large datasets
+			MaxQueryTimeoutSeconds:    getEnvInt("SEARCH_MAX_QUERY_TIMEOUT_SECONDS", 60),
 			SimpleQueryTimeoutSeconds: getEnvInt("SEARCH_SIMPLE_QUERY_TIMEOUT_SECONDS", 10), // Increased to 10s
+			MaxResultWindow:           getEnvInt("SEARCH_MAX_RESULT_WINDOW", 10000),
+			MaxPerPage:                getEnvInt("SEARCH_MAX_PER_PAGE", 100),
+			PrefixMatch:               getEnvBool("SEARCH_PREFIX_MATCH", true),
+			EmptyResultHints:          getEnvBool("SEARCH_EMPTY_RESULT_HINTS", true),
+			HighlightPreTag:           getEnv("SEARCH_HIGHLIGHT_PRE_TAG", "<mark>"),
+			HighlightPostTag:          getEnv("SEARCH_HIGHLIGHT_POST_TAG", "</mark>"),
+			RelevanceTextWeight:       getEnvFloat("SEARCH_RELEVANCE_TEXT_WEIGHT", 10),
+			RelevanceScoreWeight:      getEnvFloat("SEARCH_RELEVANCE_SCORE_WEIGHT", 1),
+		},
+		CacheTTL: CacheTTLConfig{
+			SearchSeconds:   getEnvInt("CACHE_TTL_SEARCH_SECONDS", cacheTTLSeconds),
+			StatsSeconds:    getEnvInt("CACHE_TTL_STATS_SECONDS", cacheTTLSeconds),
+			SuggestSeconds:  getEnvInt("CACHE_TTL_SUGGEST_SECONDS", cacheTTLSeconds),
+			TrendingSeconds: getEnvInt("CACHE_TTL_TRENDING_SECONDS", cacheTTLSeconds),
+		},
+		Scoring: ScoringConfig{
+			DisableFreshness:  getEnvBool("SCORING_DISABLE_FRESHNESS", false),
+			Degraded:          getEnvBool("SCORING_DEGRADED", false),
+			UpdateRetries:     getEnvInt("SCORING_UPDATE_RETRIES", 2),
+			MaxUpdateFailures: getEnvInt("SCORING_MAX_UPDATE_FAILURES", 0),
+			Weights:           loadScoringWeights(),
+		},
+		History: HistoryConfig{
+			MaxPerContent: getEnvInt("CONTENT_HISTORY_MAX_PER_ITEM", 50),
+		},
+		Tags: TagConfig{
+			MaxLength:     getEnvInt("TAG_MAX_LENGTH", 100),
+			MaxPerContent: getEnvInt("TAG_MAX_PER_CONTENT", 50),
+		},
+		Admin: AdminConfig{
+			APIKey: getEnv("ADMIN_API_KEY", ""),
+		},
+		Auth: AuthConfig{
+			Enabled: getEnvBool("AUTH_ENABLED", false),
+			APIKeys: getEnvList("AUTH_API_KEYS", ","),
 		},
 		Rate: RateLimitConfig{
-			RequestsPerMinute: getEnvInt("RATE_LIMIT_REQUESTS_PER_MINUTE", 60),
+			RequestsPerMinute:  getEnvInt("RATE_LIMIT_REQUESTS_PER_MINUTE", 60),
+			IdleTimeoutSeconds: getEnvInt("RATE_LIMIT_IDLE_TIMEOUT_SECONDS", 600),
+		},
+		Compress: CompressionConfig{
+			Enabled:      getEnvBool("COMPRESSION_ENABLED", false),
+			MinSizeBytes: getEnvInt("COMPRESSION_MIN_SIZE_BYTES", 1024),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: getEnvListOrDefault("CORS_ALLOWED_ORIGINS", ",", []string{"http://localhost:3000"}),
 		},
 		Redis: RedisConfig{
 			Enabled:  getEnvBool("REDIS_ENABLED", true),
@@ -103,6 +337,42 @@ func Load() *Config {
 	}
 }
 
+// loadProviderTimeouts reads the HTTP timeouts for the provider with the given env prefix
+// e.g. PROVIDER1_CONNECT_TIMEOUT_SECONDS, PROVIDER1_RESPONSE_HEADER_TIMEOUT_SECONDS, PROVIDER1_TIMEOUT_SECONDS
+// fetchTimeoutSeconds (PROVIDER_FETCH_TIMEOUT_SECONDS) is the default for the
+// response-header and overall timeouts, so one setting tunes every provider
+func loadProviderTimeouts(prefix string, fetchTimeoutSeconds int) ProviderTimeoutConfig {
+	return ProviderTimeoutConfig{
+		ConnectSeconds:        getEnvInt(prefix+"_CONNECT_TIMEOUT_SECONDS", 10),
+		ResponseHeaderSeconds: getEnvInt(prefix+"_RESPONSE_HEADER_TIMEOUT_SECONDS", fetchTimeoutSeconds),
+		OverallSeconds:        getEnvInt(prefix+"_TIMEOUT_SECONDS", fetchTimeoutSeconds),
+	}
+}
+
+// getEnvAppEnv reads the application environment; an unset value means development
+// Unknown values are kept so Validate can reject them rather than guess
+func getEnvAppEnv(key string) AppEnv {
+	value := AppEnv(strings.ToLower(strings.TrimSpace(os.Getenv(key))))
+	if value == "" {
+		return EnvDevelopment
+	}
+	return value
+}
+
+// getEnvFutureDateAction reads the future date action; an unset or unknown value means clamp
+func getEnvFutureDateAction(key string) FutureDateAction {
+	value := FutureDateAction(strings.ToLower(strings.TrimSpace(os.Getenv(key))))
+	switch value {
+	case FutureDateClamp, FutureDateReject:
+		return value
+	case "":
+		return FutureDateClamp
+	default:
+		log.Printf("Warning: invalid %s %q, clamping future dates", key, value)
+		return FutureDateClamp
+	}
+}
+
 // getEnv retrieves an environment variable or returns a default value
 // This provides a safe way to access environment variables with fallbacks
 func getEnv(key, defaultValue string) string {
@@ -126,6 +396,40 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvDuration retrieves an environment variable as a Go duration such as 500ms or 2s
+// Unparseable and negative values fall back to the default
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d
+	}
+	return defaultValue
+}
+
+// getEnvList retrieves an environment variable as a list split on sep
+// Entries are trimmed and empty ones dropped; returns nil when the variable is unset
+// Date layouts contain commas and spaces, hence the configurable separator
+func getEnvList(key, sep string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getEnvListOrDefault is getEnvList with defaultValue for an unset or empty variable
+func getEnvListOrDefault(key, sep string, defaultValue []string) []string {
+	if list := getEnvList(key, sep); len(list) > 0 {
+		return list
+	}
+	return defaultValue
+}
+
 // getEnvBool retrieves an environment variable as bool or returns a default value.
 func getEnvBool(key string, defaultValue bool) bool {
 	value := os.Getenv(key)
@@ -144,15 +448,114 @@ func getEnvBool(key string, defaultValue bool) bool {
 
 // GetDSN returns the MySQL Data Source Name string
 // This formats the database connection string in MySQL format
+// Times are exchanged in UTC (loc=UTC) and the session time_zone is UTC, so
+// TIMESTAMP values and CURRENT_TIMESTAMP don't depend on the server's timezone
 func (c *Config) GetDSN() string {
-	return c.Database.User + ":" + c.Database.Password + "@tcp(" + c.Database.Host + ":" + c.Database.Port + ")/" + c.Database.Name + "?charset=utf8mb4&parseTime=True&loc=Local"
+	return c.Database.User + ":" + c.Database.Password + "@tcp(" + c.Database.Host + ":" + c.Database.Port + ")/" + c.Database.Name + "?charset=utf8mb4&parseTime=True&loc=UTC&time_zone=%27%2B00%3A00%27"
 }
 
-// Validate checks if required configuration values are present
-// This helps catch configuration errors early
+// ValidationError lists every problem Validate found, so they can all be fixed in one go
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks that required configuration values are present and well-formed
+// This helps catch configuration errors at startup instead of at the first failing request
+// Returns a *ValidationError listing all problems, or nil
 func (c *Config) Validate() error {
-	// For now, we'll keep it simple and just log
-	// In production, you might want to return errors for missing critical values
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(isPort(c.Server.Port), "SERVER_PORT must be a port number, got %q", c.Server.Port)
+
+	check(c.Database.Host != "", "DB_HOST must be set")
+	check(isPort(c.Database.Port), "DB_PORT must be a port number, got %q", c.Database.Port)
+	check(c.Database.User != "", "DB_USER must be set")
+	check(c.Database.Name != "", "DB_NAME must be set")
+
+	check(isHTTPURL(c.Provider.Provider1URL), "PROVIDER1_URL must be an http(s) URL, got %q", c.Provider.Provider1URL)
+	check(isHTTPURL(c.Provider.Provider2URL), "PROVIDER2_URL must be an http(s) URL, got %q", c.Provider.Provider2URL)
+	for _, p := range []struct {
+		prefix   string
+		timeouts ProviderTimeoutConfig
+	}{
+		{"PROVIDER1", c.Provider.Provider1Timeouts},
+		{"PROVIDER2", c.Provider.Provider2Timeouts},
+		{"PROVIDER_MAPPED", c.Provider.MappedTimeouts},
+	} {
+		prefix, timeouts := p.prefix, p.timeouts
+		check(timeouts.ConnectSeconds > 0, "%s_CONNECT_TIMEOUT_SECONDS must be positive, got %d", prefix, timeouts.ConnectSeconds)
+		check(timeouts.ResponseHeaderSeconds > 0, "%s_RESPONSE_HEADER_TIMEOUT_SECONDS must be positive, got %d", prefix, timeouts.ResponseHeaderSeconds)
+		check(timeouts.OverallSeconds > 0, "%s_TIMEOUT_SECONDS must be positive, got %d", prefix, timeouts.OverallSeconds)
+	}
+
+	check(c.Provider.SyncTimeoutSeconds > 0, "PROVIDER_SYNC_TIMEOUT_SECONDS must be positive, got %d", c.Provider.SyncTimeoutSeconds)
+	check(c.Scoring.UpdateRetries >= 0, "SCORING_UPDATE_RETRIES must not be negative, got %d", c.Scoring.UpdateRetries)
+	check(c.Search.QueryTimeoutSeconds > 0, "SEARCH_QUERY_TIMEOUT_SECONDS must be positive, got %d", c.Search.QueryTimeoutSeconds)
+	check(c.Search.MaxQueryTimeoutSeconds > 0, "SEARCH_MAX_QUERY_TIMEOUT_SECONDS must be positive, got %d", c.Search.MaxQueryTimeoutSeconds)
+	check(c.Search.SimpleQueryTimeoutSeconds > 0, "SEARCH_SIMPLE_QUERY_TIMEOUT_SECONDS must be positive, got %d", c.Search.SimpleQueryTimeoutSeconds)
+
+	if c.Redis.Enabled {
+		check(isHostPort(c.Redis.Addr), "REDIS_ADDR must be host:port when Redis is enabled, got %q", c.Redis.Addr)
+	}
+
+	// Insecure settings are fine on a laptop but refused in production
+	check(c.Env == EnvDevelopment || c.Env == EnvProduction, "APP_ENV must be development or production, got %q", c.Env)
+	for _, setting := range c.insecureSettings() {
+		if c.Env == EnvProduction {
+			problems = append(problems, setting)
+		} else {
+			log.Printf("Warning: %s; this is refused with APP_ENV=production", setting)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
 	log.Println("Configuration loaded successfully")
 	return nil
 }
+
+// insecureSettings describes the settings that are unsafe outside development
+func (c *Config) insecureSettings() []string {
+	var settings []string
+	switch c.Database.Password {
+	case "":
+		settings = append(settings, "DB_PASSWORD is empty")
+	case defaultDBPassword:
+		settings = append(settings, "DB_PASSWORD is the default password")
+	}
+	if slices.Contains(c.CORS.AllowedOrigins, "*") {
+		settings = append(settings, "CORS_ALLOWED_ORIGINS allows any origin (*)")
+	}
+	if !c.Auth.Enabled {
+		settings = append(settings, "AUTH_ENABLED is off, so every endpoint is public")
+	}
+	return settings
+}
+
+// isPort reports whether s is a TCP port number
+func isPort(s string) bool {
+	port, err := strconv.Atoi(s)
+	return err == nil && port >= 1 && port <= 65535
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL with a host
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isHostPort reports whether s has the host:port shape of a network address
+func isHostPort(s string) bool {
+	host, port, err := net.SplitHostPort(s)
+	return err == nil && host != "" && isPort(port)
+}
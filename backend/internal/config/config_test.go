@@ -0,0 +1,226 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadDatabasePoolSettings(t *testing.T) {
+	t.Setenv("DB_MAX_OPEN_CONNS", "")
+	t.Setenv("DB_MAX_IDLE_CONNS", "12")
+	t.Setenv("DB_CONN_MAX_LIFETIME_SECONDS", "30")
+
+	db := Load().Database
+	if db.MaxOpenConns != 25 || db.MaxIdleConns != 12 || db.ConnMaxLifetimeSeconds != 30 {
+		t.Errorf("loaded open %d, idle %d, lifetime %ds; want 25, 12, 30s", db.MaxOpenConns, db.MaxIdleConns, db.ConnMaxLifetimeSeconds)
+	}
+}
+
+func TestLoadDatabaseConnectRetry(t *testing.T) {
+	tests := []struct {
+		retries, delay string
+		wantRetries    int
+		wantDelay      time.Duration
+	}{
+		{"", "", 5, time.Second},
+		{"10", "250ms", 10, 250 * time.Millisecond},
+		{"0", "2s", 0, 2 * time.Second},
+		{"many", "soon", 5, time.Second},
+		{"3", "-1s", 3, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("DB_CONNECT_MAX_RETRIES", tt.retries)
+		t.Setenv("DB_CONNECT_RETRY_DELAY", tt.delay)
+		db := Load().Database
+		if db.ConnectMaxRetries != tt.wantRetries || db.ConnectRetryDelay != tt.wantDelay {
+			t.Errorf("retries %q, delay %q: loaded %d, %s; want %d, %s",
+				tt.retries, tt.delay, db.ConnectMaxRetries, db.ConnectRetryDelay, tt.wantRetries, tt.wantDelay)
+		}
+	}
+}
+
+// validConfig returns a configuration Validate accepts
+func validConfig() *Config {
+	timeouts := ProviderTimeoutConfig{ConnectSeconds: 10, ResponseHeaderSeconds: 30, OverallSeconds: 30}
+	return &Config{
+		Env:      EnvDevelopment,
+		Server:   ServerConfig{Port: "8080"},
+		Database: DatabaseConfig{Host: "localhost", Port: "3306", User: "root", Password: "s3cret", Name: "search_engine"},
+		Provider: ProviderConfig{
+			Provider1URL:       "https://example.com/provider1",
+			Provider2URL:       "http://localhost:9000/provider2",
+			Provider1Timeouts:  timeouts,
+			Provider2Timeouts:  timeouts,
+			MappedTimeouts:     timeouts,
+			SyncTimeoutSeconds: 1800,
+		},
+		Search: SearchConfig{QueryTimeoutSeconds: 15, MaxQueryTimeoutSeconds: 60, SimpleQueryTimeoutSeconds: 5},
+		Redis:  RedisConfig{Enabled: true, Addr: "redis:6379"},
+		CORS:   CORSConfig{AllowedOrigins: []string{"https://app.example.com"}},
+		Auth:   AuthConfig{Enabled: true, APIKeys: []string{"key"}},
+	}
+}
+
+func TestValidateAcceptsValidConfig(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	// Redis settings don't matter while Redis is disabled
+	cfg := validConfig()
+	cfg.Redis = RedisConfig{Enabled: false, Addr: ""}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with Redis disabled = %v, want nil", err)
+	}
+}
+
+func TestValidateRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   string
+	}{
+		{"non-numeric server port", func(c *Config) { c.Server.Port = "http" }, "SERVER_PORT"},
+		{"server port out of range", func(c *Config) { c.Server.Port = "70000" }, "SERVER_PORT"},
+		{"missing DB host", func(c *Config) { c.Database.Host = "" }, "DB_HOST"},
+		{"missing DB name", func(c *Config) { c.Database.Name = "" }, "DB_NAME"},
+		{"bad DB port", func(c *Config) { c.Database.Port = "" }, "DB_PORT"},
+		{"provider URL without scheme", func(c *Config) { c.Provider.Provider1URL = "example.com/feed" }, "PROVIDER1_URL"},
+		{"provider URL with another scheme", func(c *Config) { c.Provider.Provider2URL = "ftp://example.com/feed" }, "PROVIDER2_URL"},
+		{"zero provider timeout", func(c *Config) { c.Provider.MappedTimeouts.OverallSeconds = 0 }, "PROVIDER_MAPPED_TIMEOUT_SECONDS"},
+		{"zero sync timeout", func(c *Config) { c.Provider.SyncTimeoutSeconds = 0 }, "PROVIDER_SYNC_TIMEOUT_SECONDS"},
+		{"negative score update retries", func(c *Config) { c.Scoring.UpdateRetries = -1 }, "SCORING_UPDATE_RETRIES"},
+		{"negative search timeout", func(c *Config) { c.Search.QueryTimeoutSeconds = -1 }, "SEARCH_QUERY_TIMEOUT_SECONDS"},
+		{"Redis address without port", func(c *Config) { c.Redis.Addr = "redis" }, "REDIS_ADDR"},
+		{"Redis address without host", func(c *Config) { c.Redis.Addr = ":6379" }, "REDIS_ADDR"},
+		{"unknown environment", func(c *Config) { c.Env = "prod" }, "APP_ENV"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			var validationErr *ValidationError
+			if err := cfg.Validate(); !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() = %v, want a *ValidationError", err)
+			}
+			if len(validationErr.Problems) != 1 || !strings.Contains(validationErr.Problems[0], tt.want) {
+				t.Errorf("problems = %q, want one about %s", validationErr.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateListsAllProblems(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.Port = "abc"
+	cfg.Database.Host = ""
+	cfg.Provider.Provider1URL = ""
+	cfg.Redis.Addr = "nope"
+
+	err := cfg.Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Problems) != 4 {
+		t.Fatalf("Validate() = %v, want 4 problems", err)
+	}
+	for _, name := range []string{"SERVER_PORT", "DB_HOST", "PROVIDER1_URL", "REDIS_ADDR"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not mention %s", err, name)
+		}
+	}
+}
+
+func TestValidateAcceptsLoadedDefaults(t *testing.T) {
+	if err := Load().Validate(); err != nil {
+		t.Errorf("Validate() of the defaults = %v, want nil", err)
+	}
+}
+
+// captureLog collects what the standard logger prints while fn runs
+func captureLog(t *testing.T, fn func()) string {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	fn()
+	return buf.String()
+}
+
+func TestValidateInsecureSettingsByEnvironment(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   string
+	}{
+		{"default DB password", func(c *Config) { c.Database.Password = "password" }, "DB_PASSWORD is the default password"},
+		{"empty DB password", func(c *Config) { c.Database.Password = "" }, "DB_PASSWORD is empty"},
+		{"wildcard CORS", func(c *Config) { c.CORS.AllowedOrigins = []string{"https://app.example.com", "*"} }, "CORS_ALLOWED_ORIGINS"},
+		{"auth disabled", func(c *Config) { c.Auth.Enabled = false }, "AUTH_ENABLED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" in production", func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Env = EnvProduction
+			tt.modify(cfg)
+
+			var validationErr *ValidationError
+			if err := cfg.Validate(); !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() = %v, want a *ValidationError", err)
+			}
+			if len(validationErr.Problems) != 1 || !strings.Contains(validationErr.Problems[0], tt.want) {
+				t.Errorf("problems = %q, want one about %s", validationErr.Problems, tt.want)
+			}
+		})
+
+		t.Run(tt.name+" in development", func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			var err error
+			logged := captureLog(t, func() { err = cfg.Validate() })
+			if err != nil {
+				t.Fatalf("Validate() = %v, want only a warning", err)
+			}
+			if !strings.Contains(logged, "Warning: "+tt.want) {
+				t.Errorf("log %q does not warn about %s", logged, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSecureProductionConfig(t *testing.T) {
+	cfg := validConfig()
+	cfg.Env = EnvProduction
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestLoadAppEnv(t *testing.T) {
+	for value, want := range map[string]AppEnv{"": EnvDevelopment, "production": EnvProduction, " Production ": EnvProduction, "staging": "staging"} {
+		t.Setenv("APP_ENV", value)
+		if got := Load().Env; got != want {
+			t.Errorf("APP_ENV=%q loaded %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestProductionRejectsLoadedDefaults(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("DB_PASSWORD", "")
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("AUTH_API_KEYS", "key")
+
+	var validationErr *ValidationError
+	if err := Load().Validate(); !errors.As(err, &validationErr) || !strings.Contains(err.Error(), "DB_PASSWORD is the default password") {
+		t.Errorf("Validate() = %v, want the default password refused", err)
+	}
+}
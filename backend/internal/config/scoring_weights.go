@@ -0,0 +1,189 @@
+// scoring_weights.go - Tunable scoring formula coefficients
+// Lets ranking be tuned through environment variables without recompiling
+package config
+
+import (
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FreshnessTier awards Points to content published at most MaxAgeDays ago
+type FreshnessTier struct {
+	MaxAgeDays int
+	Points     float64
+}
+
+// FreshnessCurve selects how freshness points fall off with age
+type FreshnessCurve string
+
+const (
+	FreshnessCurveStep  FreshnessCurve = "step"  // Points of the first FreshnessTier the age fits (default)
+	FreshnessCurveDecay FreshnessCurve = "decay" // Exponential decay from FreshnessMaxPoints, halving every FreshnessHalfLifeDays
+)
+
+// ScoringWeights holds the coefficients of the scoring formula
+// The zero value stands for DefaultScoringWeights (see OrDefault), so a
+// ScoringConfig built by hand keeps the stock formula
+type ScoringWeights struct {
+	// Base score: views / ViewDivisor + likes / LikeDivisor for videos,
+	// reading_time * ReadingTimeWeight + reactions / ReactionDivisor for articles
+	// A divisor of 0 or less drops its term
+	ViewDivisor       float64
+	LikeDivisor       float64
+	ReadingTimeWeight float64
+	ReactionDivisor   float64
+
+	// UseLogScaling makes views contribute log10(views+1) instead of views / ViewDivisor,
+	// so a viral video can't drown out everything else; ViewDivisor is then unused
+	// The type coefficient still multiplies the whole base score, logged views included
+	UseLogScaling bool
+
+	// Content type coefficients applied to the base score
+	VideoCoefficient   float64
+	ArticleCoefficient float64
+
+	// Engagement score: (likes / views) * VideoEngagementMultiplier for videos,
+	// (reactions / reading_time) * ArticleEngagementMultiplier for articles
+	VideoEngagementMultiplier   float64
+	ArticleEngagementMultiplier float64
+
+	// Freshness points by age, sorted by MaxAgeDays; the first tier the age fits wins
+	FreshnessTiers []FreshnessTier
+
+	// FreshnessCurve picks the tiers (step, the default) or a continuous decay:
+	// FreshnessMaxPoints * exp(-ageDays * ln 2 / FreshnessHalfLifeDays)
+	// Like UseLogScaling it is a mode rather than a weight; a half-life of 0 or less drops freshness
+	FreshnessCurve        FreshnessCurve
+	FreshnessMaxPoints    float64
+	FreshnessHalfLifeDays float64
+}
+
+// DefaultScoringWeights returns the stock scoring formula
+func DefaultScoringWeights() ScoringWeights {
+	return ScoringWeights{
+		ViewDivisor:                 1000,
+		LikeDivisor:                 100,
+		ReadingTimeWeight:           1,
+		ReactionDivisor:             50,
+		VideoCoefficient:            1.5,
+		ArticleCoefficient:          1.0,
+		VideoEngagementMultiplier:   10,
+		ArticleEngagementMultiplier: 5,
+		FreshnessTiers:              DefaultFreshnessTiers(),
+		FreshnessMaxPoints:          5,
+		FreshnessHalfLifeDays:       14,
+	}
+}
+
+// DefaultFreshnessTiers returns the stock freshness tiers: +5 up to a week, +3 up to a month, +1 up to three months
+func DefaultFreshnessTiers() []FreshnessTier {
+	return []FreshnessTier{{MaxAgeDays: 7, Points: 5}, {MaxAgeDays: 30, Points: 3}, {MaxAgeDays: 90, Points: 1}}
+}
+
+// IsZero reports whether no weight is set
+// UseLogScaling and FreshnessCurve are modes rather than weights and don't count
+func (w ScoringWeights) IsZero() bool {
+	return w.ViewDivisor == 0 && w.LikeDivisor == 0 && w.ReadingTimeWeight == 0 && w.ReactionDivisor == 0 &&
+		w.VideoCoefficient == 0 && w.ArticleCoefficient == 0 &&
+		w.VideoEngagementMultiplier == 0 && w.ArticleEngagementMultiplier == 0 &&
+		len(w.FreshnessTiers) == 0 && w.FreshnessMaxPoints == 0 && w.FreshnessHalfLifeDays == 0
+}
+
+// OrDefault returns w, or DefaultScoringWeights (keeping w's modes) when no weight is set
+func (w ScoringWeights) OrDefault() ScoringWeights {
+	if w.IsZero() {
+		d := DefaultScoringWeights()
+		d.UseLogScaling = w.UseLogScaling
+		d.FreshnessCurve = w.FreshnessCurve
+		return d
+	}
+	return w
+}
+
+// loadScoringWeights reads the scoring weights, defaulting each to the stock formula
+func loadScoringWeights() ScoringWeights {
+	d := DefaultScoringWeights()
+	return ScoringWeights{
+		ViewDivisor:                 getEnvFloat("SCORING_VIEW_DIVISOR", d.ViewDivisor),
+		UseLogScaling:               getEnvBool("SCORING_USE_LOG_SCALING", false),
+		LikeDivisor:                 getEnvFloat("SCORING_LIKE_DIVISOR", d.LikeDivisor),
+		ReadingTimeWeight:           getEnvFloat("SCORING_READING_TIME_WEIGHT", d.ReadingTimeWeight),
+		ReactionDivisor:             getEnvFloat("SCORING_REACTION_DIVISOR", d.ReactionDivisor),
+		VideoCoefficient:            getEnvFloat("SCORING_VIDEO_COEFFICIENT", d.VideoCoefficient),
+		ArticleCoefficient:          getEnvFloat("SCORING_ARTICLE_COEFFICIENT", d.ArticleCoefficient),
+		VideoEngagementMultiplier:   getEnvFloat("SCORING_VIDEO_ENGAGEMENT_MULTIPLIER", d.VideoEngagementMultiplier),
+		ArticleEngagementMultiplier: getEnvFloat("SCORING_ARTICLE_ENGAGEMENT_MULTIPLIER", d.ArticleEngagementMultiplier),
+		FreshnessTiers:              getEnvFreshnessTiers("SCORING_FRESHNESS_TIERS", d.FreshnessTiers),
+		FreshnessCurve:              getEnvFreshnessCurve("SCORING_FRESHNESS_CURVE"),
+		FreshnessMaxPoints:          getEnvFloat("SCORING_FRESHNESS_MAX_POINTS", d.FreshnessMaxPoints),
+		FreshnessHalfLifeDays:       getEnvFloat("SCORING_FRESHNESS_HALF_LIFE_DAYS", d.FreshnessHalfLifeDays),
+	}
+}
+
+// getEnvFreshnessCurve reads the freshness curve; an unset or unknown value means the step curve
+func getEnvFreshnessCurve(key string) FreshnessCurve {
+	value := FreshnessCurve(strings.ToLower(strings.TrimSpace(os.Getenv(key))))
+	switch value {
+	case FreshnessCurveStep, FreshnessCurveDecay:
+		return value
+	case "":
+		return FreshnessCurveStep
+	default:
+		log.Printf("Warning: invalid %s %q, using the step curve", key, value)
+		return FreshnessCurveStep
+	}
+}
+
+// getEnvFloat retrieves an environment variable as float64 or returns a default value
+// If the value cannot be parsed, it falls back to the default
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return defaultValue
+}
+
+// getEnvFreshnessTiers reads freshness tiers written as "days:points" pairs, e.g. "7:5,30:3,90:1"
+// An unset or malformed value falls back to the default
+func getEnvFreshnessTiers(key string, defaultValue []FreshnessTier) []FreshnessTier {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	tiers, ok := ParseFreshnessTiers(value)
+	if !ok {
+		log.Printf("Warning: invalid %s %q, using the default tiers", key, value)
+		return defaultValue
+	}
+	return tiers
+}
+
+// ParseFreshnessTiers parses comma-separated "days:points" pairs into tiers sorted by age
+// Reports false if any pair is malformed or has negative days
+func ParseFreshnessTiers(value string) ([]FreshnessTier, bool) {
+	var tiers []FreshnessTier
+	for _, pair := range strings.Split(value, ",") {
+		days, points, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found {
+			return nil, false
+		}
+		maxAge, err := strconv.Atoi(strings.TrimSpace(days))
+		if err != nil || maxAge < 0 {
+			return nil, false
+		}
+		p, err := strconv.ParseFloat(strings.TrimSpace(points), 64)
+		if err != nil {
+			return nil, false
+		}
+		tiers = append(tiers, FreshnessTier{MaxAgeDays: maxAge, Points: p})
+	}
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MaxAgeDays < tiers[j].MaxAgeDays })
+	return tiers, true
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFreshnessTiers(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   []FreshnessTier
+		wantOK bool
+	}{
+		{"stock tiers", "7:5,30:3,90:1", DefaultFreshnessTiers(), true},
+		{"unsorted with spaces", " 90 : 1 , 7:5.5", []FreshnessTier{{7, 5.5}, {90, 1}}, true},
+		{"missing points", "7:5,30", nil, false},
+		{"negative days", "-1:5", nil, false},
+		{"bad points", "7:lots", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseFreshnessTiers(tt.value)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFreshnessTiers(%q) = %v, %t, want %v, %t", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestScoringWeightsOrDefault(t *testing.T) {
+	if got := (ScoringWeights{}).OrDefault(); !reflect.DeepEqual(got, DefaultScoringWeights()) {
+		t.Errorf("zero weights OrDefault() = %+v, want the defaults", got)
+	}
+
+	// Modes survive the fallback to the default weights
+	decay := (ScoringWeights{FreshnessCurve: FreshnessCurveDecay}).OrDefault()
+	if decay.FreshnessCurve != FreshnessCurveDecay || decay.FreshnessHalfLifeDays != DefaultScoringWeights().FreshnessHalfLifeDays {
+		t.Errorf("decay-only weights OrDefault() = %+v, want the defaults with the decay curve", decay)
+	}
+
+	custom := ScoringWeights{VideoCoefficient: 2}
+	if got := custom.OrDefault(); !reflect.DeepEqual(got, custom) {
+		t.Errorf("OrDefault() = %+v, want the set weights kept", got)
+	}
+}
+
+func TestGetEnvFreshnessCurve(t *testing.T) {
+	tests := []struct {
+		value string
+		want  FreshnessCurve
+	}{
+		{"", FreshnessCurveStep},
+		{"step", FreshnessCurveStep},
+		{" Decay ", FreshnessCurveDecay},
+		{"linear", FreshnessCurveStep},
+	}
+	for _, tt := range tests {
+		t.Setenv("SCORING_FRESHNESS_CURVE", tt.value)
+		if got := getEnvFreshnessCurve("SCORING_FRESHNESS_CURVE"); got != tt.want {
+			t.Errorf("getEnvFreshnessCurve(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
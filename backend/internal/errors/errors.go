@@ -3,8 +3,15 @@
 package errors
 
 import (
+	"context"
+	"database/sql/driver"
+	stderrors "errors"
 	"fmt"
+	"net"
 	"net/http"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
 )
 
 // ErrorCode represents a specific error type
@@ -16,16 +23,28 @@ const (
 	ErrorCodeInvalidInput ErrorCode = "INVALID_INPUT"
 	ErrorCodeInvalidID    ErrorCode = "INVALID_ID"
 
+	// Authentication errors (401)
+	ErrorCodeUnauthorized ErrorCode = "UNAUTHORIZED"
+
 	// Not found errors (404)
 	ErrorCodeNotFound         ErrorCode = "NOT_FOUND"
 	ErrorCodeContentNotFound  ErrorCode = "CONTENT_NOT_FOUND"
 	ErrorCodeProviderNotFound ErrorCode = "PROVIDER_NOT_FOUND"
 
+	// Conflict errors (409)
+	ErrorCodeConflict ErrorCode = "CONFLICT"
+
+	// Payload too large (413)
+	ErrorCodeRequestTooLarge ErrorCode = "REQUEST_TOO_LARGE"
+
 	// Timeout errors (408, 504)
 	ErrorCodeTimeout        ErrorCode = "TIMEOUT"
 	ErrorCodeRequestTimeout ErrorCode = "REQUEST_TIMEOUT"
 	ErrorCodeQueryTimeout   ErrorCode = "QUERY_TIMEOUT"
 
+	// Client went away before the response (499)
+	ErrorCodeClientClosedRequest ErrorCode = "CLIENT_CLOSED_REQUEST"
+
 	// Internal server errors (500)
 	ErrorCodeInternal ErrorCode = "INTERNAL_ERROR"
 	ErrorCodeDatabase ErrorCode = "DATABASE_ERROR"
@@ -36,6 +55,10 @@ const (
 	ErrorCodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
 )
 
+// StatusClientClosedRequest is the non-standard status (from nginx) logged for a
+// request whose client disconnected before the response was ready
+const StatusClientClosedRequest = 499
+
 // AppError represents an application error with structured information
 type AppError struct {
 	Code       ErrorCode `json:"code"`
@@ -43,6 +66,10 @@ type AppError struct {
 	Details    string    `json:"details,omitempty"`
 	StatusCode int       `json:"-"`
 	Err        error     `json:"-"` // Original error for logging
+	Retryable  bool      `json:"-"` // Client may retry the same request later
+
+	// Fields maps request field names to what is wrong with them (validation errors only)
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
 // Error implements the error interface
@@ -100,6 +127,14 @@ func NewValidationErrorWithDetails(message, details string) *AppError {
 	return NewAppErrorWithDetails(ErrorCodeValidation, message, details, http.StatusBadRequest)
 }
 
+// NewFieldValidationError creates a validation error with per-field messages
+// fields maps request parameter names (e.g. "per_page") to a human-readable problem
+func NewFieldValidationError(message string, fields map[string]string) *AppError {
+	appErr := NewAppError(ErrorCodeValidation, message, http.StatusBadRequest)
+	appErr.Fields = fields
+	return appErr
+}
+
 // NewInvalidInputError creates an invalid input error
 func NewInvalidInputError(message string) *AppError {
 	return NewAppError(ErrorCodeInvalidInput, message, http.StatusBadRequest)
@@ -110,6 +145,11 @@ func NewInvalidIDError(resource string) *AppError {
 	return NewAppError(ErrorCodeInvalidID, fmt.Sprintf("Invalid %s ID", resource), http.StatusBadRequest)
 }
 
+// NewUnauthorizedError creates an authentication error
+func NewUnauthorizedError(message string) *AppError {
+	return NewAppError(ErrorCodeUnauthorized, message, http.StatusUnauthorized)
+}
+
 // NewNotFoundError creates a not found error
 func NewNotFoundError(resource string) *AppError {
 	return NewAppError(ErrorCodeNotFound, fmt.Sprintf("%s not found", resource), http.StatusNotFound)
@@ -145,6 +185,36 @@ func NewProviderNotFoundErrorWithName(name string) *AppError {
 	)
 }
 
+// NewConflictError creates a conflict error
+func NewConflictError(message, details string) *AppError {
+	return NewAppErrorWithDetails(ErrorCodeConflict, message, details, http.StatusConflict)
+}
+
+// NewDuplicateProviderURLError creates a conflict error for a provider URL that is already registered
+// existingName may be empty when the owning provider is unknown (e.g. a unique index violation)
+func NewDuplicateProviderURLError(url, existingName string) *AppError {
+	details := fmt.Sprintf("URL %s is already used by another provider", url)
+	if existingName != "" {
+		details = fmt.Sprintf("URL %s is already used by provider: %s", url, existingName)
+	}
+	return NewConflictError("Provider URL already registered", details)
+}
+
+// NewDuplicateProviderNameError creates a conflict error for a provider name that is already taken
+func NewDuplicateProviderNameError(name string) *AppError {
+	return NewConflictError("Provider name already registered", fmt.Sprintf("A provider named %s already exists", name))
+}
+
+// NewRequestTooLargeError creates an error for a request body over maxBytes
+func NewRequestTooLargeError(maxBytes int64) *AppError {
+	return NewAppErrorWithDetails(
+		ErrorCodeRequestTooLarge,
+		"Request body too large",
+		fmt.Sprintf("Request bodies are limited to %d bytes", maxBytes),
+		http.StatusRequestEntityTooLarge,
+	)
+}
+
 // NewTimeoutError creates a timeout error
 func NewTimeoutError(message string) *AppError {
 	return NewAppError(ErrorCodeTimeout, message, http.StatusRequestTimeout)
@@ -175,6 +245,37 @@ func NewQueryTimeoutError(operation string) *AppError {
 	)
 }
 
+// NewClientClosedRequestError creates an error for a request the client abandoned
+func NewClientClosedRequestError() *AppError {
+	return NewAppError(ErrorCodeClientClosedRequest, "Client closed request", StatusClientClosedRequest)
+}
+
+// errQueryBudgetExceeded is the cause of a context from WithQueryTimeout that ran out of time
+var errQueryBudgetExceeded = stderrors.New("query timeout exceeded")
+
+// WithQueryTimeout derives the context a query runs under from the request's context
+// Its cause tells FromContext whether the query's own budget ran out or the request ended
+func WithQueryTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeoutCause(parent, timeout, errQueryBudgetExceeded)
+}
+
+// FromContext maps a done context to the timeout error of whoever ended it, or nil if ctx isn't done
+// The query's budget from WithQueryTimeout running out is the server's fault (504);
+// a client disconnecting cancels the request (499), and a deadline on the request
+// itself is a request timeout (408)
+func FromContext(ctx context.Context, operation string) *AppError {
+	switch {
+	case ctx.Err() == nil:
+		return nil
+	case stderrors.Is(context.Cause(ctx), errQueryBudgetExceeded):
+		return NewQueryTimeoutError(operation)
+	case stderrors.Is(ctx.Err(), context.Canceled):
+		return NewClientClosedRequestError()
+	default:
+		return NewRequestTimeoutError()
+	}
+}
+
 // NewInternalError creates an internal server error
 func NewInternalError(message string) *AppError {
 	return NewAppError(ErrorCodeInternal, message, http.StatusInternalServerError)
@@ -186,7 +287,11 @@ func NewInternalErrorWithError(message string, err error) *AppError {
 }
 
 // NewDatabaseError creates a database error
+// Connection-level failures become a retryable service unavailable error instead of a 500
 func NewDatabaseError(operation string, err error) *AppError {
+	if IsConnectionError(err) {
+		return NewDatabaseUnavailableError(operation, err)
+	}
 	return NewAppErrorWithError(
 		ErrorCodeDatabase,
 		fmt.Sprintf("Database operation failed: %s", operation),
@@ -210,6 +315,32 @@ func NewServiceUnavailableError(message string) *AppError {
 	return NewAppError(ErrorCodeServiceUnavailable, message, http.StatusServiceUnavailable)
 }
 
+// NewDatabaseUnavailableError creates a retryable error for an unreachable database
+func NewDatabaseUnavailableError(operation string, err error) *AppError {
+	appErr := NewAppErrorWithError(
+		ErrorCodeServiceUnavailable,
+		fmt.Sprintf("Database is temporarily unavailable: %s", operation),
+		http.StatusServiceUnavailable,
+		err,
+	)
+	appErr.Retryable = true
+	return appErr
+}
+
+// IsConnectionError reports whether err means the database connection itself failed
+// Covers the driver's bad/invalid connection errors and network errors, not query errors
+func IsConnectionError(err error) bool {
+	// context errors satisfy net.Error too, but they are query timeouts, not outages
+	if err == nil || stderrors.Is(err, context.DeadlineExceeded) || stderrors.Is(err, context.Canceled) {
+		return false
+	}
+	if stderrors.Is(err, driver.ErrBadConn) || stderrors.Is(err, mysql.ErrInvalidConn) {
+		return true
+	}
+	var netErr net.Error
+	return stderrors.As(err, &netErr)
+}
+
 // IsAppError checks if an error is an AppError
 func IsAppError(err error) bool {
 	_, ok := err.(*AppError)
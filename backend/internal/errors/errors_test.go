@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"context"
+	"database/sql/driver"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"bad conn", driver.ErrBadConn, true},
+		{"wrapped invalid conn", fmt.Errorf("query: %w", mysql.ErrInvalidConn), true},
+		{"network error", &net.OpError{Op: "dial", Net: "tcp", Err: stderrors.New("connection refused")}, true},
+		{"query timeout", context.DeadlineExceeded, false},
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), false},
+		{"sql error", &mysql.MySQLError{Number: 1064, Message: "syntax error"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsConnectionError(tt.err); got != tt.want {
+				t.Errorf("IsConnectionError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDatabaseErrorMapsConnectionFailures(t *testing.T) {
+	appErr := NewDatabaseError("search content", driver.ErrBadConn)
+	if appErr.Code != ErrorCodeServiceUnavailable || appErr.StatusCode != http.StatusServiceUnavailable || !appErr.Retryable {
+		t.Errorf("got code=%s status=%d retryable=%v, want retryable 503", appErr.Code, appErr.StatusCode, appErr.Retryable)
+	}
+
+	appErr = NewDatabaseError("search content", stderrors.New("syntax error"))
+	if appErr.Code != ErrorCodeDatabase || appErr.Retryable {
+		t.Errorf("got code=%s retryable=%v, want non-retryable database error", appErr.Code, appErr.Retryable)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	budgetSpent, cancel := WithQueryTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-budgetSpent.Done()
+
+	requestCtx, cancelRequest := context.WithCancel(context.Background())
+	clientGone, cancel := WithQueryTimeout(requestCtx, time.Hour)
+	defer cancel()
+	cancelRequest()
+
+	// The request's deadline comes before the query's own
+	expiringRequest, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	requestExpired, cancel := WithQueryTimeout(expiringRequest, time.Hour)
+	defer cancel()
+	<-requestExpired.Done()
+
+	running, cancel := WithQueryTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantCode   ErrorCode
+		wantStatus int
+	}{
+		{"query budget exceeded", budgetSpent, ErrorCodeQueryTimeout, http.StatusGatewayTimeout},
+		{"client disconnected", clientGone, ErrorCodeClientClosedRequest, StatusClientClosedRequest},
+		{"request deadline exceeded", requestExpired, ErrorCodeRequestTimeout, http.StatusRequestTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := FromContext(tt.ctx, "search")
+			if appErr == nil {
+				t.Fatal("FromContext() = nil, want an error")
+			}
+			if appErr.Code != tt.wantCode || appErr.StatusCode != tt.wantStatus {
+				t.Errorf("FromContext() = %s %d, want %s %d", appErr.Code, appErr.StatusCode, tt.wantCode, tt.wantStatus)
+			}
+		})
+	}
+
+	if appErr := FromContext(running, "search"); appErr != nil {
+		t.Errorf("FromContext() on a live context = %v, want nil", appErr)
+	}
+}
@@ -0,0 +1,205 @@
+// admin_handler.go - HTTP handlers for operator-only endpoints
+// Lets operators inspect and evict cache entries while debugging stale results
+
+package handler
+
+import (
+	"log"
+	"search-engine/backend/internal/errors"
+	"search-engine/backend/internal/middleware"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/internal/service"
+	"search-engine/backend/pkg/cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler handles admin HTTP requests
+type AdminHandler struct {
+	cache         cache.Cache
+	searchService *service.SearchService
+}
+
+// NewAdminHandler creates a new AdminHandler instance
+// cache can be nil when caching is disabled; cache endpoints then report nothing cached
+func NewAdminHandler(cache cache.Cache, searchService *service.SearchService) *AdminHandler {
+	return &AdminHandler{
+		cache:         cache,
+		searchService: searchService,
+	}
+}
+
+// CacheEntryStatus describes a single cache key
+type CacheEntryStatus struct {
+	Key        string `json:"key"`
+	Exists     bool   `json:"exists"`
+	TTLSeconds *int64 `json:"ttl_seconds,omitempty"` // Seconds until expiry; -1 if the key never expires
+}
+
+// GetCacheEntry handles GET /api/v1/admin/cache/:key requests
+// Reports whether a key is cached and how long it has left
+//
+// @Summary     Inspect cache entry
+// @Description Report whether a cache key exists and its remaining TTL. Requires the X-Admin-Key header.
+// @Tags        admin
+// @Produce     json
+// @Param       X-Admin-Key  header   string  true  "Admin key"
+// @Param       key          path     string  true  "Cache key (URL-encoded)"
+// @Success     200  {object} CacheEntryStatus
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Router      /admin/cache/{key} [get]
+func (h *AdminHandler) GetCacheEntry(c *gin.Context) {
+	key := c.Param("key")
+	status := CacheEntryStatus{Key: key}
+
+	if h.cache != nil {
+		if ttl, ok := h.cache.TTL(key); ok {
+			seconds := int64(-1)
+			if ttl >= 0 {
+				seconds = int64(ttl.Seconds())
+			}
+			status.Exists = true
+			status.TTLSeconds = &seconds
+		}
+	}
+
+	middleware.JSONSuccess(c, status)
+}
+
+// DeleteCacheEntry handles DELETE /api/v1/admin/cache/:key requests
+// Evicts a single cache entry
+//
+// @Summary     Evict cache entry
+// @Description Delete a single cache key. Requires the X-Admin-Key header.
+// @Tags        admin
+// @Produce     json
+// @Param       X-Admin-Key  header   string  true  "Admin key"
+// @Param       key          path     string  true  "Cache key (URL-encoded)"
+// @Success     200  {object} map[string]interface{} "Key and whether it was deleted"
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Router      /admin/cache/{key} [delete]
+func (h *AdminHandler) DeleteCacheEntry(c *gin.Context) {
+	key := c.Param("key")
+	deleted := false
+	if h.cache != nil {
+		deleted = h.cache.Delete(key)
+	}
+
+	middleware.JSONSuccess(c, gin.H{
+		"key":     key,
+		"deleted": deleted,
+	})
+}
+
+// GetSearchCacheKey handles GET /api/v1/admin/cache-key/search requests
+// Computes the cache key for a search request so operators can inspect or evict it
+//
+// @Summary     Compute search cache key
+// @Description Return the cache key that /search would use for the same query parameters. Requires the X-Admin-Key header.
+// @Tags        admin
+// @Produce     json
+// @Param       X-Admin-Key  header   string  true   "Admin key"
+// @Param       query        query    string  false  "Same parameters as /search"
+// @Success     200  {object} map[string]string "Cache key"
+// @Failure     400  {object} map[string]string "Invalid request parameters"
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Router      /admin/cache-key/search [get]
+func (h *AdminHandler) GetSearchCacheKey(c *gin.Context) {
+	var req model.SearchRequest
+	maxPerPage := 0
+	if h.searchService != nil {
+		maxPerPage = h.searchService.MaxPerPage()
+	}
+	if appErr := bindSearchRequest(c, &req, maxPerPage); appErr != nil {
+		middleware.HandleAppError(c, appErr)
+		return
+	}
+
+	if h.searchService == nil {
+		middleware.HandleAppError(c, errors.NewServiceUnavailableError("Search service not configured"))
+		return
+	}
+
+	middleware.JSONSuccess(c, gin.H{"key": h.searchService.CacheKey(&req)})
+}
+
+// ResetCacheStats handles DELETE /api/v1/admin/cache-stats requests
+// Zeroes the search cache hit and miss counters, e.g. before measuring a TTL change
+//
+// @Summary     Reset search cache statistics
+// @Description Zero the search cache hit and miss counters and return the values they had. Requires the X-Admin-Key header.
+// @Tags        admin
+// @Produce     json
+// @Param       X-Admin-Key  header   string  true  "Admin key"
+// @Success     200  {object} model.CacheStats
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Router      /admin/cache-stats [delete]
+func (h *AdminHandler) ResetCacheStats(c *gin.Context) {
+	if h.searchService == nil {
+		middleware.HandleAppError(c, errors.NewServiceUnavailableError("Search service not configured"))
+		return
+	}
+
+	middleware.JSONSuccess(c, h.searchService.ResetCacheStats())
+}
+
+// ScoringDegradedStatus reports whether score-based ranking is disabled
+type ScoringDegradedStatus struct {
+	Degraded bool `json:"degraded"`
+}
+
+// GetScoringDegraded handles GET /api/v1/admin/scoring/degraded requests
+//
+// @Summary     Get scoring degraded mode
+// @Description Report whether score-based ranking is disabled. Requires the X-Admin-Key header.
+// @Tags        admin
+// @Produce     json
+// @Param       X-Admin-Key  header   string  true  "Admin key"
+// @Success     200  {object} ScoringDegradedStatus
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Router      /admin/scoring/degraded [get]
+func (h *AdminHandler) GetScoringDegraded(c *gin.Context) {
+	if h.searchService == nil {
+		middleware.HandleAppError(c, errors.NewServiceUnavailableError("Search service not configured"))
+		return
+	}
+
+	middleware.JSONSuccess(c, ScoringDegradedStatus{Degraded: h.searchService.ScoringDegraded()})
+}
+
+// SetScoringDegraded handles PUT /api/v1/admin/scoring/degraded requests
+// A safe fallback during scoring incidents: search orders by published_at DESC
+// instead of score and says so in the response, until switched back off
+//
+// @Summary     Set scoring degraded mode
+// @Description Turn degraded mode on or off. While on, searches that would order by score are ordered by published_at DESC and carry a notice. The setting lasts until changed or the server restarts (SCORING_DEGRADED sets the startup value). Requires the X-Admin-Key header.
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       X-Admin-Key  header   string                 true  "Admin key"
+// @Param       body         body     ScoringDegradedStatus  true  "Desired state"
+// @Success     200  {object} ScoringDegradedStatus
+// @Failure     400  {object} map[string]string "Invalid request body"
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Router      /admin/scoring/degraded [put]
+func (h *AdminHandler) SetScoringDegraded(c *gin.Context) {
+	var body struct {
+		Degraded *bool `json:"degraded"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Degraded == nil {
+		middleware.HandleAppError(c, errors.NewFieldValidationError("Invalid request body", map[string]string{
+			"degraded": "degraded must be true or false",
+		}))
+		return
+	}
+
+	if h.searchService == nil {
+		middleware.HandleAppError(c, errors.NewServiceUnavailableError("Search service not configured"))
+		return
+	}
+
+	h.searchService.SetScoringDegraded(*body.Degraded)
+	log.Printf("Scoring degraded mode set to %t", *body.Degraded)
+
+	middleware.JSONSuccess(c, ScoringDegradedStatus{Degraded: h.searchService.ScoringDegraded()})
+}
@@ -0,0 +1,108 @@
+// autocomplete_handler.go - HTTP handler for search-as-you-type
+// Suggests titles for a partially typed query
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"search-engine/backend/internal/errors"
+	"search-engine/backend/internal/middleware"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/internal/repository"
+	"search-engine/backend/pkg/cache"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// titleCompleter is the part of ContentRepository that Autocomplete needs
+type titleCompleter interface {
+	Autocomplete(ctx context.Context, prefix string, limit int) ([]string, error)
+}
+
+// AutocompleteHandler handles search-as-you-type requests
+type AutocompleteHandler struct {
+	completer    titleCompleter
+	cache        cache.Cache
+	cacheTTL     time.Duration
+	queryTimeout time.Duration
+}
+
+// NewAutocompleteHandler creates a new AutocompleteHandler instance
+// cache can be nil to disable caching; queryTimeout bounds the title query (default: 5s)
+func NewAutocompleteHandler(contentRepo *repository.ContentRepository, cache cache.Cache, cacheTTL, queryTimeout time.Duration) *AutocompleteHandler {
+	if cacheTTL <= 0 {
+		cacheTTL = time.Minute
+	}
+	if queryTimeout <= 0 {
+		queryTimeout = 5 * time.Second
+	}
+	return &AutocompleteHandler{
+		completer:    contentRepo,
+		cache:        cache,
+		cacheTTL:     cacheTTL,
+		queryTimeout: queryTimeout,
+	}
+}
+
+// autocompleteCacheKey is the cache key for a validated request
+// Titles compare case-insensitively, so "Dock" and "dock" share an entry
+func autocompleteCacheKey(req *model.AutocompleteRequest) string {
+	return fmt.Sprintf("autocomplete:%d:%s", req.Limit, strings.ToLower(req.Query))
+}
+
+// Autocomplete handles GET /api/v1/autocomplete requests
+// Returns distinct titles starting with or containing the typed text, best first
+//
+// @Summary     Autocomplete titles
+// @Description Suggest distinct titles for a partially typed query: titles starting with it first, then titles with words starting with each of its terms, higher scores first. Results are cached for CACHE_TTL_SUGGEST_SECONDS.
+// @Tags        search
+// @Accept      json
+// @Produce     json
+// @Param       q      query    string  true   "The text typed so far"
+// @Param       limit  query    int     false  "Most titles returned (default: 8, max: 20)"
+// @Success     200  {array}   string
+// @Failure     400  {object} map[string]string "Missing q or invalid limit"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /autocomplete [get]
+func (h *AutocompleteHandler) Autocomplete(c *gin.Context) {
+	var req model.AutocompleteRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.HandleAppError(c, errors.NewFieldValidationError("Invalid query parameters", map[string]string{
+			"limit": "limit must be an integer",
+		}))
+		return
+	}
+	req.Validate()
+	if req.Query == "" {
+		middleware.HandleAppError(c, errors.NewFieldValidationError("Invalid query parameters", map[string]string{
+			"q": "q is required",
+		}))
+		return
+	}
+
+	// Every keystroke asks again, so even a short-lived entry saves most queries
+	key := autocompleteCacheKey(&req)
+	if h.cache != nil {
+		if titles, ok := cache.GetJSON[[]string](h.cache, key); ok {
+			middleware.JSONSuccess(c, *titles)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.queryTimeout)
+	defer cancel()
+
+	titles, err := h.completer.Autocomplete(ctx, req.Query, req.Limit)
+	if err != nil {
+		middleware.HandleAppError(c, asDatabaseError("autocomplete", err))
+		return
+	}
+
+	if h.cache != nil {
+		_ = cache.SetJSON(h.cache, key, titles, h.cacheTTL)
+	}
+	middleware.JSONSuccess(c, titles)
+}
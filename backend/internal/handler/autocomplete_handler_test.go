@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/pkg/cache"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeTitleCompleter serves titles starting with the prefix, in order, like the LIKE branch
+type fakeTitleCompleter struct {
+	titles    []string
+	calls     int
+	gotPrefix string
+	gotLimit  int
+}
+
+func (f *fakeTitleCompleter) Autocomplete(_ context.Context, prefix string, limit int) ([]string, error) {
+	f.calls++
+	f.gotPrefix, f.gotLimit = prefix, limit
+	matches := []string{}
+	for _, title := range f.titles {
+		if len(matches) < limit && strings.HasPrefix(strings.ToLower(title), strings.ToLower(prefix)) {
+			matches = append(matches, title)
+		}
+	}
+	return matches, nil
+}
+
+func newAutocompleteRouter(completer *fakeTitleCompleter, c cache.Cache) *gin.Engine {
+	h := &AutocompleteHandler{completer: completer, cache: c, cacheTTL: time.Minute, queryTimeout: time.Second}
+	return newTestRouter(http.MethodGet, "/autocomplete", h.Autocomplete)
+}
+
+func getAutocomplete(t *testing.T, router *gin.Engine, query string) []string {
+	t.Helper()
+	w := serve(router, http.MethodGet, "/autocomplete"+query, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /autocomplete%s: status = %d, want %d (body %s)", query, w.Code, http.StatusOK, w.Body)
+	}
+	var body struct {
+		Data []string `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	return body.Data
+}
+
+func TestAutocomplete(t *testing.T) {
+	titles := []string{"Docker Basics", "Docker Compose in Practice", "dockerfile tips", "Go Tutorial"}
+	for i := 0; i < 30; i++ {
+		titles = append(titles, "Docs "+strings.Repeat("x", i+1))
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantPrefix string
+		wantLimit  int
+		want       []string
+	}{
+		{"prefix", "?q=dock", "dock", model.DefaultAutocompleteLimit, []string{"Docker Basics", "Docker Compose in Practice", "dockerfile tips"}},
+		{"trimmed", "?q=%20go%20", "go", model.DefaultAutocompleteLimit, []string{"Go Tutorial"}},
+		{"limit", "?q=doc&limit=2", "doc", 2, []string{"Docker Basics", "Docker Compose in Practice"}},
+		{"limit capped", "?q=doc&limit=1000", "doc", model.MaxAutocompleteLimit, nil},
+		{"no match", "?q=rust", "rust", model.DefaultAutocompleteLimit, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completer := &fakeTitleCompleter{titles: titles}
+			got := getAutocomplete(t, newAutocompleteRouter(completer, nil), tt.query)
+
+			if completer.gotPrefix != tt.wantPrefix || completer.gotLimit != tt.wantLimit {
+				t.Errorf("Autocomplete(%q, %d), want (%q, %d)", completer.gotPrefix, completer.gotLimit, tt.wantPrefix, tt.wantLimit)
+			}
+			if tt.want == nil {
+				if len(got) != tt.wantLimit {
+					t.Errorf("got %d titles, want %d", len(got), tt.wantLimit)
+				}
+			} else if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("titles = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutocompleteCachesResults(t *testing.T) {
+	completer := &fakeTitleCompleter{titles: []string{"Docker Basics"}}
+	router := newAutocompleteRouter(completer, cache.NewInMemoryCache(time.Minute, 0))
+
+	first := getAutocomplete(t, router, "?q=dock")
+	// Titles compare case-insensitively, so a different case is served from the same entry
+	again := getAutocomplete(t, router, "?q=DOCK")
+	if completer.calls != 1 {
+		t.Errorf("Autocomplete calls = %d, want 1", completer.calls)
+	}
+	if !reflect.DeepEqual(first, again) {
+		t.Errorf("cached titles = %q, want %q", again, first)
+	}
+
+	getAutocomplete(t, router, "?q=dock&limit=3")
+	if completer.calls != 2 {
+		t.Errorf("Autocomplete calls after a new limit = %d, want 2", completer.calls)
+	}
+}
+
+func TestAutocompleteInvalidRequest(t *testing.T) {
+	for _, query := range []string{"", "?q=%20%20", "?q=dock&limit=many"} {
+		completer := &fakeTitleCompleter{}
+		w := serve(newAutocompleteRouter(completer, nil), http.MethodGet, "/autocomplete"+query, "")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GET /autocomplete%s: status = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+		if completer.calls != 0 {
+			t.Errorf("GET /autocomplete%s queried the repository", query)
+		}
+	}
+}
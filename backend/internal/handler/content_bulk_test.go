@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"search-engine/backend/internal/config"
+	"search-engine/backend/internal/errors"
+	"search-engine/backend/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeIngester is an in-memory contentIngester keyed by provider and external ID
+// It keeps at most maxTags tags per content, and a failed upsert saves neither the item nor its tags
+type fakeIngester struct {
+	saved      map[string]*model.Content
+	tags       map[int64][]string
+	maxTags    int
+	failUpsert map[string]bool
+	nextID     int64
+}
+
+func ingestKey(providerID int, externalID string) string {
+	return fmt.Sprintf("%d/%s", providerID, externalID)
+}
+
+func (f *fakeIngester) GetByProviderAndExternalID(providerID int, externalID string) (*model.Content, error) {
+	c, ok := f.saved[ingestKey(providerID, externalID)]
+	if !ok {
+		return nil, errors.ErrContentNotFound
+	}
+	return c, nil
+}
+
+func (f *fakeIngester) UpsertWithTags(_ context.Context, c *model.Content, tags []string) (int64, int, error) {
+	if f.failUpsert[c.ExternalID] {
+		return 0, 0, fmt.Errorf("deadlock found")
+	}
+	key := ingestKey(c.ProviderID, c.ExternalID)
+	if existing, ok := f.saved[key]; ok {
+		c.ID = existing.ID
+	} else {
+		f.nextID++
+		c.ID = f.nextID
+	}
+	f.saved[key] = c
+
+	kept := tags[:min(len(tags), f.maxTags)]
+	if len(kept) > 0 {
+		f.tags[c.ID] = kept
+	}
+	return c.ID, len(tags) - len(kept), nil
+}
+
+// fakeProviderGetter knows a fixed set of provider IDs
+type fakeProviderGetter struct {
+	ids   map[int]bool
+	calls int
+}
+
+func (f *fakeProviderGetter) GetByID(id int) (*model.Provider, error) {
+	f.calls++
+	if !f.ids[id] {
+		return nil, errors.ErrProviderNotFound
+	}
+	return &model.Provider{ID: id}, nil
+}
+
+type bulkFixture struct {
+	ingester  *fakeIngester
+	providers *fakeProviderGetter
+}
+
+func newBulkFixture() *bulkFixture {
+	return &bulkFixture{
+		ingester: &fakeIngester{
+			saved:      map[string]*model.Content{ingestKey(1, "existing"): {ID: 40, ProviderID: 1, ExternalID: "existing"}},
+			tags:       map[int64][]string{},
+			maxTags:    2,
+			failUpsert: map[string]bool{},
+			nextID:     100,
+		},
+		providers: &fakeProviderGetter{ids: map[int]bool{1: true}},
+	}
+}
+
+func (f *bulkFixture) router() *gin.Engine {
+	h := &ContentHandler{
+		ingester:           f.ingester,
+		providers:          f.providers,
+		scoringCfg:         config.ScoringConfig{},
+		simpleQueryTimeout: time.Second,
+	}
+	return newTestRouter(http.MethodPost, "/content", h.IngestContent)
+}
+
+func TestIngestContentMixedItems(t *testing.T) {
+	f := newBulkFixture()
+	f.ingester.failUpsert["locked"] = true
+
+	w := serve(f.router(), http.MethodPost, "/content", `[
+		{"provider_id": 1, "external_id": "new-video", "title": "Go Tutorial", "type": "video",
+		 "views": 1000, "likes": 50, "published_at": "2024-03-15T10:00:00Z", "tags": ["go", "tutorial", "extra"], "id": 999, "score": 1e9},
+		{"provider_id": 1, "external_id": "existing", "title": "Updated", "type": "article", "reactions": 5, "published_at": "2024-03-16T10:00:00Z"},
+		{"provider_id": 1, "external_id": "no-title", "type": "video", "published_at": "2024-03-16T10:00:00Z"},
+		{"provider_id": 1, "external_id": "bad-type", "title": "Podcast", "type": "audio", "published_at": "2024-03-16T10:00:00Z"},
+		{"provider_id": 9, "external_id": "unknown-provider", "title": "Orphan", "type": "video", "published_at": "2024-03-16T10:00:00Z"},
+		{"provider_id": 1, "external_id": "locked", "title": "Locked", "type": "video", "published_at": "2024-03-16T10:00:00Z"},
+		null
+	]`)
+
+	if w.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want %d (body %s)", w.Code, http.StatusMultiStatus, w.Body)
+	}
+	var body struct {
+		Data model.BulkContentResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	got := body.Data
+	if got.Created != 1 || got.Updated != 1 || got.Failed != 5 || len(got.Results) != 7 {
+		t.Fatalf("created %d, updated %d, failed %d, %d results; want 1, 1, 5, 7",
+			got.Created, got.Updated, got.Failed, len(got.Results))
+	}
+
+	wantStatus := []model.BulkItemStatus{
+		model.BulkItemCreated, model.BulkItemUpdated,
+		model.BulkItemFailed, model.BulkItemFailed, model.BulkItemFailed, model.BulkItemFailed, model.BulkItemFailed,
+	}
+	for i, r := range got.Results {
+		if r.Index != i || r.Status != wantStatus[i] {
+			t.Errorf("result %d = index %d, status %s; want index %d, status %s", i, r.Index, r.Status, i, wantStatus[i])
+		}
+		if (r.Status == model.BulkItemFailed) != (r.Error != "") {
+			t.Errorf("result %d status %s has error %q", i, r.Status, r.Error)
+		}
+	}
+	if msg := got.Results[4].Error; !strings.Contains(msg, "provider 9 does not exist") {
+		t.Errorf("unknown provider error = %q", msg)
+	}
+
+	created := got.Results[0]
+	if created.ID != 101 || created.Score <= 0 || created.Score >= 1e9 {
+		t.Errorf("created item id %d, score %v; want a new ID and a computed score", created.ID, created.Score)
+	}
+	if created.TagsDropped != 1 || len(f.ingester.tags[101]) != 2 {
+		t.Errorf("tags dropped %d, saved %v; want 1 dropped and 2 saved", created.TagsDropped, f.ingester.tags[101])
+	}
+	if got.Results[1].ID != 40 {
+		t.Errorf("updated item id = %d, want the existing 40", got.Results[1].ID)
+	}
+	if f.providers.calls != 2 {
+		t.Errorf("provider looked up %d times, want once per distinct provider", f.providers.calls)
+	}
+}
+
+func TestIngestContentFailedItemKeepsNoTags(t *testing.T) {
+	f := newBulkFixture()
+	f.ingester.failUpsert["locked"] = true
+
+	w := serve(f.router(), http.MethodPost, "/content", `[{"provider_id": 1, "external_id": "locked", "title": "Locked", "type": "video",
+		"published_at": "2024-03-16T10:00:00Z", "tags": ["go"]}]`)
+	if w.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want %d (body %s)", w.Code, http.StatusMultiStatus, w.Body)
+	}
+	// The item and its tags are one write, so a failed item leaves no tags behind
+	if len(f.ingester.tags) != 0 || strings.Contains(w.Body.String(), "tags_") {
+		t.Errorf("failed item saved tags %v (body %s)", f.ingester.tags, w.Body)
+	}
+}
+
+func TestIngestContentAllSaved(t *testing.T) {
+	f := newBulkFixture()
+	w := serve(f.router(), http.MethodPost, "/content", `[{"provider_id": 1, "external_id": "a1", "title": "Rust", "type": "article", "published_at": "2024-03-16T10:00:00Z"}]`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", w.Code, http.StatusOK, w.Body)
+	}
+	if _, ok := f.ingester.saved[ingestKey(1, "a1")]; !ok {
+		t.Error("item was not saved")
+	}
+}
+
+func TestIngestContentLeavesLastSyncedAtUnset(t *testing.T) {
+	f := newBulkFixture()
+	// A manual import isn't a provider sync, even when the client sends a sync time
+	w := serve(f.router(), http.MethodPost, "/content", `[{"provider_id": 1, "external_id": "a1", "title": "Rust", "type": "article",
+		"published_at": "2024-03-16T10:00:00Z", "last_synced_at": "2024-03-16T10:00:00Z"}]`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", w.Code, http.StatusOK, w.Body)
+	}
+	saved, ok := f.ingester.saved[ingestKey(1, "a1")]
+	if !ok {
+		t.Fatal("item was not saved")
+	}
+	if saved.LastSyncedAt != nil {
+		t.Errorf("last_synced_at = %v, want it left unset", saved.LastSyncedAt)
+	}
+}
+
+func TestIngestContentRejectsBadBatches(t *testing.T) {
+	tooMany := "[" + strings.Repeat(`{"provider_id": 1},`, model.MaxBulkContentItems) + `{"provider_id": 1}]`
+
+	for name, body := range map[string]string{
+		"not an array": `{"provider_id": 1}`,
+		"empty":        `[]`,
+		"too many":     tooMany,
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := newBulkFixture()
+			w := serve(f.router(), http.MethodPost, "/content", body)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, http.StatusBadRequest, w.Body)
+			}
+			if len(f.ingester.saved) != 1 {
+				t.Error("a rejected batch saved items")
+			}
+		})
+	}
+}
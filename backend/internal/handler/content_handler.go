@@ -5,29 +5,86 @@ package handler
 
 import (
 	"context"
+	stderrors "errors"
+	"fmt"
+	"log"
+	"net/http"
+	"search-engine/backend/internal/config"
 	"search-engine/backend/internal/errors"
 	"search-engine/backend/internal/middleware"
+	"search-engine/backend/internal/model"
 	"search-engine/backend/internal/repository"
+	"search-engine/backend/internal/scoring"
+	"search-engine/backend/internal/service"
+	"search-engine/backend/pkg/cache"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// contentReader is the part of ContentRepository that GetContentByID needs
+type contentReader interface {
+	GetByID(ctx context.Context, id int64) (*model.Content, error)
+	GetTagsByContentID(ctx context.Context, contentID int64, order model.TagOrder) ([]string, error)
+}
+
+// contentDeleter is the part of ContentRepository that DeleteContent needs
+type contentDeleter interface {
+	GetByID(ctx context.Context, id int64) (*model.Content, error)
+	Delete(id int64) error
+}
+
+// similarFinder is the part of ContentRepository that GetSimilarContent needs
+type similarFinder interface {
+	GetByID(ctx context.Context, id int64) (*model.Content, error)
+	FindSimilarByTags(ctx context.Context, contentID int64, limit int) ([]*model.SimilarContent, error)
+	LoadTagsBatch(ctx context.Context, contents []*model.Content, order model.TagOrder) error
+}
+
+// contentIngester is the part of ContentRepository that IngestContent needs
+type contentIngester interface {
+	GetByProviderAndExternalID(providerID int, externalID string) (*model.Content, error)
+	UpsertWithTags(ctx context.Context, c *model.Content, tags []string) (int64, int, error)
+}
+
+// providerGetter is the part of ProviderRepository that IngestContent needs
+type providerGetter interface {
+	GetByID(id int) (*model.Provider, error)
+}
+
 // ContentHandler handles content-related HTTP requests
 type ContentHandler struct {
 	contentRepo        *repository.ContentRepository
+	reader             contentReader
+	deleter            contentDeleter
+	similar            similarFinder
+	ingester           contentIngester
+	providers          providerGetter
+	historyRepo        *repository.ContentHistoryRepository
+	scoringCfg         config.ScoringConfig
+	searchCache        cache.Cache
 	simpleQueryTimeout time.Duration
 }
 
 // NewContentHandler creates a new ContentHandler instance
-// simpleQueryTimeout is the timeout for simple queries like GetByID (default: 5s)
-func NewContentHandler(contentRepo *repository.ContentRepository, simpleQueryTimeout time.Duration) *ContentHandler {
+// scoringCfg is used to explain scores; simpleQueryTimeout is the timeout for simple queries like GetByID (default: 5s)
+// searchCache, if not nil, is invalidated whenever items are ingested, patched or deleted
+// Ingested tags are limited by the tag limits set on contentRepo
+func NewContentHandler(contentRepo *repository.ContentRepository, historyRepo *repository.ContentHistoryRepository, providerRepo *repository.ProviderRepository, scoringCfg config.ScoringConfig, searchCache cache.Cache, simpleQueryTimeout time.Duration) *ContentHandler {
 	if simpleQueryTimeout <= 0 {
 		simpleQueryTimeout = 5 * time.Second
 	}
 	return &ContentHandler{
 		contentRepo:        contentRepo,
+		reader:             contentRepo,
+		deleter:            contentRepo,
+		similar:            contentRepo,
+		ingester:           contentRepo,
+		providers:          providerRepo,
+		historyRepo:        historyRepo,
+		scoringCfg:         scoringCfg,
+		searchCache:        searchCache,
 		simpleQueryTimeout: simpleQueryTimeout,
 	}
 }
@@ -40,11 +97,16 @@ func NewContentHandler(contentRepo *repository.ContentRepository, simpleQueryTim
 // @Tags        content
 // @Accept      json
 // @Produce     json
-// @Param       id   path     int  true  "Content ID"
+// @Param       id         path     int     true   "Content ID"
+// @Param       tag_order  query    string  false  "Tag order: alpha or insertion (default: alpha)"
+// @Param       If-None-Match  header  string  false  "ETag of a previous response; 304 without a body while the item is unchanged"
 // @Success     200  {object} model.Content
+// @Header      200  {string}  ETag  "Version tag derived from updated_at, the same as HEAD returns"
+// @Success     304  "Content unchanged since the ETag in If-None-Match"
 // @Failure     400  {object} map[string]string "Invalid content ID"
 // @Failure     404  {object} map[string]string "Content not found"
 // @Failure     500  {object} map[string]string "Internal server error"
+// @Failure     504  {object} map[string]string "Lookup exceeded its time budget"
 // @Router      /content/{id} [get]
 func (h *ContentHandler) GetContentByID(c *gin.Context) {
 	// Parse content ID from URL parameter
@@ -57,51 +119,479 @@ func (h *ContentHandler) GetContentByID(c *gin.Context) {
 	}
 
 	// Apply timeout for simple query
-	ctx, cancel := context.WithTimeout(c.Request.Context(), h.simpleQueryTimeout)
+	ctx, cancel := errors.WithQueryTimeout(c.Request.Context(), h.simpleQueryTimeout)
 	defer cancel()
 
-	// Get content from repository with context for timeout and cancellation
-	content, err := h.contentRepo.GetByID(ctx, id)
+	content, appErr := h.getContent(ctx, id)
+	if appErr != nil {
+		middleware.HandleAppError(c, appErr)
+		return
+	}
+
+	// Same ETag as HEAD: every write, including a sync replacing the tags, bumps updated_at
+	// Checked before loading tags so an unchanged item costs a single query
+	if middleware.NotModified(c, contentETag(id, content.UpdatedAt)) {
+		return
+	}
+
+	// Load tags for the content (use same timeout)
+	tagOrder := model.NormalizeTagOrder(c.Query("tag_order"))
+	tags, err := h.reader.GetTagsByContentID(ctx, id, tagOrder)
 	if err != nil {
-		// Check for timeout
-		if ctx.Err() == context.DeadlineExceeded {
-			appErr := errors.NewRequestTimeoutErrorWithDuration(h.simpleQueryTimeout.String())
-			middleware.HandleAppError(c, appErr)
-			return
+		// Log error but don't fail the request
+		// Tags are optional metadata
+	} else {
+		content.Tags = tags
+	}
+
+	middleware.JSONSuccess(c, content)
+}
+
+// GetContentScore handles GET /api/v1/content/:id/score requests
+// Returns each component of the content's score so rankings can be explained
+//
+// @Summary     Get content score breakdown
+// @Description Get the base, freshness and engagement components of a content item's score. Freshness is 0 with a "disabled" note when SCORING_DISABLE_FRESHNESS is set
+// @Tags        content
+// @Accept      json
+// @Produce     json
+// @Param       id   path     int  true  "Content ID"
+// @Success     200  {object} scoring.ScoreBreakdown
+// @Failure     400  {object} map[string]string "Invalid content ID"
+// @Failure     404  {object} map[string]string "Content not found"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /content/{id}/score [get]
+func (h *ContentHandler) GetContentScore(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.HandleAppError(c, errors.NewInvalidIDError("content"))
+		return
+	}
+
+	ctx, cancel := errors.WithQueryTimeout(c.Request.Context(), h.simpleQueryTimeout)
+	defer cancel()
+
+	content, appErr := h.getContent(ctx, id)
+	if appErr != nil {
+		middleware.HandleAppError(c, appErr)
+		return
+	}
+
+	middleware.JSONSuccess(c, scoring.CalculateScoreBreakdown(content, h.scoringCfg, time.Now()))
+}
+
+// PatchContent handles PATCH /api/v1/content/:id requests
+// Updates only the fields present in the body, then recomputes the score
+//
+// @Summary     Partially update content
+// @Description Update selected fields of a content item; omitted fields keep their values. Patchable: title, published_at, views, likes, duration_seconds (videos), reading_time, reactions, comments (articles). The score is recomputed from the result. Requires the X-Admin-Key header.
+// @Tags        content
+// @Accept      json
+// @Produce     json
+// @Param       X-Admin-Key  header   string              true  "Admin key"
+// @Param       id           path     int                 true  "Content ID"
+// @Param       body         body     model.ContentPatch  true  "Fields to update"
+// @Success     200  {object} model.Content
+// @Failure     400  {object} map[string]string "Invalid content ID or body"
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Failure     404  {object} map[string]string "Content not found"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /content/{id} [patch]
+func (h *ContentHandler) PatchContent(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.HandleAppError(c, errors.NewInvalidIDError("content"))
+		return
+	}
+
+	var patch model.ContentPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		middleware.HandleAppError(c, errors.NewValidationErrorWithDetails("Invalid request body", err.Error()))
+		return
+	}
+	if patch.IsEmpty() {
+		middleware.HandleAppError(c, errors.NewValidationError("Request body sets no patchable field"))
+		return
+	}
+
+	ctx, cancel := errors.WithQueryTimeout(c.Request.Context(), h.simpleQueryTimeout)
+	defer cancel()
+
+	content, appErr := h.getContent(ctx, id)
+	if appErr != nil {
+		middleware.HandleAppError(c, appErr)
+		return
+	}
+
+	// Which metrics are patchable depends on the stored type
+	if fields := patch.Validate(content.Type); len(fields) > 0 {
+		middleware.HandleAppError(c, errors.NewFieldValidationError("Invalid content patch", fields))
+		return
+	}
+
+	patch.ApplyTo(content)
+	scoring.CalculateAndUpdateScore(content, h.scoringCfg)
+
+	if err := h.contentRepo.Patch(ctx, content, &patch); err != nil {
+		middleware.HandleAppError(c, h.contentError(ctx, id, err))
+		return
+	}
+
+	// Reload so timestamps reflect the write
+	updated, appErr := h.getContent(ctx, id)
+	if appErr != nil {
+		middleware.HandleAppError(c, appErr)
+		return
+	}
+	if tags, err := h.contentRepo.GetTagsByContentID(ctx, id, model.TagOrderAlpha); err == nil {
+		updated.Tags = tags
+	}
+
+	service.InvalidateSearchCache(h.searchCache)
+	middleware.JSONSuccess(c, updated)
+}
+
+// IngestContent handles POST /api/v1/content requests
+// Upserts a batch of items directly, for tests and manual imports that don't go through a provider
+// Items are validated and saved one by one, so a bad item is reported without failing the rest
+// Each item is written together with its tags, so a saved item always has them
+//
+// @Summary     Bulk ingest content
+// @Description Create or update up to 500 content items. Each item needs the provider_id of an existing provider; an item whose provider and external_id already exist is updated. Scores are computed on save and tags replaced when given; an item and its tags are saved together or not at all. The response lists a created, updated or error result per item, in request order; the status is 200 when every item was saved and 207 when some failed. Requires the X-Admin-Key header.
+// @Tags        content
+// @Accept      json
+// @Produce     json
+// @Param       X-Admin-Key  header   string           true  "Admin key"
+// @Param       body         body     []model.Content  true  "Content items"
+// @Success     200  {object} model.BulkContentResponse
+// @Success     207  {object} model.BulkContentResponse "Some items failed"
+// @Failure     400  {object} map[string]string "Invalid body, empty batch or too many items"
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Router      /content [post]
+func (h *ContentHandler) IngestContent(c *gin.Context) {
+	var items []model.Content
+	if err := c.ShouldBindJSON(&items); err != nil {
+		middleware.HandleAppError(c, errors.NewValidationErrorWithDetails("Invalid request body", "expected a JSON array of content items: "+err.Error()))
+		return
+	}
+	if len(items) == 0 {
+		middleware.HandleAppError(c, errors.NewValidationError("Request body contains no content items"))
+		return
+	}
+	if len(items) > model.MaxBulkContentItems {
+		middleware.HandleAppError(c, errors.NewValidationErrorWithDetails("Too many content items",
+			fmt.Sprintf("got %d items, at most %d are accepted per request", len(items), model.MaxBulkContentItems)))
+		return
+	}
+
+	response := model.BulkContentResponse{Results: make([]model.BulkContentItemResult, 0, len(items))}
+	knownProviders := make(map[int]error)
+	for i := range items {
+		response.Add(h.ingestItem(c.Request.Context(), i, &items[i], knownProviders))
+	}
+
+	if response.Created+response.Updated > 0 {
+		service.InvalidateSearchCache(h.searchCache)
+	}
+
+	status := http.StatusOK
+	if response.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	middleware.JSONSuccess(c, response, status)
+}
+
+// ingestItem validates, scores and upserts one bulk item with its tags
+// knownProviders caches provider lookups across the batch: nil for a provider that exists
+func (h *ContentHandler) ingestItem(ctx context.Context, index int, item *model.Content, knownProviders map[int]error) model.BulkContentItemResult {
+	result := model.BulkContentItemResult{Index: index, ExternalID: item.ExternalID, Status: model.BulkItemFailed}
+
+	// The ID and score are assigned here, never taken from the client; a manual
+	// ingest isn't a provider sync, so it leaves last_synced_at alone
+	item.ID = 0
+	item.LastSyncedAt = nil
+	if err := model.ValidateContent(item); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	providerErr, checked := knownProviders[item.ProviderID]
+	if !checked {
+		_, providerErr = h.providers.GetByID(item.ProviderID)
+		knownProviders[item.ProviderID] = providerErr
+	}
+	if providerErr != nil {
+		if stderrors.Is(providerErr, errors.ErrProviderNotFound) {
+			result.Error = fmt.Sprintf("provider %d does not exist", item.ProviderID)
+		} else {
+			result.Error = "failed to look up provider: " + providerErr.Error()
 		}
+		return result
+	}
 
-		// Check for not found first (before checking if it's AppError)
-		// This allows us to add details (like ID) to the error
-		if err == repository.ErrContentNotFound || err == errors.ErrContentNotFound {
-			appErr := errors.NewContentNotFoundErrorWithID(id)
-			middleware.HandleAppError(c, appErr)
+	status := model.BulkItemUpdated
+	if _, err := h.ingester.GetByProviderAndExternalID(item.ProviderID, item.ExternalID); err != nil {
+		if !stderrors.Is(err, repository.ErrContentNotFound) && !stderrors.Is(err, errors.ErrContentNotFound) {
+			result.Error = "failed to look up existing content: " + err.Error()
+			return result
+		}
+		status = model.BulkItemCreated
+	}
+
+	scoring.CalculateAndUpdateScore(item, h.scoringCfg)
+	id, dropped, err := h.ingester.UpsertWithTags(ctx, item, item.Tags)
+	if err != nil {
+		result.Error = "failed to save content: " + err.Error()
+		return result
+	}
+	result.Status = status
+	result.ID = id
+	result.Score = item.Score
+	result.TagsDropped = dropped
+	return result
+}
+
+// DeleteContent handles DELETE /api/v1/content/:id requests
+// Removes the item; its tags and history go with it through ON DELETE CASCADE
+// Deleting invalidates the search cache, so cached search responses stop listing the item
+//
+// @Summary     Delete content
+// @Description Delete a content item and its tags. Deleting invalidates the search cache, so cached search results stop listing it. Requires the X-Admin-Key header.
+// @Tags        content
+// @Param       X-Admin-Key  header   string  true  "Admin key"
+// @Param       id           path     int     true  "Content ID"
+// @Success     204  "Content deleted"
+// @Failure     400  {object} map[string]string "Invalid content ID"
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Failure     404  {object} map[string]string "Content not found"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /content/{id} [delete]
+func (h *ContentHandler) DeleteContent(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.HandleAppError(c, errors.NewInvalidIDError("content"))
+		return
+	}
+
+	ctx, cancel := errors.WithQueryTimeout(c.Request.Context(), h.simpleQueryTimeout)
+	defer cancel()
+
+	// Check existence first so an unknown ID is a 404 rather than a silent no-op
+	if _, err := h.deleter.GetByID(ctx, id); err != nil {
+		middleware.HandleAppError(c, h.contentError(ctx, id, err))
+		return
+	}
+
+	if err := h.deleter.Delete(id); err != nil {
+		middleware.HandleAppError(c, errors.NewDatabaseError("delete content", err))
+		return
+	}
+
+	service.InvalidateSearchCache(h.searchCache)
+	c.Status(http.StatusNoContent)
+}
+
+// defaultHistoryLimit and maxHistoryLimit bound the limit param of the history endpoints
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+// GetContentHistory handles GET /api/v1/content/:id/history requests
+// Returns metric and score snapshots recorded when syncs, edits or score updates changed the item, newest first
+//
+// @Summary     Get content history
+// @Description Get snapshots of a content item's metrics and score, newest first. A snapshot is recorded when a sync, an edit or a score recalculation changes a metric or moves the score noticeably; retention per item is capped by CONTENT_HISTORY_MAX_PER_ITEM
+// @Tags        content
+// @Accept      json
+// @Produce     json
+// @Param       id     path     int  true   "Content ID"
+// @Param       limit  query    int  false  "Maximum snapshots to return (default: 20, max: 100)"
+// @Success     200  {object} model.ContentHistoryResponse
+// @Failure     400  {object} map[string]string "Invalid content ID or limit"
+// @Failure     404  {object} map[string]string "Content not found"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /content/{id}/history [get]
+func (h *ContentHandler) GetContentHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.HandleAppError(c, errors.NewInvalidIDError("content"))
+		return
+	}
+
+	limit := defaultHistoryLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxHistoryLimit {
+			middleware.HandleAppError(c, errors.NewFieldValidationError("Invalid query parameters", map[string]string{
+				"limit": fmt.Sprintf("must be an integer between 1 and %d", maxHistoryLimit),
+			}))
 			return
 		}
+	}
 
-		// Check if it's already an AppError
-		if appErr := errors.AsAppError(err); appErr != nil {
-			// If it's a not found error without details, add ID
-			if appErr.Code == errors.ErrorCodeContentNotFound && appErr.Details == "" {
-				appErr = errors.NewContentNotFoundErrorWithID(id)
-			}
-			middleware.HandleAppError(c, appErr)
+	ctx, cancel := errors.WithQueryTimeout(c.Request.Context(), h.simpleQueryTimeout)
+	defer cancel()
+
+	// Distinguish an unknown item from one without history
+	if _, err := h.contentRepo.GetUpdatedAt(ctx, id); err != nil {
+		middleware.HandleAppError(c, h.contentError(ctx, id, err))
+		return
+	}
+
+	history, err := h.historyRepo.GetByContentID(ctx, id, limit)
+	if err != nil {
+		middleware.HandleAppError(c, h.contentError(ctx, id, err))
+		return
+	}
+
+	middleware.JSONSuccess(c, model.ContentHistoryResponse{ContentID: id, History: history})
+}
+
+// GetSimilarContent handles GET /api/v1/content/:id/similar requests
+// Returns the items sharing the most tags with the content, then the highest-scoring
+//
+// @Summary     Get similar content
+// @Description Get other content items sharing tags with a content item, ranked by the number of shared tags, then score. An item without tags has no similar content
+// @Tags        content
+// @Accept      json
+// @Produce     json
+// @Param       id     path     int  true   "Content ID"
+// @Param       limit  query    int  false  "Maximum items to return (default: 10, capped at 50)"
+// @Success     200  {object} model.SimilarContentResponse
+// @Failure     400  {object} map[string]string "Invalid content ID or limit"
+// @Failure     404  {object} map[string]string "Content not found"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /content/{id}/similar [get]
+func (h *ContentHandler) GetSimilarContent(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.HandleAppError(c, errors.NewInvalidIDError("content"))
+		return
+	}
+
+	limit := model.DefaultSimilarLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			middleware.HandleAppError(c, errors.NewFieldValidationError("Invalid query parameters", map[string]string{
+				"limit": "must be a positive integer",
+			}))
 			return
 		}
+		limit = min(limit, model.MaxSimilarLimit)
+	}
 
-		// Wrap unknown errors
-		appErr := errors.NewDatabaseError("get content by id", err)
-		middleware.HandleAppError(c, appErr)
+	ctx, cancel := errors.WithQueryTimeout(c.Request.Context(), h.simpleQueryTimeout)
+	defer cancel()
+
+	if _, err := h.similar.GetByID(ctx, id); err != nil {
+		middleware.HandleAppError(c, h.contentError(ctx, id, err))
 		return
 	}
 
-	// Load tags for the content (use same timeout)
-	tags, err := h.contentRepo.GetTagsByContentID(ctx, id)
+	similar, err := h.similar.FindSimilarByTags(ctx, id, limit)
 	if err != nil {
-		// Log error but don't fail the request
-		// Tags are optional metadata
-	} else {
-		content.Tags = tags
+		middleware.HandleAppError(c, h.contentError(ctx, id, err))
+		return
 	}
 
-	middleware.JSONSuccess(c, content)
+	contents := make([]*model.Content, len(similar))
+	for i, s := range similar {
+		contents[i] = s.Content
+	}
+	if err := h.similar.LoadTagsBatch(ctx, contents, model.TagOrderAlpha); err != nil {
+		// Tags are optional metadata, like in GetContentByID
+		log.Printf("Warning: failed to load tags of content similar to %d: %v", id, err)
+	}
+
+	middleware.JSONSuccess(c, model.SimilarContentResponse{ContentID: id, Results: similar})
+}
+
+// ContentExists handles HEAD /api/v1/content/:id requests
+// Lets clients validate cached content cheaply: 200 with Last-Modified/ETag, or 404, no body
+//
+// @Summary     Check content exists
+// @Description Check whether a content item exists without fetching it. Returns Last-Modified and ETag headers derived from updated_at.
+// @Tags        content
+// @Param       id   path     int  true  "Content ID"
+// @Success     200  "Content exists"
+// @Header      200  {string}  Last-Modified  "When the content was last updated"
+// @Header      200  {string}  ETag           "Version tag derived from updated_at"
+// @Failure     400  "Invalid content ID"
+// @Failure     404  "Content not found"
+// @Router      /content/{id} [head]
+func (h *ContentHandler) ContentExists(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := errors.WithQueryTimeout(c.Request.Context(), h.simpleQueryTimeout)
+	defer cancel()
+
+	updatedAt, err := h.contentRepo.GetUpdatedAt(ctx, id)
+	if err != nil {
+		if err == repository.ErrContentNotFound || err == errors.ErrContentNotFound {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		if appErr := errors.FromContext(ctx, "check content"); appErr != nil {
+			c.Status(appErr.StatusCode)
+			return
+		}
+		if appErr := errors.AsAppError(err); appErr != nil {
+			c.Status(appErr.StatusCode)
+			return
+		}
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("Last-Modified", updatedAt.UTC().Format(http.TimeFormat))
+	c.Header("ETag", contentETag(id, updatedAt))
+	c.Status(http.StatusOK)
+}
+
+// contentETag builds a weak ETag for a content item from its ID and updated_at
+// Any change to the row bumps updated_at, which changes the tag
+func contentETag(id int64, updatedAt time.Time) string {
+	return fmt.Sprintf(`W/"%d-%d"`, id, updatedAt.UnixNano())
+}
+
+// getContent loads a content item by ID and maps failures to AppErrors
+// Timeouts, not-found and database errors are reported the same way for every content endpoint
+func (h *ContentHandler) getContent(ctx context.Context, id int64) (*model.Content, *errors.AppError) {
+	content, err := h.reader.GetByID(ctx, id)
+	if err == nil {
+		return content, nil
+	}
+	return nil, h.contentError(ctx, id, err)
+}
+
+// contentError maps a failed content lookup to an AppError
+func (h *ContentHandler) contentError(ctx context.Context, id int64, err error) *errors.AppError {
+	// Check for a timeout or a client that went away
+	if appErr := errors.FromContext(ctx, "get content"); appErr != nil {
+		return appErr
+	}
+
+	// Check for not found first (before checking if it's AppError)
+	// This allows us to add details (like ID) to the error
+	if err == repository.ErrContentNotFound || err == errors.ErrContentNotFound {
+		return errors.NewContentNotFoundErrorWithID(id)
+	}
+
+	// Check if it's already an AppError
+	if appErr := errors.AsAppError(err); appErr != nil {
+		// If it's a not found error without details, add ID
+		if appErr.Code == errors.ErrorCodeContentNotFound && appErr.Details == "" {
+			appErr = errors.NewContentNotFoundErrorWithID(id)
+		}
+		return appErr
+	}
+
+	// Wrap unknown errors
+	return errors.NewDatabaseError("get content by id", err)
 }
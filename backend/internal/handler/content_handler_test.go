@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"search-engine/backend/internal/errors"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/pkg/cache"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeContentDeleter is an in-memory contentDeleter
+type fakeContentDeleter struct {
+	contents  map[int64]*model.Content
+	deleteErr error
+	deleted   []int64
+}
+
+func (f *fakeContentDeleter) GetByID(_ context.Context, id int64) (*model.Content, error) {
+	c, ok := f.contents[id]
+	if !ok {
+		return nil, errors.ErrContentNotFound
+	}
+	return c, nil
+}
+
+func (f *fakeContentDeleter) Delete(id int64) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	delete(f.contents, id)
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func newDeleteRouter(deleter *fakeContentDeleter, searchCache cache.Cache) *gin.Engine {
+	h := &ContentHandler{deleter: deleter, searchCache: searchCache, simpleQueryTimeout: time.Second}
+	return newTestRouter(http.MethodDelete, "/content/:id", h.DeleteContent)
+}
+
+func TestDeleteContent(t *testing.T) {
+	deleter := &fakeContentDeleter{contents: map[int64]*model.Content{7: {ID: 7, Title: "Go"}}}
+
+	w := serve(newDeleteRouter(deleter, nil), http.MethodDelete, "/content/7", "")
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d (body %s)", w.Code, http.StatusNoContent, w.Body)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty", w.Body)
+	}
+	if len(deleter.deleted) != 1 || deleter.deleted[0] != 7 {
+		t.Errorf("deleted = %v, want [7]", deleter.deleted)
+	}
+}
+
+func TestDeleteContentInvalidatesSearchCache(t *testing.T) {
+	searchCache := cache.NewInMemoryCache(time.Minute, 0)
+	deleter := &fakeContentDeleter{contents: map[int64]*model.Content{7: {ID: 7}}}
+
+	if w := serve(newDeleteRouter(deleter, searchCache), http.MethodDelete, "/content/42", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if got, _ := searchCache.Generation("search"); got != 0 {
+		t.Errorf("generation after a failed delete = %d, want 0", got)
+	}
+
+	if w := serve(newDeleteRouter(deleter, searchCache), http.MethodDelete, "/content/7", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if got, _ := searchCache.Generation("search"); got != 1 {
+		t.Errorf("generation after a delete = %d, want 1", got)
+	}
+}
+
+func TestDeleteContentNotFound(t *testing.T) {
+	deleter := &fakeContentDeleter{contents: map[int64]*model.Content{}}
+
+	w := serve(newDeleteRouter(deleter, nil), http.MethodDelete, "/content/42", "")
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	var body struct {
+		Error struct {
+			Code errors.ErrorCode `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if got := body.Error.Code; got != errors.ErrorCodeContentNotFound {
+		t.Errorf("code = %s, want %s", got, errors.ErrorCodeContentNotFound)
+	}
+	if len(deleter.deleted) != 0 {
+		t.Errorf("deleted = %v, want none", deleter.deleted)
+	}
+}
+
+func TestDeleteContentInvalidID(t *testing.T) {
+	deleter := &fakeContentDeleter{contents: map[int64]*model.Content{}}
+
+	if w := serve(newDeleteRouter(deleter, nil), http.MethodDelete, "/content/abc", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestDeleteContentDatabaseError(t *testing.T) {
+	deleter := &fakeContentDeleter{
+		contents:  map[int64]*model.Content{7: {ID: 7}},
+		deleteErr: fmt.Errorf("connection reset"),
+	}
+
+	if w := serve(newDeleteRouter(deleter, nil), http.MethodDelete, "/content/7", ""); w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"search-engine/backend/internal/errors"
+	"search-engine/backend/internal/model"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeSimilarFinder returns canned FindSimilarByTags results, already ranked
+// The ranking itself is the repository query's job and is tested there
+type fakeSimilarFinder struct {
+	contents map[int64]*model.Content // Tags hold the seeded tags
+	similar  map[int64][]*model.SimilarContent
+	gotLimit int
+}
+
+func (f *fakeSimilarFinder) GetByID(_ context.Context, id int64) (*model.Content, error) {
+	c, ok := f.contents[id]
+	if !ok {
+		return nil, errors.ErrContentNotFound
+	}
+	return c, nil
+}
+
+func (f *fakeSimilarFinder) FindSimilarByTags(_ context.Context, contentID int64, limit int) ([]*model.SimilarContent, error) {
+	f.gotLimit = limit
+	similar := f.similar[contentID]
+	return similar[:min(limit, len(similar))], nil
+}
+
+func (f *fakeSimilarFinder) LoadTagsBatch(_ context.Context, contents []*model.Content, _ model.TagOrder) error {
+	for _, c := range contents {
+		c.Tags = f.contents[c.ID].Tags
+	}
+	return nil
+}
+
+// seededSimilarFinder has a Go/Docker/Kubernetes source item and, for it, four similar items
+// in the order the repository ranks them; item 7 has no tags and nothing similar
+func seededSimilarFinder() *fakeSimilarFinder {
+	contents := map[int64]*model.Content{
+		1: {ID: 1, Score: 5, Tags: []string{"go", "docker", "kubernetes"}},
+		2: {ID: 2, Score: 1, Tags: []string{"go", "docker", "kubernetes", "helm"}},
+		3: {ID: 3, Score: 9, Tags: []string{"go"}},
+		4: {ID: 4, Score: 2, Tags: []string{"docker", "kubernetes"}},
+		5: {ID: 5, Score: 8, Tags: []string{"kubernetes"}},
+		7: {ID: 7, Score: 3},
+	}
+	// Returned without tags, like the repository; LoadTagsBatch adds them
+	similar := func(id int64, shared int) *model.SimilarContent {
+		return &model.SimilarContent{Content: &model.Content{ID: id, Score: contents[id].Score}, SharedTags: shared}
+	}
+	return &fakeSimilarFinder{
+		contents: contents,
+		similar:  map[int64][]*model.SimilarContent{1: {similar(2, 3), similar(4, 2), similar(3, 1), similar(5, 1)}},
+	}
+}
+
+func newSimilarRouter(finder *fakeSimilarFinder) *gin.Engine {
+	h := &ContentHandler{similar: finder, simpleQueryTimeout: time.Second}
+	return newTestRouter(http.MethodGet, "/content/:id/similar", h.GetSimilarContent)
+}
+
+func decodeSimilar(t *testing.T, w *httptest.ResponseRecorder) model.SimilarContentResponse {
+	t.Helper()
+	var body struct {
+		Data model.SimilarContentResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	return body.Data
+}
+
+func TestGetSimilarContentReturnsRankedResults(t *testing.T) {
+	finder := seededSimilarFinder()
+
+	w := serve(newSimilarRouter(finder), http.MethodGet, "/content/1/similar", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", w.Code, http.StatusOK, w.Body)
+	}
+	if finder.gotLimit != model.DefaultSimilarLimit {
+		t.Errorf("limit = %d, want the default %d", finder.gotLimit, model.DefaultSimilarLimit)
+	}
+
+	resp := decodeSimilar(t, w)
+	var ids []int64
+	var shared []int
+	for _, s := range resp.Results {
+		ids = append(ids, s.Content.ID)
+		shared = append(shared, s.SharedTags)
+	}
+	// The repository's order is kept
+	if want := []int64{2, 4, 3, 5}; !slices.Equal(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+	if want := []int{3, 2, 1, 1}; !slices.Equal(shared, want) {
+		t.Errorf("shared_tags = %v, want %v", shared, want)
+	}
+	if resp.ContentID != 1 || len(resp.Results[0].Content.Tags) != 4 {
+		t.Errorf("response = %+v, want content_id 1 and tags loaded", resp)
+	}
+}
+
+func TestGetSimilarContentLimit(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantLimit int
+		wantCount int
+	}{
+		{"?limit=2", 2, 2},
+		{"?limit=500", model.MaxSimilarLimit, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			finder := seededSimilarFinder()
+			w := serve(newSimilarRouter(finder), http.MethodGet, "/content/1/similar"+tt.query, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			if finder.gotLimit != tt.wantLimit {
+				t.Errorf("limit = %d, want %d", finder.gotLimit, tt.wantLimit)
+			}
+			if got := len(decodeSimilar(t, w).Results); got != tt.wantCount {
+				t.Errorf("results = %d, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestGetSimilarContentErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"unknown content", "/content/42/similar", http.StatusNotFound},
+		{"invalid id", "/content/abc/similar", http.StatusBadRequest},
+		{"invalid limit", "/content/1/similar?limit=0", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve(newSimilarRouter(seededSimilarFinder()), http.MethodGet, tt.path, ""); w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSimilarContentWithoutTags(t *testing.T) {
+	w := serve(newSimilarRouter(seededSimilarFinder()), http.MethodGet, "/content/7/similar", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := decodeSimilar(t, w).Results; len(got) != 0 {
+		t.Errorf("results = %+v, want none", got)
+	}
+}
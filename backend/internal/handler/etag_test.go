@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"search-engine/backend/internal/errors"
+	"search-engine/backend/internal/model"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeSearcher returns a fixed response for every search and keeps the last request
+type fakeSearcher struct {
+	response *model.SearchResponse
+	last     *model.SearchRequest
+}
+
+func (f *fakeSearcher) Search(ctx context.Context, req *model.SearchRequest) (*model.SearchResponse, error) {
+	f.last = req
+	return f.response, nil
+}
+
+// fakeContentReader serves one content item and counts tag lookups
+type fakeContentReader struct {
+	content   *model.Content
+	tagLoads  int
+	tagResult []string
+}
+
+func (f *fakeContentReader) GetByID(ctx context.Context, id int64) (*model.Content, error) {
+	if f.content == nil || f.content.ID != id {
+		return nil, errors.ErrContentNotFound
+	}
+	copied := *f.content
+	return &copied, nil
+}
+
+func (f *fakeContentReader) GetTagsByContentID(ctx context.Context, contentID int64, order model.TagOrder) ([]string, error) {
+	f.tagLoads++
+	return f.tagResult, nil
+}
+
+// conditionalGet serves a GET with an optional If-None-Match header
+func conditionalGet(router *gin.Engine, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSearchETag(t *testing.T) {
+	fake := &fakeSearcher{response: &model.SearchResponse{
+		Results: []model.Content{{ID: 1, Title: "Go Tutorial"}},
+		Total:   1,
+	}}
+	h := &SearchHandler{searcher: fake}
+	router := newTestRouter(http.MethodGet, "/search", h.Search)
+
+	first := conditionalGet(router, "/search?query=go", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("first request: status %d, ETag %q; want 200 with an ETag", first.Code, etag)
+	}
+
+	// The trace ID differs per request, but the ETag must not
+	again := conditionalGet(router, "/search?query=go", "")
+	if got := again.Header().Get("ETag"); got != etag {
+		t.Errorf("ETag of an identical response = %q, want %q", got, etag)
+	}
+
+	cached := conditionalGet(router, "/search?query=go", etag)
+	if cached.Code != http.StatusNotModified {
+		t.Fatalf("If-None-Match with the current ETag: status %d, want 304", cached.Code)
+	}
+	if cached.Body.Len() != 0 {
+		t.Errorf("304 body = %q, want empty", cached.Body)
+	}
+	if got := cached.Header().Get("ETag"); got != etag {
+		t.Errorf("304 ETag = %q, want %q", got, etag)
+	}
+
+	fake.response = &model.SearchResponse{
+		Results: []model.Content{{ID: 1, Title: "Go Tutorial"}, {ID: 2, Title: "Go Basics"}},
+		Total:   2,
+	}
+	changed := conditionalGet(router, "/search?query=go", etag)
+	if changed.Code != http.StatusOK {
+		t.Fatalf("stale If-None-Match: status %d, want 200", changed.Code)
+	}
+	if got := changed.Header().Get("ETag"); got == etag || got == "" {
+		t.Errorf("ETag after the results changed = %q, want a new one", got)
+	}
+}
+
+func TestGetContentByIDETag(t *testing.T) {
+	updatedAt := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	reader := &fakeContentReader{
+		content:   &model.Content{ID: 7, Title: "Go Tutorial", UpdatedAt: updatedAt},
+		tagResult: []string{"go"},
+	}
+	h := &ContentHandler{reader: reader, simpleQueryTimeout: time.Second}
+	router := newTestRouter(http.MethodGet, "/content/:id", h.GetContentByID)
+
+	first := conditionalGet(router, "/content/7", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag != contentETag(7, updatedAt) {
+		t.Fatalf("first request: status %d, ETag %q; want 200 with %q", first.Code, etag, contentETag(7, updatedAt))
+	}
+
+	tagLoads := reader.tagLoads
+	cached := conditionalGet(router, "/content/7", etag)
+	if cached.Code != http.StatusNotModified || cached.Body.Len() != 0 {
+		t.Fatalf("If-None-Match with the current ETag: status %d, body %q; want an empty 304", cached.Code, cached.Body)
+	}
+	if reader.tagLoads != tagLoads {
+		t.Error("tags were loaded for a 304 response")
+	}
+
+	reader.content.UpdatedAt = updatedAt.Add(time.Second)
+	changed := conditionalGet(router, "/content/7", etag)
+	if changed.Code != http.StatusOK {
+		t.Fatalf("If-None-Match after an update: status %d, want 200", changed.Code)
+	}
+	if got := changed.Header().Get("ETag"); got != contentETag(7, reader.content.UpdatedAt) {
+		t.Errorf("ETag after an update = %q, want %q", got, contentETag(7, reader.content.UpdatedAt))
+	}
+}
@@ -0,0 +1,51 @@
+// meta_handler.go - HTTP handlers for API metadata endpoints
+// Exposes server-side enums so clients and generated UIs never drift from them
+
+package handler
+
+import (
+	"search-engine/backend/internal/middleware"
+	"search-engine/backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetaHandler handles API metadata requests
+type MetaHandler struct{}
+
+// NewMetaHandler creates a new MetaHandler instance
+func NewMetaHandler() *MetaHandler {
+	return &MetaHandler{}
+}
+
+// EnumsResponse lists the values accepted by enum-like fields and parameters
+type EnumsResponse struct {
+	ContentTypes    []model.ContentType    `json:"content_types"`
+	ProviderFormats []model.ProviderFormat `json:"provider_formats"`
+	SortFields      []string               `json:"sort_fields"`
+	SortOrders      []string               `json:"sort_orders"`
+	TagOrders       []model.TagOrder       `json:"tag_orders"`
+	MatchModes      []model.MatchMode      `json:"match_modes"`
+	SearchPeriods   []model.SearchPeriod   `json:"search_periods"`
+}
+
+// GetEnums handles GET /api/v1/enums requests
+// Values come straight from the model constants and validation whitelists
+//
+// @Summary     Get supported enum values
+// @Description Get the valid content types, provider formats, sort fields, sort orders, tag orders, match modes and search periods
+// @Tags        meta
+// @Produce     json
+// @Success     200  {object} EnumsResponse
+// @Router      /enums [get]
+func (h *MetaHandler) GetEnums(c *gin.Context) {
+	middleware.JSONSuccess(c, EnumsResponse{
+		ContentTypes:    model.ContentTypes,
+		ProviderFormats: model.ProviderFormats,
+		SortFields:      model.SearchSortFields,
+		SortOrders:      model.SearchSortOrders,
+		TagOrders:       model.TagOrders,
+		MatchModes:      model.MatchModes,
+		SearchPeriods:   model.SearchPeriods,
+	})
+}
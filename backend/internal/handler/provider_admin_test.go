@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"search-engine/backend/internal/errors"
+	"search-engine/backend/internal/model"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeProviderStore keeps providers in memory with unique names, like the providers table
+type fakeProviderStore struct {
+	providers map[int]*model.Provider
+	nextID    int
+}
+
+func (f *fakeProviderStore) GetByID(id int) (*model.Provider, error) {
+	p, ok := f.providers[id]
+	if !ok {
+		return nil, errors.ErrProviderNotFound
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (f *fakeProviderStore) Create(p *model.Provider) error {
+	for _, existing := range f.providers {
+		if existing.Name == p.Name {
+			return errors.NewDuplicateProviderNameError(p.Name)
+		}
+	}
+	f.nextID++
+	p.ID = f.nextID
+	p.Enabled = true
+	stored := *p
+	f.providers[p.ID] = &stored
+	return nil
+}
+
+func (f *fakeProviderStore) Update(p *model.Provider) error {
+	stored := *p
+	f.providers[p.ID] = &stored
+	return nil
+}
+
+func (f *fakeProviderStore) Delete(id int) error {
+	if _, ok := f.providers[id]; !ok {
+		return errors.ErrProviderNotFound
+	}
+	delete(f.providers, id)
+	return nil
+}
+
+func newProviderStore() *fakeProviderStore {
+	return &fakeProviderStore{
+		providers: map[int]*model.Provider{
+			1: {ID: 1, Name: "provider1", URL: "https://example.com/one.json", Format: model.ProviderFormatJSON, RateLimitPerMinute: 60, Enabled: true},
+		},
+		nextID: 1,
+	}
+}
+
+func newProviderAdminRouter(store *fakeProviderStore) *gin.Engine {
+	h := &ProviderHandler{getter: store, writer: store, queryTimeout: time.Second}
+	router := newTestRouter(http.MethodPost, "/providers", h.CreateProvider)
+	router.PUT("/providers/:id", h.UpdateProvider)
+	router.DELETE("/providers/:id", h.DeleteProvider)
+	return router
+}
+
+func TestCreateProvider(t *testing.T) {
+	store := newProviderStore()
+	w := serve(newProviderAdminRouter(store), http.MethodPost, "/providers",
+		`{"name": "provider3", "url": "https://example.com/three.json", "format": "json", "rate_limit_per_minute": 30}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body %s)", w.Code, w.Body)
+	}
+
+	var body struct {
+		Data model.Provider `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Data.ID != 2 || body.Data.Name != "provider3" || !body.Data.Enabled {
+		t.Errorf("created provider = %+v, want ID 2 named provider3, enabled", body.Data)
+	}
+	if _, ok := store.providers[2]; !ok {
+		t.Error("provider was not stored")
+	}
+}
+
+func TestCreateProviderRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{"duplicate name", `{"name": "provider1", "url": "https://example.com/other.json", "format": "json", "rate_limit_per_minute": 60}`, http.StatusConflict},
+		{"missing url", `{"name": "provider3", "format": "json", "rate_limit_per_minute": 60}`, http.StatusBadRequest},
+		{"unknown format", `{"name": "provider3", "url": "https://example.com/three.csv", "format": "csv", "rate_limit_per_minute": 60}`, http.StatusBadRequest},
+		{"zero rate limit", `{"name": "provider3", "url": "https://example.com/three.json", "format": "json"}`, http.StatusBadRequest},
+		{"malformed body", `{"name": `, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newProviderStore()
+			w := serve(newProviderAdminRouter(store), http.MethodPost, "/providers", tt.body)
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantCode, w.Body)
+			}
+			if len(store.providers) != 1 {
+				t.Errorf("store holds %d providers, want the rejected one not stored", len(store.providers))
+			}
+		})
+	}
+}
+
+func TestUpdateProvider(t *testing.T) {
+	store := newProviderStore()
+	w := serve(newProviderAdminRouter(store), http.MethodPut, "/providers/1",
+		`{"url": "https://example.com/one.xml", "format": "xml", "rate_limit_per_minute": 10}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", w.Code, w.Body)
+	}
+
+	got := store.providers[1]
+	if got.URL != "https://example.com/one.xml" || got.Format != model.ProviderFormatXML || got.RateLimitPerMinute != 10 {
+		t.Errorf("stored provider = %+v, want the new url, format and rate limit", got)
+	}
+	if got.Name != "provider1" || !got.Enabled {
+		t.Errorf("stored provider = %+v, want name and enabled state kept", got)
+	}
+}
+
+func TestUpdateProviderRejects(t *testing.T) {
+	valid := `{"url": "https://example.com/one.xml", "format": "xml", "rate_limit_per_minute": 10}`
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		wantCode int
+	}{
+		{"unknown provider", "/providers/99", valid, http.StatusNotFound},
+		{"invalid id", "/providers/abc", valid, http.StatusBadRequest},
+		{"invalid url", "/providers/1", `{"url": "ftp://example.com", "format": "json", "rate_limit_per_minute": 10}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newProviderStore()
+			w := serve(newProviderAdminRouter(store), http.MethodPut, tt.path, tt.body)
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantCode, w.Body)
+			}
+			if store.providers[1].URL != "https://example.com/one.json" {
+				t.Error("rejected update changed the stored provider")
+			}
+		})
+	}
+}
+
+func TestDeleteProvider(t *testing.T) {
+	store := newProviderStore()
+	if w := serve(newProviderAdminRouter(store), http.MethodDelete, "/providers/1", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204 (body %s)", w.Code, w.Body)
+	}
+	if len(store.providers) != 0 {
+		t.Error("provider was not deleted")
+	}
+
+	if w := serve(newProviderAdminRouter(store), http.MethodDelete, "/providers/1", ""); w.Code != http.StatusNotFound {
+		t.Errorf("second delete: status = %d, want 404", w.Code)
+	}
+	if w := serve(newProviderAdminRouter(store), http.MethodDelete, "/providers/abc", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid id: status = %d, want 400", w.Code)
+	}
+}
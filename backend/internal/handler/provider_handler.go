@@ -4,50 +4,410 @@
 package handler
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"search-engine/backend/internal/errors"
 	"search-engine/backend/internal/middleware"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/internal/provider"
 	"search-engine/backend/internal/repository"
+	"search-engine/backend/internal/service"
+	"search-engine/backend/pkg/cache"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// providerLister is the part of ProviderRepository that GetProviders needs
+type providerLister interface {
+	GetPaginated(ctx context.Context, limit, offset int) ([]*model.Provider, error)
+	CountAll(ctx context.Context) (int, error)
+}
+
+// providerContentCounter is the part of ContentRepository that GetProvider needs
+type providerContentCounter interface {
+	CountByProvider(ctx context.Context, providerID int) (int, error)
+}
+
+// providerWriter is the part of ProviderRepository that the management endpoints need
+type providerWriter interface {
+	Create(p *model.Provider) error
+	Update(p *model.Provider) error
+	Delete(id int) error
+}
+
+// syncHistoryReader is the part of SyncHistoryRepository that GetSyncHistory needs
+type syncHistoryReader interface {
+	GetByProvider(ctx context.Context, providerID, limit int) ([]*model.SyncResult, error)
+}
+
 // ProviderHandler handles provider-related HTTP requests
 type ProviderHandler struct {
-	providerRepo *repository.ProviderRepository
+	providerRepo      *repository.ProviderRepository
+	lister            providerLister
+	getter            providerGetter
+	contentCounter    providerContentCounter
+	writer            providerWriter
+	syncRepo          *repository.SyncHistoryRepository
+	syncHistory       syncHistoryReader
+	searchCache       cache.Cache
+	staleAfterMinutes int
+	queryTimeout      time.Duration
 }
 
 // NewProviderHandler creates a new ProviderHandler instance
-func NewProviderHandler(providerRepo *repository.ProviderRepository) *ProviderHandler {
+// staleAfterMinutes is the staleness threshold for providers without their own
+// searchCache is invalidated when deleting a provider removes its content
+func NewProviderHandler(providerRepo *repository.ProviderRepository, contentRepo *repository.ContentRepository, syncRepo *repository.SyncHistoryRepository, searchCache cache.Cache, staleAfterMinutes int, queryTimeout time.Duration) *ProviderHandler {
+	if queryTimeout <= 0 {
+		queryTimeout = 5 * time.Second
+	}
 	return &ProviderHandler{
-		providerRepo: providerRepo,
+		providerRepo:      providerRepo,
+		lister:            providerRepo,
+		getter:            providerRepo,
+		contentCounter:    contentRepo,
+		writer:            providerRepo,
+		syncRepo:          syncRepo,
+		syncHistory:       syncRepo,
+		searchCache:       searchCache,
+		staleAfterMinutes: staleAfterMinutes,
+		queryTimeout:      queryTimeout,
 	}
 }
 
 // GetProviders handles GET /api/v1/providers requests
-// Returns a list of all providers
+// Returns one page of providers ordered by name
 //
 // @Summary     Get providers list
-// @Description Get a list of all content providers
+// @Description Get a page of content providers ordered by name, with the same pagination fields as search
 // @Tags        providers
 // @Accept      json
 // @Produce     json
-// @Success     200  {array}   model.Provider
+// @Param       page      query    int  false  "Page number (default: 1)"
+// @Param       per_page  query    int  false  "Items per page (default: 20, max: 100)"
+// @Success     200  {object} model.ProviderListResponse
+// @Failure     400  {object} map[string]string "Invalid pagination parameters"
 // @Failure     500  {object} map[string]string "Internal server error"
 // @Router      /providers [get]
 func (h *ProviderHandler) GetProviders(c *gin.Context) {
-	providers, err := h.providerRepo.GetAll()
+	var req model.ProviderListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.HandleAppError(c, errors.NewFieldValidationError("Invalid query parameters", map[string]string{
+			"page":     "page must be an integer",
+			"per_page": fmt.Sprintf("per_page must be an integer between 1 and %d", model.MaxProviderPerPage),
+		}))
+		return
+	}
+	req.Validate()
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.queryTimeout)
+	defer cancel()
+
+	total, err := h.lister.CountAll(ctx)
+	if err != nil {
+		middleware.HandleAppError(c, asDatabaseError("count providers", err))
+		return
+	}
+
+	providers, err := h.lister.GetPaginated(ctx, req.PerPage, req.GetOffset())
+	if err != nil {
+		middleware.HandleAppError(c, asDatabaseError("get providers page", err))
+		return
+	}
+
+	response := &model.ProviderListResponse{
+		Providers: providers,
+		Total:     total,
+		Page:      req.Page,
+		PerPage:   req.PerPage,
+	}
+	response.CalculateTotalPages()
+
+	middleware.JSONSuccess(c, response)
+}
+
+// GetProvider handles GET /api/v1/providers/:id requests
+// Returns a single provider with how much content it has supplied
+//
+// @Summary     Get a provider
+// @Description Get a provider by ID with its content count and last fetch time (null if never fetched)
+// @Tags        providers
+// @Accept      json
+// @Produce     json
+// @Param       id   path     int  true  "Provider ID"
+// @Success     200  {object} model.ProviderDetail
+// @Failure     400  {object} map[string]string "Invalid provider ID"
+// @Failure     404  {object} map[string]string "Provider not found"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /providers/{id} [get]
+func (h *ProviderHandler) GetProvider(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleAppError(c, errors.NewInvalidIDError("provider"))
+		return
+	}
+
+	p, appErr := h.getProvider(id)
+	if appErr != nil {
+		middleware.HandleAppError(c, appErr)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.queryTimeout)
+	defer cancel()
+
+	count, err := h.contentCounter.CountByProvider(ctx, id)
+	if err != nil {
+		middleware.HandleAppError(c, asDatabaseError("count provider content", err))
+		return
+	}
+
+	middleware.JSONSuccess(c, model.ProviderDetail{
+		Provider:      *p,
+		ContentCount:  count,
+		LastFetchedAt: p.LastFetchedAt,
+	})
+}
+
+// GetSyncHistory handles GET /api/v1/providers/:id/sync-history requests
+// Returns the provider's recorded sync runs, newest first
+//
+// @Summary     Get provider sync history
+// @Description Get a provider's recorded sync runs, newest first: when each started and finished, how many items it fetched, skipped and saved, and the error of failed runs
+// @Tags        providers
+// @Accept      json
+// @Produce     json
+// @Param       id     path     int  true   "Provider ID"
+// @Param       limit  query    int  false  "Maximum runs to return (default: 20, max: 100)"
+// @Success     200  {object} model.SyncHistoryResponse
+// @Failure     400  {object} map[string]string "Invalid provider ID or limit"
+// @Failure     404  {object} map[string]string "Provider not found"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /providers/{id}/sync-history [get]
+func (h *ProviderHandler) GetSyncHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		// Check if it's already an AppError
-		if appErr := errors.AsAppError(err); appErr != nil {
-			middleware.HandleAppError(c, appErr)
+		middleware.HandleAppError(c, errors.NewInvalidIDError("provider"))
+		return
+	}
+
+	limit := defaultHistoryLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxHistoryLimit {
+			middleware.HandleAppError(c, errors.NewFieldValidationError("Invalid query parameters", map[string]string{
+				"limit": fmt.Sprintf("must be an integer between 1 and %d", maxHistoryLimit),
+			}))
 			return
 		}
+	}
+
+	// Distinguish an unknown provider from one that never synced
+	if _, appErr := h.getProvider(id); appErr != nil {
+		middleware.HandleAppError(c, appErr)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.queryTimeout)
+	defer cancel()
+
+	history, err := h.syncHistory.GetByProvider(ctx, id, limit)
+	if err != nil {
+		middleware.HandleAppError(c, asDatabaseError("get sync history", err))
+		return
+	}
+
+	middleware.JSONSuccess(c, model.SyncHistoryResponse{ProviderID: id, History: history})
+}
+
+// GetProviderStatuses handles GET /api/v1/providers/status requests
+// Returns every provider's operational state in one call for dashboards
+//
+// @Summary     Get provider statuses
+// @Description Get every provider's enabled state, last fetch, last recorded sync run, whether a sync is running in this API process, and a stale flag. A provider is stale when its last fetch is older than its stale_after_minutes, or PROVIDER_STALE_AFTER_MINUTES when unset; disabled providers are never stale.
+// @Tags        providers
+// @Accept      json
+// @Produce     json
+// @Success     200  {array}   model.ProviderStatus
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /providers/status [get]
+func (h *ProviderHandler) GetProviderStatuses(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.queryTimeout)
+	defer cancel()
+
+	providers, err := h.providerRepo.GetAll()
+	if err != nil {
+		middleware.HandleAppError(c, asDatabaseError("get all providers", err))
+		return
+	}
+
+	latest, err := h.syncRepo.GetLatestPerProvider(ctx)
+	if err != nil {
+		middleware.HandleAppError(c, asDatabaseError("get latest sync history", err))
+		return
+	}
+	lastSyncs := make(map[int]*model.SyncResult, len(latest))
+	for _, s := range latest {
+		lastSyncs[s.ProviderID] = s
+	}
+
+	now := time.Now()
+	statuses := make([]model.ProviderStatus, 0, len(providers))
+	for _, p := range providers {
+		staleAfterMinutes := h.staleAfterMinutes
+		if p.StaleAfterMinutes != nil {
+			staleAfterMinutes = *p.StaleAfterMinutes
+		}
+
+		statuses = append(statuses, model.ProviderStatus{
+			ProviderID:        p.ID,
+			Name:              p.Name,
+			Enabled:           p.Enabled,
+			LastFetchedAt:     p.LastFetchedAt,
+			LastSync:          lastSyncs[p.ID],
+			SyncRunning:       provider.SyncRunning(p.Name),
+			StaleAfterMinutes: staleAfterMinutes,
+			Stale:             p.Enabled && model.IsStale(p.LastFetchedAt, time.Duration(staleAfterMinutes)*time.Minute, now),
+		})
+	}
+
+	middleware.JSONSuccess(c, statuses)
+}
+
+// CreateProvider handles POST /api/v1/providers requests
+// Registers a new provider; syncs pick it up on their next run when it has a field mapping
+//
+// @Summary     Create a provider
+// @Description Register a new content provider. It starts enabled; providers with a field_mapping are fetched by the next sync. Requires the X-Admin-Key header.
+// @Tags        providers
+// @Accept      json
+// @Produce     json
+// @Param       X-Admin-Key  header   string                       true  "Admin key"
+// @Param       provider     body     model.ProviderCreateRequest  true  "Provider to create"
+// @Success     201  {object} model.Provider
+// @Failure     400  {object} map[string]string "Invalid provider"
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Failure     409  {object} map[string]string "Name or URL already registered"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /providers [post]
+func (h *ProviderHandler) CreateProvider(c *gin.Context) {
+	var req model.ProviderCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleAppError(c, errors.NewValidationErrorWithDetails("Invalid request body", err.Error()))
+		return
+	}
+
+	p := req.Provider()
+	if err := model.ValidateProvider(p, nil); err != nil {
+		middleware.HandleAppError(c, errors.NewValidationErrorWithDetails("Provider validation failed", err.Error()))
+		return
+	}
+
+	if err := h.writer.Create(p); err != nil {
+		middleware.HandleAppError(c, asDatabaseError("create provider", err))
+		return
+	}
+
+	middleware.JSONSuccess(c, p, http.StatusCreated)
+}
+
+// UpdateProvider handles PUT /api/v1/providers/:id requests
+// Replaces the URL, format, rate limit and field mapping of a provider
+//
+// @Summary     Update a provider
+// @Description Replace a provider's url, format, rate_limit_per_minute and field_mapping; the name can't be changed. Requires the X-Admin-Key header.
+// @Tags        providers
+// @Accept      json
+// @Produce     json
+// @Param       X-Admin-Key  header   string                       true  "Admin key"
+// @Param       id           path     int                          true  "Provider ID"
+// @Param       provider     body     model.ProviderUpdateRequest  true  "New fetch settings"
+// @Success     200  {object} model.Provider
+// @Failure     400  {object} map[string]string "Invalid provider ID or settings"
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Failure     404  {object} map[string]string "Provider not found"
+// @Failure     409  {object} map[string]string "URL already registered"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /providers/{id} [put]
+func (h *ProviderHandler) UpdateProvider(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleAppError(c, errors.NewInvalidIDError("provider"))
+		return
+	}
+
+	var req model.ProviderUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleAppError(c, errors.NewValidationErrorWithDetails("Invalid request body", err.Error()))
+		return
+	}
 
-		// Wrap unknown errors
-		appErr := errors.NewDatabaseError("get all providers", err)
+	p, appErr := h.getProvider(id)
+	if appErr != nil {
 		middleware.HandleAppError(c, appErr)
 		return
 	}
 
-	middleware.JSONSuccess(c, providers)
+	req.ApplyTo(p)
+	if err := model.ValidateProvider(p, nil); err != nil {
+		middleware.HandleAppError(c, errors.NewValidationErrorWithDetails("Provider validation failed", err.Error()))
+		return
+	}
+
+	if err := h.writer.Update(p); err != nil {
+		middleware.HandleAppError(c, asDatabaseError("update provider", err))
+		return
+	}
+
+	middleware.JSONSuccess(c, p)
+}
+
+// DeleteProvider handles DELETE /api/v1/providers/:id requests
+// Removes a provider together with all of its content
+//
+// @Summary     Delete a provider
+// @Description Delete a provider; its content, tags and sync history are deleted with it. Requires the X-Admin-Key header.
+// @Tags        providers
+// @Param       X-Admin-Key  header   string  true  "Admin key"
+// @Param       id           path     int     true  "Provider ID"
+// @Success     204  "Provider deleted"
+// @Failure     400  {object} map[string]string "Invalid provider ID"
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Failure     404  {object} map[string]string "Provider not found"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /providers/{id} [delete]
+func (h *ProviderHandler) DeleteProvider(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleAppError(c, errors.NewInvalidIDError("provider"))
+		return
+	}
+
+	if err := h.writer.Delete(id); err != nil {
+		if err == errors.ErrProviderNotFound {
+			middleware.HandleAppError(c, errors.NewProviderNotFoundError())
+			return
+		}
+		middleware.HandleAppError(c, asDatabaseError("delete provider", err))
+		return
+	}
+
+	// The provider's content is gone from every cached search response
+	service.InvalidateSearchCache(h.searchCache)
+	c.Status(http.StatusNoContent)
+}
+
+// getProvider loads a provider by ID, mapping a miss to a 404
+func (h *ProviderHandler) getProvider(id int) (*model.Provider, *errors.AppError) {
+	p, err := h.getter.GetByID(id)
+	if err == nil {
+		return p, nil
+	}
+	if err == errors.ErrProviderNotFound {
+		return nil, errors.NewProviderNotFoundError()
+	}
+	return nil, asDatabaseError("get provider", err)
 }
@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"search-engine/backend/internal/errors"
+	"search-engine/backend/internal/model"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeProviderLister pages through an in-memory provider list
+type fakeProviderLister struct {
+	providers           []*model.Provider
+	gotLimit, gotOffset int
+}
+
+func (f *fakeProviderLister) GetPaginated(_ context.Context, limit, offset int) ([]*model.Provider, error) {
+	f.gotLimit, f.gotOffset = limit, offset
+	if offset >= len(f.providers) {
+		return []*model.Provider{}, nil
+	}
+	end := min(offset+limit, len(f.providers))
+	return f.providers[offset:end], nil
+}
+
+func (f *fakeProviderLister) CountAll(context.Context) (int, error) {
+	return len(f.providers), nil
+}
+
+func newProvidersRouter(lister *fakeProviderLister) *gin.Engine {
+	h := &ProviderHandler{lister: lister, queryTimeout: time.Second}
+	return newTestRouter(http.MethodGet, "/providers", h.GetProviders)
+}
+
+func TestGetProvidersPaginates(t *testing.T) {
+	lister := &fakeProviderLister{}
+	for i := 0; i < 45; i++ {
+		lister.providers = append(lister.providers, &model.Provider{ID: i + 1})
+	}
+
+	tests := []struct {
+		name                     string
+		query                    string
+		wantLimit, wantOffset    int
+		wantPage, wantTotalPages int
+		wantCount                int
+	}{
+		{"defaults", "", 20, 0, 1, 3, 20},
+		{"last page", "?page=3&per_page=20", 20, 40, 3, 3, 5},
+		{"per_page capped", "?per_page=1000", 100, 0, 1, 1, 45},
+		{"past the end", "?page=9", 20, 160, 9, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(newProvidersRouter(lister), http.MethodGet, "/providers"+tt.query, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body %s)", w.Code, w.Body)
+			}
+
+			var body struct {
+				Data model.ProviderListResponse `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+
+			if lister.gotLimit != tt.wantLimit || lister.gotOffset != tt.wantOffset {
+				t.Errorf("GetPaginated(%d, %d), want (%d, %d)", lister.gotLimit, lister.gotOffset, tt.wantLimit, tt.wantOffset)
+			}
+			got := body.Data
+			if got.Total != 45 || got.Page != tt.wantPage || got.PerPage != tt.wantLimit || got.TotalPages != tt.wantTotalPages {
+				t.Errorf("pagination = total %d page %d per_page %d total_pages %d", got.Total, got.Page, got.PerPage, got.TotalPages)
+			}
+			if len(got.Providers) != tt.wantCount {
+				t.Errorf("len(providers) = %d, want %d", len(got.Providers), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestGetProvidersRejectsNonIntegerPage(t *testing.T) {
+	if w := serve(newProvidersRouter(&fakeProviderLister{}), http.MethodGet, "/providers?page=abc", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+// fakeProviderDetails serves providers and their content counts from memory
+type fakeProviderDetails struct {
+	providers map[int]*model.Provider
+	counts    map[int]int
+}
+
+func (f *fakeProviderDetails) GetByID(id int) (*model.Provider, error) {
+	p, ok := f.providers[id]
+	if !ok {
+		return nil, errors.ErrProviderNotFound
+	}
+	return p, nil
+}
+
+func (f *fakeProviderDetails) CountByProvider(_ context.Context, providerID int) (int, error) {
+	return f.counts[providerID], nil
+}
+
+func newProviderRouter(details *fakeProviderDetails) *gin.Engine {
+	h := &ProviderHandler{getter: details, contentCounter: details, queryTimeout: time.Second}
+	return newTestRouter(http.MethodGet, "/providers/:id", h.GetProvider)
+}
+
+func TestGetProvider(t *testing.T) {
+	fetchedAt := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	details := &fakeProviderDetails{
+		providers: map[int]*model.Provider{
+			1: {ID: 1, Name: "provider1", Format: model.ProviderFormatJSON, Enabled: true, LastFetchedAt: &fetchedAt},
+			2: {ID: 2, Name: "provider2", Format: model.ProviderFormatXML},
+		},
+		counts: map[int]int{1: 42},
+	}
+
+	tests := []struct {
+		id            string
+		wantCount     int
+		wantFetchedAt string
+	}{
+		{"1", 42, `"2024-03-15T10:00:00Z"`},
+		{"2", 0, "null"},
+	}
+	for _, tt := range tests {
+		w := serve(newProviderRouter(details), http.MethodGet, "/providers/"+tt.id, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("provider %s: status = %d, want 200 (body %s)", tt.id, w.Code, w.Body)
+		}
+
+		var body struct {
+			Data map[string]json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if got := string(body.Data["id"]); got != tt.id {
+			t.Errorf("provider %s: id = %s", tt.id, got)
+		}
+		if got := string(body.Data["content_count"]); got != strconv.Itoa(tt.wantCount) {
+			t.Errorf("provider %s: content_count = %s, want %d", tt.id, got, tt.wantCount)
+		}
+		// Never-fetched providers report null rather than dropping the field
+		if got, ok := body.Data["last_fetched_at"]; !ok || string(got) != tt.wantFetchedAt {
+			t.Errorf("provider %s: last_fetched_at = %s (present %t), want %s", tt.id, got, ok, tt.wantFetchedAt)
+		}
+	}
+}
+
+func TestGetProviderErrors(t *testing.T) {
+	details := &fakeProviderDetails{providers: map[int]*model.Provider{}}
+
+	tests := []struct {
+		id       string
+		wantCode int
+	}{
+		{"abc", http.StatusBadRequest},
+		{"1.5", http.StatusBadRequest},
+		{"99", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if w := serve(newProviderRouter(details), http.MethodGet, "/providers/"+tt.id, ""); w.Code != tt.wantCode {
+			t.Errorf("GET /providers/%s: status = %d, want %d (body %s)", tt.id, w.Code, tt.wantCode, w.Body)
+		}
+	}
+}
+
+// fakeSyncHistory serves each provider's runs from memory, newest first
+type fakeSyncHistory struct {
+	runs     map[int][]*model.SyncResult
+	gotLimit int
+}
+
+func (f *fakeSyncHistory) GetByProvider(_ context.Context, providerID, limit int) ([]*model.SyncResult, error) {
+	f.gotLimit = limit
+	runs := f.runs[providerID]
+	if runs == nil {
+		runs = []*model.SyncResult{}
+	}
+	return runs[:min(limit, len(runs))], nil
+}
+
+func newSyncHistoryRouter(history *fakeSyncHistory) *gin.Engine {
+	details := &fakeProviderDetails{providers: map[int]*model.Provider{
+		1: {ID: 1, Name: "provider1"},
+		2: {ID: 2, Name: "provider2"},
+	}}
+	h := &ProviderHandler{getter: details, syncHistory: history, queryTimeout: time.Second}
+	return newTestRouter(http.MethodGet, "/providers/:id/sync-history", h.GetSyncHistory)
+}
+
+func TestGetSyncHistory(t *testing.T) {
+	started := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	history := &fakeSyncHistory{runs: map[int][]*model.SyncResult{1: {
+		{ID: 3, ProviderID: 1, StartedAt: started.Add(2 * time.Hour), ItemsFetched: 0, Status: model.SyncStatusFailed, ErrorMessage: "provider returned 503"},
+		{ID: 2, ProviderID: 1, StartedAt: started.Add(time.Hour), ItemsFetched: 12, Status: model.SyncStatusSuccess},
+		{ID: 1, ProviderID: 1, StartedAt: started, ItemsFetched: 10, Status: model.SyncStatusSuccess},
+	}}}
+
+	tests := []struct {
+		name      string
+		path      string
+		wantLimit int
+		wantIDs   []int64
+	}{
+		{"default limit", "/providers/1/sync-history", defaultHistoryLimit, []int64{3, 2, 1}},
+		{"limit", "/providers/1/sync-history?limit=2", 2, []int64{3, 2}},
+		{"never synced", "/providers/2/sync-history", defaultHistoryLimit, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(newSyncHistoryRouter(history), http.MethodGet, tt.path, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body %s)", w.Code, w.Body)
+			}
+			if history.gotLimit != tt.wantLimit {
+				t.Errorf("limit = %d, want %d", history.gotLimit, tt.wantLimit)
+			}
+
+			var body struct {
+				Data model.SyncHistoryResponse `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.Data.History == nil {
+				t.Fatal("history is null, want an array")
+			}
+			ids := []int64{}
+			for _, run := range body.Data.History {
+				ids = append(ids, run.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("run IDs = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+
+	w := serve(newSyncHistoryRouter(history), http.MethodGet, "/providers/1/sync-history", "")
+	var body struct {
+		Data model.SyncHistoryResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if failed := body.Data.History[0]; failed.Status != model.SyncStatusFailed || failed.ErrorMessage != "provider returned 503" {
+		t.Errorf("latest run = %+v, want the failed run with its error", failed)
+	}
+}
+
+func TestGetSyncHistoryErrors(t *testing.T) {
+	tests := []struct {
+		path     string
+		wantCode int
+	}{
+		{"/providers/abc/sync-history", http.StatusBadRequest},
+		{"/providers/1/sync-history?limit=0", http.StatusBadRequest},
+		{"/providers/1/sync-history?limit=1000", http.StatusBadRequest},
+		{"/providers/99/sync-history", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if w := serve(newSyncHistoryRouter(&fakeSyncHistory{}), http.MethodGet, tt.path, ""); w.Code != tt.wantCode {
+			t.Errorf("GET %s: status = %d, want %d (body %s)", tt.path, w.Code, tt.wantCode, w.Body)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"io"
+	"net/http/httptest"
+	"search-engine/backend/internal/middleware"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestRouter mounts handler on a test-mode router behind the API's error middleware
+func newTestRouter(method, route string, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorHandlerMiddleware())
+	router.Handle(method, route, handler)
+	return router
+}
+
+// serve sends one request through router; a non-empty body is sent as JSON
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
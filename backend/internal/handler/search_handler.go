@@ -8,14 +8,23 @@ import (
 	"search-engine/backend/internal/middleware"
 	"search-engine/backend/internal/model"
 	"search-engine/backend/internal/service"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// searcher is the part of SearchService that Search needs
+type searcher interface {
+	Search(ctx context.Context, req *model.SearchRequest) (*model.SearchResponse, error)
+}
+
 // SearchHandler handles search-related HTTP requests
 // This struct holds dependencies needed for search operations
 type SearchHandler struct {
 	searchService *service.SearchService
+	searcher      searcher
+	maxPerPage    int // Named in the per_page error message; 0 means model.DefaultMaxPerPage
 }
 
 // NewSearchHandler creates a new SearchHandler instance
@@ -23,6 +32,8 @@ type SearchHandler struct {
 func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
 	return &SearchHandler{
 		searchService: searchService,
+		searcher:      searchService,
+		maxPerPage:    searchService.MaxPerPage(),
 	}
 }
 
@@ -39,21 +50,42 @@ func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
 // @Param       provider_id  query    int      false  "Filter by provider ID"
 // @Param       start_date   query    string   false  "Filter results published on/after this date (YYYY-MM-DD)"
 // @Param       end_date     query    string   false  "Filter results published on/before this date (YYYY-MM-DD)"
+// @Param       period       query    string   false  "Date-range preset ending today: last_week, last_month, last_3_months or last_year; start_date and end_date override its ends"
+// @Param       updated_since  query  string  false  "Only content created, edited or changed by a sync at or after this RFC 3339 timestamp, e.g. 2024-03-15T10:00:00Z; resyncs that change nothing and score recalculations don't count"
 // @Param       page         query    int      false  "Page number (default: 1)"
-// @Param       per_page     query    int      false  "Items per page (default: 10, max: 100)"
-// @Param       sort_by      query    string   false  "Sort field: score, published_at, or title (default: score)"
+// @Param       per_page     query    int      false  "Items per page (default: 10, max: SEARCH_MAX_PER_PAGE, normally 100; larger values are capped)"
+// @Param       after        query    string   false  "Cursor from a previous next_cursor; continues after that page without OFFSET (page is ignored). Only with sort_by=score, sort_order=desc and without distinct_titles or group_by_provider"
+// @Param       sort_by      query    string   false  "Sort field: score, published_at, title, relevance, views, likes, reactions or comments (default: score); relevance blends the text match (FULLTEXT match score, or for short keywords the number of terms the title contains) with score; engagement sorts list content of the other type last"
 // @Param       sort_order   query    string   false  "Sort order: asc or desc (default: desc)"
+// @Param       tag_order    query    string   false  "Tag order: alpha or insertion (default: alpha)"
+// @Param       timeout_ms   query    int      false  "Query timeout override in milliseconds (clamped to the server maximum)"
+// @Param       prefix       query    bool     false  "Prefix-match keywords so go matches golang (default: server setting, normally true)"
+// @Param       match_mode   query    string   false  "How keyword terms combine in FULLTEXT mode: any or all (default: any)"
+// @Param       include_tags query    bool     false  "Also match the keyword against tags, ORed with the title match (default: true)"
+// @Param       distinct_titles  query  bool  false  "Collapse results with the same title to the highest-scoring one; total then counts titles and collapsed_count the hidden rows"
+// @Param       group_by_provider  query  bool  false  "Bucket results by provider into groups, each with that provider's top per_page matches and its match count; results is then empty. Not with distinct_titles"
+// @Param       include_facets  query  bool  false  "Add facets: match counts per type and per provider, over the same filters as the search"
+// @Param       highlight    query    bool     false  "Add highlighted_title to each result: the HTML-escaped title with query matches wrapped in <mark> tags (SEARCH_HIGHLIGHT_PRE_TAG/POST_TAG)"
+// @Param       fuzzy        query    bool     false  "When the keyword matches nothing, return titles with words spelled close to the query terms instead, closest first; the response then has fuzzy=true"
+// @Param       min_views      query  int  false  "Hide videos with fewer views (articles unaffected)"
+// @Param       min_likes      query  int  false  "Hide videos with fewer likes (articles unaffected)"
+// @Param       min_reactions  query  int  false  "Hide articles with fewer reactions (videos unaffected)"
+// @Param       min_comments   query  int  false  "Hide articles with fewer comments (videos unaffected)"
+// @Param       nocache        query  bool    false  "Skip the cached result and query the database (the fresh result is still cached)"
+// @Param       Cache-Control  header string  false  "no-cache skips the cached result like nocache=true"
+// @Param       If-None-Match  header string  false  "ETag of a previous response; 304 without a body while the response is unchanged"
 // @Success     200          {object} model.SearchResponse
+// @Header      200          {string} ETag "Hash of the response data"
+// @Success     304          "Response unchanged since the ETag in If-None-Match"
 // @Failure     400          {object} map[string]string "Invalid request parameters"
 // @Failure     500          {object} map[string]string "Internal server error"
+// @Failure     504          {object} map[string]string "Search query exceeded its time budget"
 // @Router      /search [get]
 func (h *SearchHandler) Search(c *gin.Context) {
 	// Bind query parameters to SearchRequest
 	// Gin automatically parses query string parameters
 	var req model.SearchRequest
-	if err := c.ShouldBindQuery(&req); err != nil {
-		// Use custom error type for validation errors
-		appErr := errors.NewValidationErrorWithDetails("Invalid request parameters", err.Error())
+	if appErr := bindSearchRequest(c, &req, h.maxPerPage); appErr != nil {
 		middleware.HandleAppError(c, appErr)
 		return
 	}
@@ -61,7 +93,7 @@ func (h *SearchHandler) Search(c *gin.Context) {
 	// Perform the search using the service
 	// The service handles all business logic and data processing
 	// Pass request context for timeout and cancellation support
-	response, err := h.searchService.Search(c.Request.Context(), &req)
+	response, err := h.searcher.Search(c.Request.Context(), &req)
 	if err != nil {
 		// Check if it's already an AppError
 		if appErr := errors.AsAppError(err); appErr != nil {
@@ -69,7 +101,12 @@ func (h *SearchHandler) Search(c *gin.Context) {
 			return
 		}
 
-		// Check for context timeout
+		// A client that went away or a request deadline; the service reports its own
+		// query timeouts, so a bare deadline error here is one too
+		if appErr := errors.FromContext(c.Request.Context(), "search"); appErr != nil {
+			middleware.HandleAppError(c, appErr)
+			return
+		}
 		if err == context.DeadlineExceeded {
 			appErr := errors.NewQueryTimeoutError("search")
 			middleware.HandleAppError(c, appErr)
@@ -82,8 +119,112 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		return
 	}
 
+	// Clients repeating a query get 304 while the response is unchanged
+	// A response that can't be hashed is still served, just without an ETag
+	if etag, err := middleware.ETagFor(response); err == nil && middleware.NotModified(c, etag) {
+		return
+	}
+
 	// Return successful response with search results
 	// SearchResponse already has its own structure, so we wrap it in data field
 	// for consistency with other endpoints
 	middleware.JSONSuccess(c, response)
 }
+
+// Count handles GET /api/v1/search/count requests
+// Returns only the number of results for a filter combination, without fetching rows
+//
+// @Summary     Count search results
+// @Description Count the content matching the same filters as /search without fetching rows. If the count times out, total is -1 and timed_out is true.
+// @Tags        search
+// @Accept      json
+// @Produce     json
+// @Param       query        query    string   false  "Search keyword (optional - if empty, counts all content)"
+// @Param       type         query    string   false  "Filter by content type: video or article"
+// @Param       provider_id  query    int      false  "Filter by provider ID"
+// @Param       start_date   query    string   false  "Filter results published on/after this date (YYYY-MM-DD)"
+// @Param       end_date     query    string   false  "Filter results published on/before this date (YYYY-MM-DD)"
+// @Param       period       query    string   false  "Date-range preset ending today: last_week, last_month, last_3_months or last_year; start_date and end_date override its ends"
+// @Param       updated_since  query  string  false  "Only content created, edited or changed by a sync at or after this RFC 3339 timestamp, e.g. 2024-03-15T10:00:00Z; resyncs that change nothing and score recalculations don't count"
+// @Param       timeout_ms   query    int      false  "Query timeout override in milliseconds (clamped to the server maximum)"
+// @Param       prefix       query    bool     false  "Prefix-match keywords so go matches golang (default: server setting, normally true)"
+// @Param       match_mode   query    string   false  "How keyword terms combine in FULLTEXT mode: any or all (default: any)"
+// @Param       include_tags query    bool     false  "Also match the keyword against tags, ORed with the title match (default: true)"
+// @Param       distinct_titles  query  bool  false  "Count distinct titles instead of rows"
+// @Param       min_views      query  int  false  "Hide videos with fewer views (articles unaffected)"
+// @Param       min_likes      query  int  false  "Hide videos with fewer likes (articles unaffected)"
+// @Param       min_reactions  query  int  false  "Hide articles with fewer reactions (videos unaffected)"
+// @Param       min_comments   query  int  false  "Hide articles with fewer comments (videos unaffected)"
+// @Success     200          {object} model.SearchCountResponse
+// @Failure     400          {object} map[string]string "Invalid request parameters"
+// @Failure     500          {object} map[string]string "Internal server error"
+// @Router      /search/count [get]
+func (h *SearchHandler) Count(c *gin.Context) {
+	var req model.SearchRequest
+	if appErr := bindSearchRequest(c, &req, h.maxPerPage); appErr != nil {
+		middleware.HandleAppError(c, appErr)
+		return
+	}
+
+	response, err := h.searchService.Count(c.Request.Context(), &req)
+	if err != nil {
+		if appErr := errors.AsAppError(err); appErr != nil {
+			middleware.HandleAppError(c, appErr)
+			return
+		}
+		middleware.HandleAppError(c, errors.NewServiceError("count", err))
+		return
+	}
+
+	middleware.JSONSuccess(c, response)
+}
+
+// GetCacheStats handles GET /api/v1/cache/stats requests
+// Reports how effective the search response cache is
+//
+// @Summary     Get search cache statistics
+// @Description Search cache hits, misses and hit ratio since startup or the last reset. Searches with nocache count as neither.
+// @Tags        search
+// @Produce     json
+// @Success     200  {object} model.CacheStats
+// @Router      /cache/stats [get]
+func (h *SearchHandler) GetCacheStats(c *gin.Context) {
+	middleware.JSONSuccess(c, h.searchService.CacheStats())
+}
+
+// bindSearchRequest binds query parameters into req
+// The raw query is checked first: Gin's binding errors don't say which parameter was
+// wrong, and some values bind fine but are out of range (page=0, min_views=-1), so
+// both are reported as clear per-field messages instead of being silently defaulted
+// A period preset is resolved to StartDate and EndDate as of now
+// A Cache-Control: no-cache request header sets NoCache like ?nocache=true does
+// maxPerPage is the per_page cap the per_page error message names
+func bindSearchRequest(c *gin.Context, req *model.SearchRequest, maxPerPage int) *errors.AppError {
+	if fields := model.SearchParamErrors(c.Request.URL.Query(), maxPerPage); len(fields) > 0 {
+		return errors.NewFieldValidationError("Invalid request parameters", fields)
+	}
+
+	if err := c.ShouldBindQuery(req); err != nil {
+		// Use custom error type for validation errors
+		return errors.NewValidationErrorWithDetails("Invalid request parameters", err.Error())
+	}
+	if err := req.ApplyPeriod(time.Now()); err != nil {
+		return errors.NewFieldValidationError("Invalid request parameters", map[string]string{"period": err.Error()})
+	}
+	if requestsNoCache(c.GetHeader("Cache-Control")) {
+		req.NoCache = true
+	}
+	return nil
+}
+
+// requestsNoCache reports whether a Cache-Control request header asks to bypass caches
+// Both no-cache and no-store directives count
+func requestsNoCache(cacheControl string) bool {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-cache", "no-store":
+			return true
+		}
+	}
+	return false
+}
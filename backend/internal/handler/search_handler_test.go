@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"search-engine/backend/internal/model"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newPeriodRouter(fake *fakeSearcher) *gin.Engine {
+	h := &SearchHandler{searcher: fake}
+	return newTestRouter(http.MethodGet, "/search", h.Search)
+}
+
+func TestSearchPeriod(t *testing.T) {
+	fake := &fakeSearcher{response: &model.SearchResponse{}}
+	router := newPeriodRouter(fake)
+
+	w := conditionalGet(router, "/search?period=last_week", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", w.Code, w.Body)
+	}
+	if fake.last.StartDate == nil || fake.last.EndDate == nil {
+		t.Fatalf("period=last_week set StartDate %v, EndDate %v; want both", fake.last.StartDate, fake.last.EndDate)
+	}
+	if got := fake.last.EndDate.Sub(*fake.last.StartDate); got != 8*24*time.Hour-time.Second {
+		t.Errorf("last_week spans %s, want 7 days plus today", got)
+	}
+
+	// Explicit dates win over the preset
+	w = conditionalGet(router, "/search?period=last_year&start_date=2024-01-01&end_date=2024-02-01", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", w.Code, w.Body)
+	}
+	wantStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	if !fake.last.StartDate.Equal(wantStart) || !fake.last.EndDate.Equal(wantEnd) {
+		t.Errorf("dates = %v..%v, want the explicit %v..%v", fake.last.StartDate, fake.last.EndDate, wantStart, wantEnd)
+	}
+}
+
+func TestSearchUnknownPeriod(t *testing.T) {
+	fake := &fakeSearcher{response: &model.SearchResponse{}}
+	router := newPeriodRouter(fake)
+
+	w := conditionalGet(router, "/search?period=yesterday", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	if fake.last != nil {
+		t.Error("searched despite the unknown period")
+	}
+
+	var body struct {
+		Error struct {
+			Fields map[string]string `json:"fields"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Error.Fields["period"] == "" {
+		t.Errorf("body %s has no period field error", w.Body)
+	}
+}
+
+func TestSearchUpdatedSince(t *testing.T) {
+	fake := &fakeSearcher{response: &model.SearchResponse{}}
+	router := newPeriodRouter(fake)
+
+	w := conditionalGet(router, "/search?updated_since=2024-03-15T12:00:00%2B02:00", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", w.Code, w.Body)
+	}
+	want := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	if fake.last.UpdatedSince == nil || !fake.last.UpdatedSince.Equal(want) {
+		t.Errorf("UpdatedSince = %v, want %v", fake.last.UpdatedSince, want)
+	}
+
+	fake.last = nil
+	w = conditionalGet(router, "/search?updated_since=2024-03-15", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("date without a time: status = %d, want 400", w.Code)
+	}
+	if fake.last != nil {
+		t.Error("searched despite the malformed updated_since")
+	}
+	var body struct {
+		Error struct {
+			Fields map[string]string `json:"fields"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Error.Fields["updated_since"] == "" {
+		t.Errorf("body %s has no updated_since field error", w.Body)
+	}
+}
+
+func TestSearchRejectsExtremePageValues(t *testing.T) {
+	for _, query := range []string{"page=0", "page=-3", "page=99999999999999999999", "per_page=0", "per_page=-1"} {
+		t.Run(query, func(t *testing.T) {
+			fake := &fakeSearcher{response: &model.SearchResponse{}}
+			w := conditionalGet(newPeriodRouter(fake), "/search?"+query, "")
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", w.Code)
+			}
+			if fake.last != nil {
+				t.Error("searched despite the invalid value")
+			}
+		})
+	}
+}
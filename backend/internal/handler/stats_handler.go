@@ -4,32 +4,55 @@
 package handler
 
 import (
+	"fmt"
 	"search-engine/backend/internal/errors"
 	"search-engine/backend/internal/middleware"
+	"search-engine/backend/internal/model"
 	"search-engine/backend/internal/repository"
+	"search-engine/backend/pkg/cache"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// maxProviderStatsIDs caps how many providers a single stats request may ask for
+const maxProviderStatsIDs = 100
+
+// statsCacheKey is the cache key for the global stats payload
+const statsCacheKey = "stats:global"
+
 // StatsHandler handles statistics-related HTTP requests
 type StatsHandler struct {
 	contentRepo  *repository.ContentRepository
 	providerRepo *repository.ProviderRepository
+	syncRepo     *repository.SyncHistoryRepository
+	cache        cache.Cache
+	cacheTTL     time.Duration
 }
 
 // NewStatsHandler creates a new StatsHandler instance
-func NewStatsHandler(contentRepo *repository.ContentRepository, providerRepo *repository.ProviderRepository) *StatsHandler {
+// cache can be nil to disable caching of the global stats payload
+// syncRepo supplies each provider's last sync outcome; nil leaves it out
+func NewStatsHandler(contentRepo *repository.ContentRepository, providerRepo *repository.ProviderRepository, syncRepo *repository.SyncHistoryRepository, cache cache.Cache, cacheTTL time.Duration) *StatsHandler {
+	if cacheTTL <= 0 {
+		cacheTTL = time.Minute
+	}
 	return &StatsHandler{
 		contentRepo:  contentRepo,
 		providerRepo: providerRepo,
+		syncRepo:     syncRepo,
+		cache:        cache,
+		cacheTTL:     cacheTTL,
 	}
 }
 
 // GetStats handles GET /api/v1/stats requests
-// Returns system statistics including content counts, provider info, etc.
+// Returns system statistics including content counts, provider info and each provider's last sync
 //
 // @Summary     Get system statistics
-// @Description Get statistics about the search engine including content counts, provider information, and type distribution
+// @Description Get statistics about the search engine including content counts, provider information, each provider's last sync result, and type distribution
 // @Tags        stats
 // @Accept      json
 // @Produce     json
@@ -37,6 +60,14 @@ func NewStatsHandler(contentRepo *repository.ContentRepository, providerRepo *re
 // @Failure     500  {object} map[string]string "Internal server error"
 // @Router      /stats [get]
 func (h *StatsHandler) GetStats(c *gin.Context) {
+	// Stats are expensive aggregates that tolerate some staleness
+	if h.cache != nil {
+		if stats, ok := cache.GetJSON[map[string]interface{}](h.cache, statsCacheKey); ok {
+			middleware.JSONSuccess(c, *stats)
+			return
+		}
+	}
+
 	stats, err := h.contentRepo.GetStats()
 	if err != nil {
 		// Check if it's already an AppError
@@ -66,10 +97,98 @@ func (h *StatsHandler) GetStats(c *gin.Context) {
 		return
 	}
 
-	stats["providers"] = gin.H{
+	providerStats := gin.H{
 		"total": len(providers),
 		"list":  providers,
 	}
 
+	// Last sync outcome of every provider that has synced, matched to the list by provider_id
+	if h.syncRepo != nil {
+		lastSyncs, err := h.syncRepo.GetLatestPerProvider(c.Request.Context())
+		if err != nil {
+			if appErr := errors.AsAppError(err); appErr != nil {
+				middleware.HandleAppError(c, appErr)
+				return
+			}
+			middleware.HandleAppError(c, errors.NewDatabaseError("get last syncs", err))
+			return
+		}
+		if lastSyncs == nil {
+			lastSyncs = []*model.SyncResult{}
+		}
+		providerStats["last_syncs"] = lastSyncs
+	}
+
+	stats["providers"] = providerStats
+
+	if h.cache != nil {
+		_ = cache.SetJSON(h.cache, statsCacheKey, stats, h.cacheTTL)
+	}
+
+	middleware.JSONSuccess(c, stats)
+}
+
+// GetProviderStats handles GET /api/v1/stats/providers requests
+// Returns detailed statistics for a specific set of providers
+//
+// @Summary     Get statistics for selected providers
+// @Description Get per-provider statistics (type breakdown, average score, freshness) for a comma-separated list of provider IDs
+// @Tags        stats
+// @Accept      json
+// @Produce     json
+// @Param       ids  query    string  true  "Comma-separated provider IDs (e.g. 1,2,3)"
+// @Success     200  {array}  model.ProviderStats
+// @Failure     400  {object} map[string]string "Invalid provider IDs"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /stats/providers [get]
+func (h *StatsHandler) GetProviderStats(c *gin.Context) {
+	ids, err := parseIDList(c.Query("ids"))
+	if err != nil {
+		appErr := errors.NewValidationErrorWithDetails("Invalid provider IDs", err.Error())
+		middleware.HandleAppError(c, appErr)
+		return
+	}
+
+	stats, err := h.contentRepo.GetProviderStats(c.Request.Context(), ids)
+	if err != nil {
+		// Check if it's already an AppError
+		if appErr := errors.AsAppError(err); appErr != nil {
+			middleware.HandleAppError(c, appErr)
+			return
+		}
+
+		// Wrap unknown errors
+		appErr := errors.NewDatabaseError("get provider statistics", err)
+		middleware.HandleAppError(c, appErr)
+		return
+	}
+
 	middleware.JSONSuccess(c, stats)
 }
+
+// parseIDList parses a comma-separated list of positive integer IDs
+// Duplicates are dropped while preserving the original order
+func parseIDList(raw string) ([]int, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, fmt.Errorf("ids is required")
+	}
+
+	parts := strings.Split(raw, ",")
+	if len(parts) > maxProviderStatsIDs {
+		return nil, fmt.Errorf("at most %d ids may be requested", maxProviderStatsIDs)
+	}
+
+	seen := make(map[int]bool, len(parts))
+	ids := make([]int, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid id: %q", part)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
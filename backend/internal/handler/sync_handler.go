@@ -0,0 +1,182 @@
+// sync_handler.go - HTTP handlers for provider sync endpoints
+// Triggers syncs on demand and reports what syncs did from the persisted sync history
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"search-engine/backend/internal/errors"
+	"search-engine/backend/internal/middleware"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/internal/provider"
+	"search-engine/backend/internal/repository"
+	"search-engine/backend/internal/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SyncHandler handles sync-related HTTP requests
+type SyncHandler struct {
+	contentRepo  *repository.ContentRepository
+	providerRepo *repository.ProviderRepository
+	syncRepo     *repository.SyncHistoryRepository
+	syncService  syncRunner
+	queryTimeout time.Duration
+	syncWait     time.Duration
+	syncTimeout  time.Duration
+}
+
+// syncRunner runs a full provider sync, as service.SyncService does
+type syncRunner interface {
+	Run(ctx context.Context) *model.SyncSummary
+}
+
+// NewSyncHandler creates a new SyncHandler instance
+// queryTimeout bounds the report queries (default: 5s); syncWait is how long a
+// triggered sync is waited for before answering 202 (default: 2m), and syncTimeout
+// how long it may run at all (default: 30m)
+func NewSyncHandler(contentRepo *repository.ContentRepository, providerRepo *repository.ProviderRepository, syncRepo *repository.SyncHistoryRepository, syncService *service.SyncService, queryTimeout, syncWait, syncTimeout time.Duration) *SyncHandler {
+	if queryTimeout <= 0 {
+		queryTimeout = 5 * time.Second
+	}
+	if syncWait <= 0 {
+		syncWait = 2 * time.Minute
+	}
+	if syncTimeout <= 0 {
+		syncTimeout = 30 * time.Minute
+	}
+	return &SyncHandler{
+		contentRepo:  contentRepo,
+		providerRepo: providerRepo,
+		syncRepo:     syncRepo,
+		syncService:  syncService,
+		queryTimeout: queryTimeout,
+		syncWait:     syncWait,
+		syncTimeout:  syncTimeout,
+	}
+}
+
+// SyncRunningResponse is returned when a triggered sync outlives the wait
+type SyncRunningResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// TriggerSync handles POST /api/v1/sync requests
+// Fetches every configured provider and recalculates scores without a restart
+// Only one sync runs at a time; the run is waited for up to syncWait, after which
+// it carries on in the background and the request gets 202
+//
+// @Summary     Trigger a provider sync
+// @Description Fetch from every configured provider, then recalculate scores. Returns 200 with a per-provider summary, or 202 if the sync takes longer than PROVIDER_SYNC_WAIT_SECONDS (it keeps running, for at most PROVIDER_SYNC_TIMEOUT_SECONDS; check /providers/status). Returns 409 while another sync is running. Requires the X-Admin-Key header.
+// @Tags        sync
+// @Produce     json
+// @Param       X-Admin-Key  header   string  true  "Admin key"
+// @Success     200  {object} model.SyncSummary
+// @Success     202  {object} SyncRunningResponse
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Failure     409  {object} map[string]string "A sync is already running"
+// @Router      /sync [post]
+func (h *SyncHandler) TriggerSync(c *gin.Context) {
+	release, ok := provider.TryStartSync()
+	if !ok {
+		middleware.HandleAppError(c, errors.NewConflictError("Sync already in progress", "Wait for the running sync to finish before triggering another"))
+		return
+	}
+
+	// The sync runs detached from the request so a slow run or a client
+	// disconnect doesn't abandon it halfway; the slot is released when it ends
+	// It keeps the request's trace ID, so its logs match the trace_id in the response
+	// syncTimeout still bounds it, so a hung provider can't hold the slot forever
+	syncCtx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), h.syncTimeout)
+	done := make(chan *model.SyncSummary, 1)
+	go func() {
+		defer release()
+		defer cancel()
+		done <- h.syncService.Run(syncCtx)
+	}()
+
+	timer := time.NewTimer(h.syncWait)
+	defer timer.Stop()
+
+	select {
+	case summary := <-done:
+		middleware.JSONSuccess(c, summary)
+	case <-timer.C:
+		middleware.JSONSuccess(c, SyncRunningResponse{
+			Status:  "running",
+			Message: "Sync is still running in the background; check /api/v1/providers/status",
+		}, http.StatusAccepted)
+	case <-c.Request.Context().Done():
+		// Client went away; the sync finishes on its own
+	}
+}
+
+// GetLastSyncDelta handles GET /api/v1/admin/sync/last-delta requests
+// For each provider's last sync, counts content created and updated since it started
+//
+// @Summary     Get last sync delta
+// @Description For every provider's most recent sync, report how many items were new and how many existing items were updated since the sync started. Updated counts only items whose fields changed: items resent unchanged and score recalculations are not counted. Requires the X-Admin-Key header.
+// @Tags        admin
+// @Produce     json
+// @Param       X-Admin-Key  header   string  true  "Admin key"
+// @Success     200  {object} model.SyncDeltaReport
+// @Failure     401  {object} map[string]string "Admin authentication required"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /admin/sync/last-delta [get]
+func (h *SyncHandler) GetLastSyncDelta(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.queryTimeout)
+	defer cancel()
+
+	runs, err := h.syncRepo.GetLatestPerProvider(ctx)
+	if err != nil {
+		middleware.HandleAppError(c, asDatabaseError("get last sync runs", err))
+		return
+	}
+
+	// Provider names for the report
+	providers, err := h.providerRepo.GetAll()
+	if err != nil {
+		middleware.HandleAppError(c, asDatabaseError("get all providers", err))
+		return
+	}
+	names := make(map[int]string, len(providers))
+	for _, p := range providers {
+		names[p.ID] = p.Name
+	}
+
+	report := model.SyncDeltaReport{Providers: make([]model.SyncDelta, 0, len(runs))}
+	for _, run := range runs {
+		created, updated, err := h.contentRepo.CountChangedSince(ctx, run.ProviderID, run.StartedAt)
+		if err != nil {
+			middleware.HandleAppError(c, asDatabaseError("count changed content", err))
+			return
+		}
+
+		report.Providers = append(report.Providers, model.SyncDelta{
+			ProviderID:   run.ProviderID,
+			ProviderName: names[run.ProviderID],
+			StartedAt:    run.StartedAt,
+			FinishedAt:   run.FinishedAt,
+			Status:       run.Status,
+			ErrorMessage: run.ErrorMessage,
+			ItemsFetched: run.ItemsFetched,
+			NewItems:     created,
+			UpdatedItems: updated,
+		})
+		report.TotalNew += created
+		report.TotalUpdated += updated
+	}
+
+	middleware.JSONSuccess(c, report)
+}
+
+// asDatabaseError passes AppErrors through and wraps anything else as a database error
+func asDatabaseError(operation string, err error) *errors.AppError {
+	if appErr := errors.AsAppError(err); appErr != nil {
+		return appErr
+	}
+	return errors.NewDatabaseError(operation, err)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/internal/provider"
+	"testing"
+	"time"
+)
+
+// fakeSyncRunner runs for a fixed time, or until its context ends, and reports the context it got
+type fakeSyncRunner struct {
+	runFor time.Duration
+	ctxs   chan context.Context
+}
+
+func (f *fakeSyncRunner) Run(ctx context.Context) *model.SyncSummary {
+	f.ctxs <- ctx
+	select {
+	case <-time.After(f.runFor):
+	case <-ctx.Done():
+	}
+	return &model.SyncSummary{}
+}
+
+func serveTriggerSync(h *SyncHandler) *httptest.ResponseRecorder {
+	return serve(newTestRouter(http.MethodPost, "/sync", h.TriggerSync), http.MethodPost, "/sync", "")
+}
+
+// waitForSyncSlot waits until the process-wide sync slot is free again
+func waitForSyncSlot(t *testing.T) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if release, ok := provider.TryStartSync(); ok {
+			release()
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("sync slot was never released")
+}
+
+func TestTriggerSyncWaitsForQuickRun(t *testing.T) {
+	runner := &fakeSyncRunner{ctxs: make(chan context.Context, 1)}
+	h := &SyncHandler{syncService: runner, syncWait: time.Second, syncTimeout: time.Minute}
+
+	if w := serveTriggerSync(h); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+	}
+	waitForSyncSlot(t)
+}
+
+func TestTriggerSyncBoundsBackgroundRun(t *testing.T) {
+	runner := &fakeSyncRunner{runFor: time.Hour, ctxs: make(chan context.Context, 1)}
+	h := &SyncHandler{syncService: runner, syncWait: 10 * time.Millisecond, syncTimeout: 50 * time.Millisecond}
+
+	if w := serveTriggerSync(h); w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", w.Code, w.Body)
+	}
+
+	ctx := <-runner.ctxs
+	if _, ok := ctx.Deadline(); !ok {
+		t.Fatal("background sync runs without a deadline")
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("background sync outlived its timeout")
+	}
+	waitForSyncSlot(t)
+}
+
+func TestTriggerSyncConflictsWithRunningSync(t *testing.T) {
+	release, ok := provider.TryStartSync()
+	if !ok {
+		t.Fatal("sync slot already taken")
+	}
+	defer release()
+
+	runner := &fakeSyncRunner{ctxs: make(chan context.Context, 1)}
+	h := &SyncHandler{syncService: runner, syncWait: time.Second, syncTimeout: time.Minute}
+
+	if w := serveTriggerSync(h); w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409: %s", w.Code, w.Body)
+	}
+	if len(runner.ctxs) != 0 {
+		t.Error("a second sync ran while one was in progress")
+	}
+}
@@ -0,0 +1,74 @@
+// tag_handler.go - HTTP handlers for tag endpoints
+// Lists the distinct tags for tag clouds and filter sidebars
+
+package handler
+
+import (
+	"context"
+	"search-engine/backend/internal/errors"
+	"search-engine/backend/internal/middleware"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/internal/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tagCounter is the part of ContentTagRepository that GetTags needs
+type tagCounter interface {
+	GetAllWithCounts(ctx context.Context, prefix string, limit int) ([]model.TagCount, error)
+}
+
+// TagHandler handles tag-related HTTP requests
+type TagHandler struct {
+	counter      tagCounter
+	queryTimeout time.Duration
+}
+
+// NewTagHandler creates a new TagHandler instance
+// queryTimeout bounds the tag count query (default: 5s)
+func NewTagHandler(tagRepo *repository.ContentTagRepository, queryTimeout time.Duration) *TagHandler {
+	if queryTimeout <= 0 {
+		queryTimeout = 5 * time.Second
+	}
+	return &TagHandler{
+		counter:      tagRepo,
+		queryTimeout: queryTimeout,
+	}
+}
+
+// GetTags handles GET /api/v1/tags requests
+// Returns distinct tags with how many content items carry each, most used first
+//
+// @Summary     List tags
+// @Description List distinct tags with the number of content items carrying each, most used first. Tags differing only in case are counted together.
+// @Tags        tags
+// @Accept      json
+// @Produce     json
+// @Param       limit   query    int     false  "Most tags returned (default: 50, max: 500)"
+// @Param       prefix  query    string  false  "Only tags starting with this, for typeahead"
+// @Success     200  {array}   model.TagCount
+// @Failure     400  {object} map[string]string "Invalid query parameters"
+// @Failure     500  {object} map[string]string "Internal server error"
+// @Router      /tags [get]
+func (h *TagHandler) GetTags(c *gin.Context) {
+	var req model.TagListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.HandleAppError(c, errors.NewFieldValidationError("Invalid query parameters", map[string]string{
+			"limit": "limit must be an integer",
+		}))
+		return
+	}
+	req.Validate()
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.queryTimeout)
+	defer cancel()
+
+	counts, err := h.counter.GetAllWithCounts(ctx, req.Prefix, req.Limit)
+	if err != nil {
+		middleware.HandleAppError(c, asDatabaseError("get tag counts", err))
+		return
+	}
+
+	middleware.JSONSuccess(c, counts)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"search-engine/backend/internal/model"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeTagCounter records the filters it was asked for
+type fakeTagCounter struct {
+	counts    []model.TagCount
+	gotPrefix string
+	gotLimit  int
+}
+
+func (f *fakeTagCounter) GetAllWithCounts(_ context.Context, prefix string, limit int) ([]model.TagCount, error) {
+	f.gotPrefix, f.gotLimit = prefix, limit
+	return f.counts, nil
+}
+
+func newTagsRouter(counter *fakeTagCounter) *gin.Engine {
+	h := &TagHandler{counter: counter, queryTimeout: time.Second}
+	return newTestRouter(http.MethodGet, "/tags", h.GetTags)
+}
+
+func TestGetTags(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantPrefix string
+		wantLimit  int
+	}{
+		{"defaults", "", "", model.DefaultTagListLimit},
+		{"prefix and limit", "?prefix=%20kub%20&limit=5", "kub", 5},
+		{"limit capped", "?limit=100000", "", model.MaxTagListLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := &fakeTagCounter{counts: []model.TagCount{{Tag: "kubernetes", Count: 3}}}
+
+			w := serve(newTagsRouter(counter), http.MethodGet, "/tags"+tt.query, "")
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, http.StatusOK, w.Body)
+			}
+			if counter.gotPrefix != tt.wantPrefix || counter.gotLimit != tt.wantLimit {
+				t.Errorf("GetAllWithCounts(%q, %d), want (%q, %d)", counter.gotPrefix, counter.gotLimit, tt.wantPrefix, tt.wantLimit)
+			}
+
+			var body struct {
+				Data []model.TagCount `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if len(body.Data) != 1 || body.Data[0] != (model.TagCount{Tag: "kubernetes", Count: 3}) {
+				t.Errorf("data = %+v, want the counter's tags", body.Data)
+			}
+		})
+	}
+}
+
+func TestGetTagsInvalidLimit(t *testing.T) {
+	if w := serve(newTagsRouter(&fakeTagCounter{}), http.MethodGet, "/tags?limit=abc", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"search-engine/backend/internal/errors"
+	"search-engine/backend/internal/model"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// failingSearcher fails every search with err
+type failingSearcher struct {
+	err error
+}
+
+func (f failingSearcher) Search(ctx context.Context, req *model.SearchRequest) (*model.SearchResponse, error) {
+	return nil, f.err
+}
+
+// stalledContentReader blocks lookups until their context is done
+type stalledContentReader struct{}
+
+func (stalledContentReader) GetByID(ctx context.Context, id int64) (*model.Content, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (stalledContentReader) GetTagsByContentID(ctx context.Context, contentID int64, order model.TagOrder) ([]string, error) {
+	return nil, nil
+}
+
+// serveWithContext serves a GET of path under ctx
+func serveWithContext(ctx context.Context, router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx))
+	return w
+}
+
+func cancelledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestSearchTimeoutStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		err        error
+		wantStatus int
+	}{
+		{"query deadline exceeded", context.Background(), context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{"query timeout from the service", context.Background(), errors.NewQueryTimeoutError("search"), http.StatusGatewayTimeout},
+		{"client disconnected", cancelledContext(), context.Canceled, errors.StatusClientClosedRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &SearchHandler{searcher: failingSearcher{err: tt.err}}
+			router := newTestRouter(http.MethodGet, "/search", h.Search)
+
+			if w := serveWithContext(tt.ctx, router, "/search?query=go"); w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func TestGetContentByIDTimeoutStatus(t *testing.T) {
+	expiringRequest, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantStatus int
+	}{
+		{"query budget exceeded", context.Background(), http.StatusGatewayTimeout},
+		{"client disconnected", cancelledContext(), errors.StatusClientClosedRequest},
+		{"request deadline exceeded", expiringRequest, http.StatusRequestTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The request deadline case expires well before this budget
+			h := &ContentHandler{reader: stalledContentReader{}, simpleQueryTimeout: 50 * time.Millisecond}
+			router := newTestRouter(http.MethodGet, "/content/:id", h.GetContentByID)
+
+			if w := serveWithContext(tt.ctx, router, "/content/7"); w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+// admin_auth.go - Admin authentication middleware
+// Guards operator-only endpoints with a shared admin key
+package middleware
+
+import (
+	"crypto/subtle"
+	"search-engine/backend/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminKeyHeader is the request header carrying the admin key
+const AdminKeyHeader = "X-Admin-Key"
+
+// AdminAuthMiddleware rejects requests that don't present the configured admin key
+// With no key configured every request is rejected, so admin endpoints are off by default
+func AdminAuthMiddleware(adminKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(AdminKeyHeader)
+		if adminKey == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(adminKey)) != 1 {
+			HandleAppError(c, errors.NewUnauthorizedError("Admin authentication required"))
+			return
+		}
+		c.Next()
+	}
+}
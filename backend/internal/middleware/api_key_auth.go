@@ -0,0 +1,63 @@
+// api_key_auth.go - API key authentication middleware
+// Optionally restricts the whole API to clients holding one of the configured keys
+package middleware
+
+import (
+	"crypto/subtle"
+	"search-engine/backend/internal/errors"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader is the request header carrying an API key
+// Authorization: Bearer <key> is accepted as well
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthMiddleware rejects requests that don't present one of keys
+// Requests to skipPaths (health probes) pass without a key. With no keys
+// configured every other request is rejected, so a misconfiguration fails closed
+func APIKeyAuthMiddleware(keys []string, skipPaths ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if slices.Contains(skipPaths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		provided := requestAPIKey(c)
+		if provided == "" {
+			HandleAppError(c, errors.NewUnauthorizedError("API key required"))
+			return
+		}
+		if !validAPIKey(keys, provided) {
+			HandleAppError(c, errors.NewUnauthorizedError("Invalid API key"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// requestAPIKey returns the key from X-API-Key, or from an Authorization bearer token
+func requestAPIKey(c *gin.Context) string {
+	if key := strings.TrimSpace(c.GetHeader(APIKeyHeader)); key != "" {
+		return key
+	}
+	scheme, token, ok := strings.Cut(strings.TrimSpace(c.GetHeader("Authorization")), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// validAPIKey compares provided with every key in constant time
+// All keys are checked even after a match, so timing doesn't reveal which one matched
+func validAPIKey(keys []string, provided string) bool {
+	match := 0
+	for _, key := range keys {
+		if key != "" {
+			match |= subtle.ConstantTimeCompare([]byte(provided), []byte(key))
+		}
+	}
+	return match == 1
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIKeyAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandlerMiddleware())
+	router.Use(APIKeyAuthMiddleware([]string{"key-one", "key-two"}, "/health"))
+	router.GET("/api/v1/search", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name     string
+		path     string
+		headers  map[string]string
+		wantCode int
+	}{
+		{"X-API-Key", "/api/v1/search", map[string]string{"X-API-Key": "key-one"}, http.StatusOK},
+		{"bearer token", "/api/v1/search", map[string]string{"Authorization": "Bearer key-two"}, http.StatusOK},
+		{"lowercase bearer", "/api/v1/search", map[string]string{"Authorization": "bearer key-one"}, http.StatusOK},
+		{"invalid key", "/api/v1/search", map[string]string{"X-API-Key": "key-three"}, http.StatusUnauthorized},
+		{"key prefix", "/api/v1/search", map[string]string{"X-API-Key": "key"}, http.StatusUnauthorized},
+		{"other auth scheme", "/api/v1/search", map[string]string{"Authorization": "Basic key-one"}, http.StatusUnauthorized},
+		{"missing key", "/api/v1/search", nil, http.StatusUnauthorized},
+		{"health without key", "/health", nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusUnauthorized {
+				return
+			}
+			var body struct {
+				Error struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Error.Code != "UNAUTHORIZED" {
+				t.Errorf("body = %s, want an UNAUTHORIZED error", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthMiddlewareWithoutKeysRejects(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandlerMiddleware())
+	router.Use(APIKeyAuthMiddleware(nil))
+	router.GET("/api/v1/search", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search", nil)
+	req.Header.Set(APIKeyHeader, "anything")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401 when no keys are configured", w.Code)
+	}
+}
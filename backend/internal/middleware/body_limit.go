@@ -0,0 +1,28 @@
+// body_limit.go - Request body size limit
+// Keeps a client from tying up memory with an oversized POST, PUT or PATCH body
+package middleware
+
+import (
+	"net/http"
+	"search-engine/backend/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimitMiddleware caps request bodies at maxBytes; 0 or less disables the limit
+// A body declaring a larger Content-Length is rejected with 413 before it is read;
+// reading past the limit of any other body fails, so the handler's bind reports it
+func BodyLimitMiddleware(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if maxBytes <= 0 {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > maxBytes {
+			HandleAppError(c, errors.NewRequestTooLargeError(maxBytes))
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
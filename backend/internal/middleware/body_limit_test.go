@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newBodyLimitRouter echoes the length of the body it could read, or 400 when reading failed
+func newBodyLimitRouter(maxBytes int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandlerMiddleware())
+	router.Use(BodyLimitMiddleware(maxBytes))
+	router.POST("/content", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusOK, "%d", len(body))
+	})
+	return router
+}
+
+func TestBodyLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBytes   int64
+		body       string
+		chunked    bool // No Content-Length, so the limit applies while reading
+		wantStatus int
+	}{
+		{"within the limit", 16, strings.Repeat("a", 16), false, http.StatusOK},
+		{"declared over the limit", 16, strings.Repeat("a", 17), false, http.StatusRequestEntityTooLarge},
+		{"chunked within the limit", 16, strings.Repeat("a", 16), true, http.StatusOK},
+		{"chunked over the limit", 16, strings.Repeat("a", 17), true, http.StatusBadRequest},
+		{"limit disabled", 0, strings.Repeat("a", 1024), false, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/content", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			newBodyLimitRouter(tt.maxBytes).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusRequestEntityTooLarge {
+				return
+			}
+			var body struct {
+				Error struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.Error.Code != "REQUEST_TOO_LARGE" {
+				t.Errorf("error code = %q, want REQUEST_TOO_LARGE", body.Error.Code)
+			}
+		})
+	}
+}
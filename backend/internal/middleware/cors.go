@@ -4,23 +4,51 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// CORSMiddleware adds CORS headers to responses so that the React frontend
-// (running on a different origin, e.g. http://localhost:3000) can call the API.
+// AnyOrigin in the allowed origins admits every origin, without credentials.
+const AnyOrigin = "*"
+
+// CORSMiddleware adds CORS headers to responses so that browser frontends on the
+// allowed origins (e.g. the React app on http://localhost:3000) can call the API.
 //
-// For a real production app, you would restrict AllowedOrigins instead of "*".
-func CORSMiddleware() gin.HandlerFunc {
+// A request's Origin is echoed back only when it is in allowedOrigins, and only
+// then are credentials allowed. With AnyOrigin in the list, other origins get the
+// literal "*" and no credentials: browsers reject "*" combined with credentials.
+func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
+	anyOrigin := false
+	origins := make([]string, 0, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == AnyOrigin {
+			anyOrigin = true
+			continue
+		}
+		origins = append(origins, normalizeOrigin(origin))
+	}
+
 	return func(c *gin.Context) {
-		// Allow all origins for now; tighten this when you know your frontend origin(s).
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
+		origin := c.GetHeader("Origin")
+		if origin != "" {
+			// The response depends on the Origin, so shared caches must key on it.
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+
+		switch {
+		case origin != "" && slices.Contains(origins, normalizeOrigin(origin)):
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Credentials", "true")
+		case anyOrigin:
+			c.Header("Access-Control-Allow-Origin", AnyOrigin)
+		}
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Requested-With")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 
-		// Handle preflight requests quickly.
+		// Handle preflight requests quickly. Without an Access-Control-Allow-Origin
+		// header the browser refuses the actual request for a disallowed origin.
 		if c.Request.Method == http.MethodOptions {
 			c.AbortWithStatus(http.StatusNoContent)
 			return
@@ -30,3 +58,8 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// normalizeOrigin lowercases an origin and drops a trailing slash, as scheme and host are case-insensitive.
+func normalizeOrigin(origin string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+}
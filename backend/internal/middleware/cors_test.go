@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCORSRouter(allowedOrigins []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORSMiddleware(allowedOrigins))
+	router.GET("/search", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return router
+}
+
+func TestCORSOrigins(t *testing.T) {
+	tests := []struct {
+		name            string
+		allowed         []string
+		origin          string
+		wantOrigin      string
+		wantCredentials string
+	}{
+		{"allowed origin", []string{"http://localhost:3000", "https://app.example.com"}, "https://app.example.com", "https://app.example.com", "true"},
+		{"allowed origin, different case", []string{"https://App.example.com/"}, "https://app.example.com", "https://app.example.com", "true"},
+		{"disallowed origin", []string{"http://localhost:3000"}, "https://evil.example.com", "", ""},
+		{"no origin", []string{"http://localhost:3000"}, "", "", ""},
+		{"wildcard", []string{"*"}, "https://evil.example.com", "*", ""},
+		{"wildcard with a listed origin", []string{"*", "http://localhost:3000"}, "http://localhost:3000", "http://localhost:3000", "true"},
+		{"wildcard with an unlisted origin", []string{"*", "http://localhost:3000"}, "https://other.example.com", "*", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newCORSRouter(tt.allowed)
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/search", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tt.wantCredentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, tt.wantCredentials)
+			}
+			if tt.origin != "" && w.Header().Get("Vary") != "Origin" {
+				t.Errorf("Vary = %q, want Origin", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	router := newCORSRouter([]string{"http://localhost:3000"})
+
+	for _, origin := range []string{"http://localhost:3000", "https://evil.example.com"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodOptions, "/search", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNoContent {
+			t.Errorf("%s preflight: status = %d, want 204", origin, w.Code)
+		}
+		allowed := w.Header().Get("Access-Control-Allow-Origin") == origin
+		if want := origin == "http://localhost:3000"; allowed != want {
+			t.Errorf("%s preflight: origin allowed = %t, want %t", origin, allowed, want)
+		}
+	}
+}
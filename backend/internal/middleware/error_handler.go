@@ -12,6 +12,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// retryAfterSeconds is the Retry-After value sent with retryable errors
+const retryAfterSeconds = "5"
+
 // ErrorHandlerMiddleware returns a middleware that handles errors consistently
 func ErrorHandlerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -48,13 +51,24 @@ func handleError(c *gin.Context, err error) {
 		// All other errors: details are logged but not exposed to client for security
 		if appErr.Details != "" {
 			switch appErr.Code {
-			case errors.ErrorCodeValidation, errors.ErrorCodeInvalidInput, errors.ErrorCodeInvalidID:
-				// Validation errors: Show details (user needs to fix their input)
+			case errors.ErrorCodeValidation, errors.ErrorCodeInvalidInput, errors.ErrorCodeInvalidID, errors.ErrorCodeConflict:
+				// Validation and conflict errors: Show details (user needs to fix their input)
 				errorResponse["details"] = appErr.Details
 			}
 		}
 
-		c.JSON(appErr.StatusCode, gin.H{
+		// Field-level problems are always safe to show: they only echo the client's input
+		if len(appErr.Fields) > 0 {
+			errorResponse["fields"] = appErr.Fields
+		}
+
+		// Retryable errors (e.g. database briefly unreachable) tell clients when to try again
+		if appErr.Retryable {
+			errorResponse["retryable"] = true
+			c.Header("Retry-After", retryAfterSeconds)
+		}
+
+		WriteJSON(c, appErr.StatusCode, gin.H{
 			"error":    errorResponse,
 			"trace_id": traceIDStr,
 		})
@@ -65,7 +79,7 @@ func handleError(c *gin.Context, err error) {
 	if err == sql.ErrNoRows {
 		appErr := errors.NewNotFoundError("Resource")
 		logError(c, appErr, traceIDStr)
-		c.JSON(appErr.StatusCode, gin.H{
+		WriteJSON(c, appErr.StatusCode, gin.H{
 			"error": gin.H{
 				"code":    appErr.Code,
 				"message": appErr.Message,
@@ -76,10 +90,13 @@ func handleError(c *gin.Context, err error) {
 	}
 
 	// Check for context timeout/cancellation
-	if err == context.DeadlineExceeded {
+	if err == context.DeadlineExceeded || err == context.Canceled {
 		appErr := errors.NewRequestTimeoutError()
+		if err == context.Canceled {
+			appErr = errors.NewClientClosedRequestError()
+		}
 		logError(c, appErr, traceIDStr)
-		c.JSON(appErr.StatusCode, gin.H{
+		WriteJSON(c, appErr.StatusCode, gin.H{
 			"error": gin.H{
 				"code":    appErr.Code,
 				"message": appErr.Message,
@@ -95,7 +112,7 @@ func handleError(c *gin.Context, err error) {
 
 	// Never expose internal error details in production
 	// Details are logged but not returned to client
-	c.JSON(appErr.StatusCode, gin.H{
+	WriteJSON(c, appErr.StatusCode, gin.H{
 		"error": gin.H{
 			"code":    appErr.Code,
 			"message": "An error occurred while processing your request. Please try again later.",
@@ -120,11 +137,15 @@ func logError(c *gin.Context, appErr *errors.AppError, traceID string) {
 		logMsg += fmt.Sprintf(" | details=%s", appErr.Details)
 	}
 
+	if len(appErr.Fields) > 0 {
+		logMsg += fmt.Sprintf(" | fields=%v", appErr.Fields)
+	}
+
 	if appErr.Err != nil {
 		logMsg += fmt.Sprintf(" | underlying_error=%v", appErr.Err)
 	}
 
-	log.Printf(logMsg)
+	log.Print(logMsg)
 }
 
 // HandleError is a helper function to set an error in Gin context
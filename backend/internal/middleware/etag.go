@@ -0,0 +1,51 @@
+// etag.go - Conditional GET support
+// Lets handlers tag a response and answer 304 Not Modified to clients that already have it
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETagFor returns a strong ETag for a response payload, hashed from its JSON encoding
+// Hash the data rather than the whole response: the envelope's trace_id changes on every request
+func ETagFor(data interface{}) (string, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// NotModified sets etag on the response and reports whether the request's If-None-Match
+// already holds it, in which case it has answered 304 and the handler must not write a body
+func NotModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	if !etagMatches(c.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, or is *
+// GET conditionals use the weak comparison, so W/ prefixes are ignored
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import "testing"
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		ifNoneMatch string
+		etag        string
+		want        bool
+	}{
+		{"", `"abc"`, false},
+		{`"abc"`, `"abc"`, true},
+		{`"abd"`, `"abc"`, false},
+		{`"x", "abc"`, `"abc"`, true},
+		{`*`, `"abc"`, true},
+		{`W/"abc"`, `"abc"`, true},
+		{`"7-100"`, `W/"7-100"`, true},
+		{`W/"7-100"`, `W/"7-200"`, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.ifNoneMatch, tt.etag); got != tt.want {
+			t.Errorf("etagMatches(%q, %q) = %t, want %t", tt.ifNoneMatch, tt.etag, got, tt.want)
+		}
+	}
+}
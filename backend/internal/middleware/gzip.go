@@ -0,0 +1,143 @@
+// gzip.go - Response compression middleware
+// Gzips large responses for clients that send Accept-Encoding: gzip
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultGzipMinSize is the response size below which GzipMiddleware doesn't compress
+// Gzip's framing and the CPU cost outweigh the savings on small bodies
+const DefaultGzipMinSize = 1024
+
+// GzipConfig configures GzipMiddleware
+type GzipConfig struct {
+	MinSize   int      // Bodies smaller than this many bytes are sent as is (default: DefaultGzipMinSize)
+	SkipPaths []string // Paths never compressed, e.g. /metrics whose scrapers negotiate on their own
+}
+
+// GzipMiddleware compresses response bodies of at least cfg.MinSize bytes
+// for requests whose Accept-Encoding allows gzip
+// The body is buffered until it reaches MinSize, so the decision is made
+// before any byte is sent; larger bodies are then streamed through gzip
+func GzipMiddleware(cfg GzipConfig) gin.HandlerFunc {
+	if cfg.MinSize <= 0 {
+		cfg.MinSize = DefaultGzipMinSize
+	}
+
+	return func(c *gin.Context) {
+		if slices.Contains(cfg.SkipPaths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		// The body depends on Accept-Encoding from here on, for caches too
+		c.Header("Vary", "Accept-Encoding")
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		w := &gzipResponseWriter{ResponseWriter: c.Writer, minSize: cfg.MinSize}
+		c.Writer = w
+		defer w.finish()
+
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows a gzip response
+// gzip or * with a q-value other than 0 counts; "identity" alone does not
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter holds the body back until it knows whether to compress it
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	minSize int
+	buf     bytes.Buffer
+	gz      *gzip.Writer // Set once the body reached minSize
+	raw     bool         // Set when the body is passed through uncompressed
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	switch {
+	case w.gz != nil:
+		return w.gz.Write(data)
+	case w.raw:
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf.Write(data)
+	if w.buf.Len() < w.minSize {
+		return len(data), nil
+	}
+
+	// A handler that encoded the body itself keeps it as is
+	if w.Header().Get("Content-Encoding") != "" {
+		w.raw = true
+		return len(data), w.flushBuffer(w.ResponseWriter)
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Del("Content-Length")
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+	return len(data), w.flushBuffer(w.gz)
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends what has been compressed so far; a body still below minSize stays buffered
+func (w *gzipResponseWriter) Flush() {
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// flushBuffer writes the buffered start of the body to dst
+func (w *gzipResponseWriter) flushBuffer(dst io.Writer) error {
+	_, err := dst.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// finish completes the response once the handler chain has returned
+// A body that never reached minSize is written uncompressed
+func (w *gzipResponseWriter) finish() {
+	if w.gz != nil {
+		w.gz.Close()
+		return
+	}
+	if w.buf.Len() > 0 {
+		w.flushBuffer(w.ResponseWriter)
+	}
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"search-engine/backend/internal/model"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// largeSearchResponse builds a full page of 100 results with tags, like a max per_page search
+func largeSearchResponse() *model.SearchResponse {
+	published := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	results := make([]model.Content, 100)
+	for i := range results {
+		results[i] = model.Content{
+			ID:          int64(i + 1),
+			ProviderID:  1,
+			ExternalID:  fmt.Sprintf("v%d", i+1),
+			Title:       fmt.Sprintf("Go Programming Tutorial part %d", i+1),
+			Type:        model.ContentTypeVideo,
+			Views:       1000 * i,
+			Likes:       10 * i,
+			PublishedAt: published.Add(time.Duration(i) * time.Hour),
+			Score:       float64(i) / 3,
+			Tags:        []string{"go", "programming", "tutorial", "backend"},
+		}
+	}
+	return &model.SearchResponse{Results: results, Total: 5000, Page: 1, PerPage: 100, TotalPages: 50}
+}
+
+func newGzipRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(GzipMiddleware(GzipConfig{SkipPaths: []string{"/metrics"}}))
+	router.Use(ErrorHandlerMiddleware())
+	router.GET("/search", func(c *gin.Context) { JSONSuccess(c, largeSearchResponse()) })
+	router.GET("/metrics", func(c *gin.Context) { c.String(http.StatusOK, "%s", bytes.Repeat([]byte("metric 1\n"), 500)) })
+	router.GET("/small", func(c *gin.Context) { JSONSuccess(c, gin.H{"ok": true}) })
+	return router
+}
+
+func getWithEncoding(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGzipMiddlewareCompressesLargeSearchResult(t *testing.T) {
+	router := newGzipRouter()
+
+	plain := getWithEncoding(router, "/search", "")
+	compressed := getWithEncoding(router, "/search", "br, gzip;q=0.8")
+
+	if plain.Header().Get("Content-Encoding") != "" {
+		t.Errorf("uncompressed response has Content-Encoding %q", plain.Header().Get("Content-Encoding"))
+	}
+	if compressed.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", compressed.Header().Get("Content-Encoding"))
+	}
+	for name, w := range map[string]*httptest.ResponseRecorder{"plain": plain, "gzip": compressed} {
+		if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+			t.Errorf("%s Vary = %q, want Accept-Encoding", name, got)
+		}
+		if w.Code != http.StatusOK {
+			t.Errorf("%s status = %d, want 200", name, w.Code)
+		}
+	}
+
+	t.Logf("search page: %d bytes plain, %d bytes gzipped", plain.Body.Len(), compressed.Body.Len())
+	if compressed.Body.Len()*4 > plain.Body.Len() {
+		t.Errorf("gzipped body is %d bytes for %d plain, want it at most a quarter of the size",
+			compressed.Body.Len(), plain.Body.Len())
+	}
+
+	reader, err := gzip.NewReader(compressed.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read gzipped body: %v", err)
+	}
+	if !bytes.Equal(decoded, plain.Body.Bytes()) {
+		t.Error("decompressed body differs from the uncompressed response")
+	}
+}
+
+func TestGzipMiddlewareSkips(t *testing.T) {
+	router := newGzipRouter()
+
+	tests := []struct {
+		name, path, acceptEncoding string
+	}{
+		{"small response", "/small", "gzip"},
+		{"skipped path", "/metrics", "gzip"},
+		{"gzip refused", "/search", "gzip;q=0, identity"},
+		{"other encodings only", "/search", "br, deflate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := getWithEncoding(router, tt.path, tt.acceptEncoding)
+			if got := w.Header().Get("Content-Encoding"); got != "" {
+				t.Errorf("Content-Encoding = %q, want none", got)
+			}
+			if w.Code != http.StatusOK || w.Body.Len() == 0 {
+				t.Errorf("status %d with %d body bytes, want the plain 200 body", w.Code, w.Body.Len())
+			}
+		})
+	}
+}
@@ -4,10 +4,10 @@ package middleware
 
 import (
 	"log"
+	"search-engine/backend/internal/trace"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 // LoggerMiddleware logs basic request/response information and attaches
@@ -17,8 +17,10 @@ func LoggerMiddleware() gin.HandlerFunc {
 		start := time.Now()
 
 		// Generate a simple trace ID and add to context + response headers.
-		traceID := uuid.New().String()
+		// The request context carries it too, for work that outlives the handler.
+		traceID := trace.NewID()
 		c.Set("trace_id", traceID)
+		c.Request = c.Request.WithContext(trace.WithID(c.Request.Context(), traceID))
 		c.Writer.Header().Set("X-Trace-ID", traceID)
 
 		path := c.Request.URL.Path
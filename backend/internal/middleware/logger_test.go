@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"search-engine/backend/internal/trace"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLoggerMiddlewarePutsTraceIDInRequestContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(LoggerMiddleware())
+
+	var fromContext string
+	router.GET("/sync", func(c *gin.Context) {
+		fromContext = trace.ID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sync", nil))
+
+	header := w.Header().Get("X-Trace-ID")
+	if header == "" || fromContext != header {
+		t.Errorf("request context trace ID = %q, want the X-Trace-ID header %q", fromContext, header)
+	}
+}
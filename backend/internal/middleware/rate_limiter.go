@@ -3,7 +3,9 @@
 package middleware
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -12,82 +14,213 @@ import (
 
 // simpleTokenBucket is a very small in-memory token bucket
 // used for IP-based or key-based rate limiting.
+// Tokens are refilled lazily from the time elapsed since the last refill,
+// so a bucket needs no goroutine and refills smoothly rather than once per interval.
 type simpleTokenBucket struct {
-	capacity     int
-	tokens       int
-	refillRate   int          // tokens per interval
-	refillTicker *time.Ticker // refill interval
-	mu           sync.Mutex
+	capacity   float64
+	tokens     float64
+	refillRate float64 // tokens per interval
+	interval   time.Duration
+	lastRefill time.Time // when tokens was last brought up to date
+	lastSeen   time.Time // last lookup by ipRateLimiter, for idle eviction
+	now        func() time.Time
+	mu         sync.Mutex
 }
 
-func newSimpleTokenBucket(capacity, refillRate int, interval time.Duration) *simpleTokenBucket {
+func newSimpleTokenBucket(capacity, refillRate int, interval time.Duration, now func() time.Time) *simpleTokenBucket {
 	if capacity <= 0 {
 		capacity = 60
 	}
 	if refillRate <= 0 {
 		refillRate = capacity
 	}
-	tb := &simpleTokenBucket{
-		capacity:   capacity,
-		tokens:     capacity,
-		refillRate: refillRate,
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if now == nil {
+		now = time.Now
+	}
+	start := now()
+	return &simpleTokenBucket{
+		capacity:   float64(capacity),
+		tokens:     float64(capacity),
+		refillRate: float64(refillRate),
+		interval:   interval,
+		lastRefill: start,
+		lastSeen:   start,
+		now:        now,
 	}
-	tb.refillTicker = time.NewTicker(interval)
-	go func() {
-		for range tb.refillTicker.C {
-			tb.mu.Lock()
-			tb.tokens += tb.refillRate
-			if tb.tokens > tb.capacity {
-				tb.tokens = tb.capacity
-			}
-			tb.mu.Unlock()
-		}
-	}()
-	return tb
 }
 
 func (tb *simpleTokenBucket) Allow() bool {
+	return tb.Take().allowed
+}
+
+// bucketDecision is the outcome of one Take call, with what the rate limit headers report
+type bucketDecision struct {
+	allowed    bool
+	remaining  int           // Whole tokens left after this call
+	retryAfter time.Duration // Until the next whole token; 0 when one is available
+	resetAfter time.Duration // Until the bucket is full again
+}
+
+// Take spends a token if one is available and reports the bucket's state
+func (tb *simpleTokenBucket) Take() bucketDecision {
 	tb.mu.Lock()
 	defer tb.mu.Unlock()
-	if tb.tokens <= 0 {
-		return false
+	tb.refill(tb.now())
+
+	d := bucketDecision{allowed: tb.tokens >= 1}
+	if d.allowed {
+		tb.tokens--
+	}
+	d.remaining = int(tb.tokens)
+	if tb.tokens < 1 {
+		d.retryAfter = tb.timeToEarn(1 - tb.tokens)
+	}
+	d.resetAfter = tb.timeToEarn(tb.capacity - tb.tokens)
+	return d
+}
+
+// timeToEarn returns how long the bucket takes to earn n tokens
+func (tb *simpleTokenBucket) timeToEarn(n float64) time.Duration {
+	return time.Duration(n / tb.refillRate * float64(tb.interval))
+}
+
+// refill adds the tokens earned since lastRefill, up to capacity
+// The caller must hold tb.mu
+func (tb *simpleTokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(tb.lastRefill)
+	if elapsed <= 0 {
+		return
 	}
-	tb.tokens--
-	return true
+	tb.tokens = min(tb.capacity, tb.tokens+elapsed.Seconds()/tb.interval.Seconds()*tb.refillRate)
+	tb.lastRefill = now
+}
+
+// touch records that the bucket was just used
+func (tb *simpleTokenBucket) touch(now time.Time) {
+	tb.mu.Lock()
+	tb.lastSeen = now
+	tb.mu.Unlock()
+}
+
+// idleSince reports whether the bucket hasn't been used since cutoff
+func (tb *simpleTokenBucket) idleSince(cutoff time.Time) bool {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.lastSeen.Before(cutoff)
 }
 
+// DefaultRateLimiterIdleTimeout is how long an IP's bucket is kept without requests
+const DefaultRateLimiterIdleTimeout = 10 * time.Minute
+
 // RateLimiterConfig controls how the rate limiter behaves.
 type RateLimiterConfig struct {
 	RequestsPerMinute int
+	IdleTimeout       time.Duration // Buckets idle this long are evicted (default: 10m)
+}
+
+// ipRateLimiter keeps one token bucket per client IP
+// Buckets of IPs that stop sending requests are evicted, so unique IPs
+// don't accumulate buckets forever
+type ipRateLimiter struct {
+	mu                sync.Mutex
+	buckets           map[string]*simpleTokenBucket
+	requestsPerMinute int
+	idleTimeout       time.Duration
+	now               func() time.Time // Time source; replaced in tests
+}
+
+func newIPRateLimiter(requestsPerMinute int, idleTimeout time.Duration) *ipRateLimiter {
+	return &ipRateLimiter{
+		buckets:           make(map[string]*simpleTokenBucket),
+		requestsPerMinute: requestsPerMinute,
+		idleTimeout:       idleTimeout,
+		now:               time.Now,
+	}
+}
+
+// bucket returns the bucket for ip, creating it on first use
+// The bucket is touched under the limiter lock, so a sweep can't evict it
+// between this lookup and the caller's Allow
+func (l *ipRateLimiter) bucket(ip string) *simpleTokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[ip]
+	if !ok {
+		b = newSimpleTokenBucket(l.requestsPerMinute, l.requestsPerMinute, time.Minute, l.now)
+		l.buckets[ip] = b
+	}
+	b.touch(l.now())
+	return b
+}
+
+// evictIdle removes the buckets unused since now - idleTimeout
+// Returns how many buckets were evicted
+func (l *ipRateLimiter) evictIdle(now time.Time) int {
+	cutoff := now.Add(-l.idleTimeout)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	evicted := 0
+	for ip, b := range l.buckets {
+		if b.idleSince(cutoff) {
+			delete(l.buckets, ip)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// len returns the number of tracked IPs
+func (l *ipRateLimiter) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
 }
 
 // NewIPRateLimiterMiddleware limits requests per IP address.
-// Default: 60 req/min per IP.
+// Default: 60 req/min per IP, with bursts of up to a minute's worth.
+// Buckets idle longer than cfg.IdleTimeout are evicted by a background
+// sweep that runs every half timeout.
 func NewIPRateLimiterMiddleware(cfg RateLimiterConfig) gin.HandlerFunc {
 	if cfg.RequestsPerMinute <= 0 {
 		cfg.RequestsPerMinute = 60
 	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = DefaultRateLimiterIdleTimeout
+	}
 
-	var (
-		bucketsMu sync.Mutex
-		buckets   = make(map[string]*simpleTokenBucket)
-	)
+	limiter := newIPRateLimiter(cfg.RequestsPerMinute, cfg.IdleTimeout)
+	go func() {
+		ticker := time.NewTicker(cfg.IdleTimeout / 2)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			limiter.evictIdle(now)
+		}
+	}()
 
+	return limiter.middleware()
+}
+
+// middleware returns the handler that spends a token of the client IP's bucket
+// It sets the same headers as the Redis limiter; Reset is when the bucket is full again
+func (l *ipRateLimiter) middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
+		d := l.bucket(c.ClientIP()).Take()
 
-		bucketsMu.Lock()
-		bucket, ok := buckets[ip]
-		if !ok {
-			bucket = newSimpleTokenBucket(cfg.RequestsPerMinute, cfg.RequestsPerMinute, time.Minute)
-			buckets[ip] = bucket
-		}
-		bucketsMu.Unlock()
+		c.Header("X-RateLimit-Limit", strconv.Itoa(l.requestsPerMinute))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(d.remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(l.now().Add(d.resetAfter).Unix(), 10))
 
-		if !bucket.Allow() {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error":   "rate limit exceeded",
-				"message": "Too many requests, please try again later.",
+		if !d.allowed {
+			retryAfter := waitSeconds(d.retryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.Abort()
+			WriteJSON(c, http.StatusTooManyRequests, gin.H{
+				"error":       "rate limit exceeded",
+				"message":     "Too many requests, please try again later.",
+				"retry_after": retryAfter,
 			})
 			return
 		}
@@ -95,3 +228,9 @@ func NewIPRateLimiterMiddleware(cfg RateLimiterConfig) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// waitSeconds rounds a wait up to whole seconds for the Retry-After header
+// Never less than 1, so clients don't retry immediately into another 429
+func waitSeconds(wait time.Duration) int {
+	return max(1, int(math.Ceil(wait.Seconds())))
+}
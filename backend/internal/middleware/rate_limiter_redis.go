@@ -56,10 +56,13 @@ func NewRedisRateLimiterMiddleware(cfg RedisRateLimiterConfig) gin.HandlerFunc {
 		c.Header("X-RateLimit-Reset", strconv.FormatInt(resetTime.Unix(), 10))
 
 		if !allowed {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			retryAfter := waitSeconds(time.Until(resetTime))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.Abort()
+			WriteJSON(c, http.StatusTooManyRequests, gin.H{
 				"error":       "rate limit exceeded",
 				"message":     "Too many requests, please try again later.",
-				"retry_after": int(time.Until(resetTime).Seconds()),
+				"retry_after": retryAfter,
 			})
 			return
 		}
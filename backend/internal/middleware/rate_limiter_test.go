@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeClock is a manually advanced time source
+type fakeClock struct {
+	t time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time          { return c.t }
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// allowN calls Allow n times and returns how many calls were allowed
+func allowN(b *simpleTokenBucket, n int) int {
+	allowed := 0
+	for i := 0; i < n; i++ {
+		if b.Allow() {
+			allowed++
+		}
+	}
+	return allowed
+}
+
+func TestTokenBucketRefillsWithElapsedTime(t *testing.T) {
+	clock := newFakeClock()
+	b := newSimpleTokenBucket(60, 60, time.Minute, clock.Now)
+
+	// A full minute's worth is available as a burst, then nothing more
+	if got := allowN(b, 61); got != 60 {
+		t.Fatalf("burst allowed %d requests, want 60", got)
+	}
+
+	// 60 per minute earns one token per second
+	clock.Advance(999 * time.Millisecond)
+	if b.Allow() {
+		t.Error("allowed before a whole token was earned")
+	}
+	clock.Advance(time.Millisecond)
+	if !b.Allow() {
+		t.Error("denied after a token was earned")
+	}
+
+	clock.Advance(10 * time.Second)
+	if got := allowN(b, 20); got != 10 {
+		t.Errorf("allowed %d requests after 10s, want 10", got)
+	}
+}
+
+func TestTokenBucketKeepsPartialTokensAcrossCalls(t *testing.T) {
+	clock := newFakeClock()
+	b := newSimpleTokenBucket(60, 60, time.Minute, clock.Now)
+	allowN(b, 60)
+
+	// Denied calls in between must not throw away the fraction earned so far
+	for i := 0; i < 4; i++ {
+		clock.Advance(250 * time.Millisecond)
+		if got, want := b.Allow(), i == 3; got != want {
+			t.Errorf("after %dms Allow() = %t, want %t", (i+1)*250, got, want)
+		}
+	}
+}
+
+func TestTokenBucketCapsAtCapacity(t *testing.T) {
+	clock := newFakeClock()
+	b := newSimpleTokenBucket(60, 60, time.Minute, clock.Now)
+	allowN(b, 60)
+
+	clock.Advance(time.Hour)
+	if got := allowN(b, 100); got != 60 {
+		t.Errorf("allowed %d requests after an hour idle, want the capacity of 60", got)
+	}
+}
+
+func TestIPRateLimiterEvictsIdleBuckets(t *testing.T) {
+	clock := newFakeClock()
+	l := newIPRateLimiter(60, time.Minute)
+	l.now = clock.Now
+	for i := 0; i < 500; i++ {
+		l.bucket(fmt.Sprintf("10.0.%d.%d", i/256, i%256))
+	}
+
+	// One IP keeps sending requests after the others went quiet
+	clock.Advance(2 * time.Minute)
+	active := l.bucket("192.0.2.1")
+
+	if evicted := l.evictIdle(clock.Now()); evicted != 500 {
+		t.Errorf("evictIdle() = %d, want 500", evicted)
+	}
+	if got := l.len(); got != 1 {
+		t.Fatalf("len() after eviction = %d, want only the active IP", got)
+	}
+	if l.bucket("192.0.2.1") != active {
+		t.Error("the active IP's bucket was replaced")
+	}
+}
+
+func TestIPRateLimiterKeepsLimitForRecentIPs(t *testing.T) {
+	clock := newFakeClock()
+	l := newIPRateLimiter(2, time.Minute)
+	l.now = clock.Now
+	for i := 0; i < 2; i++ {
+		if !l.bucket("192.0.2.1").Allow() {
+			t.Fatalf("request %d was limited", i+1)
+		}
+	}
+
+	// A sweep before the idle timeout must not reset the used-up bucket
+	clock.Advance(10 * time.Second)
+	l.evictIdle(clock.Now())
+	if l.bucket("192.0.2.1").Allow() {
+		t.Error("third request allowed after a sweep, want it limited")
+	}
+}
+
+func TestIPRateLimiterMiddlewareHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clock := newFakeClock()
+	l := newIPRateLimiter(2, time.Minute)
+	l.now = clock.Now
+
+	router := gin.New()
+	router.Use(l.middleware())
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		return w
+	}
+
+	// 2 per minute: one token every 30s, so after two requests the bucket
+	// is full again in a minute and the next token comes in 30s
+	tests := []struct {
+		wantCode      int
+		wantRemaining string
+		wantReset     time.Duration
+		wantRetry     string
+	}{
+		{http.StatusOK, "1", 30 * time.Second, ""},
+		{http.StatusOK, "0", time.Minute, ""},
+		{http.StatusTooManyRequests, "0", time.Minute, "30"},
+	}
+	for i, tt := range tests {
+		w := serve()
+		if w.Code != tt.wantCode {
+			t.Fatalf("request %d status = %d, want %d", i+1, w.Code, tt.wantCode)
+		}
+		if got := w.Header().Get("X-RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d X-RateLimit-Limit = %q, want 2", i+1, got)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != tt.wantRemaining {
+			t.Errorf("request %d X-RateLimit-Remaining = %q, want %q", i+1, got, tt.wantRemaining)
+		}
+		wantReset := strconv.FormatInt(clock.Now().Add(tt.wantReset).Unix(), 10)
+		if got := w.Header().Get("X-RateLimit-Reset"); got != wantReset {
+			t.Errorf("request %d X-RateLimit-Reset = %q, want %q", i+1, got, wantReset)
+		}
+		if got := w.Header().Get("Retry-After"); got != tt.wantRetry {
+			t.Errorf("request %d Retry-After = %q, want %q", i+1, got, tt.wantRetry)
+		}
+	}
+
+	// The body carries the same wait as the header
+	clock.Advance(20 * time.Second)
+	w := serve()
+	var body struct {
+		RetryAfter int `json:"retry_after"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode 429 body: %v", err)
+	}
+	if w.Code != http.StatusTooManyRequests || body.RetryAfter != 10 || w.Header().Get("Retry-After") != "10" {
+		t.Errorf("after 20s: status %d, retry_after %d, Retry-After %q; want 429 with 10s to wait",
+			w.Code, body.RetryAfter, w.Header().Get("Retry-After"))
+	}
+}
+
+func TestWaitSeconds(t *testing.T) {
+	tests := []struct {
+		wait time.Duration
+		want int
+	}{
+		{0, 1},
+		{100 * time.Millisecond, 1},
+		{2 * time.Second, 2},
+		{2100 * time.Millisecond, 3},
+	}
+	for _, tt := range tests {
+		if got := waitSeconds(tt.wait); got != tt.want {
+			t.Errorf("waitSeconds(%s) = %d, want %d", tt.wait, got, tt.want)
+		}
+	}
+}
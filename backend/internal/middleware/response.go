@@ -4,6 +4,7 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,6 +18,25 @@ func getTraceID(c *gin.Context) string {
 	return ""
 }
 
+// PrettyParam is the query parameter that asks for indented JSON
+const PrettyParam = "pretty"
+
+// wantsPrettyJSON reports whether the request asked for indented JSON (?pretty=true)
+func wantsPrettyJSON(c *gin.Context) bool {
+	pretty, err := strconv.ParseBool(c.Query(PrettyParam))
+	return err == nil && pretty
+}
+
+// WriteJSON writes obj as compact JSON, or indented when the request has ?pretty=true
+// Every JSON response goes through here so the option works on all endpoints
+func WriteJSON(c *gin.Context, code int, obj interface{}) {
+	if wantsPrettyJSON(c) {
+		c.IndentedJSON(code, obj)
+		return
+	}
+	c.JSON(code, obj)
+}
+
 // SuccessResponse represents a standard success response
 type SuccessResponse struct {
 	Data    interface{} `json:"data,omitempty"`
@@ -37,7 +57,7 @@ func JSONSuccess(c *gin.Context, data interface{}, statusCode ...int) {
 		TraceID: getTraceID(c),
 	}
 
-	c.JSON(code, response)
+	WriteJSON(c, code, response)
 }
 
 // JSONSuccessWithMessage sends a standardized success response with a message
@@ -53,7 +73,7 @@ func JSONSuccessWithMessage(c *gin.Context, message string, data interface{}, st
 		TraceID: getTraceID(c),
 	}
 
-	c.JSON(code, response)
+	WriteJSON(c, code, response)
 }
 
 // JSONCreated sends a 201 Created response (for POST requests)
@@ -0,0 +1,28 @@
+// autocomplete.go - Search-as-you-type request model
+// Title completions for a partially typed query
+package model
+
+import "strings"
+
+// Autocomplete limits
+const (
+	DefaultAutocompleteLimit = 8
+	MaxAutocompleteLimit     = 20
+)
+
+// AutocompleteRequest asks for titles matching what the user has typed so far
+type AutocompleteRequest struct {
+	Query string `form:"q"`     // The text typed so far (required)
+	Limit int    `form:"limit"` // Most titles returned (default: 8, max: 20)
+}
+
+// Validate trims the query and sets a default for an unset or out-of-range limit
+func (r *AutocompleteRequest) Validate() {
+	r.Query = strings.TrimSpace(r.Query)
+	if r.Limit < 1 {
+		r.Limit = DefaultAutocompleteLimit
+	}
+	if r.Limit > MaxAutocompleteLimit {
+		r.Limit = MaxAutocompleteLimit
+	}
+}
@@ -3,6 +3,7 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -16,6 +17,9 @@ const (
 	ContentTypeArticle ContentType = "article"
 )
 
+// ContentTypes lists every supported content type
+var ContentTypes = []ContentType{ContentTypeVideo, ContentTypeArticle}
+
 // Content represents a content item from any provider
 // This is the standardized format that unifies data from different providers
 // It matches the database schema in the contents table
@@ -39,9 +43,18 @@ type Content struct {
 	Comments    int  `json:"comments,omitempty" db:"comments"`
 
 	// Common fields
+	// All times are UTC: providers' dates are converted on parse and the DB session runs in UTC
 	PublishedAt time.Time `json:"published_at" db:"published_at"`
 	Score       float64   `json:"score" db:"score"`
 
+	// PublishedAtClamped marks a PublishedAt a sync clamped from a future date
+	// An item already stored keeps its published_at instead of being re-stamped on every sync
+	PublishedAtClamped bool `json:"-"`
+
+	// LastSyncedAt is set only when a provider sync writes the row
+	// Unlike UpdatedAt it doesn't move on internal writes such as score recalcs
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty" db:"last_synced_at"`
+
 	// Timestamps
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
@@ -49,6 +62,52 @@ type Content struct {
 	// Related data (loaded separately)
 	Tags     []string  `json:"tags,omitempty"`     // Tags associated with this content
 	Provider *Provider `json:"provider,omitempty"` // Provider information (optional)
+
+	// HighlightedTitle is the HTML-escaped title with query matches marked
+	// Set only on search results of requests with highlight=true
+	HighlightedTitle string `json:"highlighted_title,omitempty"`
+}
+
+// MarshalJSON serializes the metrics that belong to the content type
+// Videos always include views and likes and articles reactions and comments,
+// even when zero, so "0 views" is distinguishable from "not a video"
+// Metrics of the other type are omitted
+func (c Content) MarshalJSON() ([]byte, error) {
+	// contentFields has Content's fields but not its methods, avoiding recursion
+	type contentFields Content
+
+	out := struct {
+		contentFields
+		Views     *int `json:"views,omitempty"`
+		Likes     *int `json:"likes,omitempty"`
+		Reactions *int `json:"reactions,omitempty"`
+		Comments  *int `json:"comments,omitempty"`
+	}{contentFields: contentFields(c)}
+
+	switch c.Type {
+	case ContentTypeVideo:
+		out.Views, out.Likes = &c.Views, &c.Likes
+		out.ReadingTime = nil
+	case ContentTypeArticle:
+		out.Reactions, out.Comments = &c.Reactions, &c.Comments
+		out.DurationSeconds = nil
+	default:
+		// Unknown type: keep the old behavior of only emitting non-zero metrics
+		out.Views = nonZero(c.Views)
+		out.Likes = nonZero(c.Likes)
+		out.Reactions = nonZero(c.Reactions)
+		out.Comments = nonZero(c.Comments)
+	}
+
+	return json.Marshal(out)
+}
+
+// nonZero returns a pointer to v, or nil when v is zero
+func nonZero(v int) *int {
+	if v == 0 {
+		return nil
+	}
+	return &v
 }
 
 // IsVideo returns true if content type is video
@@ -73,3 +132,23 @@ func (c *Content) GetDurationString() string {
 	seconds := *c.DurationSeconds % 60
 	return fmt.Sprintf("%02d:%02d", minutes, seconds)
 }
+
+// Similar content limits for GET /content/:id/similar
+const (
+	DefaultSimilarLimit = 10
+	MaxSimilarLimit     = 50
+)
+
+// SimilarContent is a content item related to another by shared tags
+// The item is nested rather than embedded: embedding would promote Content's
+// MarshalJSON and drop SharedTags from the output
+type SimilarContent struct {
+	Content    *Content `json:"content"`
+	SharedTags int      `json:"shared_tags"` // Tags it has in common with the source item
+}
+
+// SimilarContentResponse lists the items sharing the most tags with a content item
+type SimilarContentResponse struct {
+	ContentID int64             `json:"content_id"`
+	Results   []*SimilarContent `json:"results"`
+}
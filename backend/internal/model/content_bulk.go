@@ -0,0 +1,49 @@
+// content_bulk.go - Bulk content ingestion
+// Defines the per-item results returned by POST /content
+package model
+
+// MaxBulkContentItems caps how many items one bulk ingestion request may carry
+const MaxBulkContentItems = 500
+
+// BulkItemStatus is the outcome of one item in a bulk ingestion
+type BulkItemStatus string
+
+const (
+	BulkItemCreated BulkItemStatus = "created" // New item inserted
+	BulkItemUpdated BulkItemStatus = "updated" // Existing item with the same provider and external ID overwritten
+	BulkItemFailed  BulkItemStatus = "error"   // Item rejected or not saved; see Error
+)
+
+// BulkContentItemResult reports what happened to one item, by its position in the request
+type BulkContentItemResult struct {
+	Index      int            `json:"index"`
+	ExternalID string         `json:"external_id,omitempty"`
+	Status     BulkItemStatus `json:"status"`
+	ID         int64          `json:"id,omitempty"`    // Content ID of a saved item
+	Score      float64        `json:"score,omitempty"` // Score computed for a saved item
+	Error      string         `json:"error,omitempty"`
+
+	TagsDropped int `json:"tags_dropped,omitempty"` // Tags of a saved item rejected by the tag limits
+}
+
+// BulkContentResponse summarizes a bulk ingestion
+// Items are saved one by one, so a failed item doesn't undo the others
+type BulkContentResponse struct {
+	Created int                     `json:"created"`
+	Updated int                     `json:"updated"`
+	Failed  int                     `json:"failed"`
+	Results []BulkContentItemResult `json:"results"`
+}
+
+// Add records an item's result and counts it under its status
+func (r *BulkContentResponse) Add(result BulkContentItemResult) {
+	switch result.Status {
+	case BulkItemCreated:
+		r.Created++
+	case BulkItemUpdated:
+		r.Updated++
+	default:
+		r.Failed++
+	}
+	r.Results = append(r.Results, result)
+}
@@ -0,0 +1,74 @@
+// content_history.go - Content history models
+// Defines the metric snapshots kept for each content item across syncs, edits and score updates
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// ScoreChangeThreshold is the smallest score change worth a new history snapshot
+// Scores are stored with 4 decimals; freshness decay alone moves them a little on every sync
+const ScoreChangeThreshold = 0.01
+
+// ContentSnapshot is a content item's metrics and score at one point in time
+// This matches the database schema in the content_history table
+type ContentSnapshot struct {
+	ID         int64     `json:"id" db:"id"`
+	ContentID  int64     `json:"content_id" db:"content_id"`
+	Views      int       `json:"views" db:"views"`
+	Likes      int       `json:"likes" db:"likes"`
+	Reactions  int       `json:"reactions" db:"reactions"`
+	Comments   int       `json:"comments" db:"comments"`
+	Score      float64   `json:"score" db:"score"`
+	RecordedAt time.Time `json:"recorded_at" db:"recorded_at"`
+}
+
+// ContentHistoryResponse is the snapshot history of a content item, newest first
+type ContentHistoryResponse struct {
+	ContentID int64              `json:"content_id"`
+	History   []*ContentSnapshot `json:"history"`
+}
+
+// SnapshotOf captures the tracked fields of a content item
+func SnapshotOf(c *Content) *ContentSnapshot {
+	return &ContentSnapshot{
+		ContentID: c.ID,
+		Views:     c.Views,
+		Likes:     c.Likes,
+		Reactions: c.Reactions,
+		Comments:  c.Comments,
+		Score:     c.Score,
+	}
+}
+
+// HasSignificantChange reports whether next differs from prev in a tracked field
+// Engagement counts must change at all; the score must move by at least ScoreChangeThreshold
+func HasSignificantChange(prev, next *Content) bool {
+	return prev.Views != next.Views ||
+		prev.Likes != next.Likes ||
+		prev.Reactions != next.Reactions ||
+		prev.Comments != next.Comments ||
+		math.Abs(prev.Score-next.Score) >= ScoreChangeThreshold
+}
+
+// HasContentChange reports whether next differs from prev in a way clients polling
+// for changes care about: a significant change, or a new title, type, publish date,
+// duration or reading time. Syncs that resend an item unchanged and score
+// recalculations don't count
+func HasContentChange(prev, next *Content) bool {
+	return HasSignificantChange(prev, next) ||
+		prev.Title != next.Title ||
+		prev.Type != next.Type ||
+		!prev.PublishedAt.Equal(next.PublishedAt) ||
+		!equalIntPtr(prev.DurationSeconds, next.DurationSeconds) ||
+		!equalIntPtr(prev.ReadingTime, next.ReadingTime)
+}
+
+// equalIntPtr reports whether a and b are both nil or point to equal values
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHasSignificantChange(t *testing.T) {
+	base := Content{Views: 100, Likes: 10, Reactions: 5, Comments: 2, Score: 12.5}
+
+	tests := []struct {
+		name   string
+		modify func(c *Content)
+		want   bool
+	}{
+		{"unchanged", func(c *Content) {}, false},
+		{"views changed", func(c *Content) { c.Views++ }, true},
+		{"likes changed", func(c *Content) { c.Likes-- }, true},
+		{"reactions changed", func(c *Content) { c.Reactions = 0 }, true},
+		{"comments changed", func(c *Content) { c.Comments = 3 }, true},
+		{"score drift below threshold", func(c *Content) { c.Score += ScoreChangeThreshold / 2 }, false},
+		{"score moved", func(c *Content) { c.Score -= 1 }, true},
+		{"title changed only", func(c *Content) { c.Title = "renamed" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := base
+			tt.modify(&next)
+			if got := HasSignificantChange(&base, &next); got != tt.want {
+				t.Errorf("HasSignificantChange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasContentChange(t *testing.T) {
+	duration := 90
+	base := Content{Title: "Go Tutorial", Type: ContentTypeVideo, Views: 100, DurationSeconds: &duration,
+		PublishedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name   string
+		modify func(c *Content)
+		want   bool
+	}{
+		{"unchanged", func(c *Content) {}, false},
+		{"resynced at another time", func(c *Content) { now := time.Now(); c.LastSyncedAt = &now }, false},
+		{"same duration, new pointer", func(c *Content) { d := 90; c.DurationSeconds = &d }, false},
+		{"views changed", func(c *Content) { c.Views++ }, true},
+		{"title changed", func(c *Content) { c.Title = "renamed" }, true},
+		{"published_at changed", func(c *Content) { c.PublishedAt = c.PublishedAt.AddDate(0, 0, 1) }, true},
+		{"duration removed", func(c *Content) { c.DurationSeconds = nil }, true},
+		{"reading time added", func(c *Content) { r := 5; c.ReadingTime = &r }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := base
+			tt.modify(&next)
+			if got := HasContentChange(&base, &next); got != tt.want {
+				t.Errorf("HasContentChange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
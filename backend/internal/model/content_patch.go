@@ -0,0 +1,91 @@
+// content_patch.go - Partial content updates
+// Defines the sparse payload accepted by PATCH /content/:id
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// ContentPatch is a sparse content update: nil fields are left unchanged
+// Identity fields (provider, external ID, type) and the score can't be patched;
+// the score is recomputed from the patched item
+type ContentPatch struct {
+	Title           *string    `json:"title,omitempty"`
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	Views           *int       `json:"views,omitempty"`
+	Likes           *int       `json:"likes,omitempty"`
+	DurationSeconds *int       `json:"duration_seconds,omitempty"`
+	ReadingTime     *int       `json:"reading_time,omitempty"`
+	Reactions       *int       `json:"reactions,omitempty"`
+	Comments        *int       `json:"comments,omitempty"`
+}
+
+// IsEmpty reports whether the patch sets no field
+func (p *ContentPatch) IsEmpty() bool {
+	return p.Title == nil && p.PublishedAt == nil &&
+		p.Views == nil && p.Likes == nil && p.DurationSeconds == nil &&
+		p.ReadingTime == nil && p.Reactions == nil && p.Comments == nil
+}
+
+// Validate checks the patch against the type of the content it will be applied to
+// Returns field errors keyed by JSON name; metrics of the other content type are rejected
+// rather than silently stored in columns that type never reads
+func (p *ContentPatch) Validate(contentType ContentType) map[string]string {
+	fields := make(map[string]string)
+
+	if p.Title != nil && strings.TrimSpace(*p.Title) == "" {
+		fields["title"] = "must not be empty"
+	}
+	if p.PublishedAt != nil && p.PublishedAt.IsZero() {
+		fields["published_at"] = "must be a valid timestamp"
+	}
+
+	videoOnly := map[string]*int{"views": p.Views, "likes": p.Likes, "duration_seconds": p.DurationSeconds}
+	articleOnly := map[string]*int{"reading_time": p.ReadingTime, "reactions": p.Reactions, "comments": p.Comments}
+	checkMetrics(fields, videoOnly, contentType == ContentTypeVideo, "video")
+	checkMetrics(fields, articleOnly, contentType == ContentTypeArticle, "article")
+
+	return fields
+}
+
+// checkMetrics adds field errors for set metrics that are negative or don't apply to the content type
+func checkMetrics(fields map[string]string, metrics map[string]*int, applies bool, typeName string) {
+	for name, v := range metrics {
+		switch {
+		case v == nil:
+		case !applies:
+			fields[name] = "only applies to " + typeName + " content"
+		case *v < 0:
+			fields[name] = "must be non-negative"
+		}
+	}
+}
+
+// ApplyTo copies the patch's set fields onto c
+func (p *ContentPatch) ApplyTo(c *Content) {
+	if p.Title != nil {
+		c.Title = strings.TrimSpace(*p.Title)
+	}
+	if p.PublishedAt != nil {
+		c.PublishedAt = p.PublishedAt.UTC()
+	}
+	if p.Views != nil {
+		c.Views = *p.Views
+	}
+	if p.Likes != nil {
+		c.Likes = *p.Likes
+	}
+	if p.DurationSeconds != nil {
+		c.DurationSeconds = p.DurationSeconds
+	}
+	if p.ReadingTime != nil {
+		c.ReadingTime = p.ReadingTime
+	}
+	if p.Reactions != nil {
+		c.Reactions = *p.Reactions
+	}
+	if p.Comments != nil {
+		c.Comments = *p.Comments
+	}
+}
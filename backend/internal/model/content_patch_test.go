@@ -0,0 +1,64 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int { return &v }
+
+func strPtr(v string) *string { return &v }
+
+func TestContentPatchValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		patch       ContentPatch
+		contentType ContentType
+		wantFields  []string
+	}{
+		{"video metrics on video", ContentPatch{Views: intPtr(10), Likes: intPtr(2)}, ContentTypeVideo, nil},
+		{"article metrics on article", ContentPatch{Reactions: intPtr(1), ReadingTime: intPtr(5)}, ContentTypeArticle, nil},
+		{"blank title", ContentPatch{Title: strPtr("  ")}, ContentTypeVideo, []string{"title"}},
+		{"negative metric", ContentPatch{Comments: intPtr(-1)}, ContentTypeArticle, []string{"comments"}},
+		{"article metric on video", ContentPatch{Reactions: intPtr(3)}, ContentTypeVideo, []string{"reactions"}},
+		{"video metric on article", ContentPatch{DurationSeconds: intPtr(60)}, ContentTypeArticle, []string{"duration_seconds"}},
+		{"zero published_at", ContentPatch{PublishedAt: &time.Time{}}, ContentTypeVideo, []string{"published_at"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := tt.patch.Validate(tt.contentType)
+			if len(fields) != len(tt.wantFields) {
+				t.Fatalf("Validate() = %v, want errors for %v", fields, tt.wantFields)
+			}
+			for _, f := range tt.wantFields {
+				if _, ok := fields[f]; !ok {
+					t.Errorf("Validate() = %v, missing error for %q", fields, f)
+				}
+			}
+		})
+	}
+}
+
+func TestContentPatchApplyToLeavesUnsetFields(t *testing.T) {
+	published := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := &Content{Title: "Old", Type: ContentTypeVideo, Views: 100, Likes: 7, PublishedAt: published}
+
+	patch := ContentPatch{Title: strPtr("  New title "), Views: intPtr(150)}
+	if patch.IsEmpty() {
+		t.Fatal("IsEmpty() = true for a patch with fields set")
+	}
+	patch.ApplyTo(c)
+
+	want := &Content{Title: "New title", Type: ContentTypeVideo, Views: 150, Likes: 7, PublishedAt: published}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("ApplyTo() = %+v, want %+v", c, want)
+	}
+}
+
+func TestContentPatchIsEmpty(t *testing.T) {
+	if !(&ContentPatch{}).IsEmpty() {
+		t.Error("IsEmpty() = false for an empty patch")
+	}
+}
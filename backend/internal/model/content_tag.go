@@ -3,7 +3,9 @@
 package model
 
 import (
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // ContentTag represents a tag associated with content
@@ -13,5 +15,98 @@ type ContentTag struct {
 	ID        int64     `json:"id" db:"id"`
 	ContentID int64     `json:"content_id" db:"content_id"`
 	Tag       string    `json:"tag" db:"tag"`
+	Ordinal   int       `json:"ordinal" db:"ordinal"` // Position of the tag in the provider's original list
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
+
+// TagOrder controls how tags are ordered when returned for a content item
+type TagOrder string
+
+const (
+	TagOrderAlpha     TagOrder = "alpha"     // Alphabetical order (default)
+	TagOrderInsertion TagOrder = "insertion" // Order the provider supplied them in
+)
+
+// TagOrders lists every supported tag order
+var TagOrders = []TagOrder{TagOrderAlpha, TagOrderInsertion}
+
+// DedupeTags trims tags and drops empty and duplicate ones, keeping first occurrences
+// Duplicates are compared case-insensitively to match the uk_content_tag collation,
+// so ["go", "go", "Go", "Golang"] becomes ["go", "Golang"]
+func DedupeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// MaxTagLength is the longest tag, in characters, the content_tags.tag column holds
+const MaxTagLength = 100
+
+// TagLimits bounds the tags stored for a single content item
+// Guards the tag table and UIs against malformed or abusive provider data
+type TagLimits struct {
+	MaxLength     int // Longest tag kept, in characters; 0 or above MaxTagLength means MaxTagLength
+	MaxPerContent int // Most tags kept per item, first ones win; 0 means no limit
+}
+
+// Apply dedupes tags (see DedupeTags) and enforces the limits
+// Over-long tags are rejected rather than truncated, since a cut tag is a different tag
+// Returns the kept tags and how many were dropped by the limits
+func (l TagLimits) Apply(tags []string) ([]string, int) {
+	maxLength := l.MaxLength
+	if maxLength <= 0 || maxLength > MaxTagLength {
+		maxLength = MaxTagLength
+	}
+
+	deduped := DedupeTags(tags)
+	kept := make([]string, 0, len(deduped))
+	for _, tag := range deduped {
+		if utf8.RuneCountInString(tag) > maxLength {
+			continue
+		}
+		kept = append(kept, tag)
+	}
+	if l.MaxPerContent > 0 && len(kept) > l.MaxPerContent {
+		kept = kept[:l.MaxPerContent]
+	}
+
+	return kept, len(deduped) - len(kept)
+}
+
+// Tag list limits for GET /tags
+const (
+	DefaultTagListLimit = 50
+	MaxTagListLimit     = 500
+)
+
+// TagListRequest filters the distinct tag list
+type TagListRequest struct {
+	Limit  int    `form:"limit"`  // Most tags returned (default: 50, max: 500)
+	Prefix string `form:"prefix"` // Only tags starting with this, for typeahead (optional)
+}
+
+// Validate trims the prefix and sets a default for an unset or out-of-range limit
+func (r *TagListRequest) Validate() {
+	r.Prefix = strings.TrimSpace(r.Prefix)
+	if r.Limit < 1 {
+		r.Limit = DefaultTagListLimit
+	}
+	if r.Limit > MaxTagListLimit {
+		r.Limit = MaxTagListLimit
+	}
+}
+
+// TagCount is a distinct tag and how many content items carry it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
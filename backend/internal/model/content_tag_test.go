@@ -0,0 +1,58 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDedupeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{"no duplicates", []string{"go", "docker"}, []string{"go", "docker"}},
+		{"exact duplicates", []string{"go", "go", "Golang"}, []string{"go", "Golang"}},
+		{"case and whitespace", []string{"Go", " go ", "GO"}, []string{"Go"}},
+		{"empty tags dropped", []string{"", "  ", "go"}, []string{"go"}},
+		{"nil", nil, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DedupeTags(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DedupeTags(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagLimitsApply(t *testing.T) {
+	long := strings.Repeat("x", 11)
+
+	tests := []struct {
+		name        string
+		limits      TagLimits
+		in          []string
+		want        []string
+		wantDropped int
+	}{
+		{"within limits", TagLimits{MaxLength: 10, MaxPerContent: 5}, []string{"go", "docker"}, []string{"go", "docker"}, 0},
+		{"duplicates are not counted as dropped", TagLimits{MaxLength: 10}, []string{"go", "Go"}, []string{"go"}, 0},
+		{"over-long tag rejected", TagLimits{MaxLength: 10}, []string{"go", long}, []string{"go"}, 1},
+		{"length counts characters not bytes", TagLimits{MaxLength: 2}, []string{"çğ"}, []string{"çğ"}, 0},
+		{"count capped keeping first", TagLimits{MaxPerContent: 2}, []string{"a", "b", "c", "d"}, []string{"a", "b"}, 2},
+		{"length capped at column size", TagLimits{MaxLength: 1000}, []string{strings.Repeat("x", MaxTagLength+1)}, []string{}, 1},
+		{"zero limits use column size and no count cap", TagLimits{}, []string{"a", "b", "c"}, []string{"a", "b", "c"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dropped := tt.limits.Apply(tt.in)
+			if !reflect.DeepEqual(got, tt.want) || dropped != tt.wantDropped {
+				t.Errorf("Apply(%q) = %q, %d; want %q, %d", tt.in, got, dropped, tt.want, tt.wantDropped)
+			}
+		})
+	}
+}
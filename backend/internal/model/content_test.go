@@ -0,0 +1,66 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func marshalToMap(t *testing.T, c Content) map[string]interface{} {
+	t.Helper()
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	return m
+}
+
+func TestContentMarshalJSONZeroMetricVideo(t *testing.T) {
+	readingTime := 5
+	m := marshalToMap(t, Content{Type: ContentTypeVideo, ReadingTime: &readingTime, Reactions: 3})
+
+	for _, field := range []string{"views", "likes"} {
+		if v, ok := m[field]; !ok || v != float64(0) {
+			t.Errorf("%s = %v (present %v), want explicit 0", field, v, ok)
+		}
+	}
+	for _, field := range []string{"reading_time", "reactions", "comments"} {
+		if _, ok := m[field]; ok {
+			t.Errorf("%s present on a video", field)
+		}
+	}
+}
+
+func TestContentMarshalJSONZeroMetricArticle(t *testing.T) {
+	duration := 120
+	m := marshalToMap(t, Content{Type: ContentTypeArticle, DurationSeconds: &duration, Views: 7})
+
+	for _, field := range []string{"reactions", "comments"} {
+		if v, ok := m[field]; !ok || v != float64(0) {
+			t.Errorf("%s = %v (present %v), want explicit 0", field, v, ok)
+		}
+	}
+	for _, field := range []string{"views", "likes", "duration_seconds"} {
+		if _, ok := m[field]; ok {
+			t.Errorf("%s present on an article", field)
+		}
+	}
+}
+
+func TestContentJSONRoundTrip(t *testing.T) {
+	in := Content{ID: 1, Title: "Intro", Type: ContentTypeVideo, Views: 10, Likes: 2, Tags: []string{"go"}}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var out Content
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out.ID != in.ID || out.Title != in.Title || out.Views != in.Views || out.Likes != in.Likes || len(out.Tags) != 1 {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+}
@@ -0,0 +1,43 @@
+// field_mapping.go - Field mapping for configurable providers
+// Describes where each content field lives in a provider's JSON payload,
+// so a new feed shape can be onboarded without writing a provider
+package model
+
+import "errors"
+
+// FieldMapping maps content fields to dot-separated paths in a provider payload
+// Path segments are object keys, or array indexes such as "media.0.url"
+// ItemsPath points at the item array; empty means the payload itself is the array
+type FieldMapping struct {
+	ItemsPath   string   `json:"items_path,omitempty"`
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Type        string   `json:"type,omitempty"`
+	DefaultType string   `json:"default_type,omitempty"` // Used when Type is unset or missing from an item
+	PublishedAt string   `json:"published_at"`
+	DateLayouts []string `json:"date_layouts,omitempty"` // Tried in order; RFC 3339 when empty
+	Views       string   `json:"views,omitempty"`
+	Likes       string   `json:"likes,omitempty"`
+	Duration    string   `json:"duration,omitempty"` // "MM:SS" or "HH:MM:SS" strings, or a number of seconds
+	ReadingTime string   `json:"reading_time,omitempty"`
+	Reactions   string   `json:"reactions,omitempty"`
+	Comments    string   `json:"comments,omitempty"`
+	Tags        string   `json:"tags,omitempty"` // An array of strings or a comma-separated string
+}
+
+// Validate checks that the mapping locates every field an item can't do without
+func (m *FieldMapping) Validate() error {
+	if m.ID == "" {
+		return errors.New("field_mapping.id is required")
+	}
+	if m.Title == "" {
+		return errors.New("field_mapping.title is required")
+	}
+	if m.PublishedAt == "" {
+		return errors.New("field_mapping.published_at is required")
+	}
+	if m.Type == "" && m.DefaultType == "" {
+		return errors.New("field_mapping needs a type path or a default_type")
+	}
+	return nil
+}
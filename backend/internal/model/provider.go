@@ -15,6 +15,9 @@ const (
 	ProviderFormatXML  ProviderFormat = "xml"
 )
 
+// ProviderFormats lists every supported provider format
+var ProviderFormats = []ProviderFormat{ProviderFormatJSON, ProviderFormatXML}
+
 // Provider represents a content provider
 // This matches the database schema in the providers table
 // Providers are external sources that supply content data
@@ -24,11 +27,107 @@ type Provider struct {
 	URL                string         `json:"url" db:"url"`
 	Format             ProviderFormat `json:"format" db:"format"`
 	RateLimitPerMinute int            `json:"rate_limit_per_minute" db:"rate_limit_per_minute"`
+	Enabled            bool           `json:"enabled" db:"enabled"`                                   // Disabled providers are skipped by syncs
+	StaleAfterMinutes  *int           `json:"stale_after_minutes,omitempty" db:"stale_after_minutes"` // Per-provider staleness threshold; server default when unset
+	FieldMapping       *FieldMapping  `json:"field_mapping,omitempty" db:"field_mapping"`             // Set for providers synced through a configurable provider
 	LastFetchedAt      *time.Time     `json:"last_fetched_at,omitempty" db:"last_fetched_at"`
 	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
 	UpdatedAt          time.Time      `json:"updated_at" db:"updated_at"`
 }
 
+// ProviderDetail is a single provider with its content count
+// LastFetchedAt shadows the embedded field so a never-fetched provider reports null instead of omitting it
+type ProviderDetail struct {
+	Provider
+	ContentCount  int        `json:"content_count"`
+	LastFetchedAt *time.Time `json:"last_fetched_at"`
+}
+
+// ProviderStats holds detailed content statistics for a single provider
+// Freshness counts use the same 7/30 day windows as the freshness score
+type ProviderStats struct {
+	ProviderID         int        `json:"provider_id"`
+	Name               string     `json:"name"`
+	TotalContent       int        `json:"total_content"`
+	Videos             int        `json:"videos"`
+	Articles           int        `json:"articles"`
+	AverageScore       float64    `json:"average_score"`
+	PublishedLastWeek  int        `json:"published_last_week"`
+	PublishedLastMonth int        `json:"published_last_month"`
+	NewestPublishedAt  *time.Time `json:"newest_published_at,omitempty"`
+	OldestPublishedAt  *time.Time `json:"oldest_published_at,omitempty"`
+	LastFetchedAt      *time.Time `json:"last_fetched_at,omitempty"`
+}
+
+// ProviderStatus is the operational view of a single provider
+// LastSync is the most recent recorded sync run, successful or not
+type ProviderStatus struct {
+	ProviderID        int         `json:"provider_id"`
+	Name              string      `json:"name"`
+	Enabled           bool        `json:"enabled"`
+	LastFetchedAt     *time.Time  `json:"last_fetched_at,omitempty"`
+	LastSync          *SyncResult `json:"last_sync,omitempty"`
+	SyncRunning       bool        `json:"sync_running"`
+	StaleAfterMinutes int         `json:"stale_after_minutes"`
+	Stale             bool        `json:"stale"`
+}
+
+// Pagination bounds for the provider list
+const (
+	DefaultProviderPerPage = 20
+	MaxProviderPerPage     = 100
+)
+
+// ProviderListRequest is a page of the provider list
+type ProviderListRequest struct {
+	Page    int `form:"page"`     // Page number (default: 1)
+	PerPage int `form:"per_page"` // Items per page (default: 20, max: 100)
+}
+
+// Validate sets defaults for unset or out-of-range values, like SearchRequest.Validate
+func (r *ProviderListRequest) Validate() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+	if r.PerPage < 1 {
+		r.PerPage = DefaultProviderPerPage
+	}
+	if r.PerPage > MaxProviderPerPage {
+		r.PerPage = MaxProviderPerPage
+	}
+}
+
+// GetOffset returns the row offset of the requested page
+func (r *ProviderListRequest) GetOffset() int {
+	return (r.Page - 1) * r.PerPage
+}
+
+// ProviderListResponse is a page of providers with the same pagination fields as search
+type ProviderListResponse struct {
+	Providers  []*Provider `json:"providers"`
+	Total      int         `json:"total"`
+	Page       int         `json:"page"`
+	PerPage    int         `json:"per_page"`
+	TotalPages int         `json:"total_pages"`
+}
+
+// CalculateTotalPages sets TotalPages from Total and PerPage
+func (r *ProviderListResponse) CalculateTotalPages() {
+	r.TotalPages = 0
+	if r.PerPage > 0 {
+		r.TotalPages = (r.Total + r.PerPage - 1) / r.PerPage
+	}
+}
+
+// IsStale reports whether a provider last fetched longer than staleAfter ago
+// A provider that has never been fetched is stale; a non-positive staleAfter disables the check
+func IsStale(lastFetchedAt *time.Time, staleAfter time.Duration, now time.Time) bool {
+	if staleAfter <= 0 {
+		return false
+	}
+	return lastFetchedAt == nil || now.Sub(*lastFetchedAt) > staleAfter
+}
+
 // IsJSON returns true if provider format is JSON
 // Helper method for format checking
 func (p *Provider) IsJSON() bool {
@@ -40,3 +139,41 @@ func (p *Provider) IsJSON() bool {
 func (p *Provider) IsXML() bool {
 	return p.Format == ProviderFormatXML
 }
+
+// ProviderCreateRequest is the body of POST /providers
+// New providers start enabled; a field mapping makes syncs fetch them through a configurable provider
+type ProviderCreateRequest struct {
+	Name               string         `json:"name"`
+	URL                string         `json:"url"`
+	Format             ProviderFormat `json:"format"`
+	RateLimitPerMinute int            `json:"rate_limit_per_minute"`
+	FieldMapping       *FieldMapping  `json:"field_mapping,omitempty"`
+}
+
+// Provider returns the provider the request describes
+func (r *ProviderCreateRequest) Provider() *Provider {
+	return &Provider{
+		Name:               r.Name,
+		URL:                r.URL,
+		Format:             r.Format,
+		RateLimitPerMinute: r.RateLimitPerMinute,
+		FieldMapping:       r.FieldMapping,
+	}
+}
+
+// ProviderUpdateRequest is the body of PUT /providers/:id
+// It replaces where and how a provider is fetched; the name is fixed once created
+type ProviderUpdateRequest struct {
+	URL                string         `json:"url"`
+	Format             ProviderFormat `json:"format"`
+	RateLimitPerMinute int            `json:"rate_limit_per_minute"`
+	FieldMapping       *FieldMapping  `json:"field_mapping,omitempty"`
+}
+
+// ApplyTo overwrites p's fetch settings with the request's
+func (r *ProviderUpdateRequest) ApplyTo(p *Provider) {
+	p.URL = r.URL
+	p.Format = r.Format
+	p.RateLimitPerMinute = r.RateLimitPerMinute
+	p.FieldMapping = r.FieldMapping
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsStale(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	hourAgo := now.Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		lastFetchedAt *time.Time
+		staleAfter    time.Duration
+		want          bool
+	}{
+		{"never fetched", nil, time.Hour, true},
+		{"fetched within threshold", &hourAgo, 2 * time.Hour, false},
+		{"fetched exactly at threshold", &hourAgo, time.Hour, false},
+		{"fetched before threshold", &hourAgo, 30 * time.Minute, true},
+		{"check disabled", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsStale(tt.lastFetchedAt, tt.staleAfter, now); got != tt.want {
+				t.Errorf("IsStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProviderListRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          ProviderListRequest
+		wantPage    int
+		wantPerPage int
+		wantOffset  int
+	}{
+		{"defaults", ProviderListRequest{}, 1, DefaultProviderPerPage, 0},
+		{"explicit page", ProviderListRequest{Page: 3, PerPage: 10}, 3, 10, 20},
+		{"negative values", ProviderListRequest{Page: -2, PerPage: -5}, 1, DefaultProviderPerPage, 0},
+		{"per_page capped", ProviderListRequest{Page: 2, PerPage: 500}, 2, MaxProviderPerPage, MaxProviderPerPage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.in
+			req.Validate()
+			if req.Page != tt.wantPage || req.PerPage != tt.wantPerPage {
+				t.Errorf("Validate() = page %d per_page %d, want %d and %d", req.Page, req.PerPage, tt.wantPage, tt.wantPerPage)
+			}
+			if got := req.GetOffset(); got != tt.wantOffset {
+				t.Errorf("GetOffset() = %d, want %d", got, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestProviderListResponseTotalPages(t *testing.T) {
+	tests := []struct {
+		total, perPage, want int
+	}{
+		{0, 20, 0},
+		{1, 20, 1},
+		{20, 20, 1},
+		{21, 20, 2},
+		{5, 0, 0},
+	}
+
+	for _, tt := range tests {
+		r := &ProviderListResponse{Total: tt.total, PerPage: tt.perPage}
+		r.CalculateTotalPages()
+		if r.TotalPages != tt.want {
+			t.Errorf("total %d per_page %d: TotalPages = %d, want %d", tt.total, tt.perPage, r.TotalPages, tt.want)
+		}
+	}
+}
@@ -2,20 +2,217 @@
 // Defines the API models for search operations
 package model
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Pagination bounds for search requests
+// The per_page cap is configurable (SEARCH_MAX_PER_PAGE); DefaultMaxPerPage applies when it isn't set
+const (
+	DefaultPerPage    = 10
+	DefaultMaxPerPage = 100
+)
+
+// MatchMode controls how the terms of a FULLTEXT keyword combine
+type MatchMode string
+
+const (
+	MatchModeAny MatchMode = "any" // A row matches if its title contains any term (default)
+	MatchModeAll MatchMode = "all" // A row matches only if its title contains every term
+)
+
+// MatchModes lists every supported match mode
+var MatchModes = []MatchMode{MatchModeAny, MatchModeAll}
+
+// Search sort whitelists
+// Validate, the repository and the /enums endpoint all read these, so they are the single source of truth
+var (
+	SearchSortFields = []string{"score", "published_at", "title", "views", "likes", "reactions", "comments", SortByRelevance}
+	SearchSortOrders = []string{"asc", "desc"}
+)
+
+// SortByRelevance ranks keyword searches by FULLTEXT match score blended with the
+// content score; searches without a FULLTEXT keyword order by score instead
+const SortByRelevance = "relevance"
+
+// engagementSortTypes maps each engagement sort field to the content type it measures
+var engagementSortTypes = map[string]ContentType{
+	"views":     ContentTypeVideo,
+	"likes":     ContentTypeVideo,
+	"reactions": ContentTypeArticle,
+	"comments":  ContentTypeArticle,
+}
+
+// EngagementSortType returns the content type an engagement sort field applies to
+// Content of the other type has no value for the field; ok is false for other fields
+func EngagementSortType(field string) (ContentType, bool) {
+	t, ok := engagementSortTypes[field]
+	return t, ok
+}
 
 // SearchRequest represents the search query parameters
 // This is what the API receives from clients
 type SearchRequest struct {
-	Query      string       `json:"query,omitempty" form:"query"`                                   // Search keyword (optional - if empty, returns all content)
-	Type       *ContentType `json:"type,omitempty" form:"type"`                                      // Filter by content type (optional)
-	ProviderID *int         `json:"provider_id,omitempty" form:"provider_id"`                        // Filter by provider (optional)
-	StartDate  *time.Time   `json:"start_date,omitempty" form:"start_date" time_format:"2006-01-02"` // Filter by published_at >= start_date
-	EndDate    *time.Time   `json:"end_date,omitempty" form:"end_date" time_format:"2006-01-02"`     // Filter by published_at <= end_date
-	Page       int          `json:"page,omitempty" form:"page"`                                      // Page number (default: 1)
-	PerPage    int          `json:"per_page,omitempty" form:"per_page"`                              // Items per page (default: 10)
-	SortBy     string       `json:"sort_by,omitempty" form:"sort_by"`                                // Sort field: "score", "published_at" (default: "score")
-	SortOrder  string       `json:"sort_order,omitempty" form:"sort_order"`                          // Sort order: "asc", "desc" (default: "desc")
+	Query      string       `json:"query,omitempty" form:"query"`                                                 // Search keyword (optional - if empty, returns all content)
+	Type       *ContentType `json:"type,omitempty" form:"type"`                                                   // Filter by content type (optional)
+	ProviderID *int         `json:"provider_id,omitempty" form:"provider_id"`                                     // Filter by provider (optional)
+	StartDate  *time.Time   `json:"start_date,omitempty" form:"start_date" time_format:"2006-01-02" time_utc:"1"` // Filter by published_at >= start_date (midnight UTC)
+	EndDate    *time.Time   `json:"end_date,omitempty" form:"end_date" time_format:"2006-01-02" time_utc:"1"`     // Filter by published_at <= end_date (midnight UTC)
+	Page       int          `json:"page,omitempty" form:"page"`                                                   // Page number (default: 1)
+	PerPage    int          `json:"per_page,omitempty" form:"per_page"`                                           // Items per page (default: 10)
+	SortBy     string       `json:"sort_by,omitempty" form:"sort_by"`                                             // Sort field: "score", "published_at", "title", "relevance" or an engagement metric (default: "score")
+	SortOrder  string       `json:"sort_order,omitempty" form:"sort_order"`                                       // Sort order: "asc", "desc" (default: "desc")
+	TagOrder   TagOrder     `json:"tag_order,omitempty" form:"tag_order"`                                         // Tag order: "alpha", "insertion" (default: "alpha")
+	TimeoutMs  int          `json:"timeout_ms,omitempty" form:"timeout_ms"`                                       // Query timeout override in milliseconds (clamped server-side)
+	Prefix     *bool        `json:"prefix,omitempty" form:"prefix"`                                               // Prefix-match FULLTEXT terms ("go" matches "golang"); server default when unset
+	MatchMode  MatchMode    `json:"match_mode,omitempty" form:"match_mode"`                                       // How FULLTEXT terms combine: "any", "all" (default: "any")
+
+	// IncludeTags also matches the keyword against tags, ORed with the title match
+	// Unset means true; see IncludesTags
+	IncludeTags *bool `json:"include_tags,omitempty" form:"include_tags"`
+
+	// UpdatedSince keeps content created or changed at or after it (content_changed_at),
+	// so clients can poll for changes instead of re-fetching everything
+	UpdatedSince *time.Time `json:"updated_since,omitempty" form:"updated_since" time_format:"2006-01-02T15:04:05Z07:00" time_utc:"1"`
+
+	DistinctTitles bool `json:"distinct_titles,omitempty" form:"distinct_titles"` // Collapse rows with the same normalized title to the highest-scoring one
+
+	// GroupByProvider buckets the results by provider, each bucket holding that
+	// provider's top per_page matches; page pages through every bucket at once
+	GroupByProvider bool `json:"group_by_provider,omitempty" form:"group_by_provider"`
+
+	// IncludeFacets adds facets to the response: match counts per type and per provider
+	IncludeFacets bool `json:"include_facets,omitempty" form:"include_facets"`
+
+	// Highlight adds highlighted_title to each result, with query matches marked
+	// Applied after the cache lookup, so it isn't part of the cache key
+	Highlight bool `json:"highlight,omitempty" form:"highlight"`
+
+	// Fuzzy retries a keyword search that matched nothing against titles with
+	// words spelled close to the query terms, e.g. kubernets finds Kubernetes
+	Fuzzy bool `json:"fuzzy,omitempty" form:"fuzzy"`
+
+	// Period is a date-range preset such as last_month; the handler turns it into
+	// StartDate and EndDate (see ApplyPeriod), so it isn't part of the cache key
+	Period SearchPeriod `json:"period,omitempty" form:"period"`
+
+	// After continues a score-ordered search after the row a next_cursor points at
+	// Paging this way avoids OFFSET, so deep pages stay fast; page is ignored with it
+	After *string `json:"after,omitempty" form:"after"`
+
+	// Engagement floors on the raw metrics; each applies only to its content type,
+	// so min_views hides low-view videos but leaves articles alone
+	MinViews     *int `json:"min_views,omitempty" form:"min_views"`         // Videos
+	MinLikes     *int `json:"min_likes,omitempty" form:"min_likes"`         // Videos
+	MinReactions *int `json:"min_reactions,omitempty" form:"min_reactions"` // Articles
+	MinComments  *int `json:"min_comments,omitempty" form:"min_comments"`   // Articles
+
+	// NoCache skips the cache read (the fresh result is still cached)
+	// Set by ?nocache=true or a Cache-Control: no-cache request header; not part of the cache key
+	NoCache bool `json:"-" form:"nocache"`
+}
+
+// IncludesTags reports whether the keyword should also match tags (default: true)
+func (r *SearchRequest) IncludesTags() bool {
+	return r.IncludeTags == nil || *r.IncludeTags
+}
+
+// searchParamRules describes the typed query parameters of a search request
+// Used to turn Gin's field-less binding errors into per-field messages, and to
+// reject values that bind but are out of range, like page=0 or min_views=-1
+// per_page is checked by SearchParamErrors itself, as its message names the configured cap
+var searchParamRules = []struct {
+	name    string
+	valid   func(string) bool
+	message string
+}{
+	{"page", isPositiveInteger, "page must be an integer greater than or equal to 1"},
+	{"provider_id", isInteger, "provider_id must be an integer"},
+	{"timeout_ms", isInteger, "timeout_ms must be an integer number of milliseconds"},
+	{"prefix", isBool, "prefix must be true or false"},
+	{"match_mode", isMatchMode, "match_mode must be any or all"},
+	{"include_tags", isBool, "include_tags must be true or false"},
+	{"distinct_titles", isBool, "distinct_titles must be true or false"},
+	{"group_by_provider", isBool, "group_by_provider must be true or false"},
+	{"include_facets", isBool, "include_facets must be true or false"},
+	{"highlight", isBool, "highlight must be true or false"},
+	{"fuzzy", isBool, "fuzzy must be true or false"},
+	{"nocache", isBool, "nocache must be true or false"},
+	{"min_views", isNonNegativeInteger, "min_views must be an integer greater than or equal to 0"},
+	{"min_likes", isNonNegativeInteger, "min_likes must be an integer greater than or equal to 0"},
+	{"min_reactions", isNonNegativeInteger, "min_reactions must be an integer greater than or equal to 0"},
+	{"min_comments", isNonNegativeInteger, "min_comments must be an integer greater than or equal to 0"},
+	{"start_date", isDate, "start_date must be a date in YYYY-MM-DD format"},
+	{"end_date", isDate, "end_date must be a date in YYYY-MM-DD format"},
+	{"updated_since", isTimestamp, "updated_since must be an RFC 3339 timestamp such as 2024-03-15T10:00:00Z"},
+	{"period", isSearchPeriod, ErrInvalidPeriod.Error()},
+}
+
+// SearchParamErrors checks the raw query parameters of a search request
+// Returns a map of parameter name to a friendly message for each malformed value
+// maxPerPage is the configured per_page cap; DefaultMaxPerPage is used when it is not positive
+func SearchParamErrors(values url.Values, maxPerPage int) map[string]string {
+	fields := make(map[string]string)
+	for _, rule := range searchParamRules {
+		if v := values.Get(rule.name); v != "" && !rule.valid(v) {
+			fields[rule.name] = rule.message
+		}
+	}
+	if v := values.Get("per_page"); v != "" && !isPositiveInteger(v) {
+		if maxPerPage <= 0 {
+			maxPerPage = DefaultMaxPerPage
+		}
+		// Larger values are valid, LimitPerPage caps them
+		fields["per_page"] = fmt.Sprintf("per_page must be an integer between 1 and %d; larger values are capped at %d", maxPerPage, maxPerPage)
+	}
+	return fields
+}
+
+// isInteger reports whether s parses as a base-10 integer
+func isInteger(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// isPositiveInteger reports whether s parses as a base-10 integer >= 1
+func isPositiveInteger(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 1
+}
+
+// isNonNegativeInteger reports whether s parses as a base-10 integer >= 0
+func isNonNegativeInteger(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0
+}
+
+// isBool reports whether s parses as a boolean
+func isBool(s string) bool {
+	_, err := strconv.ParseBool(s)
+	return err == nil
+}
+
+// isMatchMode reports whether s names a supported match mode
+func isMatchMode(s string) bool {
+	return slices.Contains(MatchModes, MatchMode(s))
+}
+
+// isDate reports whether s is a YYYY-MM-DD date
+func isDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
+// isTimestamp reports whether s is an RFC 3339 timestamp
+func isTimestamp(s string) bool {
+	_, err := time.Parse(time.RFC3339, s)
+	return err == nil
 }
 
 // Validate validates and sets default values for SearchRequest
@@ -26,12 +223,9 @@ func (r *SearchRequest) Validate() {
 		r.Page = 1
 	}
 
-	// Set default per_page (limit to prevent excessive results)
+	// Set default per_page; the upper bound is applied by LimitPerPage
 	if r.PerPage < 1 {
-		r.PerPage = 10
-	}
-	if r.PerPage > 100 {
-		r.PerPage = 100 // Maximum limit
+		r.PerPage = DefaultPerPage
 	}
 
 	// Set default sort_by
@@ -40,12 +234,7 @@ func (r *SearchRequest) Validate() {
 	}
 
 	// Validate sort_by values
-	validSortFields := map[string]bool{
-		"score":        true,
-		"published_at": true,
-		"title":        true,
-	}
-	if !validSortFields[r.SortBy] {
+	if !slices.Contains(SearchSortFields, r.SortBy) {
 		r.SortBy = "score" // Default to score if invalid
 	}
 
@@ -55,10 +244,21 @@ func (r *SearchRequest) Validate() {
 	}
 
 	// Validate sort_order
-	if r.SortOrder != "asc" && r.SortOrder != "desc" {
+	if !slices.Contains(SearchSortOrders, r.SortOrder) {
 		r.SortOrder = "desc" // Default to desc if invalid
 	}
 
+	// Ignore nonsensical timeout overrides (server default applies)
+	if r.TimeoutMs < 0 {
+		r.TimeoutMs = 0
+	}
+
+	// Normalize tag order (unknown values fall back to alpha)
+	r.TagOrder = NormalizeTagOrder(string(r.TagOrder))
+
+	// Normalize match mode (unknown values fall back to any)
+	r.MatchMode = NormalizeMatchMode(string(r.MatchMode))
+
 	// Normalize date range
 	if r.StartDate != nil && r.EndDate != nil {
 		if r.EndDate.Before(*r.StartDate) {
@@ -70,26 +270,185 @@ func (r *SearchRequest) Validate() {
 	}
 }
 
+// LimitPerPage caps PerPage at maxPerPage to prevent excessive results
+// maxPerPage comes from config; DefaultMaxPerPage is used when it is not positive
+func (r *SearchRequest) LimitPerPage(maxPerPage int) {
+	if maxPerPage <= 0 {
+		maxPerPage = DefaultMaxPerPage
+	}
+	if r.PerPage > maxPerPage {
+		r.PerPage = maxPerPage
+	}
+}
+
+// CheckResultWindow ensures page * per_page stays within maxWindow
+// This mirrors Elasticsearch's max_result_window and stops clients from
+// walking the whole table with offset pagination; cursor requests are exempt
+// Must be called after Validate so Page and PerPage have their defaults
+func (r *SearchRequest) CheckResultWindow(maxWindow int) error {
+	if maxWindow <= 0 || (r.After != nil && *r.After != "") {
+		// Cursor pages don't use OFFSET, so they aren't limited by depth
+		return nil
+	}
+	// Compared by division, as page * per_page can overflow for an absurd page
+	if r.PerPage > 0 && r.Page > maxWindow/r.PerPage {
+		return fmt.Errorf("page * per_page must be less than or equal to %d (got page %d, per_page %d)", maxWindow, r.Page, r.PerPage)
+	}
+	return nil
+}
+
 // GetOffset calculates the database offset for pagination
 // Used in SQL LIMIT/OFFSET queries
 func (r *SearchRequest) GetOffset() int {
 	return (r.Page - 1) * r.PerPage
 }
 
+// PastLastPage reports whether Page lies beyond the last page of total results
+// An unknown total (negative) is never past; compared in pages, as the offset of
+// an absurd page can overflow
+func (r *SearchRequest) PastLastPage(total int) bool {
+	if total < 0 || r.PerPage < 1 {
+		return false
+	}
+	return r.Page > (total+r.PerPage-1)/r.PerPage
+}
+
+// CacheStats reports how often search responses were served from the cache
+type CacheStats struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"` // hits / (hits + misses); 0 before any lookup
+}
+
+// NewCacheStats builds CacheStats from hit and miss counts, deriving the hit ratio
+func NewCacheStats(hits, misses int64) CacheStats {
+	stats := CacheStats{Hits: hits, Misses: misses}
+	if total := hits + misses; total > 0 {
+		stats.HitRatio = float64(hits) / float64(total)
+	}
+	return stats
+}
+
 // SearchResponse represents the search results
 // This is what the API returns to clients
 type SearchResponse struct {
 	Results    []Content `json:"results"`     // Search results
-	Total      int       `json:"total"`       // Total number of results
+	Total      int       `json:"total"`       // Total number of results; approximate when TotalIsEstimate is set
 	Page       int       `json:"page"`        // Current page number
 	PerPage    int       `json:"per_page"`    // Items per page
 	TotalPages int       `json:"total_pages"` // Total number of pages
+
+	// TagsPartial is true when tag loading failed or timed out, so the tags
+	// on the results may be incomplete rather than genuinely absent
+	TagsPartial bool `json:"tags_partial,omitempty"`
+
+	// Hint explains an empty result set; only set when there are no results
+	Hint *SearchHint `json:"hint,omitempty"`
+
+	// Notice flags server-side conditions affecting the results, e.g. score ranking being disabled
+	Notice string `json:"notice,omitempty"`
+
+	// ResultChecksum identifies the page's rows and their order: it changes when an item
+	// is added, removed, reordered or updated, so polling clients can skip unchanged pages
+	ResultChecksum string `json:"result_checksum"`
+
+	// CollapsedCount is how many matching rows distinct_titles hid as duplicates
+	// Total then counts distinct titles rather than rows
+	CollapsedCount int `json:"collapsed_count,omitempty"`
+
+	// TotalIsEstimate is true when the COUNT query timed out and Total was estimated
+	// The estimate comes from table statistics or the query plan, or is a lower bound
+	// from the rows paged through so far when neither applies
+	TotalIsEstimate bool `json:"total_is_estimate,omitempty"`
+
+	// Fuzzy is true when nothing matched the keyword as typed and the results are
+	// near spellings found by the fuzzy fallback, ordered closest first
+	Fuzzy bool `json:"fuzzy,omitempty"`
+
+	// Suggestions are existing tags spelled close to the query ("did you mean")
+	// Only set when nothing matched; at most three, closest first
+	Suggestions []string `json:"suggestions,omitempty"`
+
+	// NextCursor fetches the page after this one when passed as after
+	// Set on full pages of score-ordered searches; absent on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+
+	// Groups holds the results of a group_by_provider search, one per provider with
+	// rows on the page, ordered by provider ID; Results is then empty
+	Groups []ProviderGroup `json:"groups,omitempty"`
+
+	// Facets counts the matching rows per type and per provider, for filter UIs
+	// Only set with include_facets; absent when the facet query timed out
+	Facets *SearchFacets `json:"facets,omitempty"`
+}
+
+// SearchFacets breaks a search's matching rows down by type and by provider
+// Counted over the same filters as the search, the type and provider filters included,
+// so each breakdown sums to the row total; entries come largest first
+type SearchFacets struct {
+	Types     []TypeFacet     `json:"types"`
+	Providers []ProviderFacet `json:"providers"`
+}
+
+// TypeFacet is the number of matching rows of one content type
+type TypeFacet struct {
+	Type  ContentType `json:"type"`
+	Count int         `json:"count"`
+}
+
+// ProviderFacet is the number of matching rows of one provider
+type ProviderFacet struct {
+	ProviderID int `json:"provider_id"`
+	Count      int `json:"count"`
+}
+
+// ProviderGroup is one provider's bucket of a group_by_provider search
+type ProviderGroup struct {
+	ProviderID int       `json:"provider_id"`
+	Count      int       `json:"count"`   // Matching rows of this provider across all pages
+	Results    []Content `json:"results"` // At most per_page rows, in the requested sort order
+}
+
+// SearchMode is the matching strategy used for a keyword query
+type SearchMode string
+
+const (
+	SearchModeNone     SearchMode = "none"     // No keyword, filters only
+	SearchModeFullText SearchMode = "fulltext" // MATCH ... AGAINST in boolean mode
+	SearchModeLike     SearchMode = "like"     // LIKE fallback for short queries
+)
+
+// SearchHint explains why a search returned no results
+// Helps clients and support tell a too-specific query from a too-short one
+type SearchHint struct {
+	Mode           SearchMode `json:"mode"`                      // Matching strategy that was used
+	EffectiveQuery string     `json:"effective_query,omitempty"` // Query as sent to the database after sanitizing
+	ActiveFilters  []string   `json:"active_filters,omitempty"`  // Filters that narrowed the search
+	Suggestion     string     `json:"suggestion"`                // What to try next
+}
+
+// SearchCountResponse represents the result of a count-only search
+// Clients use it to decide whether to show results without fetching rows
+type SearchCountResponse struct {
+	Total int `json:"total"` // Number of matching results, -1 if the count timed out
+
+	// TimedOut is true when the COUNT query exceeded its timeout and Total is unknown
+	TimedOut bool `json:"timed_out,omitempty"`
 }
 
 // CalculateTotalPages computes the total number of pages based on total results
 // Helper method for pagination metadata
 // If total is -1 (unknown/estimated), total_pages will be 0
+// Grouped responses page through every group at once, so the largest group decides
 func (r *SearchResponse) CalculateTotalPages() {
+	if r.Groups != nil && r.PerPage > 0 {
+		largest := 0
+		for _, g := range r.Groups {
+			largest = max(largest, g.Count)
+		}
+		r.TotalPages = (largest + r.PerPage - 1) / r.PerPage
+		return
+	}
 	if r.Total < 0 {
 		// Total is unknown (e.g., COUNT query timed out)
 		r.TotalPages = 0
@@ -101,3 +460,20 @@ func (r *SearchResponse) CalculateTotalPages() {
 		r.TotalPages = 0
 	}
 }
+
+// CalculateResultChecksum sets ResultChecksum from the results' (id, updated_at) pairs in order
+// Hashing identities rather than the body keeps it cheap and stable across
+// formatting changes such as pretty output or tag order
+func (r *SearchResponse) CalculateResultChecksum() {
+	h := sha256.New()
+	for _, c := range r.Results {
+		fmt.Fprintf(h, "%d:%d\n", c.ID, c.UpdatedAt.UnixNano())
+	}
+	for _, g := range r.Groups {
+		fmt.Fprintf(h, "provider %d\n", g.ProviderID)
+		for _, c := range g.Results {
+			fmt.Fprintf(h, "%d:%d\n", c.ID, c.UpdatedAt.UnixNano())
+		}
+	}
+	r.ResultChecksum = hex.EncodeToString(h.Sum(nil)[:16])
+}
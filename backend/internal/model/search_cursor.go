@@ -0,0 +1,77 @@
+// search_cursor.go - Keyset pagination cursors for search
+// A cursor holds the (score, id) of the last row a client saw, so the next page
+// starts right after it instead of making MySQL skip OFFSET rows
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SearchCursor is the sort key of the last row of a page
+type SearchCursor struct {
+	Score float64
+	ID    int64
+}
+
+// Encode returns the opaque string clients send back as after
+func (c SearchCursor) Encode() string {
+	raw := strconv.FormatFloat(c.Score, 'f', -1, 64) + ":" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ErrInvalidCursor is returned for an after value that isn't a cursor this API issued
+var ErrInvalidCursor = errors.New("after must be a next_cursor value returned by a previous search")
+
+// ParseSearchCursor decodes a cursor produced by SearchCursor.Encode
+func ParseSearchCursor(s string) (SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return SearchCursor{}, ErrInvalidCursor
+	}
+	scorePart, idPart, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return SearchCursor{}, ErrInvalidCursor
+	}
+	score, err := strconv.ParseFloat(scorePart, 64)
+	if err != nil || math.IsNaN(score) || math.IsInf(score, 0) {
+		return SearchCursor{}, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil || id <= 0 {
+		return SearchCursor{}, ErrInvalidCursor
+	}
+	return SearchCursor{Score: score, ID: id}, nil
+}
+
+// CursorOf returns the cursor that continues after c
+func CursorOf(c *Content) SearchCursor {
+	return SearchCursor{Score: c.Score, ID: c.ID}
+}
+
+// SupportsCursor reports whether the request's ordering can be paged with a cursor
+// Cursors follow the default score DESC, id DESC order; other orders,
+// distinct_titles, which reorders rows after grouping, and group_by_provider,
+// which pages every provider at once, use page numbers only
+// Must be called after Validate so SortBy and SortOrder have their defaults
+func (r *SearchRequest) SupportsCursor() bool {
+	return r.SortBy == "score" && r.SortOrder == "desc" && !r.DistinctTitles && !r.GroupByProvider
+}
+
+// Cursor decodes the request's after cursor; nil means page-number pagination
+func (r *SearchRequest) Cursor() (*SearchCursor, error) {
+	if r.After == nil || *r.After == "" {
+		return nil, nil
+	}
+	if !r.SupportsCursor() {
+		return nil, errors.New("after only works with sort_by=score, sort_order=desc and without distinct_titles or group_by_provider")
+	}
+	c, err := ParseSearchCursor(*r.After)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,78 @@
+// search_period.go - Date-range presets for search
+// A period like last_month stands in for a start_date/end_date pair relative to today
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SearchPeriod names a date range ending today
+type SearchPeriod string
+
+const (
+	PeriodLastWeek    SearchPeriod = "last_week"     // The past 7 days
+	PeriodLastMonth   SearchPeriod = "last_month"    // Since the same day last month
+	PeriodLast3Months SearchPeriod = "last_3_months" // Since the same day 3 months ago
+	PeriodLastYear    SearchPeriod = "last_year"     // Since the same day last year
+)
+
+// SearchPeriods lists every supported period
+var SearchPeriods = []SearchPeriod{PeriodLastWeek, PeriodLastMonth, PeriodLast3Months, PeriodLastYear}
+
+// ErrInvalidPeriod is returned for a period value that isn't in SearchPeriods
+var ErrInvalidPeriod = fmt.Errorf("period must be one of %s", joinPeriods(SearchPeriods))
+
+func joinPeriods(periods []SearchPeriod) string {
+	names := make([]string, len(periods))
+	for i, p := range periods {
+		names[i] = string(p)
+	}
+	return strings.Join(names, ", ")
+}
+
+// isSearchPeriod reports whether s names a supported period
+func isSearchPeriod(s string) bool {
+	return slices.Contains(SearchPeriods, SearchPeriod(s))
+}
+
+// Range returns the dates a period covers as of now
+// Both ends fall on whole days (UTC) so a period maps to the same dates, and the
+// same cache key, all day long; end is the last second of today
+func (p SearchPeriod) Range(now time.Time) (start, end time.Time, ok bool) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	switch p {
+	case PeriodLastWeek:
+		start = today.AddDate(0, 0, -7)
+	case PeriodLastMonth:
+		start = today.AddDate(0, -1, 0)
+	case PeriodLast3Months:
+		start = today.AddDate(0, -3, 0)
+	case PeriodLastYear:
+		start = today.AddDate(-1, 0, 0)
+	default:
+		return time.Time{}, time.Time{}, false
+	}
+	return start, today.Add(24*time.Hour - time.Second), true
+}
+
+// ApplyPeriod fills StartDate and EndDate from Period as of now
+// Explicit dates win: a start_date or end_date sent with a period replaces that end of its range
+func (r *SearchRequest) ApplyPeriod(now time.Time) error {
+	if r.Period == "" {
+		return nil
+	}
+	start, end, ok := r.Period.Range(now)
+	if !ok {
+		return ErrInvalidPeriod
+	}
+	if r.StartDate == nil {
+		r.StartDate = &start
+	}
+	if r.EndDate == nil {
+		r.EndDate = &end
+	}
+	return nil
+}
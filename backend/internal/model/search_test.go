@@ -0,0 +1,288 @@
+package model
+
+import (
+	"math"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCalculateResultChecksum(t *testing.T) {
+	t1 := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+
+	checksum := func(results ...Content) string {
+		r := &SearchResponse{Results: results}
+		r.CalculateResultChecksum()
+		return r.ResultChecksum
+	}
+
+	base := checksum(Content{ID: 1, UpdatedAt: t1}, Content{ID: 2, UpdatedAt: t1})
+
+	if got := checksum(Content{ID: 1, UpdatedAt: t1, Title: "other"}, Content{ID: 2, UpdatedAt: t1, Score: 9}); got != base {
+		t.Errorf("checksum changed for fields other than id and updated_at: %s != %s", got, base)
+	}
+
+	changed := map[string]string{
+		"reordered": checksum(Content{ID: 2, UpdatedAt: t1}, Content{ID: 1, UpdatedAt: t1}),
+		"updated":   checksum(Content{ID: 1, UpdatedAt: t1}, Content{ID: 2, UpdatedAt: t2}),
+		"removed":   checksum(Content{ID: 1, UpdatedAt: t1}),
+		"added":     checksum(Content{ID: 1, UpdatedAt: t1}, Content{ID: 2, UpdatedAt: t1}, Content{ID: 3, UpdatedAt: t1}),
+	}
+	for name, got := range changed {
+		if got == base {
+			t.Errorf("%s: checksum unchanged (%s)", name, got)
+		}
+	}
+
+	if empty := checksum(); empty == "" || empty == base {
+		t.Errorf("empty result checksum = %q", empty)
+	}
+}
+
+func TestSearchRequestValidateSortFields(t *testing.T) {
+	for _, field := range []string{"score", "published_at", "title", "views", "likes", "reactions", "comments"} {
+		r := SearchRequest{SortBy: field}
+		r.Validate()
+		if r.SortBy != field {
+			t.Errorf("Validate() changed sort_by %q to %q", field, r.SortBy)
+		}
+	}
+
+	r := SearchRequest{SortBy: "duration_seconds"}
+	r.Validate()
+	if r.SortBy != "score" {
+		t.Errorf("Validate() kept unsupported sort_by, got %q", r.SortBy)
+	}
+}
+
+func TestEngagementSortType(t *testing.T) {
+	if got, ok := EngagementSortType("views"); !ok || got != ContentTypeVideo {
+		t.Errorf("EngagementSortType(views) = %q, %v, want video", got, ok)
+	}
+	if got, ok := EngagementSortType("comments"); !ok || got != ContentTypeArticle {
+		t.Errorf("EngagementSortType(comments) = %q, %v, want article", got, ok)
+	}
+	if _, ok := EngagementSortType("score"); ok {
+		t.Error("EngagementSortType(score) ok = true, want false")
+	}
+}
+
+func TestSearchCursorRoundTrip(t *testing.T) {
+	for _, c := range []SearchCursor{{Score: 0, ID: 1}, {Score: 12.3456, ID: 42}, {Score: -3.5, ID: 9007199254740993}} {
+		got, err := ParseSearchCursor(c.Encode())
+		if err != nil || got != c {
+			t.Errorf("ParseSearchCursor(%+v.Encode()) = %+v, %v", c, got, err)
+		}
+	}
+
+	for _, s := range []string{"", "not base64!", "MTIz", "YWJjOjE", "MS41OjA", "TmFOOjE"} {
+		if _, err := ParseSearchCursor(s); err == nil {
+			t.Errorf("ParseSearchCursor(%q) error = nil, want invalid cursor", s)
+		}
+	}
+}
+
+func TestSearchRequestCursor(t *testing.T) {
+	after := SearchCursor{Score: 4.5, ID: 10}.Encode()
+	garbage := "garbage!"
+
+	tests := []struct {
+		name    string
+		req     SearchRequest
+		want    *SearchCursor
+		wantErr bool
+	}{
+		{"no cursor", SearchRequest{}, nil, false},
+		{"default sort", SearchRequest{After: &after}, &SearchCursor{Score: 4.5, ID: 10}, false},
+		{"other sort", SearchRequest{After: &after, SortBy: "published_at"}, nil, true},
+		{"ascending score", SearchRequest{After: &after, SortOrder: "asc"}, nil, true},
+		{"distinct titles", SearchRequest{After: &after, DistinctTitles: true}, nil, true},
+		{"grouped by provider", SearchRequest{After: &after, GroupByProvider: true}, nil, true},
+		{"malformed", SearchRequest{After: &garbage}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Validate()
+			got, err := tt.req.Cursor()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Cursor() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("Cursor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchRequestApplyPeriod(t *testing.T) {
+	now := time.Date(2024, 3, 31, 15, 30, 0, 0, time.UTC)
+	endOfToday := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+	explicitStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	explicitEnd := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		req       SearchRequest
+		wantStart *time.Time
+		wantEnd   *time.Time
+		wantErr   bool
+	}{
+		{"no period", SearchRequest{}, nil, nil, false},
+		{"last week", SearchRequest{Period: PeriodLastWeek}, ptrTime(time.Date(2024, 3, 24, 0, 0, 0, 0, time.UTC)), &endOfToday, false},
+		// AddDate normalizes February 31st to March 2nd
+		{"last month", SearchRequest{Period: PeriodLastMonth}, ptrTime(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)), &endOfToday, false},
+		{"last 3 months", SearchRequest{Period: PeriodLast3Months}, ptrTime(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)), &endOfToday, false},
+		{"last year", SearchRequest{Period: PeriodLastYear}, ptrTime(time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC)), &endOfToday, false},
+		{"explicit dates win", SearchRequest{Period: PeriodLastWeek, StartDate: &explicitStart, EndDate: &explicitEnd}, &explicitStart, &explicitEnd, false},
+		{"explicit start only", SearchRequest{Period: PeriodLastWeek, StartDate: &explicitStart}, &explicitStart, &endOfToday, false},
+		{"explicit end only", SearchRequest{Period: PeriodLastYear, EndDate: &explicitEnd}, ptrTime(time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC)), &explicitEnd, false},
+		{"unknown period", SearchRequest{Period: "last_decade"}, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.ApplyPeriod(now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyPeriod() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !sameTime(tt.req.StartDate, tt.wantStart) {
+				t.Errorf("StartDate = %v, want %v", tt.req.StartDate, tt.wantStart)
+			}
+			if !sameTime(tt.req.EndDate, tt.wantEnd) {
+				t.Errorf("EndDate = %v, want %v", tt.req.EndDate, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func TestSearchParamErrorsExtremePages(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantField string // empty when the query is valid
+	}{
+		{"page=1&per_page=1", ""},
+		{"page=99999999&per_page=100", ""}, // well-formed; the result window rejects it later
+		{"page=0", "page"},
+		{"page=-1", "page"},
+		{"page=99999999999999999999", "page"},
+		{"page=1.5", "page"},
+		{"per_page=0", "per_page"},
+		{"per_page=-10", "per_page"},
+		{"per_page=abc", "per_page"},
+		{"min_views=-1", "min_views"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fields := SearchParamErrors(values, DefaultMaxPerPage)
+			if tt.wantField == "" {
+				if len(fields) > 0 {
+					t.Errorf("SearchParamErrors = %v, want none", fields)
+				}
+				return
+			}
+			if fields[tt.wantField] == "" || len(fields) != 1 {
+				t.Errorf("SearchParamErrors = %v, want only %s", fields, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestSearchParamErrorsPerPageMessage(t *testing.T) {
+	values := url.Values{"per_page": {"0"}}
+	tests := []struct {
+		maxPerPage int
+		want       string
+	}{
+		{50, "per_page must be an integer between 1 and 50; larger values are capped at 50"},
+		{0, "per_page must be an integer between 1 and 100; larger values are capped at 100"},
+	}
+	for _, tt := range tests {
+		if got := SearchParamErrors(values, tt.maxPerPage)["per_page"]; got != tt.want {
+			t.Errorf("max %d: per_page message = %q, want %q", tt.maxPerPage, got, tt.want)
+		}
+	}
+
+	// Values over the cap are capped later, not rejected
+	if fields := SearchParamErrors(url.Values{"per_page": {"500"}}, 50); len(fields) > 0 {
+		t.Errorf("SearchParamErrors = %v, want none for a per_page over the cap", fields)
+	}
+}
+
+func TestCheckResultWindowExtremePages(t *testing.T) {
+	tests := []struct {
+		page    int
+		perPage int
+		wantErr bool
+	}{
+		{100, 100, false},
+		{101, 100, true},
+		{99999999, 100, true},
+		// page * per_page overflows int; it must still be rejected, not wrap around
+		{math.MaxInt / 50, 100, true},
+		{math.MaxInt, 100, true},
+	}
+
+	for _, tt := range tests {
+		r := SearchRequest{Page: tt.page, PerPage: tt.perPage}
+		if err := r.CheckResultWindow(10000); (err != nil) != tt.wantErr {
+			t.Errorf("page %d, per_page %d: CheckResultWindow() = %v, want error %t", tt.page, tt.perPage, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSearchRequestPastLastPage(t *testing.T) {
+	tests := []struct {
+		page, perPage, total int
+		want                 bool
+	}{
+		{1, 10, 0, true},
+		{1, 10, 5, false},
+		{2, 10, 10, true},
+		{2, 10, 11, false},
+		{math.MaxInt, 100, 1000, true},
+		{5, 10, -1, false}, // Unknown total
+		{5, 0, 10, false},  // per_page not validated yet
+	}
+
+	for _, tt := range tests {
+		r := SearchRequest{Page: tt.page, PerPage: tt.perPage}
+		if got := r.PastLastPage(tt.total); got != tt.want {
+			t.Errorf("page %d, per_page %d, total %d: PastLastPage() = %t, want %t", tt.page, tt.perPage, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateTotalPagesGroups(t *testing.T) {
+	resp := &SearchResponse{
+		Total:   14,
+		PerPage: 5,
+		Groups:  []ProviderGroup{{ProviderID: 1, Count: 3}, {ProviderID: 2, Count: 11}},
+	}
+	resp.CalculateTotalPages()
+	// Pages follow the largest group, not the rows of all groups together
+	if resp.TotalPages != 3 {
+		t.Errorf("total_pages = %d, want 3", resp.TotalPages)
+	}
+
+	resp = &SearchResponse{PerPage: 5, Groups: []ProviderGroup{}}
+	resp.CalculateTotalPages()
+	if resp.TotalPages != 0 {
+		t.Errorf("total_pages with no groups = %d, want 0", resp.TotalPages)
+	}
+}
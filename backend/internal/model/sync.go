@@ -0,0 +1,78 @@
+// sync.go - Provider sync models
+// Defines the persisted outcome of sync runs and the reports built from them
+package model
+
+import (
+	"time"
+)
+
+// SyncStatus represents the outcome of a provider sync run
+type SyncStatus string
+
+const (
+	SyncStatusSuccess SyncStatus = "success"
+	SyncStatusFailed  SyncStatus = "failed"
+)
+
+// SyncResult is one provider sync run
+// This matches the database schema in the sync_history table
+type SyncResult struct {
+	ID            int64      `json:"id" db:"id"`
+	ProviderID    int        `json:"provider_id" db:"provider_id"`
+	StartedAt     time.Time  `json:"started_at" db:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+	ItemsFetched  int        `json:"items_fetched" db:"items_fetched"`
+	ItemsSkipped  int        `json:"items_skipped" db:"items_skipped"`   // Provider items that could not be transformed
+	ItemsUpserted int        `json:"items_upserted" db:"items_upserted"` // Fetched items saved to the database
+	TagsDropped   int        `json:"tags_dropped" db:"tags_dropped"`     // Tags rejected by the tag limits
+	Status        SyncStatus `json:"status" db:"status"`
+	ErrorMessage  string     `json:"error_message,omitempty" db:"error_message"`
+}
+
+// SyncHistoryResponse is the sync run history of a provider, newest first
+type SyncHistoryResponse struct {
+	ProviderID int           `json:"provider_id"`
+	History    []*SyncResult `json:"history"`
+}
+
+// ProviderSyncReport is the outcome of syncing one provider during a sync run
+type ProviderSyncReport struct {
+	Provider      string `json:"provider"`
+	Disabled      bool   `json:"disabled,omitempty"` // Skipped because the provider is disabled
+	ItemsFetched  int    `json:"items_fetched"`
+	ItemsSkipped  int    `json:"items_skipped"`
+	ItemsUpserted int    `json:"items_upserted"`
+	TagsDropped   int    `json:"tags_dropped"`
+	DurationMs    int64  `json:"duration_ms"`
+	Error         string `json:"error,omitempty"`
+}
+
+// SyncSummary is the outcome of a full sync: every provider, then the score recalculation
+type SyncSummary struct {
+	StartedAt   time.Time            `json:"started_at"`
+	DurationMs  int64                `json:"duration_ms"`
+	Providers   []ProviderSyncReport `json:"providers"`
+	ScoreErrors []string             `json:"score_errors,omitempty"` // Providers whose scores failed to recalculate
+}
+
+// SyncDelta describes what a provider's last sync changed
+// Updated counts existing rows written since the sync started, which includes
+// the score recalculation that follows every sync
+type SyncDelta struct {
+	ProviderID   int        `json:"provider_id"`
+	ProviderName string     `json:"provider_name"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	Status       SyncStatus `json:"status"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+	ItemsFetched int        `json:"items_fetched"`
+	NewItems     int        `json:"new_items"`
+	UpdatedItems int        `json:"updated_items"`
+}
+
+// SyncDeltaReport aggregates the last sync delta of every provider
+type SyncDeltaReport struct {
+	Providers    []SyncDelta `json:"providers"`
+	TotalNew     int         `json:"total_new"`
+	TotalUpdated int         `json:"total_updated"`
+}
@@ -5,6 +5,8 @@ package model
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -15,7 +17,7 @@ func ValidateContent(c *Content) error {
 		return errors.New("title is required")
 	}
 
-	if c.Type != ContentTypeVideo && c.Type != ContentTypeArticle {
+	if !slices.Contains(ContentTypes, c.Type) {
 		return errors.New("type must be 'video' or 'article'")
 	}
 
@@ -62,7 +64,10 @@ func ValidateContent(c *Content) error {
 
 // ValidateProvider validates a Provider model
 // Ensures all required fields are present and valid
-func ValidateProvider(p *Provider) error {
+// sameURL is the provider already registered for p's URL, or nil. A provider reusing
+// another's row for the same feed must read it in the same format: the feed is synced
+// under the stored row's name, which would otherwise disagree with the parser used
+func ValidateProvider(p *Provider, sameURL *Provider) error {
 	if p.Name == "" {
 		return errors.New("name is required")
 	}
@@ -75,7 +80,7 @@ func ValidateProvider(p *Provider) error {
 		return errors.New("url must start with http:// or https://")
 	}
 
-	if p.Format != ProviderFormatJSON && p.Format != ProviderFormatXML {
+	if !slices.Contains(ProviderFormats, p.Format) {
 		return errors.New("format must be 'json' or 'xml'")
 	}
 
@@ -83,6 +88,19 @@ func ValidateProvider(p *Provider) error {
 		return errors.New("rate_limit_per_minute must be at least 1")
 	}
 
+	if sameURL != nil && sameURL.ID != p.ID && sameURL.Format != p.Format {
+		return fmt.Errorf("url is already registered to %s provider %s, not %s", sameURL.Format, sameURL.Name, p.Format)
+	}
+
+	if p.FieldMapping != nil {
+		if p.Format != ProviderFormatJSON {
+			return errors.New("field_mapping is only supported for json providers")
+		}
+		if err := p.FieldMapping.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -112,27 +130,65 @@ func NormalizeProviderFormat(s string) ProviderFormat {
 	return ProviderFormatJSON // Default fallback
 }
 
-// ParseDuration parses a duration string (e.g., "15:30") to seconds
-// Returns the duration in seconds and any error
+// NormalizeTagOrder normalizes a tag order string
+// Unknown values fall back to alphabetical order for backward compatibility
+func NormalizeTagOrder(s string) TagOrder {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == string(TagOrderInsertion) {
+		return TagOrderInsertion
+	}
+	return TagOrderAlpha
+}
+
+// NormalizeMatchMode normalizes a match mode string
+// Unknown values fall back to any, which keeps the original OR-like matching
+func NormalizeMatchMode(s string) MatchMode {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == string(MatchModeAll) {
+		return MatchModeAll
+	}
+	return MatchModeAny
+}
+
+// ParseDuration parses a duration string (e.g., "15:30" or "1:23:45") to seconds
+// Returns nil for an empty string; see ParseDurationSeconds for accepted formats
 func ParseDuration(durationStr string) (*int, error) {
 	if durationStr == "" {
 		return nil, nil
 	}
 
-	parts := strings.Split(durationStr, ":")
-	if len(parts) != 2 {
-		return nil, errors.New("invalid duration format, expected MM:SS or HH:MM:SS")
+	totalSeconds, err := ParseDurationSeconds(durationStr)
+	if err != nil {
+		return nil, err
+	}
+	return &totalSeconds, nil
+}
+
+// ParseDurationSeconds parses an "MM:SS" or "HH:MM:SS" duration to seconds
+// Minutes may exceed 59 in MM:SS ("90:00"), but seconds, and minutes after an
+// hour field, must be below 60; any other shape is an error
+func ParseDurationSeconds(durationStr string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(durationStr), ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return 0, fmt.Errorf("invalid duration %q, expected MM:SS or HH:MM:SS", durationStr)
 	}
 
-	var totalSeconds int
-	if len(parts) == 2 {
-		// MM:SS format
-		var minutes, seconds int
-		if _, err := fmt.Sscanf(durationStr, "%d:%d", &minutes, &seconds); err != nil {
-			return nil, err
+	values := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || strings.HasPrefix(part, "+") {
+			return 0, fmt.Errorf("invalid duration %q, expected MM:SS or HH:MM:SS", durationStr)
 		}
-		totalSeconds = minutes*60 + seconds
+		values[i] = n
 	}
 
-	return &totalSeconds, nil
+	// Every field after the first is a base-60 digit
+	totalSeconds := values[0]
+	for _, v := range values[1:] {
+		if v >= 60 {
+			return 0, fmt.Errorf("invalid duration %q, minutes and seconds must be below 60", durationStr)
+		}
+		totalSeconds = totalSeconds*60 + v
+	}
+	return totalSeconds, nil
 }
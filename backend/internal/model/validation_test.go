@@ -0,0 +1,73 @@
+package model
+
+import "testing"
+
+func TestParseDurationSeconds(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"05:30", 330, false},
+		{"1:23:45", 5025, false},
+		{"0:00", 0, false},
+		{"90:00", 5400, false},
+		{" 12:05 ", 725, false},
+		{"abc", 0, true},
+		{"1:2:3:4", 0, true},
+		{"12", 0, true},
+		{"1:60", 0, true},
+		{"1:60:00", 0, true},
+		{"1:-5", 0, true},
+		{"+1:05", 0, true},
+		{"1:", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDurationSeconds(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDurationSeconds(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDurationSeconds(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	if got, err := ParseDuration(""); err != nil || got != nil {
+		t.Errorf("ParseDuration(\"\") = %v, %v, want nil, nil", got, err)
+	}
+	if got, err := ParseDuration("1:23:45"); err != nil || got == nil || *got != 5025 {
+		t.Errorf("ParseDuration(\"1:23:45\") = %v, %v, want 5025", got, err)
+	}
+	if _, err := ParseDuration("abc"); err == nil {
+		t.Error("ParseDuration(\"abc\") error = nil")
+	}
+}
+
+func TestValidateProviderSameURL(t *testing.T) {
+	configured := &Provider{Name: "provider1", URL: "https://example.com/feed", Format: ProviderFormatJSON, RateLimitPerMinute: 60}
+
+	tests := []struct {
+		name    string
+		sameURL *Provider
+		wantErr bool
+	}{
+		{"no provider on the url", nil, false},
+		{"reused row in the same format", &Provider{ID: 3, Name: "legacy", Format: ProviderFormatJSON}, false},
+		{"reused row in another format", &Provider{ID: 3, Name: "legacy", Format: ProviderFormatXML}, true},
+		{"the provider itself changing format", &Provider{ID: 0, Name: "provider1", Format: ProviderFormatXML}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateProvider(configured, tt.sameURL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateProvider() error = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,15 +3,22 @@
 package provider
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
 	"search-engine/backend/internal/model"
+	"time"
 )
 
 // Provider defines the interface that all content providers must implement
 // This allows us to work with different providers (JSON, XML, etc.) uniformly
 type Provider interface {
 	// Fetch retrieves content from the provider's API
-	// Returns a list of standardized Content models
-	Fetch() ([]*model.Content, error)
+	// Returns the standardized Content models and a TransformError for every
+	// item that couldn't be transformed; the error is set only when the fetch failed
+	// Cancelling ctx abandons the fetch, including any remaining pages and retries
+	Fetch(ctx context.Context) ([]*model.Content, []TransformError, error)
 
 	// GetName returns the provider's identifier name
 	GetName() string
@@ -36,3 +43,109 @@ func (p *BaseProvider) GetName() string {
 func (p *BaseProvider) GetURL() string {
 	return p.URL
 }
+
+// transformItems converts raw provider items with transform, setting aside the ones it rejects
+// Each rejected item comes back as a TransformError with its raw form and reason, so a
+// partial failure doesn't stop the sync but doesn't go unnoticed either
+func transformItems[T any](items []T, id func(T) string, transform func(T) (*model.Content, error)) ([]*model.Content, []TransformError) {
+	contents := make([]*model.Content, 0, len(items))
+	var rejected []TransformError
+	for _, item := range items {
+		content, err := transform(item)
+		if err != nil {
+			rejected = append(rejected, newTransformError(id(item), item, err))
+			continue
+		}
+		contents = append(contents, content)
+	}
+	return contents, rejected
+}
+
+// fetchBody downloads the raw response body from url, retrying transient failures per retry
+// Goes through the shared response cache so back-to-back fetches reuse the body
+// and callers waiting on an in-flight download share its retries
+func fetchBody(ctx context.Context, client *http.Client, url string, retry RetryPolicy) ([]byte, error) {
+	return responseCache.do(url, func() ([]byte, error) {
+		return withRetry(ctx, retry, url, func() ([]byte, error) {
+			return fetchOnce(ctx, client, url)
+		})
+	})
+}
+
+// fetchOnce performs a single GET of url and reads the body
+func fetchOnce(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Setting the header ourselves turns off the transport's transparent gzip
+	// handling, so decodeBody decompresses whatever comes back
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Surface throttling separately so the manager can back off
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, &ThrottledError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	// Check HTTP status code
+	// Non-200 status codes indicate an error
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	// Read response body, decompressing it if the provider compressed it
+	body, err := decodeBody(resp.Body, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
+
+// FormatMismatchError is returned when a provider's response isn't in its configured format
+// Usually a misconfigured provider row, e.g. a JSON provider pointing at an XML feed
+type FormatMismatchError struct {
+	Configured model.ProviderFormat
+	Detected   model.ProviderFormat
+}
+
+func (e *FormatMismatchError) Error() string {
+	return fmt.Sprintf("provider format mismatch: configured=%s, detected=%s", e.Configured, e.Detected)
+}
+
+// utf8BOM is skipped when sniffing a response's format
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// detectFormat sniffs the format of a response body from its first significant byte
+// Returns an empty format when the body looks like neither JSON nor XML
+func detectFormat(body []byte) model.ProviderFormat {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(body, utf8BOM), " \t\r\n")
+	if len(trimmed) == 0 {
+		return ""
+	}
+	switch trimmed[0] {
+	case '{', '[':
+		return model.ProviderFormatJSON
+	case '<':
+		return model.ProviderFormatXML
+	default:
+		return ""
+	}
+}
+
+// checkFormat fails with a FormatMismatchError when body is clearly in another format
+// Bodies that can't be sniffed are left to the parser to reject
+func checkFormat(body []byte, configured model.ProviderFormat) error {
+	detected := detectFormat(body)
+	if detected != "" && detected != configured {
+		return &FormatMismatchError{Configured: configured, Detected: detected}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"errors"
+	"search-engine/backend/internal/model"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want model.ProviderFormat
+	}{
+		{"json object", `{"contents": []}`, model.ProviderFormatJSON},
+		{"json array", `[1, 2]`, model.ProviderFormatJSON},
+		{"xml declaration", `<?xml version="1.0"?><feed/>`, model.ProviderFormatXML},
+		{"xml without declaration", `<feed></feed>`, model.ProviderFormatXML},
+		{"leading whitespace", "\n\t  {\"a\": 1}", model.ProviderFormatJSON},
+		{"utf-8 bom", "\xEF\xBB\xBF<feed/>", model.ProviderFormatXML},
+		{"empty", "", ""},
+		{"plain text", "Service Unavailable", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectFormat([]byte(tt.body)); got != tt.want {
+				t.Errorf("detectFormat(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	err := checkFormat([]byte(`<?xml version="1.0"?><feed/>`), model.ProviderFormatJSON)
+
+	var mismatch *FormatMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected FormatMismatchError, got %v", err)
+	}
+	if want := "provider format mismatch: configured=json, detected=xml"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+
+	if err := checkFormat([]byte(`{"contents": []}`), model.ProviderFormatJSON); err != nil {
+		t.Errorf("matching format: unexpected error %v", err)
+	}
+	if err := checkFormat([]byte("not a feed"), model.ProviderFormatXML); err != nil {
+		t.Errorf("unknown format: unexpected error %v", err)
+	}
+}
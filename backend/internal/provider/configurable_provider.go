@@ -0,0 +1,319 @@
+// configurable_provider.go - Generic provider driven by a field mapping
+// Reads any JSON feed whose items can be located with the paths in a model.FieldMapping
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"search-engine/backend/internal/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigurableProvider implements the Provider interface for mapped JSON feeds
+// Unlike JSONProvider it has no fixed item struct; every field is looked up by path
+type ConfigurableProvider struct {
+	BaseProvider
+	client      *http.Client
+	mapping     model.FieldMapping
+	dateLayouts []string
+	retry       RetryPolicy
+}
+
+// NewConfigurableProvider creates a provider that reads items with mapping
+// Date layouts come from the mapping; empty uses DefaultJSONDateLayouts
+func NewConfigurableProvider(name, url string, mapping model.FieldMapping, timeouts HTTPTimeouts, retry RetryPolicy) *ConfigurableProvider {
+	return &ConfigurableProvider{
+		BaseProvider: BaseProvider{
+			Name: name,
+			URL:  url,
+		},
+		client:      newHTTPClient(timeouts),
+		mapping:     mapping,
+		dateLayouts: dateLayoutsOrDefault(mapping.DateLayouts, DefaultJSONDateLayouts),
+		retry:       retry.withDefaults(),
+	}
+}
+
+// NewFromModel builds the provider for a stored provider that carries a field mapping
+// Returns an error for providers without one, which use the built-in JSON or XML providers
+func NewFromModel(p *model.Provider, timeouts HTTPTimeouts, retry RetryPolicy) (Provider, error) {
+	if p.FieldMapping == nil {
+		return nil, fmt.Errorf("provider %s has no field mapping", p.Name)
+	}
+	if err := model.ValidateProvider(p, nil); err != nil {
+		return nil, fmt.Errorf("provider %s: %w", p.Name, err)
+	}
+	return NewConfigurableProvider(p.Name, p.URL, *p.FieldMapping, timeouts, retry), nil
+}
+
+// Fetch retrieves content from the provider and maps each item with the field mapping
+func (p *ConfigurableProvider) Fetch(ctx context.Context) ([]*model.Content, []TransformError, error) {
+	body, err := fetchBody(ctx, p.client, p.URL, p.retry)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch from configurable provider: %w", err)
+	}
+
+	if err := checkFormat(body, model.ProviderFormatJSON); err != nil {
+		return nil, nil, err
+	}
+
+	return p.parse(body)
+}
+
+// parse decodes body and transforms the items found at the mapping's items path
+func (p *ConfigurableProvider) parse(body []byte) ([]*model.Content, []TransformError, error) {
+	// Decode numbers as json.Number so large IDs and counts keep their exact digits
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var payload any
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	raw, ok := lookupPath(payload, p.mapping.ItemsPath)
+	if !ok {
+		return nil, nil, fmt.Errorf("items path %q not found in response", p.mapping.ItemsPath)
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, nil, fmt.Errorf("items path %q is not an array", p.mapping.ItemsPath)
+	}
+
+	contents, rejected := transformItems(items,
+		func(item any) string {
+			id, _ := p.stringField(item, p.mapping.ID)
+			return id
+		},
+		p.transformToContent)
+
+	return contents, rejected, nil
+}
+
+// transformToContent converts one mapped item to a standard Content model
+func (p *ConfigurableProvider) transformToContent(item any) (*model.Content, error) {
+	id, ok := p.stringField(item, p.mapping.ID)
+	if !ok || id == "" {
+		return nil, &FieldError{Field: "id", Err: fmt.Errorf("missing value at %q", p.mapping.ID)}
+	}
+	title, ok := p.stringField(item, p.mapping.Title)
+	if !ok || title == "" {
+		return nil, &FieldError{Field: "title", Err: fmt.Errorf("missing value at %q", p.mapping.Title)}
+	}
+
+	contentType, ok := p.stringField(item, p.mapping.Type)
+	if !ok || contentType == "" {
+		contentType = p.mapping.DefaultType
+	}
+
+	content := &model.Content{
+		ExternalID: id,
+		Title:      title,
+		Type:       model.NormalizeContentType(contentType),
+	}
+
+	publishedAt, err := p.timeField(item, p.mapping.PublishedAt)
+	if err != nil {
+		return nil, &FieldError{Field: "published_at", Err: err}
+	}
+	content.PublishedAt = publishedAt
+
+	if content.IsVideo() {
+		if content.Views, err = p.intField(item, "views", p.mapping.Views); err != nil {
+			return nil, err
+		}
+		if content.Likes, err = p.intField(item, "likes", p.mapping.Likes); err != nil {
+			return nil, err
+		}
+		if content.DurationSeconds, err = p.durationField(item, p.mapping.Duration); err != nil {
+			return nil, err
+		}
+	} else if content.IsArticle() {
+		if content.ReadingTime, err = p.optionalIntField(item, "reading_time", p.mapping.ReadingTime); err != nil {
+			return nil, err
+		}
+		if content.Reactions, err = p.intField(item, "reactions", p.mapping.Reactions); err != nil {
+			return nil, err
+		}
+		if content.Comments, err = p.intField(item, "comments", p.mapping.Comments); err != nil {
+			return nil, err
+		}
+	}
+
+	content.Tags = p.tagsField(item, p.mapping.Tags)
+
+	return content, nil
+}
+
+// stringField reads the value at path as a string; numbers are formatted as written
+func (p *ConfigurableProvider) stringField(item any, path string) (string, bool) {
+	if path == "" {
+		return "", false
+	}
+	value, ok := lookupPath(item, path)
+	if !ok {
+		return "", false
+	}
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v), true
+	case json.Number:
+		return v.String(), true
+	default:
+		return "", false
+	}
+}
+
+// intField reads the integer at path; an unmapped or missing field is 0
+func (p *ConfigurableProvider) intField(item any, name, path string) (int, error) {
+	n, err := p.optionalIntField(item, name, path)
+	if err != nil || n == nil {
+		return 0, err
+	}
+	return *n, nil
+}
+
+// optionalIntField reads the integer at path; an unmapped or missing field is nil
+func (p *ConfigurableProvider) optionalIntField(item any, name, path string) (*int, error) {
+	if path == "" {
+		return nil, nil
+	}
+	value, ok := lookupPath(item, path)
+	if !ok {
+		return nil, nil
+	}
+	n, err := toInt(value)
+	if err != nil {
+		return nil, &FieldError{Field: name, Err: err}
+	}
+	return &n, nil
+}
+
+// durationField reads a duration given as "MM:SS", "HH:MM:SS" or a number of seconds
+func (p *ConfigurableProvider) durationField(item any, path string) (*int, error) {
+	if path == "" {
+		return nil, nil
+	}
+	value, ok := lookupPath(item, path)
+	if !ok {
+		return nil, nil
+	}
+	if s, isString := value.(string); isString && strings.Contains(s, ":") {
+		seconds, err := model.ParseDurationSeconds(s)
+		if err != nil {
+			return nil, &FieldError{Field: "duration", Err: err}
+		}
+		return &seconds, nil
+	}
+	seconds, err := toInt(value)
+	if err != nil {
+		return nil, &FieldError{Field: "duration", Err: err}
+	}
+	return &seconds, nil
+}
+
+// timeField reads a timestamp given as a date string or as Unix seconds
+func (p *ConfigurableProvider) timeField(item any, path string) (time.Time, error) {
+	value, ok := lookupPath(item, path)
+	if !ok {
+		return time.Time{}, fmt.Errorf("missing value at %q", path)
+	}
+	switch v := value.(type) {
+	case string:
+		return parseDate(v, p.dateLayouts)
+	case json.Number:
+		seconds, err := v.Int64()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid unix timestamp %s", v)
+		}
+		return time.Unix(seconds, 0).UTC(), nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported value %v at %q", value, path)
+	}
+}
+
+// tagsField reads tags from an array of strings or a comma-separated string
+func (p *ConfigurableProvider) tagsField(item any, path string) []string {
+	if path == "" {
+		return nil
+	}
+	value, ok := lookupPath(item, path)
+	if !ok {
+		return nil
+	}
+
+	var raw []string
+	switch v := value.(type) {
+	case []any:
+		for _, tag := range v {
+			if s, ok := tag.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	case string:
+		raw = strings.Split(v, ",")
+	}
+
+	tags := make([]string, 0, len(raw))
+	for _, tag := range raw {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// lookupPath walks a decoded JSON value along a dot-separated path
+// Segments index objects by key and arrays by position; an empty path returns v itself
+func lookupPath(v any, path string) (any, bool) {
+	if path == "" {
+		return v, true
+	}
+	for _, segment := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	if v == nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// toInt converts a JSON number, or a string holding one, to an int
+// Fractional numbers are truncated, since some feeds send counts as floats
+func toInt(value any) (int, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return int(n), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %s", v)
+		}
+		return int(math.Trunc(f)), nil
+	case string:
+		return parseIntString(v)
+	default:
+		return 0, fmt.Errorf("unsupported value %v", value)
+	}
+}
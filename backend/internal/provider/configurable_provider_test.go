@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"reflect"
+	"search-engine/backend/internal/model"
+	"testing"
+	"time"
+)
+
+func TestConfigurableProviderMapsNestedPayload(t *testing.T) {
+	body := []byte(`{
+		"data": {"items": [
+			{"uid": 101, "headline": "Go Tutorial", "kind": "video",
+			 "stats": {"views": 1500, "likes": "120", "length": "12:30"},
+			 "released": "2024-03-15T10:00:00+02:00", "labels": ["go", " tutorial "]},
+			{"uid": 102, "headline": "Go Tips", "kind": "text",
+			 "stats": {"read_minutes": 7, "reactions": 40.0},
+			 "released": "2024-03-16T08:00:00Z", "labels": []},
+			{"uid": 103, "kind": "video", "released": "2024-03-17T08:00:00Z"}
+		]}
+	}`)
+	p := NewConfigurableProvider("mapped", "", model.FieldMapping{
+		ItemsPath:   "data.items",
+		ID:          "uid",
+		Title:       "headline",
+		Type:        "kind",
+		PublishedAt: "released",
+		Views:       "stats.views",
+		Likes:       "stats.likes",
+		Duration:    "stats.length",
+		ReadingTime: "stats.read_minutes",
+		Reactions:   "stats.reactions",
+		Tags:        "labels",
+	}, DefaultHTTPTimeouts(), DefaultRetryPolicy())
+
+	contents, rejected, err := p.parse(body)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(contents) != 2 {
+		t.Fatalf("got %d contents, want 2", len(contents))
+	}
+	if len(rejected) != 1 || rejected[0].ExternalID != "103" || rejected[0].Field != "title" {
+		t.Errorf("rejected = %+v, want item 103 rejected for its title", rejected)
+	}
+
+	duration := 750
+	wantVideo := &model.Content{
+		ExternalID:      "101",
+		Title:           "Go Tutorial",
+		Type:            model.ContentTypeVideo,
+		Views:           1500,
+		Likes:           120,
+		DurationSeconds: &duration,
+		PublishedAt:     time.Date(2024, 3, 15, 8, 0, 0, 0, time.UTC),
+		Tags:            []string{"go", "tutorial"},
+	}
+	if !reflect.DeepEqual(contents[0], wantVideo) {
+		t.Errorf("video = %+v, want %+v", contents[0], wantVideo)
+	}
+
+	readingTime := 7
+	wantArticle := &model.Content{
+		ExternalID:  "102",
+		Title:       "Go Tips",
+		Type:        model.ContentTypeArticle,
+		ReadingTime: &readingTime,
+		Reactions:   40,
+		PublishedAt: time.Date(2024, 3, 16, 8, 0, 0, 0, time.UTC),
+		Tags:        []string{},
+	}
+	if !reflect.DeepEqual(contents[1], wantArticle) {
+		t.Errorf("article = %+v, want %+v", contents[1], wantArticle)
+	}
+}
+
+func TestConfigurableProviderMapsTopLevelArray(t *testing.T) {
+	body := []byte(`[
+		{"id": "a-1", "name": "Intro to Go", "date": "15/03/2024", "seconds": 95, "meta": {"tags": "go, basics"}},
+		{"id": "a-2", "name": "Channels", "date": 1710460800, "seconds": "not a number"}
+	]`)
+	p := NewConfigurableProvider("mapped", "", model.FieldMapping{
+		ID:          "id",
+		Title:       "name",
+		DefaultType: "video",
+		PublishedAt: "date",
+		DateLayouts: []string{"02/01/2006"},
+		Duration:    "seconds",
+		Tags:        "meta.tags",
+	}, DefaultHTTPTimeouts(), DefaultRetryPolicy())
+
+	contents, rejected, err := p.parse(body)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(contents) != 1 {
+		t.Fatalf("got %d contents, want 1", len(contents))
+	}
+	if len(rejected) != 1 || rejected[0].ExternalID != "a-2" || rejected[0].Field != "duration" {
+		t.Errorf("rejected = %+v, want item a-2 rejected for its duration", rejected)
+	}
+
+	c := contents[0]
+	if c.ExternalID != "a-1" || c.Type != model.ContentTypeVideo {
+		t.Errorf("content = %+v, want video a-1", c)
+	}
+	if c.DurationSeconds == nil || *c.DurationSeconds != 95 {
+		t.Errorf("duration = %v, want 95", c.DurationSeconds)
+	}
+	if want := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC); !c.PublishedAt.Equal(want) {
+		t.Errorf("published_at = %s, want %s", c.PublishedAt, want)
+	}
+	if want := []string{"go", "basics"}; !reflect.DeepEqual(c.Tags, want) {
+		t.Errorf("tags = %v, want %v", c.Tags, want)
+	}
+}
+
+func TestConfigurableProviderItemsPathErrors(t *testing.T) {
+	p := NewConfigurableProvider("mapped", "", model.FieldMapping{
+		ItemsPath: "data.items", ID: "id", Title: "title", PublishedAt: "date", DefaultType: "video",
+	}, DefaultHTTPTimeouts(), DefaultRetryPolicy())
+
+	for _, body := range []string{`{"data": {}}`, `{"data": {"items": {"id": 1}}}`} {
+		if _, _, err := p.parse([]byte(body)); err == nil {
+			t.Errorf("parse(%s) error = nil, want an items path error", body)
+		}
+	}
+}
+
+func TestLookupPath(t *testing.T) {
+	doc := map[string]any{
+		"a": map[string]any{"b": []any{"x", map[string]any{"c": "deep"}}},
+		"n": nil,
+	}
+	tests := []struct {
+		path   string
+		want   any
+		wantOK bool
+	}{
+		{"a.b.0", "x", true},
+		{"a.b.1.c", "deep", true},
+		{"a.b.2", nil, false},
+		{"a.b.x", nil, false},
+		{"a.missing", nil, false},
+		{"n", nil, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := lookupPath(doc, tt.path)
+		if ok != tt.wantOK || (ok && got != tt.want) {
+			t.Errorf("lookupPath(%q) = %v, %v, want %v, %v", tt.path, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNewFromModelRequiresValidMapping(t *testing.T) {
+	stored := &model.Provider{
+		Name: "mapped", URL: "https://example.com/feed", Format: model.ProviderFormatJSON, RateLimitPerMinute: 60,
+	}
+	if _, err := NewFromModel(stored, DefaultHTTPTimeouts(), DefaultRetryPolicy()); err == nil {
+		t.Error("NewFromModel() without a mapping error = nil")
+	}
+
+	stored.FieldMapping = &model.FieldMapping{ID: "id", Title: "title"}
+	if _, err := NewFromModel(stored, DefaultHTTPTimeouts(), DefaultRetryPolicy()); err == nil {
+		t.Error("NewFromModel() with an incomplete mapping error = nil")
+	}
+
+	stored.FieldMapping.PublishedAt = "date"
+	stored.FieldMapping.DefaultType = "article"
+	if _, err := NewFromModel(stored, DefaultHTTPTimeouts(), DefaultRetryPolicy()); err != nil {
+		t.Errorf("NewFromModel() error = %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+// content_encoding.go - Compressed provider responses
+// Providers are asked for gzip or deflate bodies, which are decompressed before parsing
+package provider
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// acceptEncoding lists the compressions decodeBody understands
+const acceptEncoding = "gzip, deflate"
+
+// decodeBody reads body, undoing contentEncoding
+// deflate is zlib-wrapped per the HTTP spec, but some servers send raw deflate, so both are accepted
+func decodeBody(body io.Reader, contentEncoding string) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "", "identity":
+		return io.ReadAll(body)
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	case "deflate":
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		if zr, err := zlib.NewReader(bytes.NewReader(raw)); err == nil {
+			defer zr.Close()
+			return io.ReadAll(zr)
+		}
+		fr := flate.NewReader(bytes.NewReader(raw))
+		defer fr.Close()
+		return io.ReadAll(fr)
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding %q", contentEncoding)
+	}
+}
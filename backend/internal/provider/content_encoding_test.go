@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func compress(t *testing.T, encoding, body string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw deflate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	default:
+		return []byte(body)
+	}
+	if _, err := io.WriteString(w, body); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeBody(t *testing.T) {
+	const body = `{"contents": []}`
+	tests := []struct {
+		name            string
+		encoding        string
+		contentEncoding string
+	}{
+		{"uncompressed", "", ""},
+		{"identity", "", "identity"},
+		{"gzip", "gzip", "gzip"},
+		{"gzip, mixed case", "gzip", " GZip "},
+		{"zlib deflate", "deflate", "deflate"},
+		{"raw deflate", "raw deflate", "deflate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeBody(bytes.NewReader(compress(t, tt.encoding, body)), tt.contentEncoding)
+			if err != nil {
+				t.Fatalf("decodeBody: %v", err)
+			}
+			if string(got) != body {
+				t.Errorf("decodeBody = %q, want %q", got, body)
+			}
+		})
+	}
+}
+
+func TestDecodeBodyErrors(t *testing.T) {
+	if _, err := decodeBody(strings.NewReader("not gzip"), "gzip"); err == nil {
+		t.Error("corrupt gzip body: expected an error")
+	}
+	if _, err := decodeBody(strings.NewReader("data"), "br"); err == nil || !strings.Contains(err.Error(), `"br"`) {
+		t.Errorf("unsupported encoding: error = %v, want one naming br", err)
+	}
+}
+
+// gzipFeed serves body gzip-compressed to clients that accept gzip and plain to the rest
+func gzipFeed(t *testing.T, body string) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			io.WriteString(w, body)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compress(t, "gzip", body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestProvidersDecodeGzipResponses(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		newFeed func(url string) Provider
+	}{
+		{
+			"JSON",
+			`{"contents": [{"id": "v1", "title": "Go Tutorial", "type": "video", "published_at": "2024-03-15T10:00:00Z"}]}`,
+			func(url string) Provider {
+				return NewJSONProvider("provider1", url, DefaultHTTPTimeouts(), nil, fastRetry, DefaultPaginationPolicy())
+			},
+		},
+		{
+			"XML",
+			`<?xml version="1.0"?><feed><items><item><id>v1</id><headline>Go Tutorial</headline><type>article</type><publication_date>2024-03-15</publication_date></item></items></feed>`,
+			func(url string) Provider {
+				return NewXMLProvider("provider2", url, DefaultHTTPTimeouts(), nil, fastRetry, DefaultPaginationPolicy())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := fetchIDs(t, tt.newFeed(gzipFeed(t, tt.body)))
+			if want := []string{"v1"}; !slices.Equal(ids, want) {
+				t.Errorf("fetched IDs = %v, want %v", ids, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// date_layouts.go - Configurable date parsing for provider items
+// Each provider tries its candidate layouts in order, so a new date format is a config change
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultJSONDateLayouts are tried when no layouts are configured for a JSON provider
+// Provider 1 sends ISO 8601 timestamps such as "2024-03-15T10:00:00Z"
+var DefaultJSONDateLayouts = []string{time.RFC3339}
+
+// DefaultXMLDateLayouts are tried when no layouts are configured for an XML provider
+// Provider 2 sends dates such as "2024-03-15", taken as midnight UTC
+var DefaultXMLDateLayouts = []string{"2006-01-02"}
+
+// parseDate parses value with the first layout that matches
+// Values without a zone are read as UTC and offsets are converted, so every result is UTC
+func parseDate(value string, layouts []string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date %q matches none of the layouts %q", value, layouts)
+}
+
+// dateLayoutsOrDefault returns layouts, or defaults when none are configured
+func dateLayoutsOrDefault(layouts, defaults []string) []string {
+	if len(layouts) == 0 {
+		return defaults
+	}
+	return layouts
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	layouts := []string{time.RFC3339, "2006-01-02", "02/01/2006"}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"first layout", "2024-03-15T10:00:00Z", time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC), false},
+		{"offset converted to UTC", "2024-03-15T12:00:00+02:00", time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC), false},
+		{"second layout", "2024-03-15", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), false},
+		{"later layout", "15/03/2024", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), false},
+		{"surrounding whitespace", " 2024-03-15\n", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), false},
+		{"no layout matches", "March 15, 2024", time.Time{}, true},
+		{"empty", "", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDate(tt.value, layouts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) || (!tt.wantErr && got.Location() != time.UTC) {
+				t.Errorf("parseDate(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONProviderSkipsUnparseableDates(t *testing.T) {
+	p := NewJSONProvider("provider1", "", DefaultHTTPTimeouts(), []string{"2006-01-02 15:04"}, DefaultRetryPolicy(), DefaultPaginationPolicy())
+
+	if _, err := p.transformToContent(JSONContentItem{ID: "a", Title: "A", Type: "video", PublishedAt: "2024-03-15 10:00"}); err != nil {
+		t.Errorf("configured layout rejected: %v", err)
+	}
+	if _, err := p.transformToContent(JSONContentItem{ID: "b", Title: "B", Type: "video", PublishedAt: "2024-03-15T10:00:00Z"}); err == nil {
+		t.Error("default layout accepted although layouts were configured")
+	}
+}
@@ -0,0 +1,92 @@
+// fetch_cache.go - Short-lived cache of raw provider responses
+// Lets overlapping or back-to-back syncs reuse a download instead of hitting the upstream again
+package provider
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultFetchCacheTTL is how long a raw provider response is reused by default
+// Kept small so it only absorbs redundant fetches within a single sync window
+const DefaultFetchCacheTTL = 5 * time.Second
+
+// fetchCacheEntry holds one download, in flight or completed
+// done is closed once body/err/fetchedAt are set
+type fetchCacheEntry struct {
+	done      chan struct{}
+	body      []byte
+	err       error
+	fetchedAt time.Time
+}
+
+// ready reports whether the download has completed
+func (e *fetchCacheEntry) ready() bool {
+	select {
+	case <-e.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// fetchCache caches raw response bodies keyed by URL
+// Concurrent fetches of the same URL share a single download
+type fetchCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*fetchCacheEntry
+}
+
+// responseCache is shared by every provider instance in the process
+// Startup and manual syncs build separate providers, so the cache can't live on one of them
+var responseCache = &fetchCache{
+	ttl:     DefaultFetchCacheTTL,
+	entries: make(map[string]*fetchCacheEntry),
+}
+
+// SetFetchCacheTTL configures how long raw provider responses are reused
+// A TTL of 0 or less disables the cache
+func SetFetchCacheTTL(ttl time.Duration) {
+	responseCache.mu.Lock()
+	defer responseCache.mu.Unlock()
+
+	responseCache.ttl = ttl
+	responseCache.entries = make(map[string]*fetchCacheEntry)
+}
+
+// do returns the cached body for url, or calls fetch and caches its result
+// Failed downloads are not cached, but callers waiting on them share the error
+func (c *fetchCache) do(url string, fetch func() ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	if c.ttl <= 0 {
+		c.mu.Unlock()
+		return fetch()
+	}
+
+	entry, ok := c.entries[url]
+	if ok && (!entry.ready() || time.Since(entry.fetchedAt) < c.ttl) {
+		c.mu.Unlock()
+		<-entry.done
+		return entry.body, entry.err
+	}
+
+	// No usable entry: this caller performs the download
+	entry = &fetchCacheEntry{done: make(chan struct{})}
+	c.entries[url] = entry
+	c.mu.Unlock()
+
+	entry.body, entry.err = fetch()
+	entry.fetchedAt = time.Now()
+	close(entry.done)
+
+	if entry.err != nil {
+		c.mu.Lock()
+		if c.entries[url] == entry {
+			delete(c.entries, url)
+		}
+		c.mu.Unlock()
+	}
+
+	return entry.body, entry.err
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFetchCacheReusesRecentBody(t *testing.T) {
+	c := &fetchCache{ttl: time.Minute, entries: make(map[string]*fetchCacheEntry)}
+	calls := 0
+	fetch := func() ([]byte, error) {
+		calls++
+		return []byte("body"), nil
+	}
+
+	for i := 0; i < 3; i++ {
+		body, err := c.do("http://example.com/feed", fetch)
+		if err != nil || string(body) != "body" {
+			t.Fatalf("do() = %q, %v", body, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+}
+
+func TestFetchCacheDoesNotCacheErrorsOrWhenDisabled(t *testing.T) {
+	c := &fetchCache{ttl: time.Minute, entries: make(map[string]*fetchCacheEntry)}
+	calls := 0
+	failing := func() ([]byte, error) {
+		calls++
+		return nil, errors.New("boom")
+	}
+	c.do("http://example.com/feed", failing)
+	c.do("http://example.com/feed", failing)
+	if calls != 2 {
+		t.Errorf("failing fetch called %d times, want 2", calls)
+	}
+
+	disabled := &fetchCache{entries: make(map[string]*fetchCacheEntry)}
+	calls = 0
+	ok := func() ([]byte, error) {
+		calls++
+		return []byte("body"), nil
+	}
+	disabled.do("http://example.com/feed", ok)
+	disabled.do("http://example.com/feed", ok)
+	if calls != 2 {
+		t.Errorf("fetch with cache disabled called %d times, want 2", calls)
+	}
+}
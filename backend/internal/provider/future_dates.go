@@ -0,0 +1,75 @@
+// future_dates.go - Guard against items published in the future
+// A future published_at would keep an item at the top freshness score and the head
+// of newest-first sorts, so such items are clamped to the sync time or set aside
+package provider
+
+import (
+	"fmt"
+	"search-engine/backend/internal/config"
+	"search-engine/backend/internal/model"
+	"time"
+)
+
+// FutureDatePolicy configures what a sync does with items published too far ahead
+// An item counts as future-dated when its published_at is more than Tolerance after the sync time
+type FutureDatePolicy struct {
+	Action    config.FutureDateAction
+	Tolerance time.Duration // Slack for clock skew and timezone rounding
+}
+
+// DefaultFutureDatePolicy returns the future date policy used when none is configured
+func DefaultFutureDatePolicy() FutureDatePolicy {
+	return FutureDatePolicy{
+		Action:    config.FutureDateClamp,
+		Tolerance: 24 * time.Hour,
+	}
+}
+
+// FutureDatePolicyFromConfig converts the future date settings, keeping defaults for unset fields
+// A tolerance of 0 is kept: any published_at after the sync time is then future-dated
+func FutureDatePolicyFromConfig(cfg config.ProviderFutureDateConfig) FutureDatePolicy {
+	return FutureDatePolicy{
+		Action:    cfg.Action,
+		Tolerance: time.Duration(cfg.ToleranceHours) * time.Hour,
+	}.withDefaults()
+}
+
+// withDefaults fills an empty action and a negative tolerance from DefaultFutureDatePolicy
+func (p FutureDatePolicy) withDefaults() FutureDatePolicy {
+	defaults := DefaultFutureDatePolicy()
+	if p.Action == "" {
+		p.Action = defaults.Action
+	}
+	if p.Tolerance < 0 {
+		p.Tolerance = defaults.Tolerance
+	}
+	return p
+}
+
+// apply checks contents against now, returning the ones to save
+// Future-dated items get published_at = now when clamping and come back as
+// rejected when rejecting; clamped counts the items that were changed
+// Clamped items are marked so that only their first insert uses now
+func (p FutureDatePolicy) apply(contents []*model.Content, now time.Time) (kept []*model.Content, rejected []TransformError, clamped int) {
+	limit := now.Add(p.Tolerance)
+	kept = make([]*model.Content, 0, len(contents))
+	for _, c := range contents {
+		if !c.PublishedAt.After(limit) {
+			kept = append(kept, c)
+			continue
+		}
+		if p.Action == config.FutureDateReject {
+			err := &FieldError{
+				Field: "published_at",
+				Err:   fmt.Errorf("%s is more than %s in the future", c.PublishedAt.Format(time.RFC3339), p.Tolerance),
+			}
+			rejected = append(rejected, newTransformError(c.ExternalID, c, err))
+			continue
+		}
+		c.PublishedAt = now
+		c.PublishedAtClamped = true
+		kept = append(kept, c)
+		clamped++
+	}
+	return kept, rejected, clamped
+}
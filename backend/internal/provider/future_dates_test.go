@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"search-engine/backend/internal/config"
+	"search-engine/backend/internal/model"
+	"testing"
+	"time"
+)
+
+func TestFutureDatePolicyApply(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		action        config.FutureDateAction
+		publishedAt   time.Time
+		wantKept      bool
+		wantPublished time.Time
+	}{
+		{"past", config.FutureDateReject, now.AddDate(0, 0, -3), true, now.AddDate(0, 0, -3)},
+		{"inside the tolerance, clamp", config.FutureDateClamp, now.Add(23 * time.Hour), true, now.Add(23 * time.Hour)},
+		{"inside the tolerance, reject", config.FutureDateReject, now.Add(23 * time.Hour), true, now.Add(23 * time.Hour)},
+		{"at the tolerance", config.FutureDateReject, now.Add(24 * time.Hour), true, now.Add(24 * time.Hour)},
+		{"outside the tolerance, clamp", config.FutureDateClamp, now.Add(25 * time.Hour), true, now},
+		{"outside the tolerance, reject", config.FutureDateReject, now.Add(25 * time.Hour), false, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := FutureDatePolicy{Action: tt.action, Tolerance: 24 * time.Hour}
+			content := &model.Content{ExternalID: "v1", Title: "Go Tutorial", PublishedAt: tt.publishedAt}
+			kept, rejected, clamped := policy.apply([]*model.Content{content}, now)
+
+			if !tt.wantKept {
+				if len(kept) != 0 || len(rejected) != 1 {
+					t.Fatalf("kept %d, rejected %d; want the item rejected", len(kept), len(rejected))
+				}
+				if r := rejected[0]; r.ExternalID != "v1" || r.Field != "published_at" {
+					t.Errorf("rejected item %q field %q, want v1 field published_at", r.ExternalID, r.Field)
+				}
+				return
+			}
+			if len(kept) != 1 || len(rejected) != 0 {
+				t.Fatalf("kept %d, rejected %d; want the item kept", len(kept), len(rejected))
+			}
+			if !kept[0].PublishedAt.Equal(tt.wantPublished) {
+				t.Errorf("published_at = %s, want %s", kept[0].PublishedAt, tt.wantPublished)
+			}
+			if wantClamped := !tt.wantPublished.Equal(tt.publishedAt); (clamped == 1) != wantClamped || kept[0].PublishedAtClamped != wantClamped {
+				t.Errorf("clamped = %d, marked %t; want clamped %t", clamped, kept[0].PublishedAtClamped, wantClamped)
+			}
+		})
+	}
+}
+
+func TestFutureDatePolicyFromConfig(t *testing.T) {
+	got := FutureDatePolicyFromConfig(config.ProviderFutureDateConfig{ToleranceHours: -1})
+	if got != DefaultFutureDatePolicy() {
+		t.Errorf("unset config = %+v, want the defaults %+v", got, DefaultFutureDatePolicy())
+	}
+
+	got = FutureDatePolicyFromConfig(config.ProviderFutureDateConfig{Action: config.FutureDateReject, ToleranceHours: 0})
+	if got.Action != config.FutureDateReject || got.Tolerance != 0 {
+		t.Errorf("reject with no tolerance = %+v, want it kept as configured", got)
+	}
+}
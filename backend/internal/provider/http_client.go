@@ -0,0 +1,71 @@
+// http_client.go - HTTP client construction for providers
+// Splits the fetch timeout into connect, response-header and overall phases
+package provider
+
+import (
+	"net"
+	"net/http"
+	"search-engine/backend/internal/config"
+	"time"
+)
+
+// HTTPTimeouts configures each phase of a provider fetch separately
+// A short connect timeout fails fast on dead hosts while a long overall
+// timeout still allows large, slow feeds to finish downloading
+type HTTPTimeouts struct {
+	Connect        time.Duration // TCP dial and TLS handshake
+	ResponseHeader time.Duration // Wait for response headers once the request is sent
+	Overall        time.Duration // Whole request including reading the body
+}
+
+// DefaultHTTPTimeouts returns the timeouts used when none are configured
+// The overall timeout matches the previous single 30s client timeout
+func DefaultHTTPTimeouts() HTTPTimeouts {
+	return HTTPTimeouts{
+		Connect:        10 * time.Second,
+		ResponseHeader: 30 * time.Second,
+		Overall:        30 * time.Second,
+	}
+}
+
+// HTTPTimeoutsFromConfig converts per-provider timeout settings, keeping defaults for unset fields
+func HTTPTimeoutsFromConfig(cfg config.ProviderTimeoutConfig) HTTPTimeouts {
+	return HTTPTimeouts{
+		Connect:        time.Duration(cfg.ConnectSeconds) * time.Second,
+		ResponseHeader: time.Duration(cfg.ResponseHeaderSeconds) * time.Second,
+		Overall:        time.Duration(cfg.OverallSeconds) * time.Second,
+	}.withDefaults()
+}
+
+// withDefaults fills zero or negative timeouts from DefaultHTTPTimeouts
+func (t HTTPTimeouts) withDefaults() HTTPTimeouts {
+	defaults := DefaultHTTPTimeouts()
+	if t.Connect <= 0 {
+		t.Connect = defaults.Connect
+	}
+	if t.ResponseHeader <= 0 {
+		t.ResponseHeader = defaults.ResponseHeader
+	}
+	if t.Overall <= 0 {
+		t.Overall = defaults.Overall
+	}
+	return t
+}
+
+// newHTTPClient builds an HTTP client whose transport enforces the phase timeouts
+func newHTTPClient(timeouts HTTPTimeouts) *http.Client {
+	timeouts = timeouts.withDefaults()
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   timeouts.Connect,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = timeouts.Connect
+	transport.ResponseHeaderTimeout = timeouts.ResponseHeader
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeouts.Overall,
+	}
+}
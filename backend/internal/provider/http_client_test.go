@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchTimesOutOnSlowProvider(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	timeouts := HTTPTimeouts{Connect: time.Second, ResponseHeader: time.Second, Overall: 50 * time.Millisecond}
+	p := NewJSONProvider("slow", srv.URL, timeouts, nil, RetryPolicy{MaxAttempts: 1}, DefaultPaginationPolicy())
+
+	start := time.Now()
+	_, _, err := p.Fetch(context.Background())
+	if err == nil {
+		t.Fatal("Fetch succeeded against a provider slower than the timeout")
+	}
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("err = %v, want a timeout error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Fetch took %s, want it cut off near the 50ms timeout", elapsed)
+	}
+}
+
+func TestHTTPTimeoutsWithDefaults(t *testing.T) {
+	got := HTTPTimeouts{Overall: 5 * time.Second}.withDefaults()
+	want := HTTPTimeouts{Connect: 10 * time.Second, ResponseHeader: 30 * time.Second, Overall: 5 * time.Second}
+	if got != want {
+		t.Errorf("withDefaults() = %+v, want %+v", got, want)
+	}
+}
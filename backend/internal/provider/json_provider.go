@@ -3,12 +3,11 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"search-engine/backend/internal/model"
-	"time"
 )
 
 // JSONProviderResponse represents the JSON structure from Provider 1
@@ -34,7 +33,7 @@ type JSONContentItem struct {
 type JSONMetrics struct {
 	Views    *int    `json:"views,omitempty"`    // Video metric
 	Likes    *int    `json:"likes,omitempty"`    // Video metric
-	Duration *string `json:"duration,omitempty"` // Video metric (format: "MM:SS")
+	Duration *string `json:"duration,omitempty"` // Video metric (format: "MM:SS" or "HH:MM:SS")
 
 	ReadingTime *int `json:"reading_time,omitempty"` // Article metric
 	Reactions   *int `json:"reactions,omitempty"`    // Article metric
@@ -52,65 +51,65 @@ type JSONPagination struct {
 // This handles fetching and parsing data from Provider 1
 type JSONProvider struct {
 	BaseProvider
-	client *http.Client
+	client      *http.Client
+	dateLayouts []string
+	retry       RetryPolicy
+	pagination  PaginationPolicy
 }
 
 // NewJSONProvider creates a new JSON provider instance
-// Sets up HTTP client with timeout for reliable requests
-func NewJSONProvider(name, url string) *JSONProvider {
+// Sets up an HTTP client with separate connect, response-header and overall timeouts
+// dateLayouts are tried in order for published_at; empty uses DefaultJSONDateLayouts
+// retry configures how transient fetch failures are retried; zero fields use DefaultRetryPolicy
+// pagination configures how later pages are requested; zero fields use DefaultPaginationPolicy
+func NewJSONProvider(name, url string, timeouts HTTPTimeouts, dateLayouts []string, retry RetryPolicy, pagination PaginationPolicy) *JSONProvider {
 	return &JSONProvider{
 		BaseProvider: BaseProvider{
 			Name: name,
 			URL:  url,
 		},
-		client: &http.Client{
-			Timeout: 30 * time.Second, // 30 second timeout for API requests
-		},
+		client:      newHTTPClient(timeouts),
+		dateLayouts: dateLayoutsOrDefault(dateLayouts, DefaultJSONDateLayouts),
+		retry:       retry.withDefaults(),
+		pagination:  pagination.withDefaults(),
 	}
 }
 
 // Fetch retrieves content from the JSON provider's API
-// Downloads JSON data, parses it, and transforms it to standard format
-func (p *JSONProvider) Fetch() ([]*model.Content, error) {
-	// Make HTTP GET request to provider URL
-	// This fetches the raw JSON data
-	resp, err := p.client.Get(p.URL)
+// Downloads JSON data, following the feed's pagination, parses it, and transforms it to standard format
+func (p *JSONProvider) Fetch(ctx context.Context) ([]*model.Content, []TransformError, error) {
+	// Download every page of JSON data (each reused if fetched moments ago)
+	items, err := fetchPages(ctx, p.client, p.URL, p.retry, p.pagination, parseJSONPage)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch from JSON provider: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch from JSON provider: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Check HTTP status code
-	// Non-200 status codes indicate an error
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
+	// Transform JSON items to standard Content models, setting aside ones that fail
+	contents, rejected := transformItems(items,
+		func(item JSONContentItem) string { return item.ID },
+		p.transformToContent)
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	return contents, rejected, nil
+}
+
+// parseJSONPage parses one page of the JSON feed into its items and pagination metadata
+func parseJSONPage(body []byte) ([]JSONContentItem, pageInfo, error) {
+	// Catch a provider configured with the wrong format before parsing fails vaguely
+	if err := checkFormat(body, model.ProviderFormatJSON); err != nil {
+		return nil, pageInfo{}, err
 	}
 
-	// Parse JSON response
 	var jsonResponse JSONProviderResponse
 	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		return nil, pageInfo{}, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
-	// Transform JSON items to standard Content models
-	contents := make([]*model.Content, 0, len(jsonResponse.Contents))
-	for _, item := range jsonResponse.Contents {
-		content, err := p.transformToContent(item)
-		if err != nil {
-			// Log error but continue processing other items
-			// This ensures partial failures don't stop the entire sync
-			continue
-		}
-		contents = append(contents, content)
+	info := pageInfo{
+		total:   jsonResponse.Pagination.Total,
+		page:    jsonResponse.Pagination.Page,
+		perPage: jsonResponse.Pagination.PerPage,
 	}
-
-	return contents, nil
+	return jsonResponse.Contents, info, nil
 }
 
 // transformToContent converts a JSONContentItem to a standard Content model
@@ -122,11 +121,11 @@ func (p *JSONProvider) transformToContent(item JSONContentItem) (*model.Content,
 		Type:       model.NormalizeContentType(item.Type),
 	}
 
-	// Parse published_at timestamp
-	// JSON provider uses ISO 8601 format: "2024-03-15T10:00:00Z"
-	publishedAt, err := time.Parse(time.RFC3339, item.PublishedAt)
+	// Parse published_at timestamp with the configured layouts
+	// Offsets are converted so every stored time is UTC
+	publishedAt, err := parseDate(item.PublishedAt, p.dateLayouts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse published_at: %w", err)
+		return nil, &FieldError{Field: "published_at", Err: err}
 	}
 	content.PublishedAt = publishedAt
 
@@ -140,10 +139,10 @@ func (p *JSONProvider) transformToContent(item JSONContentItem) (*model.Content,
 			content.Likes = *item.Metrics.Likes
 		}
 		if item.Metrics.Duration != nil {
-			// Parse duration string (e.g., "15:30") to seconds
-			durationSeconds, err := parseDurationString(*item.Metrics.Duration)
+			// Parse duration string (e.g., "15:30" or "1:23:45") to seconds
+			durationSeconds, err := model.ParseDurationSeconds(*item.Metrics.Duration)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse duration: %w", err)
+				return nil, &FieldError{Field: "duration", Err: err}
 			}
 			content.DurationSeconds = &durationSeconds
 		}
@@ -165,14 +164,3 @@ func (p *JSONProvider) transformToContent(item JSONContentItem) (*model.Content,
 
 	return content, nil
 }
-
-// parseDurationString parses a duration string in "MM:SS" format to seconds
-// Example: "15:30" -> 930 seconds
-func parseDurationString(durationStr string) (int, error) {
-	var minutes, seconds int
-	_, err := fmt.Sscanf(durationStr, "%d:%d", &minutes, &seconds)
-	if err != nil {
-		return 0, fmt.Errorf("invalid duration format: %s", durationStr)
-	}
-	return minutes*60 + seconds, nil
-}
@@ -3,9 +3,14 @@
 package provider
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"search-engine/backend/internal/model"
 	"search-engine/backend/internal/repository"
+	"search-engine/backend/internal/trace"
+	"sort"
 	"sync"
 	"time"
 )
@@ -14,27 +19,51 @@ import (
 // Handles fetching from all providers, rate limiting, and data persistence
 type Manager struct {
 	providers    map[string]Provider
-	providerRepo *repository.ProviderRepository
-	contentRepo  *repository.ContentRepository
-	tagRepo      *repository.ContentTagRepository
+	providerRepo providerStore
+	contentRepo  contentStore
+	syncRepo     syncRecorder // nil when sync runs aren't recorded
+	futureDates  FutureDatePolicy
 	rateLimiters map[string]*RateLimiter
 	mu           sync.RWMutex // Protects rateLimiters map
 }
 
+// providerStore is the part of the provider repository the manager reads and updates
+type providerStore interface {
+	GetByName(name string) (*model.Provider, error)
+	GetAll() ([]*model.Provider, error)
+	UpdateLastFetched(id int, fetchedAt time.Time) error
+}
+
+// syncRecorder is the part of the sync history repository the manager records runs through
+type syncRecorder interface {
+	Create(s *model.SyncResult) error
+}
+
 // NewManager creates a new ProviderManager instance
 // Initializes rate limiters for each provider
+// syncRepo records every sync run; it can be nil to skip recording
 func NewManager(
 	providerRepo *repository.ProviderRepository,
 	contentRepo *repository.ContentRepository,
-	tagRepo *repository.ContentTagRepository,
+	syncRepo *repository.SyncHistoryRepository,
 ) *Manager {
-	return &Manager{
+	m := &Manager{
 		providers:    make(map[string]Provider),
 		providerRepo: providerRepo,
 		contentRepo:  contentRepo,
-		tagRepo:      tagRepo,
+		futureDates:  DefaultFutureDatePolicy(),
 		rateLimiters: make(map[string]*RateLimiter),
 	}
+	// Keep a nil repository as a nil interface so recordSync can tell it's unset
+	if syncRepo != nil {
+		m.syncRepo = syncRepo
+	}
+	return m
+}
+
+// SetFutureDatePolicy sets how synced items published too far in the future are handled
+func (m *Manager) SetFutureDatePolicy(policy FutureDatePolicy) {
+	m.futureDates = policy.withDefaults()
 }
 
 // RegisterProvider adds a provider to the manager
@@ -49,19 +78,81 @@ func (m *Manager) RegisterProvider(provider Provider) {
 	// Get rate limit from database if provider exists
 	providerModel, err := m.providerRepo.GetByName(provider.GetName())
 	if err == nil {
-		m.rateLimiters[provider.GetName()] = NewRateLimiter(providerModel.RateLimitPerMinute)
+		m.rateLimiters[provider.GetName()] = NewRateLimiter(provider.GetName(), providerModel.RateLimitPerMinute)
 	} else {
 		// Default rate limit if provider not in database
-		m.rateLimiters[provider.GetName()] = NewRateLimiter(60)
+		m.rateLimiters[provider.GetName()] = NewRateLimiter(provider.GetName(), 60)
+	}
+}
+
+// RegisterMappedProviders registers a ConfigurableProvider for every stored provider with a field mapping
+// Providers already registered under the same name are left as they are; a provider whose
+// mapping is invalid is logged and skipped so it can't hold up the others
+func (m *Manager) RegisterMappedProviders(timeouts HTTPTimeouts, retry RetryPolicy) error {
+	stored, err := m.providerRepo.GetAll()
+	if err != nil {
+		return fmt.Errorf("list providers: %w", err)
 	}
+
+	for _, p := range stored {
+		if p.FieldMapping == nil {
+			continue
+		}
+		m.mu.RLock()
+		_, registered := m.providers[p.Name]
+		m.mu.RUnlock()
+		if registered {
+			continue
+		}
+
+		mapped, err := NewFromModel(p, timeouts, retry)
+		if err != nil {
+			log.Printf("Warning: Skipping mapped provider %s: %v", p.Name, err)
+			continue
+		}
+		m.RegisterProvider(mapped)
+	}
+	return nil
 }
 
 // FetchAll fetches content from all registered providers
-// Handles rate limiting and error recovery per provider
-func (m *Manager) FetchAll() error {
-	var wg sync.WaitGroup
-	errors := make(chan error, len(m.providers))
+// Returns every provider's report, sorted by name; when some providers fail the
+// error is a *MultiProviderError naming each one, and the others still synced
+// Sync logs carry the trace ID in ctx (see trace.WithID)
+func (m *Manager) FetchAll(ctx context.Context) ([]model.ProviderSyncReport, error) {
+	reports, failures := m.syncAll(ctx)
+	if len(failures) > 0 {
+		return reports, &MultiProviderError{Failures: failures}
+	}
+	return reports, nil
+}
 
+// LogSyncReports logs one line per provider report, for sync runs started outside a request
+// Lines carry the trace ID in ctx, like the sync's own logs
+func LogSyncReports(ctx context.Context, reports []model.ProviderSyncReport) {
+	for _, r := range reports {
+		switch {
+		case r.Disabled:
+			trace.Logf(ctx, "Provider %s: skipped (disabled)", r.Provider)
+		case r.Error != "":
+			trace.Logf(ctx, "Provider %s: failed after %dms: %s", r.Provider, r.DurationMs, r.Error)
+		default:
+			trace.Logf(ctx, "Provider %s: fetched %d, upserted %d, skipped %d, tags dropped %d in %dms",
+				r.Provider, r.ItemsFetched, r.ItemsUpserted, r.ItemsSkipped, r.TagsDropped, r.DurationMs)
+		}
+	}
+}
+
+// SyncAll syncs every registered provider concurrently and reports on each, sorted by name
+// Failures are carried in the reports, for callers that show a per-provider summary
+func (m *Manager) SyncAll(ctx context.Context) []model.ProviderSyncReport {
+	reports, _ := m.syncAll(ctx)
+	return reports
+}
+
+// syncAll syncs every registered provider concurrently
+// Returns the reports and the failures, both sorted by provider name
+func (m *Manager) syncAll(ctx context.Context) ([]model.ProviderSyncReport, []ProviderFailure) {
 	m.mu.RLock()
 	providers := make([]Provider, 0, len(m.providers))
 	for _, p := range m.providers {
@@ -70,40 +161,150 @@ func (m *Manager) FetchAll() error {
 	m.mu.RUnlock()
 
 	// Fetch from all providers concurrently
-	// Each provider runs in its own goroutine for parallel processing
-	for _, provider := range providers {
+	// Each provider runs in its own goroutine and writes only its own slots
+	reports := make([]model.ProviderSyncReport, len(providers))
+	errs := make([]error, len(providers))
+	var wg sync.WaitGroup
+	for i, provider := range providers {
 		wg.Add(1)
-		go func(p Provider) {
+		go func(i int, p Provider) {
 			defer wg.Done()
-			if err := m.fetchFromProvider(p); err != nil {
-				log.Printf("Error fetching from provider %s: %v", p.GetName(), err)
-				errors <- err
+			report, err := m.fetchFromProvider(ctx, p)
+			if err != nil {
+				trace.Logf(ctx, "Error fetching from provider %s: %v", p.GetName(), err)
 			}
-		}(provider)
+			reports[i] = report
+			errs[i] = err
+		}(i, provider)
 	}
-
 	wg.Wait()
-	close(errors)
 
-	// Check if any errors occurred
-	hasErrors := false
-	for err := range errors {
+	var failures []ProviderFailure
+	for i, err := range errs {
 		if err != nil {
-			hasErrors = true
-			log.Printf("Provider fetch error: %v", err)
+			failures = append(failures, ProviderFailure{Provider: providers[i].GetName(), Err: err})
 		}
 	}
 
-	if hasErrors {
-		return fmt.Errorf("some providers failed to fetch")
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Provider < reports[j].Provider })
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Provider < failures[j].Provider })
+	return reports, failures
+}
+
+// fetchFromProvider fetches content from a single provider and records the run
+// The outcome is written to sync history whether the sync succeeded or not
+// Providers disabled in the database are skipped without a recorded run
+func (m *Manager) fetchFromProvider(ctx context.Context, provider Provider) (model.ProviderSyncReport, error) {
+	report := model.ProviderSyncReport{Provider: provider.GetName()}
+	if providerModel, err := m.providerRepo.GetByName(provider.GetName()); err == nil && !providerModel.Enabled {
+		trace.Logf(ctx, "Skipping disabled provider: %s", provider.GetName())
+		report.Disabled = true
+		return report, nil
 	}
 
-	return nil
+	defer markSyncRunning(provider.GetName())()
+
+	startedAt := time.Now()
+	counts, err := m.syncProvider(ctx, provider)
+	m.recordSync(ctx, provider.GetName(), startedAt, counts, err)
+
+	report.ItemsFetched = counts.fetched
+	report.ItemsSkipped = counts.skipped
+	report.ItemsUpserted = counts.upserted
+	report.TagsDropped = counts.tagsDropped
+	report.DurationMs = time.Since(startedAt).Milliseconds()
+	if err != nil {
+		report.Error = err.Error()
+	}
+	return report, err
+}
+
+// syncCounts tallies what a single provider sync did
+type syncCounts struct {
+	fetched     int // Items the provider returned
+	skipped     int // Items the provider returned that couldn't be transformed
+	upserted    int // Items saved to the database
+	tagsDropped int // Tags rejected by the tag limits
+}
+
+// contentStore is the part of the content repository a sync saves items through
+type contentStore interface {
+	UpsertWithTags(ctx context.Context, c *model.Content, tags []string) (int64, int, error)
 }
 
-// fetchFromProvider fetches content from a single provider
+// recordSync writes the outcome of a sync run to sync history
+// Failures to record are logged but never fail the sync itself
+func (m *Manager) recordSync(ctx context.Context, providerName string, startedAt time.Time, counts syncCounts, syncErr error) {
+	if m.syncRepo == nil {
+		return
+	}
+
+	providerModel, err := m.providerRepo.GetByName(providerName)
+	if err != nil {
+		trace.Logf(ctx, "Failed to record sync for provider %s: %v", providerName, err)
+		return
+	}
+
+	finishedAt := time.Now()
+	result := &model.SyncResult{
+		ProviderID:    providerModel.ID,
+		StartedAt:     startedAt,
+		FinishedAt:    &finishedAt,
+		ItemsFetched:  counts.fetched,
+		ItemsSkipped:  counts.skipped,
+		ItemsUpserted: counts.upserted,
+		TagsDropped:   counts.tagsDropped,
+		Status:        model.SyncStatusSuccess,
+	}
+	if syncErr != nil {
+		result.Status = model.SyncStatusFailed
+		result.ErrorMessage = syncErr.Error()
+	}
+
+	if err := m.syncRepo.Create(result); err != nil {
+		trace.Logf(ctx, "Failed to record sync for provider %s: %v", providerName, err)
+	}
+}
+
+// maxThrottleRetryWait is the longest upstream Retry-After we wait out within one sync
+// Longer waits fail the sync; the lowered rate, and a block of at most this long,
+// still apply to the next one
+const maxThrottleRetryWait = time.Minute
+
+// fetchWithBackoff fetches from a provider through its rate limiter
+// When the upstream throttles us the limiter backs off, and the fetch is
+// retried once if the requested wait is short enough
+// Returns ctx's error if it ends while waiting on the limiter
+func (m *Manager) fetchWithBackoff(ctx context.Context, provider Provider, limiter *RateLimiter) ([]*model.Content, []TransformError, error) {
+	for attempt := 0; ; attempt++ {
+		// Wait for rate limit before making request
+		// This prevents exceeding the provider's rate limit
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, nil, err
+		}
+
+		trace.Logf(ctx, "Fetching from provider: %s", provider.GetName())
+		contents, rejected, err := provider.Fetch(ctx)
+
+		var throttled *ThrottledError
+		if err == nil || !errors.As(err, &throttled) {
+			return contents, rejected, err
+		}
+
+		// The limiter is shared by later syncs, so a hostile or buggy Retry-After
+		// (say a day) must not block the provider for that long
+		limiter.Throttle(min(throttled.RetryAfter, maxThrottleRetryWait))
+		if attempt > 0 || throttled.RetryAfter > maxThrottleRetryWait {
+			return nil, nil, err
+		}
+		trace.Logf(ctx, "Retrying provider %s after throttling (retry after %s)", provider.GetName(), throttled.RetryAfter)
+	}
+}
+
+// syncProvider fetches and persists content from a single provider
 // Handles rate limiting, data transformation, and database persistence
-func (m *Manager) fetchFromProvider(provider Provider) error {
+// Returns how many items the provider returned and how many tags were dropped
+func (m *Manager) syncProvider(ctx context.Context, provider Provider) (syncCounts, error) {
 	providerName := provider.GetName()
 
 	// Get rate limiter for this provider
@@ -112,70 +313,91 @@ func (m *Manager) fetchFromProvider(provider Provider) error {
 	m.mu.RUnlock()
 
 	if !exists {
-		limiter = NewRateLimiter(60) // Default rate limit
+		limiter = NewRateLimiter(providerName, 60) // Default rate limit
 	}
 
-	// Wait for rate limit before making request
-	// This prevents exceeding the provider's rate limit
-	limiter.Wait()
-
-	log.Printf("Fetching from provider: %s", providerName)
-
-	// Fetch content from provider
-	contents, err := provider.Fetch()
+	contents, rejected, err := m.fetchWithBackoff(ctx, provider, limiter)
 	if err != nil {
-		return fmt.Errorf("failed to fetch from provider %s: %w", providerName, err)
+		return syncCounts{}, fmt.Errorf("failed to fetch from provider %s: %w", providerName, err)
 	}
 
-	log.Printf("Fetched %d items from provider: %s", len(contents), providerName)
+	trace.Logf(ctx, "Fetched %d items from provider: %s", len(contents), providerName)
+	contents, futureDated, clamped := m.futureDates.apply(contents, time.Now())
+	rejected = append(rejected, futureDated...)
+	if clamped > 0 {
+		trace.Logf(ctx, "Clamped published_at to now for %d future-dated items from provider %s", clamped, providerName)
+	}
+	logDeadLetters(ctx, providerName, rejected)
+	counts := syncCounts{fetched: len(contents), skipped: len(rejected)}
 
 	// Get provider model from database
 	providerModel, err := m.providerRepo.GetByName(providerName)
 	if err != nil {
-		return fmt.Errorf("provider not found in database: %s", providerName)
+		return counts, fmt.Errorf("provider not found in database: %s", providerName)
 	}
 
-	// Save each content item to database
-	// Use Upsert to handle duplicates (same external_id from same provider)
-	for _, content := range contents {
-		content.ProviderID = providerModel.ID
+	upserted, tagsDropped := saveContents(ctx, m.contentRepo, providerModel.ID, contents)
+	counts.upserted = upserted
+	counts.tagsDropped = tagsDropped
 
-		// Upsert content (create or update)
-		if err := m.contentRepo.Upsert(content); err != nil {
-			log.Printf("Failed to upsert content %s: %v", content.ExternalID, err)
-			continue
+	// Update last_fetched_at timestamp
+	if err := m.providerRepo.UpdateLastFetched(providerModel.ID, time.Now()); err != nil {
+		trace.Logf(ctx, "Failed to update last_fetched_at for provider %s: %v", providerName, err)
+	}
+
+	trace.Logf(ctx, "Successfully synced %d of %d items from provider: %s", counts.upserted, len(contents), providerName)
+	return counts, nil
+}
+
+// logDeadLetters logs every item a provider returned but couldn't transform
+// Each line carries the external ID, failing field and raw item, so format drift
+// at a provider can be diagnosed from the sync logs alone
+func logDeadLetters(ctx context.Context, providerName string, rejected []TransformError) {
+	if len(rejected) == 0 {
+		return
+	}
+	for _, r := range rejected {
+		field := r.Field
+		if field == "" {
+			field = "-"
 		}
+		trace.Logf(ctx, "Dead letter from provider %s: item %q field %s: %s; raw item: %s",
+			providerName, r.ExternalID, field, r.Reason, r.Raw)
+	}
+	trace.Logf(ctx, "Skipped %d items from provider %s", len(rejected), providerName)
+}
+
+// saveContents upserts each item under providerID together with its tags
+// Each item and its tags are written in one transaction, so a failure leaves neither
+// behind; the item is logged and left out of the upserted count rather than failing
+// the rest. An item sent without tags keeps its stored ones, and every item is stamped
+// with its sync time. Returns the items saved
+// and the tags dropped
+func saveContents(ctx context.Context, contentRepo contentStore, providerID int, contents []*model.Content) (upserted, tagsDropped int) {
+	// Upsert handles duplicates (same external_id from same provider)
+	for _, content := range contents {
+		content.ProviderID = providerID
+		syncedAt := time.Now()
+		content.LastSyncedAt = &syncedAt
 
-		// Get the content ID (needed for tags)
-		existingContent, err := m.contentRepo.GetByProviderAndExternalID(
-			content.ProviderID,
-			content.ExternalID,
-		)
+		id, dropped, err := contentRepo.UpsertWithTags(ctx, content, content.Tags)
 		if err != nil {
-			log.Printf("Failed to get content after upsert: %v", err)
+			trace.Logf(ctx, "Failed to upsert content %s: %v", content.ExternalID, err)
 			continue
 		}
+		upserted++
 
-		// Save tags
-		if len(content.Tags) > 0 {
-			if err := m.tagRepo.ReplaceTags(existingContent.ID, content.Tags); err != nil {
-				log.Printf("Failed to save tags for content %d: %v", existingContent.ID, err)
-			}
+		if dropped > 0 {
+			trace.Logf(ctx, "Dropped %d tags over the tag limits for content %d", dropped, id)
+			tagsDropped += dropped
 		}
 	}
-
-	// Update last_fetched_at timestamp
-	if err := m.providerRepo.UpdateLastFetched(providerModel.ID, time.Now()); err != nil {
-		log.Printf("Failed to update last_fetched_at for provider %s: %v", providerName, err)
-	}
-
-	log.Printf("Successfully synced %d items from provider: %s", len(contents), providerName)
-	return nil
+	return upserted, tagsDropped
 }
 
 // FetchFromProvider fetches content from a specific provider by name
 // Useful for manual sync or testing individual providers
-func (m *Manager) FetchFromProvider(providerName string) error {
+func (m *Manager) FetchFromProvider(ctx context.Context, providerName string) error {
 	m.mu.RLock()
 	provider, exists := m.providers[providerName]
 	m.mu.RUnlock()
@@ -184,7 +406,8 @@ func (m *Manager) FetchFromProvider(providerName string) error {
 		return fmt.Errorf("provider not found: %s", providerName)
 	}
 
-	return m.fetchFromProvider(provider)
+	_, err := m.fetchFromProvider(ctx, provider)
+	return err
 }
 
 // GetProviders returns a list of all registered provider names
@@ -0,0 +1,310 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/internal/trace"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeContentStore is an in-memory contentStore that fails upserts for chosen external IDs
+// It keeps at most maxTags tags per content (0 means no limit) and reports the rest as dropped
+type fakeContentStore struct {
+	failUpsert map[string]bool
+	saved      map[string]*model.Content
+	maxTags    int
+	tags       map[int64][]string
+}
+
+func (f *fakeContentStore) UpsertWithTags(_ context.Context, c *model.Content, tags []string) (int64, int, error) {
+	if f.failUpsert[c.ExternalID] {
+		return 0, 0, fmt.Errorf("deadlock found")
+	}
+	if existing, ok := f.saved[c.ExternalID]; ok {
+		c.ID = existing.ID
+	} else {
+		c.ID = int64(len(f.saved) + 1)
+	}
+	f.saved[c.ExternalID] = c
+
+	kept := tags
+	if f.maxTags > 0 && len(kept) > f.maxTags {
+		kept = kept[:f.maxTags]
+	}
+	if f.tags == nil {
+		f.tags = map[int64][]string{}
+	}
+	f.tags[c.ID] = kept
+	return c.ID, len(tags) - len(kept), nil
+}
+
+func TestSyncCountsWithMixedItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"contents": [
+			{"id": "v1", "title": "Go", "type": "video", "published_at": "2024-03-15T10:00:00Z", "tags": ["go", "intro", "extra"]},
+			{"id": "v2", "title": "Bad date", "type": "video", "published_at": "yesterday"},
+			{"id": "v3", "title": "Bad duration", "type": "video", "published_at": "2024-03-15T10:00:00Z", "metrics": {"duration": "soon"}},
+			{"id": "a1", "title": "Rust", "type": "article", "published_at": "2024-03-16T10:00:00Z"},
+			{"id": "a2", "title": "Lost", "type": "article", "published_at": "2024-03-17T10:00:00Z"}
+		]}`)
+	}))
+	defer server.Close()
+
+	p := NewJSONProvider("provider1", server.URL, DefaultHTTPTimeouts(), nil, DefaultRetryPolicy(), DefaultPaginationPolicy())
+	contents, rejected, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(contents) != 3 || len(rejected) != 2 {
+		t.Fatalf("fetched %d, rejected %d; want 3 fetched, 2 rejected", len(contents), len(rejected))
+	}
+
+	store := &fakeContentStore{failUpsert: map[string]bool{"a2": true}, saved: map[string]*model.Content{}, maxTags: 2}
+	upserted, tagsDropped := saveContents(context.Background(), store, 7, contents)
+
+	if upserted != 2 {
+		t.Errorf("upserted = %d, want 2", upserted)
+	}
+	if tagsDropped != 1 {
+		t.Errorf("tagsDropped = %d, want 1", tagsDropped)
+	}
+	for id, c := range store.saved {
+		if c.ProviderID != 7 {
+			t.Errorf("content %s provider_id = %d, want 7", id, c.ProviderID)
+		}
+		if c.LastSyncedAt == nil {
+			t.Errorf("content %s saved without a sync time", id)
+		}
+	}
+}
+
+// fakeProvider returns fixed contents or a fixed error from Fetch
+type fakeProvider struct {
+	BaseProvider
+	contents []*model.Content
+	err      error
+}
+
+func (p *fakeProvider) Fetch(context.Context) ([]*model.Content, []TransformError, error) {
+	return p.contents, nil, p.err
+}
+
+// fakeProviderStore is an in-memory providerStore
+type fakeProviderStore struct {
+	providers map[string]*model.Provider
+	fetched   map[int]bool
+}
+
+func (f *fakeProviderStore) GetByName(name string) (*model.Provider, error) {
+	p, ok := f.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("provider %s not found", name)
+	}
+	return p, nil
+}
+
+func (f *fakeProviderStore) GetAll() ([]*model.Provider, error) {
+	all := make([]*model.Provider, 0, len(f.providers))
+	for _, p := range f.providers {
+		all = append(all, p)
+	}
+	return all, nil
+}
+
+func (f *fakeProviderStore) UpdateLastFetched(id int, _ time.Time) error {
+	f.fetched[id] = true
+	return nil
+}
+
+func TestFetchAllReportsPartialFailure(t *testing.T) {
+	providers := &fakeProviderStore{
+		providers: map[string]*model.Provider{
+			"healthy": {ID: 1, Name: "healthy", Enabled: true, RateLimitPerMinute: 60},
+			"down":    {ID: 2, Name: "down", Enabled: true, RateLimitPerMinute: 60},
+		},
+		fetched: map[int]bool{},
+	}
+	store := &fakeContentStore{saved: map[string]*model.Content{}}
+	m := &Manager{
+		providers:    map[string]Provider{},
+		providerRepo: providers,
+		contentRepo:  store,
+		rateLimiters: map[string]*RateLimiter{},
+	}
+	unavailable := &StatusError{StatusCode: http.StatusServiceUnavailable}
+	m.RegisterProvider(&fakeProvider{BaseProvider: BaseProvider{Name: "healthy"}, contents: []*model.Content{{ExternalID: "v1", Title: "Go"}}})
+	m.RegisterProvider(&fakeProvider{BaseProvider: BaseProvider{Name: "down"}, err: unavailable})
+
+	reports, err := m.FetchAll(context.Background())
+
+	var partial *MultiProviderError
+	if !errors.As(err, &partial) {
+		t.Fatalf("FetchAll() error = %v, want a *MultiProviderError", err)
+	}
+	if len(partial.Failures) != 1 || partial.Failures[0].Provider != "down" || !partial.Failed("down") || partial.Failed("healthy") {
+		t.Errorf("failures = %+v, want only provider down", partial.Failures)
+	}
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("errors.As(StatusError) = %v, want the 503 from provider down", statusErr)
+	}
+
+	if len(reports) != 2 || reports[0].Provider != "down" || reports[1].Provider != "healthy" {
+		t.Fatalf("reports = %+v, want down then healthy", reports)
+	}
+	if reports[0].Error == "" {
+		t.Error("report for down has no error")
+	}
+	if reports[1].Error != "" || reports[1].ItemsUpserted != 1 {
+		t.Errorf("report for healthy = %+v, want 1 item upserted and no error", reports[1])
+	}
+	if !providers.fetched[1] || providers.fetched[2] {
+		t.Errorf("last_fetched_at updated for %v, want only the healthy provider", providers.fetched)
+	}
+}
+
+func TestFetchAllWithoutFailures(t *testing.T) {
+	m := &Manager{
+		providers: map[string]Provider{},
+		providerRepo: &fakeProviderStore{
+			providers: map[string]*model.Provider{"healthy": {ID: 1, Name: "healthy", Enabled: true, RateLimitPerMinute: 60}},
+			fetched:   map[int]bool{},
+		},
+		contentRepo:  &fakeContentStore{saved: map[string]*model.Content{}},
+		rateLimiters: map[string]*RateLimiter{},
+	}
+	m.RegisterProvider(&fakeProvider{BaseProvider: BaseProvider{Name: "healthy"}})
+
+	if _, err := m.FetchAll(context.Background()); err != nil {
+		t.Errorf("FetchAll() error = %v, want nil", err)
+	}
+}
+
+func TestFetchAllLogsUnderTraceID(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	m := &Manager{
+		providers: map[string]Provider{},
+		providerRepo: &fakeProviderStore{
+			providers: map[string]*model.Provider{"healthy": {ID: 1, Name: "healthy", Enabled: true, RateLimitPerMinute: 60}},
+			fetched:   map[int]bool{},
+		},
+		contentRepo:  &fakeContentStore{saved: map[string]*model.Content{}},
+		rateLimiters: map[string]*RateLimiter{},
+	}
+	m.RegisterProvider(&fakeProvider{BaseProvider: BaseProvider{Name: "healthy"}, contents: []*model.Content{{ExternalID: "v1", Title: "Go"}}})
+
+	ctx := trace.WithID(context.Background(), "req-123")
+	reports, err := m.FetchAll(ctx)
+	if err != nil {
+		t.Fatalf("FetchAll() error = %v", err)
+	}
+	LogSyncReports(ctx, reports)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("got %d log lines, want the fetch, sync and report lines:\n%s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "trace=req-123") {
+			t.Errorf("log line without the trace ID: %s", line)
+		}
+	}
+}
+
+// fakeSyncRecorder keeps every recorded sync run
+type fakeSyncRecorder struct {
+	runs []*model.SyncResult
+}
+
+func (f *fakeSyncRecorder) Create(s *model.SyncResult) error {
+	s.ID = int64(len(f.runs) + 1)
+	f.runs = append(f.runs, s)
+	return nil
+}
+
+func TestFetchAllRecordsSyncHistory(t *testing.T) {
+	recorder := &fakeSyncRecorder{}
+	m := &Manager{
+		providers: map[string]Provider{},
+		providerRepo: &fakeProviderStore{
+			providers: map[string]*model.Provider{
+				"healthy": {ID: 1, Name: "healthy", Enabled: true, RateLimitPerMinute: 60},
+				"down":    {ID: 2, Name: "down", Enabled: true, RateLimitPerMinute: 60},
+			},
+			fetched: map[int]bool{},
+		},
+		contentRepo:  &fakeContentStore{saved: map[string]*model.Content{}},
+		syncRepo:     recorder,
+		rateLimiters: map[string]*RateLimiter{},
+	}
+	m.RegisterProvider(&fakeProvider{BaseProvider: BaseProvider{Name: "healthy"}, contents: []*model.Content{{ExternalID: "v1", Title: "Go"}, {ExternalID: "v2", Title: "Rust"}}})
+	m.RegisterProvider(&fakeProvider{BaseProvider: BaseProvider{Name: "down"}, err: &StatusError{StatusCode: http.StatusServiceUnavailable}})
+
+	before := time.Now()
+	m.FetchAll(context.Background())
+
+	if len(recorder.runs) != 2 {
+		t.Fatalf("recorded %d runs, want one per provider", len(recorder.runs))
+	}
+	runs := map[int]*model.SyncResult{}
+	for _, run := range recorder.runs {
+		runs[run.ProviderID] = run
+		if run.StartedAt.Before(before) || run.FinishedAt == nil || run.FinishedAt.Before(run.StartedAt) {
+			t.Errorf("provider %d: started %v, finished %v; want both during the sync", run.ProviderID, run.StartedAt, run.FinishedAt)
+		}
+	}
+
+	if healthy := runs[1]; healthy == nil || healthy.Status != model.SyncStatusSuccess || healthy.ItemsFetched != 2 || healthy.ItemsUpserted != 2 || healthy.ErrorMessage != "" {
+		t.Errorf("healthy run = %+v, want a success with 2 items fetched and saved", healthy)
+	}
+	if failed := runs[2]; failed == nil || failed.Status != model.SyncStatusFailed || failed.ItemsFetched != 0 || failed.ErrorMessage == "" {
+		t.Errorf("down run = %+v, want a failure with its error message", failed)
+	}
+}
+
+func TestFetchWithBackoffCapsRetryAfter(t *testing.T) {
+	provider := &fakeProvider{err: &ThrottledError{StatusCode: http.StatusTooManyRequests, RetryAfter: 24 * time.Hour}}
+	limiter := NewRateLimiter("throttled", 60)
+
+	m := &Manager{}
+	if _, _, err := m.fetchWithBackoff(context.Background(), provider, limiter); err == nil {
+		t.Fatal("fetchWithBackoff succeeded, want the throttling error")
+	}
+
+	limiter.mu.Lock()
+	blockedFor := time.Until(limiter.blockedUntil)
+	limiter.mu.Unlock()
+	if blockedFor > maxThrottleRetryWait {
+		t.Errorf("limiter blocked for %s, want at most %s", blockedFor, maxThrottleRetryWait)
+	}
+}
+
+func TestFetchWithBackoffStopsWhenContextIsDone(t *testing.T) {
+	provider := &fakeProvider{err: &ThrottledError{StatusCode: http.StatusTooManyRequests, RetryAfter: maxThrottleRetryWait}}
+	limiter := NewRateLimiter("throttled", 60)
+
+	// The Retry-After is short enough to wait out, but the sync ends first
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	m := &Manager{}
+	start := time.Now()
+	if _, _, err := m.fetchWithBackoff(ctx, provider, limiter); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("fetchWithBackoff = %v, want the context's deadline error", err)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("fetchWithBackoff waited %s after the context ended", waited)
+	}
+}
@@ -0,0 +1,47 @@
+// multi_provider_error.go - Partial sync failures
+// Lets a sync report every provider that failed without hiding the ones that succeeded
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProviderFailure is one provider's failed sync
+type ProviderFailure struct {
+	Provider string
+	Err      error
+}
+
+// MultiProviderError lists every provider that failed during a FetchAll run
+// Providers missing from Failures synced normally; their reports say how it went
+type MultiProviderError struct {
+	Failures []ProviderFailure // Sorted by provider name
+}
+
+func (e *MultiProviderError) Error() string {
+	parts := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		parts[i] = fmt.Sprintf("%s: %v", f.Provider, f.Err)
+	}
+	return fmt.Sprintf("%d providers failed to fetch: %s", len(e.Failures), strings.Join(parts, "; "))
+}
+
+// Unwrap exposes each provider's error to errors.Is and errors.As
+func (e *MultiProviderError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// Failed reports whether the named provider is among the failures
+func (e *MultiProviderError) Failed(name string) bool {
+	for _, f := range e.Failures {
+		if f.Provider == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+// pagination.go - Following paginated provider feeds
+// Fetches every page of a feed that reports more items than its first page holds
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"search-engine/backend/internal/config"
+	"strconv"
+)
+
+// PaginationPolicy configures how a provider follows a paginated feed
+// Pages after the first are requested by adding PageParam=N to the provider URL
+type PaginationPolicy struct {
+	PageParam string // Query parameter carrying the page number
+	MaxPages  int    // Most pages fetched per sync, the first included; guards against endless feeds
+}
+
+// DefaultPaginationPolicy returns the pagination policy used when none is configured
+func DefaultPaginationPolicy() PaginationPolicy {
+	return PaginationPolicy{
+		PageParam: "page",
+		MaxPages:  50,
+	}
+}
+
+// PaginationPolicyFromConfig converts the pagination settings, keeping defaults for unset fields
+func PaginationPolicyFromConfig(cfg config.ProviderPaginationConfig) PaginationPolicy {
+	return PaginationPolicy{
+		PageParam: cfg.PageParam,
+		MaxPages:  cfg.MaxPages,
+	}.withDefaults()
+}
+
+// withDefaults fills empty or non-positive fields from DefaultPaginationPolicy
+func (p PaginationPolicy) withDefaults() PaginationPolicy {
+	defaults := DefaultPaginationPolicy()
+	if p.PageParam == "" {
+		p.PageParam = defaults.PageParam
+	}
+	if p.MaxPages <= 0 {
+		p.MaxPages = defaults.MaxPages
+	}
+	return p
+}
+
+// pageInfo is the pagination metadata a feed reports with each page
+// Zero fields mean the feed didn't say
+type pageInfo struct {
+	total   int
+	page    int
+	perPage int
+}
+
+// hasNext reports whether items remain after page number n
+func (i pageInfo) hasNext(n int) bool {
+	return i.perPage > 0 && i.total > i.perPage*n
+}
+
+// fetchPages downloads the feed at baseURL and, while it reports more items, the pages after it
+// parse turns one response body into its items and pagination metadata. The first page is the
+// URL as configured; a failure on any page fails the whole fetch. Stops early, with a warning,
+// at pagination.MaxPages or when the feed ignores the page parameter
+func fetchPages[T any](ctx context.Context, client *http.Client, baseURL string, retry RetryPolicy, pagination PaginationPolicy, parse func(body []byte) ([]T, pageInfo, error)) ([]T, error) {
+	var items []T
+	pageURL := baseURL
+	for n := 1; ; n++ {
+		var pageItems []T
+		var info pageInfo
+		body, err := fetchBody(ctx, client, pageURL, retry)
+		if err == nil {
+			pageItems, info, err = parse(body)
+		}
+		if err != nil {
+			if n == 1 {
+				return nil, err
+			}
+			return nil, fmt.Errorf("page %d: %w", n, err)
+		}
+		// A feed that ignores the page parameter serves its first page again; don't take it twice
+		if n > 1 && info.page != 0 && info.page != n {
+			log.Printf("Warning: %s answered page %d when asked for page %d; is %q its page parameter? Keeping %d items", baseURL, info.page, n, pagination.PageParam, len(items))
+			return items, nil
+		}
+		items = append(items, pageItems...)
+
+		switch {
+		case !info.hasNext(n) || len(pageItems) == 0:
+			return items, nil
+		case n >= pagination.MaxPages:
+			log.Printf("Warning: %s stopped after %d pages with %d of %d items; raise PROVIDER_MAX_PAGES to fetch the rest", baseURL, n, len(items), info.total)
+			return items, nil
+		}
+
+		if pageURL, err = withPageParam(baseURL, pagination.PageParam, n+1); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// withPageParam returns rawURL with param set to page, keeping its other query parameters
+func withPageParam(rawURL, param string, page int) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set(param, strconv.Itoa(page))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
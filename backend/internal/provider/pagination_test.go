@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// pagedFeed serves five items two per page, reading the page number from param
+// page renders one page; requested records the page numbers asked for
+type pagedFeed struct {
+	param     string
+	page      func(n int, ids []string) string
+	mu        sync.Mutex
+	requested []int
+}
+
+const pagedTotal, pagedPerPage = 5, 2
+
+func (f *pagedFeed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := 1
+	if v := r.URL.Query().Get(f.param); v != "" {
+		n, _ = strconv.Atoi(v)
+	}
+	f.mu.Lock()
+	f.requested = append(f.requested, n)
+	f.mu.Unlock()
+
+	if r.URL.Query().Get("key") != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var ids []string
+	for i := (n-1)*pagedPerPage + 1; i <= min(n*pagedPerPage, pagedTotal); i++ {
+		ids = append(ids, fmt.Sprintf("v%d", i))
+	}
+	fmt.Fprint(w, f.page(n, ids))
+}
+
+func jsonPage(n int, ids []string) string {
+	items := make([]string, len(ids))
+	for i, id := range ids {
+		items[i] = fmt.Sprintf(`{"id": %q, "title": "Item %s", "type": "video", "published_at": "2024-03-15T10:00:00Z"}`, id, id)
+	}
+	return fmt.Sprintf(`{"contents": [%s], "pagination": {"total": %d, "page": %d, "per_page": %d}}`,
+		strings.Join(items, ","), pagedTotal, n, pagedPerPage)
+}
+
+func xmlPage(n int, ids []string) string {
+	var items strings.Builder
+	for _, id := range ids {
+		fmt.Fprintf(&items, `<item><id>%s</id><headline>Item %s</headline><type>article</type><publication_date>2024-03-15</publication_date></item>`, id, id)
+	}
+	return fmt.Sprintf(`<?xml version="1.0"?><feed><items>%s</items><meta><total_count>%d</total_count><current_page>%d</current_page><items_per_page>%d</items_per_page></meta></feed>`,
+		items.String(), pagedTotal, n, pagedPerPage)
+}
+
+func servePagedFeed(t *testing.T, feed *pagedFeed) string {
+	t.Helper()
+	srv := httptest.NewServer(feed)
+	t.Cleanup(srv.Close)
+	// The provider URL's own query parameters must survive on later pages
+	return srv.URL + "/feed?key=secret"
+}
+
+func fetchIDs(t *testing.T, p Provider) []string {
+	t.Helper()
+	contents, rejected, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(rejected) > 0 {
+		t.Fatalf("rejected %d items: %v", len(rejected), rejected[0])
+	}
+	ids := make([]string, len(contents))
+	for i, c := range contents {
+		ids[i] = c.ExternalID
+	}
+	return ids
+}
+
+func TestProvidersFollowPagination(t *testing.T) {
+	tests := []struct {
+		name      string
+		page      func(int, []string) string
+		newFeed   func(url string) Provider
+		param     string
+		wantIDs   []string
+		wantPages []int
+	}{
+		{
+			"JSON", jsonPage,
+			func(url string) Provider {
+				return NewJSONProvider("provider1", url, DefaultHTTPTimeouts(), nil, fastRetry, DefaultPaginationPolicy())
+			},
+			"page", []string{"v1", "v2", "v3", "v4", "v5"}, []int{1, 2, 3},
+		},
+		{
+			"XML", xmlPage,
+			func(url string) Provider {
+				return NewXMLProvider("provider2", url, DefaultHTTPTimeouts(), nil, fastRetry, DefaultPaginationPolicy())
+			},
+			"page", []string{"v1", "v2", "v3", "v4", "v5"}, []int{1, 2, 3},
+		},
+		{
+			"custom page parameter", jsonPage,
+			func(url string) Provider {
+				return NewJSONProvider("provider1", url, DefaultHTTPTimeouts(), nil, fastRetry, PaginationPolicy{PageParam: "p"})
+			},
+			"p", []string{"v1", "v2", "v3", "v4", "v5"}, []int{1, 2, 3},
+		},
+		{
+			"max pages", xmlPage,
+			func(url string) Provider {
+				return NewXMLProvider("provider2", url, DefaultHTTPTimeouts(), nil, fastRetry, PaginationPolicy{MaxPages: 2})
+			},
+			"page", []string{"v1", "v2", "v3", "v4"}, []int{1, 2},
+		},
+		{
+			"feed ignoring the page parameter", jsonPage,
+			func(url string) Provider {
+				return NewJSONProvider("provider1", url, DefaultHTTPTimeouts(), nil, fastRetry, PaginationPolicy{PageParam: "offset"})
+			},
+			"page", []string{"v1", "v2"}, []int{1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := &pagedFeed{param: tt.param, page: tt.page}
+			p := tt.newFeed(servePagedFeed(t, feed))
+
+			if ids := fetchIDs(t, p); !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("fetched %v, want %v", ids, tt.wantIDs)
+			}
+			if !slices.Equal(feed.requested, tt.wantPages) {
+				t.Errorf("requested pages %v, want %v", feed.requested, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestProviderFailsOnLaterPageError(t *testing.T) {
+	feed := &pagedFeed{param: "page", page: func(n int, ids []string) string {
+		if n == 2 {
+			return "<html>maintenance</html>"
+		}
+		return jsonPage(n, ids)
+	}}
+	p := NewJSONProvider("provider1", servePagedFeed(t, feed), DefaultHTTPTimeouts(), nil, fastRetry, DefaultPaginationPolicy())
+
+	_, _, err := p.Fetch(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "page 2") {
+		t.Errorf("Fetch() error = %v, want a failure naming page 2", err)
+	}
+}
+
+func TestProviderStopsPagingWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	feed := &pagedFeed{param: "page", page: func(n int, ids []string) string {
+		cancel() // The sync is abandoned while page 1 is being served
+		return jsonPage(n, ids)
+	}}
+	p := NewJSONProvider("provider1", servePagedFeed(t, feed), DefaultHTTPTimeouts(), nil, fastRetry, DefaultPaginationPolicy())
+
+	_, _, err := p.Fetch(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Fetch() error = %v, want context.Canceled", err)
+	}
+	if len(feed.requested) > 1 {
+		t.Errorf("requested pages %v after cancelling, want none past page 1", feed.requested)
+	}
+}
+
+func TestWithPageParam(t *testing.T) {
+	tests := []struct {
+		url, param string
+		page       int
+		want       string
+	}{
+		{"https://example.com/feed", "page", 2, "https://example.com/feed?page=2"},
+		{"https://example.com/feed?key=abc", "p", 3, "https://example.com/feed?key=abc&p=3"},
+		{"https://example.com/feed?page=1&key=abc", "page", 2, "https://example.com/feed?key=abc&page=2"},
+	}
+	for _, tt := range tests {
+		got, err := withPageParam(tt.url, tt.param, tt.page)
+		if err != nil || got != tt.want {
+			t.Errorf("withPageParam(%q, %q, %d) = %q, %v; want %q", tt.url, tt.param, tt.page, got, err, tt.want)
+		}
+	}
+}
@@ -1,30 +1,80 @@
 // rate_limiter.go - Rate limiting for provider requests
-// Implements token bucket algorithm for rate limiting
+// Implements token bucket algorithm for rate limiting, lowered adaptively
+// while an upstream is throttling us
 package provider
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
 	"sync"
 	"time"
 )
 
+// throttleCooldown is how long a lowered rate is kept after the last throttled response
+// Once it passes without new throttling, the configured rate is restored
+const throttleCooldown = 5 * time.Minute
+
+// ThrottledError is returned when a provider answers 429 Too Many Requests
+// RetryAfter is the upstream's Retry-After, or 0 when it didn't send one
+type ThrottledError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("throttled by provider (status %d, retry after %s)", e.StatusCode, e.RetryAfter)
+	}
+	return fmt.Sprintf("throttled by provider (status %d)", e.StatusCode)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+// Returns 0 when the header is missing, invalid or already in the past
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
 // RateLimiter implements a token bucket rate limiter
 // This ensures we don't exceed the provider's rate limit
+// Throttle temporarily lowers the rate when the upstream pushes back
 type RateLimiter struct {
-	rate       int        // Requests per minute
-	tokens     int        // Current available tokens
-	maxTokens  int        // Maximum tokens (same as rate)
-	lastUpdate time.Time  // Last time tokens were refilled
-	mu         sync.Mutex // Protects token bucket
+	name         string     // Provider name, used in logs
+	rate         int        // Requests per minute currently in effect
+	baseRate     int        // Configured requests per minute, restored after a cooldown
+	tokens       int        // Current available tokens
+	maxTokens    int        // Maximum tokens (same as rate)
+	lastUpdate   time.Time  // Last time tokens were refilled
+	blockedUntil time.Time  // No requests before this (upstream Retry-After)
+	throttledAt  time.Time  // Last time the upstream throttled us; zero when not throttled
+	mu           sync.Mutex // Protects token bucket
 }
 
 // NewRateLimiter creates a new rate limiter
 // rate: maximum requests per minute
-func NewRateLimiter(rate int) *RateLimiter {
+// name identifies the provider in adaptive rate change logs
+func NewRateLimiter(name string, rate int) *RateLimiter {
 	if rate < 1 {
 		rate = 1 // Minimum 1 request per minute
 	}
 	return &RateLimiter{
+		name:       name,
 		rate:       rate,
+		baseRate:   rate,
 		tokens:     rate,
 		maxTokens:  rate,
 		lastUpdate: time.Now(),
@@ -33,12 +83,25 @@ func NewRateLimiter(rate int) *RateLimiter {
 
 // Wait blocks until a token is available
 // This implements the token bucket algorithm
-func (rl *RateLimiter) Wait() {
+// It also waits out any upstream Retry-After recorded by Throttle
+// Returns ctx's error, without taking a token, if it is done first
+func (rl *RateLimiter) Wait(ctx context.Context) error {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
+	// Honor the upstream's Retry-After before anything else
+	if wait := time.Until(rl.blockedUntil); wait > 0 {
+		rl.mu.Unlock()
+		err := waitContext(ctx, wait)
+		rl.mu.Lock()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Refill tokens based on elapsed time
 	now := time.Now()
+	rl.restoreIfCooledDown(now)
 	elapsed := now.Sub(rl.lastUpdate)
 
 	// Calculate how many tokens to add
@@ -55,7 +118,7 @@ func (rl *RateLimiter) Wait() {
 	// If we have tokens, use one immediately
 	if rl.tokens > 0 {
 		rl.tokens--
-		return
+		return nil
 	}
 
 	// No tokens available, calculate wait time
@@ -64,14 +127,75 @@ func (rl *RateLimiter) Wait() {
 	waitTime := timePerToken - elapsed
 	if waitTime > 0 {
 		rl.mu.Unlock()
-		time.Sleep(waitTime)
+		err := waitContext(ctx, waitTime)
 		rl.mu.Lock()
+		if err != nil {
+			return err
+		}
 		rl.tokens--
 		rl.lastUpdate = time.Now()
 	} else {
 		rl.tokens--
 		rl.lastUpdate = now
 	}
+	return nil
+}
+
+// waitContext waits for d, returning ctx's error early if it is cancelled first
+func waitContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Throttle reacts to the upstream throttling us
+// Requests are blocked for retryAfter and the rate is halved (minimum 1/min);
+// the configured rate comes back once throttleCooldown passes without throttling
+func (rl *RateLimiter) Throttle(retryAfter time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	rl.throttledAt = now
+	if until := now.Add(retryAfter); until.After(rl.blockedUntil) {
+		rl.blockedUntil = until
+	}
+
+	lowered := max(rl.rate/2, 1)
+	if lowered < rl.rate {
+		log.Printf("Provider %s is throttling us: lowering rate from %d to %d requests/min for at least %s",
+			rl.name, rl.rate, lowered, throttleCooldown)
+		rl.setRate(lowered)
+	}
+	// Drop banked tokens so the next request really waits for the lower rate
+	rl.tokens = 0
+	rl.lastUpdate = now
+}
+
+// restoreIfCooledDown restores the configured rate once the throttle cooldown has passed
+// Must be called with rl.mu held
+func (rl *RateLimiter) restoreIfCooledDown(now time.Time) {
+	if rl.throttledAt.IsZero() || now.Sub(rl.throttledAt) < throttleCooldown {
+		return
+	}
+	rl.throttledAt = time.Time{}
+	if rl.rate != rl.baseRate {
+		log.Printf("Provider %s stopped throttling: restoring rate from %d to %d requests/min",
+			rl.name, rl.rate, rl.baseRate)
+		rl.setRate(rl.baseRate)
+	}
+}
+
+// Rate returns the requests per minute currently in effect
+func (rl *RateLimiter) Rate() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.rate
 }
 
 // SetRate updates the rate limit
@@ -83,6 +207,13 @@ func (rl *RateLimiter) SetRate(rate int) {
 	if rate < 1 {
 		rate = 1
 	}
+	rl.baseRate = rate
+	rl.setRate(rate)
+}
+
+// setRate changes the rate in effect without touching the configured one
+// Must be called with rl.mu held
+func (rl *RateLimiter) setRate(rate int) {
 	rl.rate = rate
 	rl.maxTokens = rate
 	if rl.tokens > rate {
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"missing", "", 0},
+		{"seconds", "30", 30 * time.Second},
+		{"negative seconds", "-5", 0},
+		{"http date", now.Add(2 * time.Minute).Format(http.TimeFormat), 2 * time.Minute},
+		{"date in the past", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"garbage", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiterThrottleLowersAndRestoresRate(t *testing.T) {
+	rl := NewRateLimiter("test", 60)
+
+	rl.Throttle(0)
+	if got := rl.Rate(); got != 30 {
+		t.Fatalf("rate after first throttle = %d, want 30", got)
+	}
+	rl.Throttle(0)
+	if got := rl.Rate(); got != 15 {
+		t.Fatalf("rate after second throttle = %d, want 15", got)
+	}
+
+	// Still within the cooldown: the lowered rate stays
+	rl.mu.Lock()
+	rl.restoreIfCooledDown(rl.throttledAt.Add(throttleCooldown - time.Second))
+	rl.mu.Unlock()
+	if got := rl.Rate(); got != 15 {
+		t.Fatalf("rate within cooldown = %d, want 15", got)
+	}
+
+	rl.mu.Lock()
+	rl.restoreIfCooledDown(rl.throttledAt.Add(throttleCooldown))
+	rl.mu.Unlock()
+	if got := rl.Rate(); got != 60 {
+		t.Fatalf("rate after cooldown = %d, want 60", got)
+	}
+}
+
+func TestRateLimiterThrottleKeepsMinimumRate(t *testing.T) {
+	rl := NewRateLimiter("test", 1)
+	rl.Throttle(0)
+	if got := rl.Rate(); got != 1 {
+		t.Errorf("rate = %d, want 1", got)
+	}
+}
+
+func TestRateLimiterThrottleBlocksForRetryAfter(t *testing.T) {
+	rl := NewRateLimiter("test", 60)
+	rl.Throttle(time.Hour)
+
+	rl.mu.Lock()
+	blockedFor := time.Until(rl.blockedUntil)
+	rl.mu.Unlock()
+	if blockedFor < 59*time.Minute {
+		t.Errorf("blocked for %s, want about 1h", blockedFor)
+	}
+}
+
+func TestRateLimiterWaitStopsWhenContextIsDone(t *testing.T) {
+	rl := NewRateLimiter("test", 60)
+	rl.Throttle(time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := rl.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait = %v, want the context's deadline error", err)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("Wait blocked for %s after the context ended", waited)
+	}
+}
+
+func TestRateLimiterWaitForTokenStopsWhenContextIsDone(t *testing.T) {
+	// One request a minute with the only token spent: the next one waits about a minute
+	rl := NewRateLimiter("test", 1)
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := rl.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait = %v, want context.Canceled", err)
+	}
+	rl.mu.Lock()
+	tokens := rl.tokens
+	rl.mu.Unlock()
+	if tokens != 0 {
+		t.Errorf("tokens = %d after a cancelled wait, want 0", tokens)
+	}
+}
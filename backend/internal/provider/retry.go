@@ -0,0 +1,117 @@
+// retry.go - Retry with exponential backoff for provider fetches
+// Rides out network blips and transient upstream errors instead of losing a whole sync
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"search-engine/backend/internal/config"
+	"time"
+)
+
+// RetryPolicy configures how a provider fetch is retried
+// Attempt n (from 0) waits BaseDelay * 2^n before the next one, capped at MaxDelay
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first; 1 disables retries
+	BaseDelay   time.Duration // Wait before the first retry
+	MaxDelay    time.Duration // Longest single wait
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+}
+
+// RetryPolicyFromConfig converts the retry settings, keeping defaults for unset fields
+func RetryPolicyFromConfig(cfg config.ProviderRetryConfig) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: cfg.MaxAttempts,
+		BaseDelay:   time.Duration(cfg.BaseDelayMs) * time.Millisecond,
+	}.withDefaults()
+}
+
+// withDefaults fills zero or negative fields from DefaultRetryPolicy
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaults.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaults.MaxDelay
+	}
+	return p
+}
+
+// delay returns the backoff before retrying after attempt (0 = the first attempt)
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 0; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxDelay)
+}
+
+// StatusError is returned for a non-200 response other than 429 (see ThrottledError)
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// isRetryable reports whether a failed fetch may succeed if tried again
+// Network and read errors, 429 and 5xx are transient; other statuses and a done context are not
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// withRetry calls fetch until it succeeds, fails permanently, runs out of attempts,
+// or ctx is done, backing off exponentially between attempts
+// A 429 waits for the upstream's Retry-After when longer than the backoff; one longer
+// than MaxDelay is returned at once so the manager's rate limiter can back off instead
+func withRetry(ctx context.Context, policy RetryPolicy, url string, fetch func() ([]byte, error)) ([]byte, error) {
+	policy = policy.withDefaults()
+
+	for attempt := 0; ; attempt++ {
+		body, err := fetch()
+		if err == nil || attempt+1 >= policy.MaxAttempts || !isRetryable(ctx, err) {
+			return body, err
+		}
+
+		wait := policy.delay(attempt)
+		var throttled *ThrottledError
+		if errors.As(err, &throttled) {
+			if throttled.RetryAfter > policy.MaxDelay {
+				return nil, err
+			}
+			wait = max(wait, throttled.RetryAfter)
+		}
+
+		log.Printf("Fetch from %s failed (attempt %d of %d), retrying in %s: %v", url, attempt+1, policy.MaxAttempts, wait, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w (gave up retrying: %v)", err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
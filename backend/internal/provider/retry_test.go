@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetry retries quickly so tests don't wait on real backoff
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+// flakyServer answers failStatus to the first failures requests, then body
+func flakyServer(t *testing.T, failures int32, failStatus int, body string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(failStatus)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestJSONProviderFetchRetriesTransientFailures(t *testing.T) {
+	srv, requests := flakyServer(t, 2, http.StatusServiceUnavailable,
+		`{"contents": [{"id": "v1", "title": "Go", "type": "video", "published_at": "2024-03-15T10:00:00Z"}]}`)
+
+	p := NewJSONProvider("provider1", srv.URL, DefaultHTTPTimeouts(), nil, fastRetry, DefaultPaginationPolicy())
+	contents, _, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(contents) != 1 || contents[0].ExternalID != "v1" {
+		t.Errorf("contents = %+v, want the single item", contents)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3 (two failures, then success)", got)
+	}
+}
+
+func TestXMLProviderFetchRetriesTransientFailures(t *testing.T) {
+	srv, requests := flakyServer(t, 2, http.StatusTooManyRequests,
+		`<?xml version="1.0"?><feed><items></items></feed>`)
+
+	p := NewXMLProvider("provider2", srv.URL, DefaultHTTPTimeouts(), nil, fastRetry, DefaultPaginationPolicy())
+	if _, _, err := p.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3 (two failures, then success)", got)
+	}
+}
+
+func TestFetchBodyRetryLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		status       int
+		wantRequests int32
+		wantStatus   int
+	}{
+		{"gives up after max attempts", 5, http.StatusBadGateway, 3, http.StatusBadGateway},
+		{"client errors are not retried", 5, http.StatusNotFound, 1, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := flakyServer(t, tt.failures, tt.status, "ok")
+
+			_, err := fetchBody(context.Background(), http.DefaultClient, srv.URL, fastRetry)
+			var status *StatusError
+			if !errors.As(err, &status) || status.StatusCode != tt.wantStatus {
+				t.Fatalf("err = %v, want status %d", err, tt.wantStatus)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestWithRetryStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}
+
+	_, err := withRetry(ctx, policy, "test", func() ([]byte, error) {
+		calls++
+		cancel() // Cancel while the first backoff would be waiting
+		return nil, &StatusError{StatusCode: http.StatusServiceUnavailable}
+	})
+
+	if err == nil || calls != 1 {
+		t.Errorf("withRetry = %v after %d calls, want an error after 1 call", err, calls)
+	}
+}
+
+func TestWithRetryReturnsLongThrottleImmediately(t *testing.T) {
+	calls := 0
+	_, err := withRetry(context.Background(), fastRetry, "test", func() ([]byte, error) {
+		calls++
+		return nil, &ThrottledError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute}
+	})
+
+	var throttled *ThrottledError
+	if !errors.As(err, &throttled) || calls != 1 {
+		t.Errorf("withRetry = %v after %d calls, want the ThrottledError after 1 call", err, calls)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for attempt, w := range want {
+		if got := p.delay(attempt); got != w {
+			t.Errorf("delay(%d) = %s, want %s", attempt, got, w)
+		}
+	}
+}
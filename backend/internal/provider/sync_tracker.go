@@ -0,0 +1,51 @@
+// sync_tracker.go - Tracks which provider syncs are running in this process
+// Lets status endpoints report in-flight syncs without touching the manager that runs them
+package provider
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// runningSyncs maps provider name to the start time of its in-flight sync
+// Package-level because startup and manual syncs build separate managers
+var runningSyncs = struct {
+	mu      sync.Mutex
+	started map[string]time.Time
+}{started: make(map[string]time.Time)}
+
+// markSyncRunning records that a sync of the named provider started
+// The returned func marks it finished
+func markSyncRunning(name string) func() {
+	runningSyncs.mu.Lock()
+	runningSyncs.started[name] = time.Now()
+	runningSyncs.mu.Unlock()
+
+	return func() {
+		runningSyncs.mu.Lock()
+		delete(runningSyncs.started, name)
+		runningSyncs.mu.Unlock()
+	}
+}
+
+// SyncRunning reports whether a sync of the named provider is running in this process
+// Syncs run by the standalone sync command are not visible here
+func SyncRunning(name string) bool {
+	runningSyncs.mu.Lock()
+	defer runningSyncs.mu.Unlock()
+	_, ok := runningSyncs.started[name]
+	return ok
+}
+
+// syncInProgress guards full sync runs so only one runs at a time in this process
+var syncInProgress atomic.Bool
+
+// TryStartSync claims the process-wide sync slot
+// Returns false if a full sync is already running; otherwise the returned func releases the slot
+func TryStartSync() (release func(), ok bool) {
+	if !syncInProgress.CompareAndSwap(false, true) {
+		return nil, false
+	}
+	return func() { syncInProgress.Store(false) }, true
+}
@@ -0,0 +1,22 @@
+package provider
+
+import "testing"
+
+func TestTryStartSyncAllowsOneRunAtATime(t *testing.T) {
+	release, ok := TryStartSync()
+	if !ok {
+		t.Fatal("first TryStartSync() = false, want true")
+	}
+
+	if _, ok := TryStartSync(); ok {
+		t.Fatal("TryStartSync() while a sync runs = true, want false")
+	}
+
+	release()
+
+	release, ok = TryStartSync()
+	if !ok {
+		t.Fatal("TryStartSync() after release = false, want true")
+	}
+	release()
+}
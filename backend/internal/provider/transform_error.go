@@ -0,0 +1,60 @@
+// transform_error.go - Dead letters for provider items that fail transformation
+// A rejected item is kept with its raw form and the reason, so format drift at a
+// provider shows up in the sync logs instead of as silently missing content
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// maxDeadLetterRawBytes caps how much of a rejected item's raw form is kept
+const maxDeadLetterRawBytes = 1024
+
+// FieldError is a transformation failure attributed to one item field
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %v", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// TransformError describes a provider item that couldn't be transformed to content
+// Field is empty when the failure isn't tied to a single field
+type TransformError struct {
+	ExternalID string `json:"external_id"`
+	Field      string `json:"field,omitempty"`
+	Reason     string `json:"reason"`
+	Raw        string `json:"raw"` // JSON form of the item as parsed, truncated to maxDeadLetterRawBytes
+}
+
+func (e TransformError) Error() string {
+	return fmt.Sprintf("item %q: %s", e.ExternalID, e.Reason)
+}
+
+// newTransformError builds the dead letter for item rejected with err
+func newTransformError(externalID string, item any, err error) TransformError {
+	te := TransformError{ExternalID: externalID, Reason: err.Error()}
+
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		te.Field = fieldErr.Field
+	}
+
+	if raw, marshalErr := json.Marshal(item); marshalErr == nil {
+		te.Raw = string(raw)
+	} else {
+		te.Raw = fmt.Sprintf("%+v", item)
+	}
+	if len(te.Raw) > maxDeadLetterRawBytes {
+		te.Raw = te.Raw[:maxDeadLetterRawBytes] + "..."
+	}
+	return te
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMalformedItemsBecomeTransformErrors(t *testing.T) {
+	jsonProvider := NewJSONProvider("provider1", "", DefaultHTTPTimeouts(), nil, DefaultRetryPolicy(), DefaultPaginationPolicy())
+	badDuration := "soon"
+	contents, rejected := transformItems([]JSONContentItem{
+		{ID: "ok", Title: "Go", Type: "video", PublishedAt: "2024-03-15T10:00:00Z"},
+		{ID: "bad-date", Title: "Go", Type: "video", PublishedAt: "15/03/2024"},
+		{ID: "bad-duration", Title: "Go", Type: "video", PublishedAt: "2024-03-15T10:00:00Z", Metrics: JSONMetrics{Duration: &badDuration}},
+	}, func(item JSONContentItem) string { return item.ID }, jsonProvider.transformToContent)
+
+	if len(contents) != 1 || contents[0].ExternalID != "ok" {
+		t.Errorf("contents = %+v, want only item ok", contents)
+	}
+	wantFields := map[string]string{"bad-date": "published_at", "bad-duration": "duration"}
+	if len(rejected) != len(wantFields) {
+		t.Fatalf("rejected = %+v, want %d items", rejected, len(wantFields))
+	}
+	for _, r := range rejected {
+		if r.Field != wantFields[r.ExternalID] {
+			t.Errorf("item %s field = %q, want %q", r.ExternalID, r.Field, wantFields[r.ExternalID])
+		}
+		if r.Reason == "" || !strings.Contains(r.Raw, r.ExternalID) {
+			t.Errorf("item %s reason = %q, raw = %q; want a reason and the raw item", r.ExternalID, r.Reason, r.Raw)
+		}
+	}
+}
+
+func TestXMLMalformedItemsBecomeTransformErrors(t *testing.T) {
+	xmlProvider := NewXMLProvider("provider2", "", DefaultHTTPTimeouts(), nil, DefaultRetryPolicy(), DefaultPaginationPolicy())
+	views := "lots"
+	_, rejected := transformItems([]XMLContentItem{
+		{ID: "x1", Headline: "Go", Type: "video", PublicationDate: "2024-03-15", Stats: XMLStats{Views: &views}},
+	}, func(item XMLContentItem) string { return item.ID }, xmlProvider.transformToContent)
+
+	if len(rejected) != 1 || rejected[0].ExternalID != "x1" || rejected[0].Field != "views" {
+		t.Errorf("rejected = %+v, want item x1 rejected for its views", rejected)
+	}
+}
+
+func TestNewTransformErrorTruncatesRaw(t *testing.T) {
+	te := newTransformError("big", map[string]string{"title": strings.Repeat("x", 2*maxDeadLetterRawBytes)}, &FieldError{Field: "title", Err: errors.New("too long")})
+	if len(te.Raw) != maxDeadLetterRawBytes+len("...") {
+		t.Errorf("raw length = %d, want %d", len(te.Raw), maxDeadLetterRawBytes+len("..."))
+	}
+	if te.Field != "title" {
+		t.Errorf("field = %q, want title", te.Field)
+	}
+}
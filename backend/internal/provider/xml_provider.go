@@ -3,14 +3,13 @@
 package provider
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
-	"io"
 	"net/http"
 	"search-engine/backend/internal/model"
 	"strconv"
 	"strings"
-	"time"
 )
 
 // XMLProviderResponse represents the XML structure from Provider 2
@@ -38,7 +37,7 @@ type XMLContentItem struct {
 type XMLStats struct {
 	Views    *string `xml:"views,omitempty"`    // Video metric (string in XML)
 	Likes    *string `xml:"likes,omitempty"`    // Video metric (string in XML)
-	Duration *string `xml:"duration,omitempty"` // Video metric (format: "MM:SS")
+	Duration *string `xml:"duration,omitempty"` // Video metric (format: "MM:SS" or "HH:MM:SS")
 
 	ReadingTime *string `xml:"reading_time,omitempty"` // Article metric (string in XML)
 	Reactions   *string `xml:"reactions,omitempty"`    // Article metric (string in XML)
@@ -61,65 +60,65 @@ type XMLMeta struct {
 // This handles fetching and parsing data from Provider 2
 type XMLProvider struct {
 	BaseProvider
-	client *http.Client
+	client      *http.Client
+	dateLayouts []string
+	retry       RetryPolicy
+	pagination  PaginationPolicy
 }
 
 // NewXMLProvider creates a new XML provider instance
-// Sets up HTTP client with timeout for reliable requests
-func NewXMLProvider(name, url string) *XMLProvider {
+// Sets up an HTTP client with separate connect, response-header and overall timeouts
+// dateLayouts are tried in order for publication_date; empty uses DefaultXMLDateLayouts
+// retry configures how transient fetch failures are retried; zero fields use DefaultRetryPolicy
+// pagination configures how later pages are requested; zero fields use DefaultPaginationPolicy
+func NewXMLProvider(name, url string, timeouts HTTPTimeouts, dateLayouts []string, retry RetryPolicy, pagination PaginationPolicy) *XMLProvider {
 	return &XMLProvider{
 		BaseProvider: BaseProvider{
 			Name: name,
 			URL:  url,
 		},
-		client: &http.Client{
-			Timeout: 30 * time.Second, // 30 second timeout for API requests
-		},
+		client:      newHTTPClient(timeouts),
+		dateLayouts: dateLayoutsOrDefault(dateLayouts, DefaultXMLDateLayouts),
+		retry:       retry.withDefaults(),
+		pagination:  pagination.withDefaults(),
 	}
 }
 
 // Fetch retrieves content from the XML provider's API
-// Downloads XML data, parses it, and transforms it to standard format
-func (p *XMLProvider) Fetch() ([]*model.Content, error) {
-	// Make HTTP GET request to provider URL
-	// This fetches the raw XML data
-	resp, err := p.client.Get(p.URL)
+// Downloads XML data, following the feed's pagination, parses it, and transforms it to standard format
+func (p *XMLProvider) Fetch(ctx context.Context) ([]*model.Content, []TransformError, error) {
+	// Download every page of XML data (each reused if fetched moments ago)
+	items, err := fetchPages(ctx, p.client, p.URL, p.retry, p.pagination, parseXMLPage)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch from XML provider: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch from XML provider: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Check HTTP status code
-	// Non-200 status codes indicate an error
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
+	// Transform XML items to standard Content models, setting aside ones that fail
+	contents, rejected := transformItems(items,
+		func(item XMLContentItem) string { return item.ID },
+		p.transformToContent)
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	return contents, rejected, nil
+}
+
+// parseXMLPage parses one page of the XML feed into its items and pagination metadata
+func parseXMLPage(body []byte) ([]XMLContentItem, pageInfo, error) {
+	// Catch a provider configured with the wrong format before parsing fails vaguely
+	if err := checkFormat(body, model.ProviderFormatXML); err != nil {
+		return nil, pageInfo{}, err
 	}
 
-	// Parse XML response
 	var xmlResponse XMLProviderResponse
 	if err := xml.Unmarshal(body, &xmlResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse XML: %w", err)
+		return nil, pageInfo{}, fmt.Errorf("failed to parse XML: %w", err)
 	}
 
-	// Transform XML items to standard Content models
-	contents := make([]*model.Content, 0, len(xmlResponse.Items))
-	for _, item := range xmlResponse.Items {
-		content, err := p.transformToContent(item)
-		if err != nil {
-			// Log error but continue processing other items
-			// This ensures partial failures don't stop the entire sync
-			continue
-		}
-		contents = append(contents, content)
+	info := pageInfo{
+		total:   xmlResponse.Meta.TotalCount,
+		page:    xmlResponse.Meta.CurrentPage,
+		perPage: xmlResponse.Meta.ItemsPerPage,
 	}
-
-	return contents, nil
+	return xmlResponse.Items, info, nil
 }
 
 // transformToContent converts an XMLContentItem to a standard Content model
@@ -131,11 +130,11 @@ func (p *XMLProvider) transformToContent(item XMLContentItem) (*model.Content, e
 		Type:       model.NormalizeContentType(item.Type),
 	}
 
-	// Parse publication_date timestamp
-	// XML provider uses date format: "2024-03-15"
-	publishedAt, err := time.Parse("2006-01-02", item.PublicationDate)
+	// Parse publication_date timestamp with the configured layouts
+	// Date-only values such as "2024-03-15" are taken as midnight UTC
+	publishedAt, err := parseDate(item.PublicationDate, p.dateLayouts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse publication_date: %w", err)
+		return nil, &FieldError{Field: "publication_date", Err: err}
 	}
 	content.PublishedAt = publishedAt
 
@@ -145,22 +144,22 @@ func (p *XMLProvider) transformToContent(item XMLContentItem) (*model.Content, e
 		if item.Stats.Views != nil {
 			views, err := parseIntString(*item.Stats.Views)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse views: %w", err)
+				return nil, &FieldError{Field: "views", Err: err}
 			}
 			content.Views = views
 		}
 		if item.Stats.Likes != nil {
 			likes, err := parseIntString(*item.Stats.Likes)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse likes: %w", err)
+				return nil, &FieldError{Field: "likes", Err: err}
 			}
 			content.Likes = likes
 		}
 		if item.Stats.Duration != nil {
 			// Parse duration string (e.g., "25:15") to seconds
-			durationSeconds, err := parseDurationString(*item.Stats.Duration)
+			durationSeconds, err := model.ParseDurationSeconds(*item.Stats.Duration)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse duration: %w", err)
+				return nil, &FieldError{Field: "duration", Err: err}
 			}
 			content.DurationSeconds = &durationSeconds
 		}
@@ -169,21 +168,21 @@ func (p *XMLProvider) transformToContent(item XMLContentItem) (*model.Content, e
 		if item.Stats.ReadingTime != nil {
 			readingTime, err := parseIntString(*item.Stats.ReadingTime)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse reading_time: %w", err)
+				return nil, &FieldError{Field: "reading_time", Err: err}
 			}
 			content.ReadingTime = &readingTime
 		}
 		if item.Stats.Reactions != nil {
 			reactions, err := parseIntString(*item.Stats.Reactions)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse reactions: %w", err)
+				return nil, &FieldError{Field: "reactions", Err: err}
 			}
 			content.Reactions = reactions
 		}
 		if item.Stats.Comments != nil {
 			comments, err := parseIntString(*item.Stats.Comments)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse comments: %w", err)
+				return nil, &FieldError{Field: "comments", Err: err}
 			}
 			content.Comments = comments
 		}
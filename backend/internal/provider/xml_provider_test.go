@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"net/http/httptest"
+	"search-engine/backend/internal/model"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// TestXMLDateOnlyItemFiltersRegardlessOfServerTZ parses a date-only XML item and
+// binds a one-day start/end range the way the search handler does, under
+// several server timezones; the item must fall inside the range every time
+func TestXMLDateOnlyItemFiltersRegardlessOfServerTZ(t *testing.T) {
+	originalLocal := time.Local
+	t.Cleanup(func() { time.Local = originalLocal })
+
+	for _, zone := range []string{"UTC", "America/Los_Angeles", "Asia/Tokyo", "Pacific/Kiritimati"} {
+		t.Run(zone, func(t *testing.T) {
+			loc, err := time.LoadLocation(zone)
+			if err != nil {
+				t.Skipf("timezone data unavailable: %v", err)
+			}
+			time.Local = loc
+
+			p := NewXMLProvider("provider2", "", DefaultHTTPTimeouts(), nil, DefaultRetryPolicy(), DefaultPaginationPolicy())
+			content, err := p.transformToContent(XMLContentItem{
+				ID:              "v1",
+				Headline:        "Go Tutorial",
+				Type:            "article",
+				PublicationDate: "2024-03-10",
+			})
+			if err != nil {
+				t.Fatalf("transformToContent: %v", err)
+			}
+			if content.PublishedAt.Location() != time.UTC {
+				t.Errorf("published_at location = %s, want UTC", content.PublishedAt.Location())
+			}
+
+			req := httptest.NewRequest("GET", "/search?start_date=2024-03-10&end_date=2024-03-10", nil)
+			var search model.SearchRequest
+			if err := binding.Query.Bind(req, &search); err != nil {
+				t.Fatalf("bind: %v", err)
+			}
+
+			if content.PublishedAt.Before(*search.StartDate) || content.PublishedAt.After(*search.EndDate) {
+				t.Errorf("published_at %s outside [%s, %s]", content.PublishedAt, search.StartDate, search.EndDate)
+			}
+		})
+	}
+}
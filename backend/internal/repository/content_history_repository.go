@@ -0,0 +1,99 @@
+// content_history_repository.go - Database operations for content history
+// Stores metric snapshots per content item and keeps each item's history bounded
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"search-engine/backend/internal/model"
+)
+
+// ContentHistoryRepository handles all database operations for content history
+type ContentHistoryRepository struct {
+	db            *sql.DB
+	maxPerContent int
+}
+
+// NewContentHistoryRepository creates a new ContentHistoryRepository instance
+// maxPerContent caps the snapshots retained per item; older ones are pruned on insert
+func NewContentHistoryRepository(db *sql.DB, maxPerContent int) *ContentHistoryRepository {
+	return &ContentHistoryRepository{
+		db:            db,
+		maxPerContent: maxPerContent,
+	}
+}
+
+// Record inserts a snapshot and prunes the item's history down to maxPerContent
+func (r *ContentHistoryRepository) Record(s *model.ContentSnapshot) error {
+	query := `
+		INSERT INTO content_history (content_id, views, likes, reactions, comments, score)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, s.ContentID, s.Views, s.Likes, s.Reactions, s.Comments, s.Score)
+	if err != nil {
+		return fmt.Errorf("failed to record content history: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	s.ID = id
+
+	return r.prune(s.ContentID)
+}
+
+// prune deletes all but the newest maxPerContent snapshots of a content item
+// MySQL can't LIMIT a subquery in DELETE ... IN, so the cutoff id is looked up first
+func (r *ContentHistoryRepository) prune(contentID int64) error {
+	if r.maxPerContent <= 0 {
+		return nil
+	}
+
+	var cutoffID int64
+	err := r.db.QueryRow(`
+		SELECT id FROM content_history
+		WHERE content_id = ?
+		ORDER BY id DESC
+		LIMIT 1 OFFSET ?
+	`, contentID, r.maxPerContent-1).Scan(&cutoffID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find content history cutoff: %w", err)
+	}
+
+	if _, err := r.db.Exec(`DELETE FROM content_history WHERE content_id = ? AND id < ?`, contentID, cutoffID); err != nil {
+		return fmt.Errorf("failed to prune content history: %w", err)
+	}
+	return nil
+}
+
+// GetByContentID returns up to limit snapshots of a content item, newest first
+func (r *ContentHistoryRepository) GetByContentID(ctx context.Context, contentID int64, limit int) ([]*model.ContentSnapshot, error) {
+	query := `
+		SELECT id, content_id, views, likes, reactions, comments, score, recorded_at
+		FROM content_history
+		WHERE content_id = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, contentID, limit)
+	if err != nil {
+		return nil, databaseError("get content history", err)
+	}
+	defer rows.Close()
+
+	history := []*model.ContentSnapshot{}
+	for rows.Next() {
+		s := &model.ContentSnapshot{}
+		if err := rows.Scan(&s.ID, &s.ContentID, &s.Views, &s.Likes, &s.Reactions, &s.Comments, &s.Score, &s.RecordedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan content history: %w", err)
+		}
+		history = append(history, s)
+	}
+
+	return history, rows.Err()
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"search-engine/backend/internal/model"
+	"testing"
+)
+
+// trackedRow is a stored row as trackedFields reads it: id, views, likes, reactions, comments, score
+func trackedRow(id int64, views int64, score float64) []driver.Value {
+	return []driver.Value{id, views, int64(0), int64(0), int64(0), score}
+}
+
+func newHistoryRepo(rows ...[]driver.Value) (*fakeDB, *ContentRepository) {
+	db := &fakeDB{results: map[string][][]driver.Value{"SELECT id, views": rows}}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+	r.EnableHistory(10)
+	return db, r
+}
+
+// historyInserts returns the content IDs snapshotted into content_history
+func historyInserts(db *fakeDB) []driver.Value {
+	var ids []driver.Value
+	for _, s := range db.statementsLike("INSERT INTO content_history") {
+		ids = append(ids, s.args[0])
+	}
+	return ids
+}
+
+func TestPatchRecordsHistory(t *testing.T) {
+	tests := []struct {
+		name  string
+		views int
+		score float64
+		want  int
+	}{
+		{"views edited", 200, 2.0, 1},
+		{"score moved", 100, 2.5, 1},
+		{"nothing tracked changed", 100, 2.001, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, r := newHistoryRepo(trackedRow(7, 100, 2.0))
+			c := &model.Content{ID: 7, Title: "Edited", Views: tt.views, Score: tt.score}
+			title := "Edited"
+
+			if err := r.Patch(context.Background(), c, &model.ContentPatch{Title: &title}); err != nil {
+				t.Fatalf("Patch: %v", err)
+			}
+			if got := historyInserts(db); len(got) != tt.want {
+				t.Errorf("snapshots = %v, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateScoresBatchRecordsSignificantMoves(t *testing.T) {
+	db, r := newHistoryRepo(trackedRow(1, 100, 2.0), trackedRow(2, 100, 2.0))
+
+	// Freshness decay alone nudges item 2; only item 1's move is worth a snapshot
+	if err := r.UpdateScoresBatch(context.Background(), map[int64]float64{1: 3.0, 2: 2.001}); err != nil {
+		t.Fatalf("UpdateScoresBatch: %v", err)
+	}
+	got := historyInserts(db)
+	if len(got) != 1 || got[0] != int64(1) {
+		t.Fatalf("snapshots = %v, want one for content 1", got)
+	}
+	if score := db.statementsLike("INSERT INTO content_history")[0].args[5]; score != 3.0 {
+		t.Errorf("snapshot score = %v, want the new 3.0", score)
+	}
+}
+
+func TestUpdateScoreRecordsHistory(t *testing.T) {
+	db, r := newHistoryRepo(trackedRow(7, 100, 2.0))
+
+	if err := r.UpdateScore(context.Background(), 7, 4.0); err != nil {
+		t.Fatalf("UpdateScore: %v", err)
+	}
+	if got := historyInserts(db); len(got) != 1 || got[0] != int64(7) {
+		t.Errorf("snapshots = %v, want one for content 7", got)
+	}
+}
+
+func TestScoreUpdatesSkipHistoryWhenDisabled(t *testing.T) {
+	db := &fakeDB{}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+
+	if err := r.UpdateScoresBatch(context.Background(), map[int64]float64{1: 3.0}); err != nil {
+		t.Fatalf("UpdateScoresBatch: %v", err)
+	}
+	if n := len(db.statementsLike("SELECT")); n != 0 {
+		t.Errorf("ran %d reads with history disabled, want 0", n)
+	}
+}
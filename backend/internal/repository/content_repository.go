@@ -7,8 +7,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log"
+	"math"
 	apperrors "search-engine/backend/internal/errors"
 	"search-engine/backend/internal/model"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -22,6 +27,65 @@ var ErrContentNotFound = apperrors.ErrContentNotFound
 type ContentRepository struct {
 	db                *sql.DB
 	minFullTextLength int
+	history           *ContentHistoryRepository // nil disables history snapshots
+	relevance         RelevanceWeights
+	tagLimits         model.TagLimits // Applied to the tags UpsertWithTags writes
+}
+
+// execer runs statements on a *sql.DB or inside a *sql.Tx
+// Lets the write helpers serve both standalone writes and transactions
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// RelevanceWeights weigh the FULLTEXT match score against the stored content score
+// for sort_by=relevance
+type RelevanceWeights struct {
+	Text  float64
+	Score float64
+}
+
+// DefaultRelevanceWeights lets a strong title match outrank a moderately more popular item
+var DefaultRelevanceWeights = RelevanceWeights{Text: 10, Score: 1}
+
+// contentColumns lists the contents columns in the order scanContent expects
+// Keeping it in one place means new columns only need adding here and in scanContent
+const contentColumns = `id, provider_id, external_id, title, type,
+		       views, likes, duration_seconds,
+		       reading_time, reactions, comments,
+		       published_at, score, last_synced_at, created_at, updated_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanContent scans a single contents row selected with contentColumns
+func scanContent(row rowScanner) (*model.Content, error) {
+	c := &model.Content{}
+	err := row.Scan(
+		&c.ID,
+		&c.ProviderID,
+		&c.ExternalID,
+		&c.Title,
+		&c.Type,
+		&c.Views,
+		&c.Likes,
+		&c.DurationSeconds,
+		&c.ReadingTime,
+		&c.Reactions,
+		&c.Comments,
+		&c.PublishedAt,
+		&c.Score,
+		&c.LastSyncedAt,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
 }
 
 // NewContentRepository creates a new ContentRepository instance
@@ -33,12 +97,39 @@ func NewContentRepository(db *sql.DB, minFullTextLength int) *ContentRepository
 	return &ContentRepository{
 		db:                db,
 		minFullTextLength: minFullTextLength,
+		relevance:         DefaultRelevanceWeights,
 	}
 }
 
+// SetRelevanceWeights sets the weights sort_by=relevance combines
+func (r *ContentRepository) SetRelevanceWeights(w RelevanceWeights) {
+	r.relevance = w
+}
+
+// SetTagLimits sets the limits UpsertWithTags applies to tags, like the tag repository's
+func (r *ContentRepository) SetTagLimits(limits model.TagLimits) {
+	r.tagLimits = limits
+}
+
+// EnableHistory makes content writes snapshot significant changes into content_history:
+// syncs (Upsert, UpsertWithTags), edits (Update, Patch) and score updates alike
+// maxPerContent caps the snapshots kept per item; 0 or less leaves history disabled
+func (r *ContentRepository) EnableHistory(maxPerContent int) {
+	if maxPerContent <= 0 {
+		r.history = nil
+		return
+	}
+	r.history = NewContentHistoryRepository(r.db, maxPerContent)
+}
+
 // Create inserts a new content item into the database
 // Returns the created content with its generated ID
 func (r *ContentRepository) Create(c *model.Content) error {
+	return insertContent(context.Background(), r.db, c)
+}
+
+// insertContent inserts c through db and sets its generated ID
+func insertContent(ctx context.Context, db execer, c *model.Content) error {
 	// Validate content before inserting
 	if err := model.ValidateContent(c); err != nil {
 		return apperrors.NewValidationErrorWithDetails("Content validation failed", err.Error())
@@ -49,10 +140,11 @@ func (r *ContentRepository) Create(c *model.Content) error {
 			provider_id, external_id, title, type,
 			views, likes, duration_seconds,
 			reading_time, reactions, comments,
-			published_at, score
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			published_at, score, last_synced_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	result, err := r.db.Exec(
+	result, err := db.ExecContext(
+		ctx,
 		query,
 		c.ProviderID,
 		c.ExternalID,
@@ -66,6 +158,7 @@ func (r *ContentRepository) Create(c *model.Content) error {
 		c.Comments,
 		c.PublishedAt,
 		c.Score,
+		c.LastSyncedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create content: %w", err)
@@ -85,314 +178,1282 @@ func (r *ContentRepository) Create(c *model.Content) error {
 // ctx is used for timeout and cancellation support
 func (r *ContentRepository) GetByID(ctx context.Context, id int64) (*model.Content, error) {
 	query := `
-		SELECT id, provider_id, external_id, title, type,
-		       views, likes, duration_seconds,
-		       reading_time, reactions, comments,
-		       published_at, score, created_at, updated_at
+		SELECT ` + contentColumns + `
 		FROM contents
 		WHERE id = ?
 	`
-	c := &model.Content{}
+	c, err := scanContent(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrContentNotFound
+		}
+		return nil, databaseError("get content by id", err)
+	}
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&c.ID,
-		&c.ProviderID,
-		&c.ExternalID,
-		&c.Title,
-		&c.Type,
-		&c.Views,
-		&c.Likes,
-		&c.DurationSeconds,
-		&c.ReadingTime,
-		&c.Reactions,
-		&c.Comments,
-		&c.PublishedAt,
-		&c.Score,
-		&c.CreatedAt,
-		&c.UpdatedAt,
-	)
+	return c, nil
+}
+
+// GetUpdatedAt returns only the updated_at of a content item
+// A lightweight existence/freshness check that avoids fetching the full row
+func (r *ContentRepository) GetUpdatedAt(ctx context.Context, id int64) (time.Time, error) {
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, "SELECT updated_at FROM contents WHERE id = ?", id).Scan(&updatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, apperrors.ErrContentNotFound
+		}
+		return time.Time{}, databaseError("get content updated_at", err)
+	}
+	return updatedAt, nil
+}
+
+// CountByProvider counts the content items a provider has supplied
+func (r *ContentRepository) CountByProvider(ctx context.Context, providerID int) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM contents WHERE provider_id = ?", providerID).Scan(&count)
+	if err != nil {
+		return 0, databaseError("count provider content", err)
+	}
+	return count, nil
+}
+
+// CountChangedSince counts a provider's contents created and updated since a point in time
+// created counts rows inserted since then; updated counts older rows whose fields changed
+// since then (content_changed_at), not ones merely resynced or rescored
+func (r *ContentRepository) CountChangedSince(ctx context.Context, providerID int, since time.Time) (created, updated int, err error) {
+	query := `
+		SELECT
+			COALESCE(SUM(created_at >= ?), 0),
+			COALESCE(SUM(created_at < ? AND content_changed_at >= ?), 0)
+		FROM contents
+		WHERE provider_id = ? AND content_changed_at >= ?
+	`
+	err = r.db.QueryRowContext(ctx, query, since, since, since, providerID, since).Scan(&created, &updated)
+	if err != nil {
+		return 0, 0, databaseError("count changed content", err)
+	}
+	return created, updated, nil
+}
+
+// GetByProviderAndExternalID retrieves content by provider ID and external ID
+// This is used to check if content already exists before inserting
+func (r *ContentRepository) GetByProviderAndExternalID(providerID int, externalID string) (*model.Content, error) {
+	return getByProviderAndExternalID(context.Background(), r.db, providerID, externalID)
+}
+
+// getByProviderAndExternalID looks up content through db
+func getByProviderAndExternalID(ctx context.Context, db execer, providerID int, externalID string) (*model.Content, error) {
+	query := `
+		SELECT ` + contentColumns + `
+		FROM contents
+		WHERE provider_id = ? AND external_id = ?
+	`
+	c, err := scanContent(db.QueryRowContext(ctx, query, providerID, externalID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, apperrors.ErrContentNotFound
 		}
-		return nil, apperrors.NewDatabaseError("get content by id", err)
+		return nil, databaseError("get content by provider and external id", err)
+	}
+
+	return c, nil
+}
+
+// Update updates an existing content item
+// Updates all fields except ID and timestamps, and counts as a change of the item
+// last_synced_at is only overwritten when c.LastSyncedAt is set (sync path)
+func (r *ContentRepository) Update(c *model.Content) error {
+	ctx := context.Background()
+	stored := r.trackedFields(ctx, []int64{c.ID})
+	if err := updateContent(ctx, r.db, c, true); err != nil {
+		return err
+	}
+	r.recordHistoryIfChanged(stored[c.ID], c)
+	return nil
+}
+
+// updateContent updates the row of c.ID through db
+// changed moves content_changed_at; resyncing an unchanged item leaves it
+func updateContent(ctx context.Context, db execer, c *model.Content, changed bool) error {
+	// Validate content before updating
+	if err := model.ValidateContent(c); err != nil {
+		return apperrors.NewValidationErrorWithDetails("Content validation failed", err.Error())
+	}
+
+	query := `
+		UPDATE contents
+		SET title = ?, type = ?,
+		    views = ?, likes = ?, duration_seconds = ?,
+		    reading_time = ?, reactions = ?, comments = ?,
+		    published_at = ?, score = ?,
+		    last_synced_at = COALESCE(?, last_synced_at),
+		    content_changed_at = IF(?, CURRENT_TIMESTAMP, content_changed_at),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+	_, err := db.ExecContext(
+		ctx,
+		query,
+		c.Title,
+		c.Type,
+		c.Views,
+		c.Likes,
+		c.DurationSeconds,
+		c.ReadingTime,
+		c.Reactions,
+		c.Comments,
+		c.PublishedAt,
+		c.Score,
+		c.LastSyncedAt,
+		changed,
+		c.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update content: %w", err)
+	}
+	return nil
+}
+
+// Patch writes the columns set in patch, plus the recomputed score, for an existing item
+// Values are taken from c, the stored item with the patch already applied,
+// so the SET clause only touches the fields the caller sent
+func (r *ContentRepository) Patch(ctx context.Context, c *model.Content, patch *model.ContentPatch) error {
+	var sets []string
+	var args []interface{}
+	set := func(column string, value interface{}) {
+		sets = append(sets, column+" = ?")
+		args = append(args, value)
+	}
+
+	if patch.Title != nil {
+		set("title", c.Title)
+	}
+	if patch.PublishedAt != nil {
+		set("published_at", c.PublishedAt)
+	}
+	if patch.Views != nil {
+		set("views", c.Views)
+	}
+	if patch.Likes != nil {
+		set("likes", c.Likes)
+	}
+	if patch.DurationSeconds != nil {
+		set("duration_seconds", c.DurationSeconds)
+	}
+	if patch.ReadingTime != nil {
+		set("reading_time", c.ReadingTime)
+	}
+	if patch.Reactions != nil {
+		set("reactions", c.Reactions)
+	}
+	if patch.Comments != nil {
+		set("comments", c.Comments)
+	}
+	set("score", c.Score)
+
+	// An edit is a change of the item, even one that only touches the score
+	query := "UPDATE contents SET " + strings.Join(sets, ", ") +
+		", content_changed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ?"
+	args = append(args, c.ID)
+
+	stored := r.trackedFields(ctx, []int64{c.ID})
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return databaseError("patch content", err)
+	}
+	r.recordHistoryIfChanged(stored[c.ID], c)
+	return nil
+}
+
+// UpdateScore updates only the score field for a content item
+// This is used by the scoring service to update scores efficiently
+func (r *ContentRepository) UpdateScore(ctx context.Context, id int64, score float64) error {
+	query := `
+		UPDATE contents
+		SET score = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+	stored := r.trackedFields(ctx, []int64{id})
+	_, err := r.db.ExecContext(ctx, query, score, id)
+	if err != nil {
+		return fmt.Errorf("failed to update score: %w", err)
+	}
+	r.recordScoreHistory(stored, map[int64]float64{id: score})
+	return nil
+}
+
+// UpdateScoresBatch writes many scores in one statement, keyed by content ID
+// The update runs in a transaction, so either every score is written or none is
+func (r *ContentRepository) UpdateScoresBatch(ctx context.Context, updates map[int64]float64) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(updates))
+	for id := range updates {
+		ids = append(ids, id)
+	}
+	stored := r.trackedFields(ctx, ids)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query, args := scoreBatchQuery(updates)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to update scores: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	r.recordScoreHistory(stored, updates)
+	return nil
+}
+
+// scoreBatchQuery builds the UpdateScoresBatch statement and its args
+// IDs are sorted so the statement, and the order rows are locked in, is deterministic
+func scoreBatchQuery(updates map[int64]float64) (string, []interface{}) {
+	ids := make([]int64, 0, len(updates))
+	for id := range updates {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	var cases strings.Builder
+	args := make([]interface{}, 0, len(ids)*3)
+	for _, id := range ids {
+		cases.WriteString(" WHEN ? THEN ?")
+		args = append(args, id, updates[id])
+	}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	query := "UPDATE contents SET score = CASE id" + cases.String() + " END, updated_at = CURRENT_TIMESTAMP" +
+		" WHERE id IN (" + placeholders + ")"
+	return query, args
+}
+
+// Delete removes a content item from the database
+// This will cascade delete associated tags due to foreign key constraint
+func (r *ContentRepository) Delete(id int64) error {
+	query := `DELETE FROM contents WHERE id = ?`
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete content: %w", err)
+	}
+	return nil
+}
+
+// Upsert creates or updates a content item
+// If content exists (by provider_id + external_id), it updates; otherwise creates new
+// This is useful when syncing data from providers
+// Upsert is the sync write path, so it also stamps last_synced_at
+// and, with history enabled, snapshots new items and significant changes
+func (r *ContentRepository) Upsert(c *model.Content) error {
+	syncedAt := time.Now()
+	c.LastSyncedAt = &syncedAt
+
+	existing, err := r.GetByProviderAndExternalID(c.ProviderID, c.ExternalID)
+	if err != nil {
+		if errors.Is(err, ErrContentNotFound) || errors.Is(err, apperrors.ErrContentNotFound) {
+			if err := r.Create(c); err != nil {
+				return err
+			}
+			r.recordHistory(c)
+			return nil
+		}
+		return databaseError("check existing content", err)
+	}
+
+	c.ID = existing.ID
+	keepStoredFields(existing, c)
+	if err := updateContent(context.Background(), r.db, c, model.HasContentChange(existing, c)); err != nil {
+		return err
+	}
+	if model.HasSignificantChange(existing, c) {
+		r.recordHistory(c)
+	}
+	return nil
+}
+
+// UpsertWithTags upserts c like Upsert and replaces its tags with tags, in one transaction
+// Either the item and all of its tags are written or nothing is. The row is written with
+// INSERT ... ON DUPLICATE KEY UPDATE rather than a locking lookup: a SELECT ... FOR UPDATE
+// of a missing row takes a gap lock, and parallel provider syncs inserting into the same
+// gap would deadlock. Empty tags leave the stored tags as they are, since providers
+// omit tags rather than clear them. last_synced_at is only overwritten when
+// c.LastSyncedAt is set, which provider syncs do and manual ingests don't. The tag
+// limits set by SetTagLimits apply; returns the content ID (also set on c) and how
+// many tags the limits dropped
+func (r *ContentRepository) UpsertWithTags(ctx context.Context, c *model.Content, tags []string) (int64, int, error) {
+	tags, dropped := r.tagLimits.Apply(tags)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, dropped, databaseError("begin content upsert", err)
+	}
+	defer tx.Rollback()
+
+	// A plain read takes no locks; it only feeds the kept score and the history check
+	existing, err := getByProviderAndExternalID(ctx, tx, c.ProviderID, c.ExternalID)
+	if err != nil && !errors.Is(err, apperrors.ErrContentNotFound) {
+		return 0, dropped, databaseError("check existing content", err)
+	}
+	if existing != nil {
+		keepStoredFields(existing, c)
+	}
+
+	changed := existing == nil || model.HasContentChange(existing, c)
+	if err := upsertContent(ctx, tx, c, changed); err != nil {
+		return 0, dropped, err
+	}
+	if len(tags) > 0 {
+		if err := writeTags(ctx, tx, c.ID, tags); err != nil {
+			return 0, dropped, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, dropped, fmt.Errorf("failed to commit content upsert: %w", err)
+	}
+
+	// History is best-effort and outside the transaction, as in Upsert
+	if existing == nil || model.HasSignificantChange(existing, c) {
+		r.recordHistory(c)
+	}
+	return c.ID, dropped, nil
+}
+
+// upsertContent inserts c, or updates the row with its provider_id and external_id, through db
+// id = LAST_INSERT_ID(id) makes LastInsertId report the existing row's ID on an update;
+// changed moves content_changed_at of an existing row (a new row gets the column default)
+func upsertContent(ctx context.Context, db execer, c *model.Content, changed bool) error {
+	if err := model.ValidateContent(c); err != nil {
+		return apperrors.NewValidationErrorWithDetails("Content validation failed", err.Error())
+	}
+
+	query := `
+		INSERT INTO contents (
+			provider_id, external_id, title, type,
+			views, likes, duration_seconds,
+			reading_time, reactions, comments,
+			published_at, score, last_synced_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			id = LAST_INSERT_ID(id),
+			title = VALUES(title), type = VALUES(type),
+			views = VALUES(views), likes = VALUES(likes), duration_seconds = VALUES(duration_seconds),
+			reading_time = VALUES(reading_time), reactions = VALUES(reactions), comments = VALUES(comments),
+			published_at = VALUES(published_at), score = VALUES(score),
+			last_synced_at = COALESCE(VALUES(last_synced_at), last_synced_at),
+			content_changed_at = IF(?, CURRENT_TIMESTAMP, content_changed_at),
+			updated_at = CURRENT_TIMESTAMP
+	`
+	result, err := db.ExecContext(
+		ctx,
+		query,
+		c.ProviderID,
+		c.ExternalID,
+		c.Title,
+		c.Type,
+		c.Views,
+		c.Likes,
+		c.DurationSeconds,
+		c.ReadingTime,
+		c.Reactions,
+		c.Comments,
+		c.PublishedAt,
+		c.Score,
+		c.LastSyncedAt,
+		changed,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert content: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	c.ID = id
+	return nil
+}
+
+// keepStoredFields carries over the stored values a sync must not overwrite
+// Providers don't send scores, so the stored one is kept instead of zeroing it until the
+// recalculation that follows the sync. A clamped future date is only a stand-in: keeping
+// the stored one stops the item being re-stamped "published now" on every sync
+func keepStoredFields(existing, c *model.Content) {
+	if c.Score == 0 {
+		c.Score = existing.Score
+	}
+	if c.PublishedAtClamped {
+		c.PublishedAt = existing.PublishedAt
+	}
+}
+
+// recordHistory snapshots a content item when history is enabled
+// A failed snapshot is logged rather than returned: the content write already succeeded
+func (r *ContentRepository) recordHistory(c *model.Content) {
+	if r.history == nil {
+		return
+	}
+	if err := r.history.Record(model.SnapshotOf(c)); err != nil {
+		log.Printf("Warning: content %d history not recorded: %v", c.ID, err)
+	}
+}
+
+// trackedFields reads the history-tracked fields of the given items as stored, keyed by ID
+// It only reads with history enabled; a failed read is logged and yields no items,
+// so the write it precedes goes ahead without a snapshot
+func (r *ContentRepository) trackedFields(ctx context.Context, ids []int64) map[int64]*model.Content {
+	if r.history == nil || len(ids) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id, views, likes, reactions, comments, score FROM contents WHERE id IN ("+placeholders+")", args...)
+	if err != nil {
+		log.Printf("Warning: history not recorded for %d content items: %v", len(ids), err)
+		return nil
+	}
+	defer rows.Close()
+
+	stored := make(map[int64]*model.Content, len(ids))
+	for rows.Next() {
+		c := &model.Content{}
+		if err := rows.Scan(&c.ID, &c.Views, &c.Likes, &c.Reactions, &c.Comments, &c.Score); err != nil {
+			log.Printf("Warning: history not recorded for %d content items: %v", len(ids), err)
+			return nil
+		}
+		stored[c.ID] = c
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Warning: history not recorded for %d content items: %v", len(ids), err)
+		return nil
+	}
+	return stored
+}
+
+// recordHistoryIfChanged snapshots c when it differs significantly from stored,
+// its fields before the write; without stored fields nothing is recorded
+func (r *ContentRepository) recordHistoryIfChanged(stored, c *model.Content) {
+	if stored != nil && model.HasSignificantChange(stored, c) {
+		r.recordHistory(c)
+	}
+}
+
+// recordScoreHistory snapshots the items whose written score moved significantly
+// from the stored one; their metrics are unchanged by a score update
+func (r *ContentRepository) recordScoreHistory(stored map[int64]*model.Content, scores map[int64]float64) {
+	for id, score := range scores {
+		prev, ok := stored[id]
+		if !ok {
+			continue
+		}
+		next := *prev
+		next.Score = score
+		r.recordHistoryIfChanged(prev, &next)
+	}
+}
+
+// Search searches for content based on the search request
+// Supports keyword search, type filtering, sorting, and pagination
+// ctx is used for timeout and cancellation support
+func (r *ContentRepository) Search(ctx context.Context, req *model.SearchRequest) ([]*model.Content, int, error) {
+	whereClause, args := r.buildSearchFilters(req)
+	return r.searchFiltered(ctx, req, whereClause, args)
+}
+
+// SearchWithFacets runs Search and counts its matches per type and per provider
+// Both reuse one built WHERE clause and its args; facets is nil when the facet query timed out
+func (r *ContentRepository) SearchWithFacets(ctx context.Context, req *model.SearchRequest) ([]*model.Content, int, *model.SearchFacets, error) {
+	whereClause, args := r.buildSearchFilters(req)
+	contents, total, err := r.searchFiltered(ctx, req, whereClause, args)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	facets, err := r.searchFacets(ctx, whereClause, args)
+	return contents, total, facets, err
+}
+
+// Facets counts the matches of a search per type and per provider, without fetching rows
+// Used by search modes that don't go through SearchWithFacets; nil means the query timed out
+func (r *ContentRepository) Facets(ctx context.Context, req *model.SearchRequest) (*model.SearchFacets, error) {
+	whereClause, args := r.buildSearchFilters(req)
+	return r.searchFacets(ctx, whereClause, args)
+}
+
+// searchFiltered is Search over an already built WHERE clause and its args
+func (r *ContentRepository) searchFiltered(ctx context.Context, req *model.SearchRequest, whereClause string, args []interface{}) ([]*model.Content, int, error) {
+	cursor, err := req.Cursor()
+	if err != nil {
+		return nil, 0, apperrors.NewFieldValidationError("Invalid cursor", map[string]string{"after": err.Error()})
+	}
+
+	orderBy, orderArgs := r.searchOrder(req)
+
+	// Count total results (for pagination)
+	// The cursor only positions the page, so it's left out of the count
+	total, err := r.countSearchResults(ctx, whereClause, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// A page past the last can't hold rows, so skip its OFFSET scan
+	if cursor == nil && req.PastLastPage(total) {
+		return nil, total, nil
+	}
+
+	pageWhere, pageArgs, pagination := searchPage(whereClause, args, cursor)
+
+	// Build SELECT query with pagination
+	query := fmt.Sprintf(`
+		SELECT `+contentColumns+`
+		FROM contents
+		%s
+		%s
+		%s
+	`, pageWhere, orderBy, pagination)
+
+	pageArgs = append(pageArgs, orderArgs...)
+	pageArgs = append(pageArgs, req.PerPage)
+	if cursor == nil {
+		pageArgs = append(pageArgs, req.GetOffset())
+	}
+
+	contents, err := r.querySearchPage(ctx, query, pageArgs)
+	return contents, total, err
+}
+
+// searchPage positions a search page after cursor, or by OFFSET without one
+// Rows come in score DESC, id DESC order, so the rows after the cursor are exactly
+// those whose (score, id) is below it. Returns the page's WHERE clause and args and
+// its LIMIT clause, whose placeholders the caller fills
+func searchPage(whereClause string, args []interface{}, cursor *model.SearchCursor) (string, []interface{}, string) {
+	pageArgs := append([]interface{}{}, args...)
+	if cursor == nil {
+		return whereClause, pageArgs, "LIMIT ? OFFSET ?"
+	}
+
+	const cursorClause = "(score, id) < (?, ?)"
+	if whereClause == "" {
+		whereClause = "WHERE " + cursorClause
+	} else {
+		whereClause += " AND " + cursorClause
+	}
+	return whereClause, append(pageArgs, cursor.Score, cursor.ID), "LIMIT ?"
+}
+
+// normalizedTitleExpr is the SQL expression distinct_titles groups titles by
+// The title collation is already case-insensitive; LOWER keeps the intent explicit
+const normalizedTitleExpr = "LOWER(TRIM(title))"
+
+// SearchDistinctTitles searches like Search but keeps only the highest-scoring row
+// per normalized title, so the same content listed under several external IDs shows once
+// Returns the page, the number of distinct titles, and how many rows were collapsed
+func (r *ContentRepository) SearchDistinctTitles(ctx context.Context, req *model.SearchRequest) ([]*model.Content, int, int, error) {
+	whereClause, args := r.buildSearchFilters(req)
+
+	rowCount, total, err := r.countDistinctTitles(ctx, whereClause, args)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	collapsed := 0
+	if total >= 0 {
+		collapsed = rowCount - total
+	}
+
+	// MATCH can't run on the materialized derived table, so for relevance the
+	// inner query selects the match score and the outer one orders by it
+	relevanceColumn, orderBy, orderArgs := "", searchOrderBy(req), []interface{}(nil)
+	if matchExpr, matchArgs, ok := r.relevanceMatch(req); ok {
+		relevanceColumn = ", " + matchExpr + " AS text_relevance"
+		args = append(matchArgs, args...)
+		orderBy, orderArgs = r.relevanceOrderBy("text_relevance", req.SortOrder)
+	}
+
+	// Rank rows within each normalized title and keep the top one
+	query := fmt.Sprintf(`
+		SELECT `+contentColumns+`
+		FROM (
+			SELECT contents.*%s,
+			       ROW_NUMBER() OVER (PARTITION BY %s ORDER BY score DESC, id DESC) AS title_rank
+			FROM contents
+			%s
+		) ranked
+		WHERE title_rank = 1
+		%s
+		LIMIT ? OFFSET ?
+	`, relevanceColumn, normalizedTitleExpr, whereClause, orderBy)
+
+	args = append(args, orderArgs...)
+	args = append(args, req.PerPage, req.GetOffset())
+
+	contents, err := r.querySearchPage(ctx, query, args)
+	return contents, total, collapsed, err
+}
+
+// SearchByProvider searches like Search but ranks rows within each provider, returning
+// the rows ranked on the request's page of every provider, ordered by provider then rank
+// counts maps each returned provider to its number of matching rows across all pages,
+// and total counts the matching rows of every provider
+func (r *ContentRepository) SearchByProvider(ctx context.Context, req *model.SearchRequest) (contents []*model.Content, counts map[int]int, total int, err error) {
+	whereClause, args := r.buildSearchFilters(req)
+
+	total, err = r.countSearchResults(ctx, whereClause, args)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	// No provider has more rows than all of them together, so every group is past its end too
+	counts = make(map[int]int)
+	if req.PastLastPage(total) {
+		return nil, counts, total, nil
+	}
+
+	query, queryArgs := r.providerGroupsQuery(req, whereClause, args)
+	rows, err := r.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, nil, 0, databaseError("search content by provider", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var providerTotal int
+		c, err := scanContent(withExtraColumns{row: rows, extra: []interface{}{&providerTotal}})
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to scan content: %w", err)
+		}
+		contents = append(contents, c)
+		counts[c.ProviderID] = providerTotal
+	}
+	return contents, counts, total, rows.Err()
+}
+
+// providerGroupsQuery builds the windowed query behind SearchByProvider from the search filters
+// The innermost query applies the filters; for relevance it also selects the match
+// score, since MATCH can't run on the derived table the window functions rank
+func (r *ContentRepository) providerGroupsQuery(req *model.SearchRequest, whereClause string, args []interface{}) (string, []interface{}) {
+	args = append([]interface{}{}, args...)
+	relevanceColumn, orderBy, orderArgs := "", searchOrderBy(req), []interface{}(nil)
+	if matchExpr, matchArgs, ok := r.relevanceMatch(req); ok {
+		relevanceColumn = ", " + matchExpr + " AS text_relevance"
+		args = append(matchArgs, args...)
+		orderBy, orderArgs = r.relevanceOrderBy("text_relevance", req.SortOrder)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT `+contentColumns+`, provider_total
+		FROM (
+			SELECT matched.*,
+			       ROW_NUMBER() OVER (PARTITION BY provider_id %s) AS provider_rank,
+			       COUNT(*) OVER (PARTITION BY provider_id) AS provider_total
+			FROM (
+				SELECT contents.*%s
+				FROM contents
+				%s
+			) matched
+		) ranked
+		WHERE provider_rank > ? AND provider_rank <= ?
+		ORDER BY provider_id, provider_rank
+	`, orderBy, relevanceColumn, whereClause)
+
+	// The window's ORDER BY comes first in the query text, so its args lead
+	args = append(orderArgs, args...)
+	args = append(args, req.GetOffset(), req.GetOffset()+req.PerPage)
+	return query, args
+}
+
+// searchOrderBy builds the ORDER BY clause with whitelist validation to prevent SQL injection
+// id is accepted for internal callers on top of the public sort fields
+// Engagement fields only apply to one content type, so rows of the other type
+// (and NULL metrics) sort after every row with a value, in either direction
+// relevance orders by score here; see relevanceMatch for when it ranks by text match
+func searchOrderBy(req *model.SearchRequest) string {
+	sortBy := req.SortBy
+	if sortBy == model.SortByRelevance || (!slices.Contains(model.SearchSortFields, sortBy) && sortBy != "id") {
+		sortBy = "score" // Default to score if invalid
+	}
+
+	sortOrder := searchSortDirection(req.SortOrder)
+
+	if contentType, ok := model.EngagementSortType(sortBy); ok {
+		return fmt.Sprintf("ORDER BY (type <> '%s' OR %s IS NULL), %s %s, id DESC", contentType, sortBy, sortBy, sortOrder)
+	}
+	return fmt.Sprintf("ORDER BY %s %s, id DESC", sortBy, sortOrder)
+}
+
+// searchSortDirection validates a sort order against the whitelist, defaulting to DESC
+func searchSortDirection(sortOrder string) string {
+	sortOrder = strings.ToLower(sortOrder)
+	if !slices.Contains(model.SearchSortOrders, sortOrder) {
+		sortOrder = "desc" // Default to DESC if invalid
+	}
+	return strings.ToUpper(sortOrder)
+}
+
+// fullTextMatchExpr is the FULLTEXT match score relevance sorting blends in
+const fullTextMatchExpr = "MATCH(title) AGAINST(? IN BOOLEAN MODE)"
+
+// relevanceMatch returns the text match expression to rank by when req sorts by relevance,
+// with the args of its placeholders: the FULLTEXT match score for a FULLTEXT keyword, and
+// for a short keyword on the LIKE path the number of its terms the title contains.
+// Rows matched through their tags can contain fewer of them, so those rank lower.
+// Without a keyword ok is false and relevance falls back to ordering by score
+func (r *ContentRepository) relevanceMatch(req *model.SearchRequest) (string, []interface{}, bool) {
+	if req.SortBy != model.SortByRelevance {
+		return "", nil, false
+	}
+	switch mode, effectiveQuery := r.DescribeQuery(req); mode {
+	case model.SearchModeFullText:
+		return fullTextMatchExpr, []interface{}{effectiveQuery}, true
+	case model.SearchModeLike:
+		expr, args := likeTermCountExpr(effectiveQuery)
+		return expr, args, true
+	}
+	return "", nil, false
+}
+
+// searchOrder builds the ORDER BY clause of Search and the args of its placeholders
+func (r *ContentRepository) searchOrder(req *model.SearchRequest) (string, []interface{}) {
+	matchExpr, matchArgs, ok := r.relevanceMatch(req)
+	if !ok {
+		return searchOrderBy(req), nil
+	}
+	orderBy, weights := r.relevanceOrderBy(matchExpr, req.SortOrder)
+	return orderBy, append(matchArgs, weights...)
+}
+
+// relevanceOrderBy orders by matchExpr and score combined with the relevance weights
+// The returned args are the two weights, in that order after any args of matchExpr
+func (r *ContentRepository) relevanceOrderBy(matchExpr, sortOrder string) (string, []interface{}) {
+	orderBy := fmt.Sprintf("ORDER BY (%s * ? + score * ?) %s, id DESC", matchExpr, searchSortDirection(sortOrder))
+	return orderBy, []interface{}{r.relevance.Text, r.relevance.Score}
+}
+
+// querySearchPage runs a search page query selecting contentColumns and scans the rows
+func (r *ContentRepository) querySearchPage(ctx context.Context, query string, args []interface{}) ([]*model.Content, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, databaseError("search content", err)
+	}
+	defer rows.Close()
+
+	var contents []*model.Content
+	for rows.Next() {
+		c, err := scanContent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan content: %w", err)
+		}
+		contents = append(contents, c)
+	}
+
+	return contents, rows.Err()
+}
+
+// searchCountTimeout bounds the COUNT query behind search pagination
+// COUNT can be slow on large tables, so it gets its own reasonable timeout
+const searchCountTimeout = 10 * time.Second
+
+// Count returns the number of contents matching the request's filters
+// It runs only the COUNT portion of Search, so no rows are fetched or scanned
+// A total of -1 means the count timed out
+// With distinct_titles it counts distinct normalized titles instead of rows
+func (r *ContentRepository) Count(ctx context.Context, req *model.SearchRequest) (int, error) {
+	whereClause, args := r.buildSearchFilters(req)
+	if req.DistinctTitles {
+		_, distinct, err := r.countDistinctTitles(ctx, whereClause, args)
+		return distinct, err
+	}
+	return r.countSearchResults(ctx, whereClause, args)
+}
+
+// DescribeQuery reports which matching strategy a request's keyword uses
+// and the effective query string handed to the database for it
+func (r *ContentRepository) DescribeQuery(req *model.SearchRequest) (model.SearchMode, string) {
+	trimmedQuery := strings.TrimSpace(req.Query)
+	switch {
+	case trimmedQuery == "":
+		return model.SearchModeNone, ""
+	case len(trimmedQuery) >= r.minFullTextLength:
+		booleanQuery := fullTextQuery(trimmedQuery, req.Prefix, req.MatchMode)
+		if booleanQuery == "" {
+			// Nothing but boolean operators: there is no keyword left to match
+			return model.SearchModeNone, ""
+		}
+		return model.SearchModeFullText, booleanQuery
+	default:
+		return model.SearchModeLike, strings.Join(splitSearchTerms(trimmedQuery), " ")
 	}
+}
 
-	return c, nil
+// booleanOperatorStripper replaces the FULLTEXT boolean-mode operators with spaces
+// Users type plain keywords; an injected operator would change the query's meaning
+// or make it invalid. MySQL splits words on these characters anyway, so "real-time"
+// still becomes the terms "real" and "time"
+var booleanOperatorStripper = strings.NewReplacer(
+	"+", " ", "-", " ", "*", " ", `"`, " ",
+	"(", " ", ")", " ", "<", " ", ">", " ", "~", " ", "@", " ",
+)
+
+// fullTextQuery builds the boolean-mode query for a trimmed keyword
+// Each term gets a * so it prefix-matches ("go" also finds "golang") unless the
+// request asked for exact-word matching; in "all" mode each term also gets a +
+// so every term is required. Returns "" when no term is left after stripping operators
+func fullTextQuery(trimmedQuery string, prefix *bool, matchMode model.MatchMode) string {
+	terms := splitSearchTerms(booleanOperatorStripper.Replace(trimmedQuery))
+	for i, term := range terms {
+		if matchMode == model.MatchModeAll {
+			term = "+" + term
+		}
+		if prefix == nil || *prefix {
+			term += "*"
+		}
+		terms[i] = term
+	}
+	return strings.Join(terms, " ")
 }
 
-// GetByProviderAndExternalID retrieves content by provider ID and external ID
-// This is used to check if content already exists before inserting
-func (r *ContentRepository) GetByProviderAndExternalID(providerID int, externalID string) (*model.Content, error) {
-	query := `
-		SELECT id, provider_id, external_id, title, type,
-		       views, likes, duration_seconds,
-		       reading_time, reactions, comments,
-		       published_at, score, created_at, updated_at
-		FROM contents
-		WHERE provider_id = ? AND external_id = ?
-	`
-	c := &model.Content{}
+// buildSearchFilters builds the WHERE clause and args for a search request
+// Search and Count share it so both always apply exactly the same filters
+func (r *ContentRepository) buildSearchFilters(req *model.SearchRequest) (string, []interface{}) {
+	b := newSearchFilterBuilder()
 
-	err := r.db.QueryRow(query, providerID, externalID).Scan(
-		&c.ID,
-		&c.ProviderID,
-		&c.ExternalID,
-		&c.Title,
-		&c.Type,
-		&c.Views,
-		&c.Likes,
-		&c.DurationSeconds,
-		&c.ReadingTime,
-		&c.Reactions,
-		&c.Comments,
-		&c.PublishedAt,
-		&c.Score,
-		&c.CreatedAt,
-		&c.UpdatedAt,
-	)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, apperrors.ErrContentNotFound
+	// Keyword search: FULLTEXT index, or LIKE for queries too short for it
+	// LIKE matches each term independently so word order doesn't matter
+	// Tags are matched with the same terms and combine with the title match via OR
+	trimmedQuery := strings.TrimSpace(req.Query)
+	switch mode, effectiveQuery := r.DescribeQuery(req); mode {
+	case model.SearchModeFullText:
+		b.AddFullText(effectiveQuery)
+		if req.IncludesTags() {
+			match := tagMatchPrefix
+			if req.Prefix != nil && !*req.Prefix {
+				match = tagMatchExact
+			}
+			terms := splitSearchTerms(booleanOperatorStripper.Replace(trimmedQuery))
+			b.OrTags(terms, match, req.MatchMode == model.MatchModeAll)
+		}
+	case model.SearchModeLike:
+		b.AddLike(trimmedQuery)
+		if req.IncludesTags() {
+			b.OrTags(splitSearchTerms(trimmedQuery), tagMatchContains, true)
 		}
-		return nil, apperrors.NewDatabaseError("get content by provider and external id", err)
 	}
 
-	return c, nil
+	return addRequestFilters(b, req).Build()
 }
 
-// Update updates an existing content item
-// Updates all fields except ID and timestamps
-func (r *ContentRepository) Update(c *model.Content) error {
-	// Validate content before updating
-	if err := model.ValidateContent(c); err != nil {
-		return apperrors.NewValidationErrorWithDetails("Content validation failed", err.Error())
+// addRequestFilters adds the request's non-keyword filters to b
+func addRequestFilters(b *searchFilterBuilder, req *model.SearchRequest) *searchFilterBuilder {
+	return b.AddType(req.Type).
+		AddProvider(req.ProviderID).
+		AddDateRange(req.StartDate, req.EndDate).
+		AddUpdatedSince(req.UpdatedSince).
+		AddMinEngagement(req.MinViews, req.MinLikes, req.MinReactions, req.MinComments)
+}
+
+// FuzzyCandidates returns up to limit of the highest-scoring contents that pass the
+// request's filters and whose title contains one of fragments
+// The caller compares the candidates' titles with the query; see SearchService's fuzzy fallback
+func (r *ContentRepository) FuzzyCandidates(ctx context.Context, req *model.SearchRequest, fragments []string, limit int) ([]*model.Content, error) {
+	if len(fragments) == 0 {
+		return nil, nil
 	}
+	whereClause, args := addRequestFilters(newSearchFilterBuilder().AddTitleFragments(fragments), req).Build()
 
-	query := `
-		UPDATE contents
-		SET title = ?, type = ?,
-		    views = ?, likes = ?, duration_seconds = ?,
-		    reading_time = ?, reactions = ?, comments = ?,
-		    published_at = ?, score = ?,
-		    updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
-	`
-	_, err := r.db.Exec(
-		query,
-		c.Title,
-		c.Type,
-		c.Views,
-		c.Likes,
-		c.DurationSeconds,
-		c.ReadingTime,
-		c.Reactions,
-		c.Comments,
-		c.PublishedAt,
-		c.Score,
-		c.ID,
-	)
+	query := fmt.Sprintf(`
+		SELECT `+contentColumns+`
+		FROM contents
+		%s
+		ORDER BY score DESC, id DESC
+		LIMIT ?
+	`, whereClause)
+	return r.querySearchPage(ctx, query, append(args, limit))
+}
+
+// Autocomplete returns up to limit distinct titles matching a partially typed query
+// Titles starting with it come first; once it is long enough for FULLTEXT, titles with
+// words starting with each of its terms follow. Ties go to the higher-scoring title
+func (r *ContentRepository) Autocomplete(ctx context.Context, prefix string, limit int) ([]string, error) {
+	query, args := r.autocompleteQuery(strings.TrimSpace(prefix), limit)
+	if query == "" {
+		return []string{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to update content: %w", err)
+		return nil, databaseError("autocomplete", err)
 	}
-	return nil
+	defer rows.Close()
+
+	titles := make([]string, 0, limit)
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			return nil, fmt.Errorf("failed to scan autocomplete title: %w", err)
+		}
+		titles = append(titles, title)
+	}
+
+	return titles, rows.Err()
 }
 
-// UpdateScore updates only the score field for a content item
-// This is used by the scoring service to update scores efficiently
-func (r *ContentRepository) UpdateScore(id int64, score float64) error {
-	query := `
-		UPDATE contents
-		SET score = ?, updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
-	`
-	_, err := r.db.Exec(query, score, id)
-	if err != nil {
-		return fmt.Errorf("failed to update score: %w", err)
+// autocompleteQuery builds the Autocomplete query and its args for a trimmed prefix
+// Returns "" when the prefix is empty
+func (r *ContentRepository) autocompleteQuery(prefix string, limit int) (string, []interface{}) {
+	if prefix == "" {
+		return "", nil
 	}
-	return nil
+	startsWith := likeEscaper.Replace(prefix) + "%"
+
+	where := "title LIKE ?"
+	args := []interface{}{startsWith}
+	if len(prefix) >= r.minFullTextLength {
+		// Every term is required and prefix-matched, as the last one is usually half typed
+		if booleanQuery := fullTextQuery(prefix, nil, model.MatchModeAll); booleanQuery != "" {
+			where += " OR " + fullTextMatchExpr
+			args = append(args, booleanQuery)
+		}
+	}
+	args = append(args, startsWith, limit)
+
+	query := "SELECT title FROM contents WHERE " + where +
+		" GROUP BY title ORDER BY title LIKE ? DESC, MAX(score) DESC, title LIMIT ?"
+	return query, args
 }
 
-// Delete removes a content item from the database
-// This will cascade delete associated tags due to foreign key constraint
-func (r *ContentRepository) Delete(id int64) error {
-	query := `DELETE FROM contents WHERE id = ?`
-	_, err := r.db.Exec(query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete content: %w", err)
+// countSearchResults counts the rows matching a WHERE clause built by buildSearchFilters
+// Use a separate context with timeout so COUNT can't block too long; if it
+// times out the total is reported as -1 (unknown) instead of failing the request
+func (r *ContentRepository) countSearchResults(ctx context.Context, whereClause string, args []interface{}) (int, error) {
+	countCtx, countCancel := context.WithTimeout(ctx, searchCountTimeout)
+	defer countCancel()
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM contents %s", whereClause)
+	var total int
+	if err := r.db.QueryRowContext(countCtx, countQuery, args...).Scan(&total); err != nil {
+		if countCtx.Err() == context.DeadlineExceeded {
+			return -1, nil // Use -1 to indicate estimated/unknown total
+		}
+		return 0, databaseError("count results", err)
 	}
-	return nil
+	return total, nil
 }
 
-// Upsert creates or updates a content item
-// If content exists (by provider_id + external_id), it updates; otherwise creates new
-// This is useful when syncing data from providers
-func (r *ContentRepository) Upsert(c *model.Content) error {
-	existing, err := r.GetByProviderAndExternalID(c.ProviderID, c.ExternalID)
+// facetCount is the number of matching rows of one type and provider
+type facetCount struct {
+	contentType model.ContentType
+	providerID  int
+	count       int
+}
+
+// searchFacets counts the rows matching whereClause per type and per provider
+// One GROUP BY over both columns yields both breakdowns; it shares countSearchResults'
+// timeout, and on timeout facets is nil rather than an error
+func (r *ContentRepository) searchFacets(ctx context.Context, whereClause string, args []interface{}) (*model.SearchFacets, error) {
+	facetCtx, facetCancel := context.WithTimeout(ctx, searchCountTimeout)
+	defer facetCancel()
+
+	rows, err := r.db.QueryContext(facetCtx, facetsQuery(whereClause), args...)
 	if err != nil {
-		if errors.Is(err, ErrContentNotFound) || errors.Is(err, apperrors.ErrContentNotFound) {
-			return r.Create(c)
+		if facetCtx.Err() == context.DeadlineExceeded {
+			return nil, nil
 		}
-		return apperrors.NewDatabaseError("check existing content", err)
+		return nil, databaseError("count search facets", err)
 	}
+	defer rows.Close()
 
-	c.ID = existing.ID
-	return r.Update(c)
+	var counts []facetCount
+	for rows.Next() {
+		var fc facetCount
+		if err := rows.Scan(&fc.contentType, &fc.providerID, &fc.count); err != nil {
+			return nil, fmt.Errorf("failed to scan facet count: %w", err)
+		}
+		counts = append(counts, fc)
+	}
+	if err := rows.Err(); err != nil {
+		if facetCtx.Err() == context.DeadlineExceeded {
+			return nil, nil
+		}
+		return nil, databaseError("count search facets", err)
+	}
+	return buildFacets(counts), nil
 }
 
-// Search searches for content based on the search request
-// Supports keyword search, type filtering, sorting, and pagination
-// ctx is used for timeout and cancellation support
-func (r *ContentRepository) Search(ctx context.Context, req *model.SearchRequest) ([]*model.Content, int, error) {
-	// Build WHERE clause
-	whereClauses := []string{}
-	args := []interface{}{}
-	trimmedQuery := strings.TrimSpace(req.Query)
-	useFullText := len(trimmedQuery) >= r.minFullTextLength
+// facetsQuery counts the rows matching whereClause per (type, provider) pair
+func facetsQuery(whereClause string) string {
+	return fmt.Sprintf("SELECT type, provider_id, COUNT(*) FROM contents %s GROUP BY type, provider_id", whereClause)
+}
 
-	// Keyword search using FULLTEXT index
-	if req.Query != "" {
-		if useFullText {
-			whereClauses = append(whereClauses, "MATCH(title) AGAINST(? IN BOOLEAN MODE)")
-			args = append(args, trimmedQuery+"*")
-		} else {
-			whereClauses = append(whereClauses, "title LIKE ?")
-			args = append(args, "%"+trimmedQuery+"%")
-		}
+// buildFacets sums per-(type, provider) counts into the type and provider breakdowns
+// Entries are ordered by count descending, ties by type name or provider ID
+func buildFacets(counts []facetCount) *model.SearchFacets {
+	byType := make(map[model.ContentType]int)
+	byProvider := make(map[int]int)
+	for _, fc := range counts {
+		byType[fc.contentType] += fc.count
+		byProvider[fc.providerID] += fc.count
+	}
+
+	facets := &model.SearchFacets{
+		Types:     make([]model.TypeFacet, 0, len(byType)),
+		Providers: make([]model.ProviderFacet, 0, len(byProvider)),
+	}
+	for t, n := range byType {
+		facets.Types = append(facets.Types, model.TypeFacet{Type: t, Count: n})
 	}
+	for id, n := range byProvider {
+		facets.Providers = append(facets.Providers, model.ProviderFacet{ProviderID: id, Count: n})
+	}
+	sort.Slice(facets.Types, func(i, j int) bool {
+		a, b := facets.Types[i], facets.Types[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.Type < b.Type)
+	})
+	sort.Slice(facets.Providers, func(i, j int) bool {
+		a, b := facets.Providers[i], facets.Providers[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.ProviderID < b.ProviderID)
+	})
+	return facets
+}
 
-	// Type filter
-	if req.Type != nil {
-		whereClauses = append(whereClauses, "type = ?")
-		args = append(args, *req.Type)
+// ErrNoRowEstimate is returned by EstimateSearchResults when the planner has no usable estimate
+var ErrNoRowEstimate = errors.New("no row estimate available")
+
+// EstimateSearchResults estimates how many rows match the request's filters without counting them
+// Search falls back to it when COUNT times out. Unfiltered searches use the table's row
+// statistics; filtered ones use EXPLAIN's estimate for the contents table. FULLTEXT and
+// distinct_titles searches have no meaningful estimate and return ErrNoRowEstimate
+func (r *ContentRepository) EstimateSearchResults(ctx context.Context, req *model.SearchRequest) (int, error) {
+	if req.DistinctTitles {
+		return 0, ErrNoRowEstimate
+	}
+	whereClause, args := r.buildSearchFilters(req)
+	if whereClause == "" {
+		return r.estimateTableRows(ctx)
 	}
 
-	// Provider filter
-	if req.ProviderID != nil {
-		whereClauses = append(whereClauses, "provider_id = ?")
-		args = append(args, *req.ProviderID)
+	rows, err := r.db.QueryContext(ctx, "EXPLAIN SELECT id FROM contents "+whereClause, args...)
+	if err != nil {
+		return 0, databaseError("explain search", err)
 	}
+	defer rows.Close()
 
-	// Date range filters
-	if req.StartDate != nil {
-		whereClauses = append(whereClauses, "published_at >= ?")
-		args = append(args, *req.StartDate)
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, databaseError("explain search", err)
+	}
+	var plan [][]sql.NullString
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(values))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return 0, databaseError("explain search", err)
+		}
+		plan = append(plan, values)
 	}
-	if req.EndDate != nil {
-		whereClauses = append(whereClauses, "published_at <= ?")
-		args = append(args, *req.EndDate)
+	if err := rows.Err(); err != nil {
+		return 0, databaseError("explain search", err)
 	}
 
-	whereClause := ""
-	if len(whereClauses) > 0 {
-		whereClause = "WHERE " + strings.Join(whereClauses, " AND ")
+	estimate, ok := explainRowEstimate(columns, plan)
+	if !ok {
+		return 0, ErrNoRowEstimate
 	}
+	return estimate, nil
+}
 
-	// Build ORDER BY clause with whitelist validation to prevent SQL injection
-	validSortFields := map[string]bool{
-		"score":        true,
-		"published_at": true,
-		"title":        true,
-		"id":           true,
+// estimateTableRows reads InnoDB's approximate row count for the contents table
+func (r *ContentRepository) estimateTableRows(ctx context.Context) (int, error) {
+	var tableRows sql.NullInt64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT TABLE_ROWS FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'contents'
+	`).Scan(&tableRows)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !tableRows.Valid) {
+		return 0, ErrNoRowEstimate
 	}
-	sortBy := req.SortBy
-	if !validSortFields[sortBy] {
-		sortBy = "score" // Default to score if invalid
+	if err != nil {
+		return 0, databaseError("estimate table rows", err)
 	}
+	return int(tableRows.Int64), nil
+}
 
-	validSortOrders := map[string]bool{
-		"ASC":  true,
-		"DESC": true,
+// explainRowEstimate picks the contents table's row estimate out of EXPLAIN output
+// The estimate is rows scaled by the filtered percentage. A fulltext access reports
+// a placeholder row count, so it yields no estimate
+func explainRowEstimate(columns []string, plan [][]sql.NullString) (int, bool) {
+	col := make(map[string]int, len(columns))
+	for i, name := range columns {
+		col[strings.ToLower(name)] = i
 	}
-	sortOrder := strings.ToUpper(req.SortOrder)
-	if !validSortOrders[sortOrder] {
-		sortOrder = "DESC" // Default to DESC if invalid
+	tableCol, hasTable := col["table"]
+	rowsCol, hasRows := col["rows"]
+	if !hasTable || !hasRows {
+		return 0, false
 	}
 
-	orderBy := fmt.Sprintf("ORDER BY %s %s, id DESC", sortBy, sortOrder)
+	// The outer query is listed first; tag subqueries over other tables come after it
+	for _, row := range plan {
+		if row[tableCol].String != "contents" {
+			continue
+		}
+		if typeCol, ok := col["type"]; ok && row[typeCol].String == "fulltext" {
+			return 0, false
+		}
+		rows, err := strconv.ParseFloat(row[rowsCol].String, 64)
+		if err != nil {
+			return 0, false
+		}
+		if filteredCol, ok := col["filtered"]; ok && row[filteredCol].Valid {
+			if filtered, err := strconv.ParseFloat(row[filteredCol].String, 64); err == nil {
+				rows = rows * filtered / 100
+			}
+		}
+		return int(math.Round(rows)), true
+	}
+	return 0, false
+}
 
-	// Count total results (for pagination)
-	// Use a separate context with timeout for COUNT query to prevent it from blocking too long
-	// COUNT can be slow on large tables, so we give it a reasonable timeout
-	countCtx, countCancel := context.WithTimeout(ctx, 10*time.Second)
+// countDistinctTitles counts matching rows and distinct normalized titles in one query
+// Shares countSearchResults' timeout; on timeout both counts are -1
+func (r *ContentRepository) countDistinctTitles(ctx context.Context, whereClause string, args []interface{}) (rowCount, distinct int, err error) {
+	countCtx, countCancel := context.WithTimeout(ctx, searchCountTimeout)
 	defer countCancel()
 
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM contents %s", whereClause)
-	var total int
-	err := r.db.QueryRowContext(countCtx, countQuery, args...).Scan(&total)
-	if err != nil {
+	countQuery := fmt.Sprintf("SELECT COUNT(*), COUNT(DISTINCT %s) FROM contents %s", normalizedTitleExpr, whereClause)
+	if err := r.db.QueryRowContext(countCtx, countQuery, args...).Scan(&rowCount, &distinct); err != nil {
 		if countCtx.Err() == context.DeadlineExceeded {
-			// If COUNT times out, estimate total based on returned results
-			// This allows pagination to work even if COUNT is slow
-			total = -1 // Use -1 to indicate estimated/unknown total
-		} else {
-			return nil, 0, apperrors.NewDatabaseError("count results", err)
+			return -1, -1, nil
 		}
+		return 0, 0, databaseError("count distinct titles", err)
 	}
+	return rowCount, distinct, nil
+}
 
-	// Build SELECT query with pagination
-	query := fmt.Sprintf(`
-		SELECT id, provider_id, external_id, title, type,
-		       views, likes, duration_seconds,
-		       reading_time, reactions, comments,
-		       published_at, score, created_at, updated_at
-		FROM contents
-		%s
-		%s
-		LIMIT ? OFFSET ?
-	`, whereClause, orderBy)
+// likeEscaper escapes LIKE wildcards so user input is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
 
-	args = append(args, req.PerPage, req.GetOffset())
+// splitSearchTerms splits a query on whitespace, dropping duplicate terms
+// Terms are compared case-insensitively since the title collation is case-insensitive
+func splitSearchTerms(query string) []string {
+	fields := strings.Fields(query)
+	seen := make(map[string]bool, len(fields))
+	terms := make([]string, 0, len(fields))
+	for _, f := range fields {
+		key := strings.ToLower(f)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		terms = append(terms, f)
+	}
+	return terms
+}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+// buildLikeClause builds the LIKE fallback condition for a short query
+// Each term becomes its own "title LIKE ?" and the terms are ANDed together,
+// so "guide docker" matches "Docker Guide" just like "docker guide" does
+// Every row it matches contains every term; sort_by=relevance ranks rows that
+// matched through their tags instead by likeTermCountExpr
+func buildLikeClause(query string) (string, []interface{}) {
+	terms := splitSearchTerms(query)
+	clauses := make([]string, len(terms))
+	args := make([]interface{}, len(terms))
+	for i, term := range terms {
+		clauses[i] = "title LIKE ?"
+		args[i] = "%" + likeEscaper.Replace(term) + "%"
+	}
+	return "(" + strings.Join(clauses, " AND ") + ")", args
+}
+
+// likeTermCountExpr counts how many of a short query's terms a title contains
+// Each "title LIKE ?" is 0 or 1 in MySQL, so their sum is the number of matching terms
+func likeTermCountExpr(query string) (string, []interface{}) {
+	terms := splitSearchTerms(query)
+	clauses := make([]string, len(terms))
+	args := make([]interface{}, len(terms))
+	for i, term := range terms {
+		clauses[i] = "(title LIKE ?)"
+		args[i] = "%" + likeEscaper.Replace(term) + "%"
+	}
+	return "(" + strings.Join(clauses, " + ") + ")", args
+}
+
+// similarByTagsQuery selects contents sharing tags with a source item, most shared tags first
+// Shared tags are counted in a derived table so contentColumns stays unambiguous
+const similarByTagsQuery = `
+		SELECT ` + contentColumns + `, shared.shared_tags
+		FROM contents
+		JOIN (
+			SELECT t.content_id, COUNT(*) AS shared_tags
+			FROM content_tags src
+			JOIN content_tags t ON t.tag = src.tag AND t.content_id <> src.content_id
+			WHERE src.content_id = ?
+			GROUP BY t.content_id
+		) shared ON shared.content_id = contents.id
+		ORDER BY shared.shared_tags DESC, score DESC, id DESC
+		LIMIT ?
+	`
+
+// FindSimilarByTags returns up to limit other contents sharing tags with contentID
+// Ranked by the number of shared tags, then score; the source item is never included
+// Returns an empty slice when the item has no tags or nothing shares them
+func (r *ContentRepository) FindSimilarByTags(ctx context.Context, contentID int64, limit int) ([]*model.SimilarContent, error) {
+	rows, err := r.db.QueryContext(ctx, similarByTagsQuery, contentID, limit)
 	if err != nil {
-		return nil, 0, apperrors.NewDatabaseError("search content", err)
+		return nil, databaseError("find similar content", err)
 	}
 	defer rows.Close()
 
-	var contents []*model.Content
+	similar := make([]*model.SimilarContent, 0, limit)
 	for rows.Next() {
-		c := &model.Content{}
-		err := rows.Scan(
-			&c.ID,
-			&c.ProviderID,
-			&c.ExternalID,
-			&c.Title,
-			&c.Type,
-			&c.Views,
-			&c.Likes,
-			&c.DurationSeconds,
-			&c.ReadingTime,
-			&c.Reactions,
-			&c.Comments,
-			&c.PublishedAt,
-			&c.Score,
-			&c.CreatedAt,
-			&c.UpdatedAt,
-		)
+		s := &model.SimilarContent{}
+		s.Content, err = scanContent(withExtraColumns{row: rows, extra: []interface{}{&s.SharedTags}})
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan content: %w", err)
+			return nil, fmt.Errorf("failed to scan similar content: %w", err)
 		}
-		contents = append(contents, c)
+		similar = append(similar, s)
 	}
 
-	return contents, total, rows.Err()
+	return similar, rows.Err()
+}
+
+// withExtraColumns scans contentColumns followed by extra selected columns
+// Lets queries that add computed columns reuse scanContent
+type withExtraColumns struct {
+	row   rowScanner
+	extra []interface{}
+}
+
+// Scan scans into dest, then into the extra destinations
+func (s withExtraColumns) Scan(dest ...interface{}) error {
+	return s.row.Scan(append(dest, s.extra...)...)
 }
 
 // GetByProviderID retrieves all content items for a specific provider
 // Useful for syncing or listing provider-specific content
-func (r *ContentRepository) GetByProviderID(providerID int, limit, offset int) ([]*model.Content, error) {
+func (r *ContentRepository) GetByProviderID(ctx context.Context, providerID int, limit, offset int) ([]*model.Content, error) {
 	query := `
-		SELECT id, provider_id, external_id, title, type,
-		       views, likes, duration_seconds,
-		       reading_time, reactions, comments,
-		       published_at, score, created_at, updated_at
+		SELECT ` + contentColumns + `
 		FROM contents
 		WHERE provider_id = ?
 		ORDER BY published_at DESC
 		LIMIT ? OFFSET ?
 	`
-	rows, err := r.db.Query(query, providerID, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, providerID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get content by provider id: %w", err)
 	}
@@ -400,24 +1461,36 @@ func (r *ContentRepository) GetByProviderID(providerID int, limit, offset int) (
 
 	var contents []*model.Content
 	for rows.Next() {
-		c := &model.Content{}
-		err := rows.Scan(
-			&c.ID,
-			&c.ProviderID,
-			&c.ExternalID,
-			&c.Title,
-			&c.Type,
-			&c.Views,
-			&c.Likes,
-			&c.DurationSeconds,
-			&c.ReadingTime,
-			&c.Reactions,
-			&c.Comments,
-			&c.PublishedAt,
-			&c.Score,
-			&c.CreatedAt,
-			&c.UpdatedAt,
-		)
+		c, err := scanContent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan content: %w", err)
+		}
+		contents = append(contents, c)
+	}
+
+	return contents, rows.Err()
+}
+
+// GetAfterID retrieves up to limit content items with an id above afterID, in id order
+// Paging on the last id seen walks every row exactly once regardless of provider,
+// and unlike OFFSET it stays correct when rows are added or removed between pages
+func (r *ContentRepository) GetAfterID(ctx context.Context, afterID int64, limit int) ([]*model.Content, error) {
+	query := `
+		SELECT ` + contentColumns + `
+		FROM contents
+		WHERE id > ?
+		ORDER BY id
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, databaseError("get content page", err)
+	}
+	defer rows.Close()
+
+	contents := make([]*model.Content, 0, limit)
+	for rows.Next() {
+		c, err := scanContent(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan content: %w", err)
 		}
@@ -430,7 +1503,7 @@ func (r *ContentRepository) GetByProviderID(providerID int, limit, offset int) (
 // LoadTags loads tags for a content item
 // This is a helper method to populate the Tags field
 func (r *ContentRepository) LoadTags(content *model.Content) error {
-	tagRepo := NewContentTagRepository(r.db)
+	tagRepo := NewContentTagRepository(r.db, model.TagLimits{})
 	tags, err := tagRepo.GetByContentID(content.ID)
 	if err != nil {
 		return fmt.Errorf("failed to load tags: %w", err)
@@ -444,10 +1517,21 @@ func (r *ContentRepository) LoadTags(content *model.Content) error {
 	return nil
 }
 
+// tagOrderByClause returns the ORDER BY expression for the requested tag order
+// Insertion order relies on the ordinal column, with id as a tiebreaker for
+// rows written before ordinals were recorded
+func tagOrderByClause(order model.TagOrder) string {
+	if order == model.TagOrderInsertion {
+		return "ordinal, id"
+	}
+	return "tag"
+}
+
 // LoadTagsBatch loads tags for multiple content items efficiently
 // This reduces the number of database queries when loading multiple contents
 // ctx is used for timeout and cancellation support
-func (r *ContentRepository) LoadTagsBatch(ctx context.Context, contents []*model.Content) error {
+// order selects alphabetical or insertion ordering of each item's tags
+func (r *ContentRepository) LoadTagsBatch(ctx context.Context, contents []*model.Content, order model.TagOrder) error {
 	if len(contents) == 0 {
 		return nil
 	}
@@ -466,8 +1550,8 @@ func (r *ContentRepository) LoadTagsBatch(ctx context.Context, contents []*model
 		SELECT content_id, tag
 		FROM content_tags
 		WHERE content_id IN (%s)
-		ORDER BY content_id, tag
-	`, placeholders)
+		ORDER BY content_id, %s
+	`, placeholders, tagOrderByClause(order))
 
 	// Convert []int64 to []interface{}
 	args := make([]interface{}, len(contentIDs))
@@ -507,13 +1591,14 @@ func (r *ContentRepository) LoadTagsBatch(ctx context.Context, contents []*model
 // GetTagsByContentID retrieves all tags for a specific content item
 // Returns an empty slice if no tags exist
 // ctx is used for timeout and cancellation support
-func (r *ContentRepository) GetTagsByContentID(ctx context.Context, contentID int64) ([]string, error) {
-	query := `
+// order selects alphabetical or insertion ordering
+func (r *ContentRepository) GetTagsByContentID(ctx context.Context, contentID int64, order model.TagOrder) ([]string, error) {
+	query := fmt.Sprintf(`
 		SELECT tag
 		FROM content_tags
 		WHERE content_id = ?
-		ORDER BY tag
-	`
+		ORDER BY %s
+	`, tagOrderByClause(order))
 	rows, err := r.db.QueryContext(ctx, query, contentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tags by content id: %w", err)
@@ -610,3 +1695,83 @@ func (r *ContentRepository) GetStats() (map[string]interface{}, error) {
 
 	return stats, nil
 }
+
+// GetProviderStats retrieves detailed statistics for the given providers
+// All providers are aggregated in a single grouped query constrained to ids
+// Providers with no content are included with zero counts; unknown IDs are omitted
+func (r *ContentRepository) GetProviderStats(ctx context.Context, ids []int) ([]*model.ProviderStats, error) {
+	if len(ids) == 0 {
+		return []*model.ProviderStats{}, nil
+	}
+
+	placeholders := strings.Repeat("?,", len(ids))
+	placeholders = placeholders[:len(placeholders)-1] // Remove trailing comma
+
+	query := fmt.Sprintf(`
+		SELECT p.id, p.name, p.last_fetched_at,
+		       COUNT(c.id),
+		       COALESCE(SUM(c.type = 'video'), 0),
+		       COALESCE(SUM(c.type = 'article'), 0),
+		       AVG(c.score),
+		       COALESCE(SUM(c.published_at >= ?), 0),
+		       COALESCE(SUM(c.published_at >= ?), 0),
+		       MAX(c.published_at),
+		       MIN(c.published_at)
+		FROM providers p
+		LEFT JOIN contents c ON c.provider_id = p.id
+		WHERE p.id IN (%s)
+		GROUP BY p.id, p.name, p.last_fetched_at
+		ORDER BY p.name
+	`, placeholders)
+
+	now := time.Now()
+	args := make([]interface{}, 0, len(ids)+2)
+	args = append(args, now.AddDate(0, 0, -7), now.AddDate(0, 0, -30))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, databaseError("get provider stats", err)
+	}
+	defer rows.Close()
+
+	stats := []*model.ProviderStats{}
+	for rows.Next() {
+		ps := &model.ProviderStats{}
+		var lastFetchedAt, newest, oldest sql.NullTime
+		var avgScore sql.NullFloat64
+		err := rows.Scan(
+			&ps.ProviderID,
+			&ps.Name,
+			&lastFetchedAt,
+			&ps.TotalContent,
+			&ps.Videos,
+			&ps.Articles,
+			&avgScore,
+			&ps.PublishedLastWeek,
+			&ps.PublishedLastMonth,
+			&newest,
+			&oldest,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan provider stats: %w", err)
+		}
+		if lastFetchedAt.Valid {
+			ps.LastFetchedAt = &lastFetchedAt.Time
+		}
+		if avgScore.Valid {
+			ps.AverageScore = avgScore.Float64
+		}
+		if newest.Valid {
+			ps.NewestPublishedAt = &newest.Time
+		}
+		if oldest.Valid {
+			ps.OldestPublishedAt = &oldest.Time
+		}
+		stats = append(stats, ps)
+	}
+
+	return stats, rows.Err()
+}
@@ -0,0 +1,610 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"search-engine/backend/internal/model"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildLikeClauseIgnoresTermOrder(t *testing.T) {
+	clause, args := buildLikeClause("docker guide")
+	reorderedClause, reorderedArgs := buildLikeClause("guide   docker")
+
+	if clause != "(title LIKE ? AND title LIKE ?)" {
+		t.Fatalf("unexpected clause: %s", clause)
+	}
+	if clause != reorderedClause {
+		t.Fatalf("expected identical clauses, got %q and %q", clause, reorderedClause)
+	}
+
+	toSorted := func(in []interface{}) []string {
+		out := make([]string, len(in))
+		for i, a := range in {
+			out[i] = a.(string)
+		}
+		sort.Strings(out)
+		return out
+	}
+	if !reflect.DeepEqual(toSorted(args), toSorted(reorderedArgs)) {
+		t.Fatalf("expected the same terms regardless of order, got %v and %v", args, reorderedArgs)
+	}
+	if !reflect.DeepEqual(toSorted(args), []string{"%docker%", "%guide%"}) {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
+
+func TestBuildLikeClauseDedupesAndEscapes(t *testing.T) {
+	clause, args := buildLikeClause("Go go 100%")
+
+	if clause != "(title LIKE ? AND title LIKE ?)" {
+		t.Fatalf("unexpected clause: %s", clause)
+	}
+	want := []interface{}{"%Go%", `%100\%%`}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("expected %v, got %v", want, args)
+	}
+}
+
+func TestFullTextQueryMultiWord(t *testing.T) {
+	exact := false
+
+	tests := []struct {
+		name   string
+		query  string
+		prefix *bool
+		mode   model.MatchMode
+		want   string
+	}{
+		{"any mode prefixes every term", "docker kubernetes", nil, model.MatchModeAny, "docker* kubernetes*"},
+		{"all mode requires every term", "docker kubernetes", nil, model.MatchModeAll, "+docker* +kubernetes*"},
+		{"exact words in any mode", "docker kubernetes", &exact, model.MatchModeAny, "docker kubernetes"},
+		{"exact words in all mode", "docker kubernetes", &exact, model.MatchModeAll, "+docker +kubernetes"},
+		{"extra whitespace and duplicates", "  docker \t Docker   kubernetes ", nil, model.MatchModeAll, "+docker* +kubernetes*"},
+		{"injected operators stripped", `+docker -"kubernetes*"`, nil, model.MatchModeAny, "docker* kubernetes*"},
+		{"operators split words", "real-time", nil, model.MatchModeAll, "+real* +time*"},
+		{"only operators", `+-*"`, nil, model.MatchModeAll, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fullTextQuery(tt.query, tt.prefix, tt.mode); got != tt.want {
+				t.Errorf("fullTextQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeQueryMatchModes(t *testing.T) {
+	r := NewContentRepository(nil, 3)
+
+	tests := []struct {
+		name      string
+		req       model.SearchRequest
+		wantMode  model.SearchMode
+		wantQuery string
+	}{
+		{"multi-word any", model.SearchRequest{Query: "docker kubernetes", MatchMode: model.MatchModeAny}, model.SearchModeFullText, "docker* kubernetes*"},
+		{"multi-word all", model.SearchRequest{Query: "docker kubernetes", MatchMode: model.MatchModeAll}, model.SearchModeFullText, "+docker* +kubernetes*"},
+		{"short query keeps LIKE", model.SearchRequest{Query: "go", MatchMode: model.MatchModeAll}, model.SearchModeLike, "go"},
+		{"operators only", model.SearchRequest{Query: `"+*"`, MatchMode: model.MatchModeAll}, model.SearchModeNone, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, query := r.DescribeQuery(&tt.req)
+			if mode != tt.wantMode || query != tt.wantQuery {
+				t.Errorf("DescribeQuery() = (%s, %q), want (%s, %q)", mode, query, tt.wantMode, tt.wantQuery)
+			}
+		})
+	}
+}
+
+// fakeRow records the destinations it was asked to scan into
+type fakeRow struct {
+	dest []interface{}
+}
+
+func (f *fakeRow) Scan(dest ...interface{}) error {
+	f.dest = dest
+	return nil
+}
+
+func TestWithExtraColumnsAppendsDestinations(t *testing.T) {
+	row := &fakeRow{}
+	var sharedTags int
+
+	if _, err := scanContent(withExtraColumns{row: row, extra: []interface{}{&sharedTags}}); err != nil {
+		t.Fatalf("scanContent: %v", err)
+	}
+
+	// contentColumns has 16 columns; the extra column must come last
+	if len(row.dest) != 17 {
+		t.Fatalf("scanned %d columns, want 17", len(row.dest))
+	}
+	if row.dest[16] != &sharedTags {
+		t.Error("extra destination is not the last scanned column")
+	}
+}
+
+func TestScoreBatchQuery(t *testing.T) {
+	query, args := scoreBatchQuery(map[int64]float64{42: 1.5, 7: 0.25, 19: 3})
+
+	wantQuery := "UPDATE contents SET score = CASE id WHEN ? THEN ? WHEN ? THEN ? WHEN ? THEN ? END, updated_at = CURRENT_TIMESTAMP WHERE id IN (?, ?, ?)"
+	if query != wantQuery {
+		t.Errorf("query = %q, want %q", query, wantQuery)
+	}
+	wantArgs := []interface{}{int64(7), 0.25, int64(19), 3.0, int64(42), 1.5, int64(7), int64(19), int64(42)}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestUpdateScoresBatchWritesEveryScore(t *testing.T) {
+	db := &fakeDB{}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+
+	if err := r.UpdateScoresBatch(context.Background(), map[int64]float64{42: 1.5, 7: 0.25}); err != nil {
+		t.Fatalf("UpdateScoresBatch: %v", err)
+	}
+	updates := db.statementsLike("UPDATE contents SET score")
+	if len(updates) != 1 || !updates[0].inTx || db.commits != 1 {
+		t.Fatalf("updates = %+v, commits %d; want one committed statement", updates, db.commits)
+	}
+	wantArgs := []driver.Value{int64(7), 0.25, int64(42), 1.5, int64(7), int64(42)}
+	if !reflect.DeepEqual(updates[0].args, wantArgs) {
+		t.Errorf("args = %v, want %v", updates[0].args, wantArgs)
+	}
+}
+
+func TestUpdateScoresBatchRollsBackOnError(t *testing.T) {
+	db := &fakeDB{failOn: "UPDATE contents SET score"}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+
+	if err := r.UpdateScoresBatch(context.Background(), map[int64]float64{42: 1.5, 7: 0.25}); err == nil {
+		t.Fatal("UpdateScoresBatch succeeded despite a failed statement")
+	}
+	if db.commits != 0 || db.rollbacks != 1 {
+		t.Errorf("commits %d, rollbacks %d; want the transaction rolled back", db.commits, db.rollbacks)
+	}
+	for _, s := range db.statements {
+		if !s.inTx {
+			t.Errorf("statement ran outside the transaction: %s", s.query)
+		}
+	}
+}
+
+// BenchmarkScoreUpdates compares writing a page of scores row by row with one batch
+// Each statement costs a simulated 1ms round trip, which is what the batch saves
+func BenchmarkScoreUpdates(b *testing.B) {
+	scores := make(map[int64]float64, 100)
+	for id := int64(1); id <= 100; id++ {
+		scores[id] = float64(id) / 10
+	}
+	r := NewContentRepository(sql.OpenDB(&fakeDB{latency: time.Millisecond}), 3)
+	ctx := context.Background()
+
+	b.Run("row by row", func(b *testing.B) {
+		for b.Loop() {
+			for id, score := range scores {
+				if err := r.UpdateScore(ctx, id, score); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for b.Loop() {
+			if err := r.UpdateScoresBatch(ctx, scores); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestFindSimilarByTagsQuery(t *testing.T) {
+	similarRow := func(id int64, shared int64) []driver.Value {
+		return append(existingContentRow(id, 1), shared)
+	}
+	db := &fakeDB{results: map[string][][]driver.Value{"shared_tags": {similarRow(2, 3), similarRow(4, 2)}}}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+
+	similar, err := r.FindSimilarByTags(context.Background(), 1, 5)
+	if err != nil {
+		t.Fatalf("FindSimilarByTags: %v", err)
+	}
+
+	queries := db.statementsLike("shared_tags")
+	if len(queries) != 1 {
+		t.Fatalf("queries = %+v, want one", queries)
+	}
+	query := strings.Join(strings.Fields(queries[0].query), " ")
+	for _, want := range []string{
+		// Tags are matched against the source item's, never the source itself
+		"FROM content_tags src JOIN content_tags t ON t.tag = src.tag AND t.content_id <> src.content_id WHERE src.content_id = ?",
+		"COUNT(*) AS shared_tags",
+		"GROUP BY t.content_id",
+		// Most shared tags first, ties broken by score, then id so pages are stable
+		"ORDER BY shared.shared_tags DESC, score DESC, id DESC LIMIT ?",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query %q lacks %q", query, want)
+		}
+	}
+	if want := []driver.Value{int64(1), int64(5)}; !reflect.DeepEqual(queries[0].args, want) {
+		t.Errorf("args = %v, want %v", queries[0].args, want)
+	}
+
+	if len(similar) != 2 || similar[0].Content.ID != 2 || similar[0].SharedTags != 3 || similar[1].SharedTags != 2 {
+		t.Errorf("similar = %+v, want contents 2 and 4 with their shared tag counts", similar)
+	}
+}
+
+func TestLikeTermCountExpr(t *testing.T) {
+	// A title matched through its tags may contain only some of the terms
+	expr, args := likeTermCountExpr("guide  docker guide")
+	if want := "((title LIKE ?) + (title LIKE ?))"; expr != want {
+		t.Errorf("expr = %q, want %q", expr, want)
+	}
+	if want := []interface{}{"%guide%", "%docker%"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+}
+
+func TestSearchOrderRelevance(t *testing.T) {
+	r := NewContentRepository(nil, 3)
+	r.SetRelevanceWeights(RelevanceWeights{Text: 5, Score: 0.5})
+
+	tests := []struct {
+		name     string
+		req      model.SearchRequest
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			"keyword ranks by match and score",
+			model.SearchRequest{Query: "docker guide", SortBy: "relevance"},
+			"ORDER BY (MATCH(title) AGAINST(? IN BOOLEAN MODE) * ? + score * ?) DESC, id DESC",
+			[]interface{}{"docker* guide*", 5.0, 0.5},
+		},
+		{
+			"ascending keyword search",
+			model.SearchRequest{Query: "docker", SortBy: "relevance", SortOrder: "asc"},
+			"ORDER BY (MATCH(title) AGAINST(? IN BOOLEAN MODE) * ? + score * ?) ASC, id DESC",
+			[]interface{}{"docker*", 5.0, 0.5},
+		},
+		{"no keyword falls back to score", model.SearchRequest{SortBy: "relevance"}, "ORDER BY score DESC, id DESC", nil},
+		{
+			"LIKE keyword ranks by matching terms",
+			model.SearchRequest{Query: "go", SortBy: "relevance"},
+			"ORDER BY (((title LIKE ?)) * ? + score * ?) DESC, id DESC",
+			[]interface{}{"%go%", 5.0, 0.5},
+		},
+		{"other sorts take no args", model.SearchRequest{Query: "docker", SortBy: "score"}, "ORDER BY score DESC, id DESC", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args := r.searchOrder(&tt.req)
+			if got != tt.want || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("searchOrder() = (%q, %v), want (%q, %v)", got, args, tt.want, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSearchOrderBy(t *testing.T) {
+	tests := []struct {
+		sortBy, sortOrder string
+		want              string
+	}{
+		{"score", "desc", "ORDER BY score DESC, id DESC"},
+		{"published_at", "asc", "ORDER BY published_at ASC, id DESC"},
+		{"views", "desc", "ORDER BY (type <> 'video' OR views IS NULL), views DESC, id DESC"},
+		{"likes", "asc", "ORDER BY (type <> 'video' OR likes IS NULL), likes ASC, id DESC"},
+		{"reactions", "desc", "ORDER BY (type <> 'article' OR reactions IS NULL), reactions DESC, id DESC"},
+		{"comments", "asc", "ORDER BY (type <> 'article' OR comments IS NULL), comments ASC, id DESC"},
+		{"views; DROP TABLE contents", "desc", "ORDER BY score DESC, id DESC"},
+		{"views", "sideways", "ORDER BY (type <> 'video' OR views IS NULL), views DESC, id DESC"},
+		{"relevance", "asc", "ORDER BY score ASC, id DESC"},
+	}
+
+	for _, tt := range tests {
+		got := searchOrderBy(&model.SearchRequest{SortBy: tt.sortBy, SortOrder: tt.sortOrder})
+		if got != tt.want {
+			t.Errorf("searchOrderBy(%q, %q) = %q, want %q", tt.sortBy, tt.sortOrder, got, tt.want)
+		}
+	}
+}
+
+func TestSearchPageWithCursor(t *testing.T) {
+	where, args, limit := searchPage("WHERE type = ?", []interface{}{"video"}, nil)
+	if where != "WHERE type = ?" || limit != "LIMIT ? OFFSET ?" || !reflect.DeepEqual(args, []interface{}{"video"}) {
+		t.Errorf("searchPage() without cursor = (%q, %v, %q)", where, args, limit)
+	}
+
+	where, args, limit = searchPage("", nil, &model.SearchCursor{Score: 7.5, ID: 12})
+	if where != "WHERE (score, id) < (?, ?)" || limit != "LIMIT ?" || !reflect.DeepEqual(args, []interface{}{7.5, int64(12)}) {
+		t.Errorf("searchPage() with cursor only = (%q, %v, %q)", where, args, limit)
+	}
+
+	filterArgs := []interface{}{"video"}
+	where, args, _ = searchPage("WHERE type = ?", filterArgs, &model.SearchCursor{Score: 7.5, ID: 12})
+	if where != "WHERE type = ? AND (score, id) < (?, ?)" || !reflect.DeepEqual(args, []interface{}{"video", 7.5, int64(12)}) {
+		t.Errorf("searchPage() with filter and cursor = (%q, %v)", where, args)
+	}
+	if len(filterArgs) != 1 {
+		t.Error("searchPage() modified the filter args, which the count query reuses")
+	}
+}
+
+func TestCursorPageResumesInsideTiedScores(t *testing.T) {
+	// The first page ends inside a run of rows sharing score 3, the case where a
+	// score-only cursor skips or repeats rows
+	firstPage := [][]driver.Value{existingContentRow(23, 3), existingContentRow(19, 3), existingContentRow(15, 3)}
+	db := &fakeDB{results: map[string][][]driver.Value{"COUNT(*)": {{int64(9)}}, "ORDER BY": firstPage}}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+	videoType := model.ContentTypeVideo
+	req := &model.SearchRequest{Type: &videoType, SortBy: "score", SortOrder: "desc", Page: 1, PerPage: 3}
+
+	rows, _, err := r.Search(context.Background(), req)
+	if err != nil || len(rows) != 3 {
+		t.Fatalf("first page = %d rows, %v; want 3", len(rows), err)
+	}
+	after := model.CursorOf(rows[len(rows)-1]).Encode()
+	req.After = &after
+	if _, _, err := r.Search(context.Background(), req); err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+
+	pages := db.statementsLike("ORDER BY")
+	if len(pages) != 2 {
+		t.Fatalf("page queries = %+v, want 2", pages)
+	}
+	// The second page keeps the filters and resumes after the last row's (score, id):
+	// under the id tie-break, that is score 3 with a lower id, then every lower score
+	query := strings.Join(strings.Fields(pages[1].query), " ")
+	if want := "WHERE type = ? AND (score, id) < (?, ?) ORDER BY score DESC, id DESC LIMIT ?"; !strings.HasSuffix(query, want) {
+		t.Errorf("second page query = %q, want it to end with %q", query, want)
+	}
+	if want := []driver.Value{"video", 3.0, int64(15), int64(3)}; !reflect.DeepEqual(pages[1].args, want) {
+		t.Errorf("second page args = %v, want %v", pages[1].args, want)
+	}
+}
+
+func TestExplainRowEstimate(t *testing.T) {
+	columns := []string{"id", "select_type", "table", "type", "rows", "filtered"}
+	row := func(values ...string) []sql.NullString {
+		out := make([]sql.NullString, len(values))
+		for i, v := range values {
+			out[i] = sql.NullString{String: v, Valid: v != "NULL"}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		plan   [][]sql.NullString
+		want   int
+		wantOK bool
+	}{
+		{"rows scaled by filtered", [][]sql.NullString{row("1", "SIMPLE", "contents", "range", "12000", "50.00")}, 6000, true},
+		{"no filtered column value", [][]sql.NullString{row("1", "SIMPLE", "contents", "ALL", "800", "NULL")}, 800, true},
+		{
+			"subquery rows are ignored",
+			[][]sql.NullString{
+				row("1", "PRIMARY", "contents", "ref", "300", "100.00"),
+				row("2", "SUBQUERY", "content_tags", "ref", "90000", "100.00"),
+			},
+			300, true,
+		},
+		{"fulltext has no estimate", [][]sql.NullString{row("1", "SIMPLE", "contents", "fulltext", "1", "100.00")}, 0, false},
+		{"contents missing", [][]sql.NullString{row("1", "SIMPLE", "tags", "ALL", "10", "100.00")}, 0, false},
+		{"empty plan", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := explainRowEstimate(columns, tt.plan)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("explainRowEstimate() = %d, %t, want %d, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAutocompleteQuery(t *testing.T) {
+	r := NewContentRepository(nil, 3)
+	const likeOnly = "SELECT title FROM contents WHERE title LIKE ? GROUP BY title ORDER BY title LIKE ? DESC, MAX(score) DESC, title LIMIT ?"
+	const withFullText = "SELECT title FROM contents WHERE title LIKE ? OR MATCH(title) AGAINST(? IN BOOLEAN MODE) GROUP BY title ORDER BY title LIKE ? DESC, MAX(score) DESC, title LIMIT ?"
+
+	tests := []struct {
+		name      string
+		prefix    string
+		limit     int
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{"empty", "", 8, "", nil},
+		{"too short for FULLTEXT", "do", 8, likeOnly, []interface{}{"do%", "do%", 8}},
+		{"prefix", "dock", 8, withFullText, []interface{}{"dock%", "+dock*", "dock%", 8}},
+		{"every term required", "docker comp", 5, withFullText, []interface{}{"docker comp%", "+docker* +comp*", "docker comp%", 5}},
+		{"wildcards are literal", "100%_", 8, withFullText, []interface{}{`100\%\_%`, "+100%_*", `100\%\_%`, 8}},
+		{"only operators", "+++", 8, likeOnly, []interface{}{"+++%", "+++%", 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := r.autocompleteQuery(tt.prefix, tt.limit)
+			if query != tt.wantQuery {
+				t.Errorf("query = %q, want %q", query, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestProviderGroupsQuery(t *testing.T) {
+	r := NewContentRepository(nil, 3)
+	r.SetRelevanceWeights(RelevanceWeights{Text: 5, Score: 0.5})
+
+	tests := []struct {
+		name       string
+		req        model.SearchRequest
+		wantWindow string
+		wantArgs   []interface{}
+	}{
+		{
+			"first page by score",
+			model.SearchRequest{Page: 1, PerPage: 5, SortBy: "score", SortOrder: "desc"},
+			"PARTITION BY provider_id ORDER BY score DESC, id DESC",
+			[]interface{}{0, 5},
+		},
+		{
+			"third page ranks past the first two pages of each provider",
+			model.SearchRequest{Page: 3, PerPage: 5, SortBy: "published_at", SortOrder: "asc"},
+			"PARTITION BY provider_id ORDER BY published_at ASC, id DESC",
+			[]interface{}{10, 15},
+		},
+		{
+			"relevance ranks by the inner match score",
+			model.SearchRequest{Query: "docker", Page: 1, PerPage: 3, SortBy: "relevance", SortOrder: "desc"},
+			"PARTITION BY provider_id ORDER BY (text_relevance * ? + score * ?) DESC, id DESC",
+			[]interface{}{5.0, 0.5, "docker*", 0, 3},
+		},
+		{
+			"relevance of a short keyword ranks by matching terms",
+			model.SearchRequest{Query: "go", Page: 1, PerPage: 3, SortBy: "relevance", SortOrder: "desc"},
+			"PARTITION BY provider_id ORDER BY (text_relevance * ? + score * ?) DESC, id DESC",
+			[]interface{}{5.0, 0.5, "%go%", 0, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := r.providerGroupsQuery(&tt.req, "", nil)
+			if !strings.Contains(query, tt.wantWindow) {
+				t.Errorf("query does not rank by %q:\n%s", tt.wantWindow, query)
+			}
+			// Each provider keeps only the ranks on the requested page
+			if !strings.Contains(query, "WHERE provider_rank > ? AND provider_rank <= ?") {
+				t.Errorf("query does not limit ranks per provider:\n%s", query)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestProviderGroupsQueryKeepsFilterArgs(t *testing.T) {
+	r := NewContentRepository(nil, 3)
+	filterArgs := []interface{}{"video"}
+
+	_, args := r.providerGroupsQuery(&model.SearchRequest{Page: 1, PerPage: 2}, "WHERE type = ?", filterArgs)
+	if want := []interface{}{"video", 0, 2}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+	// The filter args are shared with the COUNT query, so they must not change
+	if len(filterArgs) != 1 {
+		t.Errorf("filter args changed to %v", filterArgs)
+	}
+}
+
+func TestFacetsQueryReusesSearchFilters(t *testing.T) {
+	r := NewContentRepository(nil, 3)
+	videos := model.ContentTypeVideo
+	whereClause, _ := r.buildSearchFilters(&model.SearchRequest{Query: "docker", Type: &videos})
+
+	query := facetsQuery(whereClause)
+	if !strings.Contains(query, whereClause) || !strings.HasSuffix(query, "GROUP BY type, provider_id") {
+		t.Errorf("facets query %q does not group the search's filtered rows", query)
+	}
+}
+
+func TestSearchWithFacetsCountsTheSearchedRows(t *testing.T) {
+	// Per (type, provider) counts, as GROUP BY type, provider_id returns them
+	db := &fakeDB{results: map[string][][]driver.Value{
+		"SELECT COUNT(*) FROM contents": {{int64(5)}},
+		"GROUP BY type, provider_id": {
+			{"article", int64(1), int64(1)},
+			{"video", int64(1), int64(1)},
+			{"article", int64(2), int64(2)},
+			{"video", int64(2), int64(1)},
+		},
+	}}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+	minViews := 100
+	req := &model.SearchRequest{Query: "docker", MinViews: &minViews, SortBy: "score", SortOrder: "desc", Page: 1, PerPage: 10}
+
+	_, _, facets, err := r.SearchWithFacets(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SearchWithFacets: %v", err)
+	}
+
+	// The facet query runs over exactly the search's WHERE clause and args,
+	// the keyword match and the engagement floor included
+	where, args := r.buildSearchFilters(req)
+	wantArgs := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if wantArgs[i], err = driver.DefaultParameterConverter.ConvertValue(arg); err != nil {
+			t.Fatalf("convert arg %v: %v", arg, err)
+		}
+	}
+	queries := db.statementsLike("GROUP BY type, provider_id")
+	if len(queries) != 1 {
+		t.Fatalf("facet queries = %+v, want one", queries)
+	}
+	if want := facetsQuery(where); queries[0].query != want {
+		t.Errorf("facet query = %q, want %q", queries[0].query, want)
+	}
+	if !reflect.DeepEqual(queries[0].args, wantArgs) {
+		t.Errorf("facet args = %v, want the search's %v", queries[0].args, wantArgs)
+	}
+	counts := db.statementsLike("SELECT COUNT(*) FROM contents")
+	if len(counts) != 1 || !reflect.DeepEqual(counts[0].args, queries[0].args) {
+		t.Errorf("count args = %+v, want the facet query's %v", counts, queries[0].args)
+	}
+
+	wantTypes := []model.TypeFacet{{Type: model.ContentTypeArticle, Count: 3}, {Type: model.ContentTypeVideo, Count: 2}}
+	if !reflect.DeepEqual(facets.Types, wantTypes) {
+		t.Errorf("type facets = %+v, want %+v", facets.Types, wantTypes)
+	}
+	wantProviders := []model.ProviderFacet{{ProviderID: 2, Count: 3}, {ProviderID: 1, Count: 2}}
+	if !reflect.DeepEqual(facets.Providers, wantProviders) {
+		t.Errorf("provider facets = %+v, want %+v", facets.Providers, wantProviders)
+	}
+}
+
+func TestBuildFacetsEmpty(t *testing.T) {
+	facets := buildFacets(nil)
+	if facets.Types == nil || facets.Providers == nil || len(facets.Types)+len(facets.Providers) != 0 {
+		t.Errorf("buildFacets(nil) = %+v, want empty, non-nil lists", facets)
+	}
+}
+
+func TestCountChangedSinceUsesContentChangedAt(t *testing.T) {
+	db := &fakeDB{results: map[string][][]driver.Value{"SUM(created_at": {{int64(2), int64(5)}}}}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+
+	since := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	created, updated, err := r.CountChangedSince(context.Background(), 4, since)
+	if err != nil {
+		t.Fatalf("CountChangedSince: %v", err)
+	}
+	if created != 2 || updated != 5 {
+		t.Errorf("got %d created, %d updated; want 2, 5", created, updated)
+	}
+
+	// Resyncs and score recalcs move updated_at, so it can't tell what changed
+	query := db.statements[0].query
+	if strings.Contains(query, "updated_at") || !strings.Contains(query, "content_changed_at >= ?") {
+		t.Errorf("query does not count on content_changed_at:\n%s", query)
+	}
+}
@@ -3,6 +3,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"search-engine/backend/internal/model"
@@ -11,13 +12,15 @@ import (
 // ContentTagRepository handles all database operations for content tags
 // This repository encapsulates tag-related database queries
 type ContentTagRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	limits model.TagLimits
 }
 
 // NewContentTagRepository creates a new ContentTagRepository instance
 // This allows dependency injection of the database connection
-func NewContentTagRepository(db *sql.DB) *ContentTagRepository {
-	return &ContentTagRepository{db: db}
+// limits are enforced on every tag write (CreateBatch, ReplaceTags)
+func NewContentTagRepository(db *sql.DB, limits model.TagLimits) *ContentTagRepository {
+	return &ContentTagRepository{db: db, limits: limits}
 }
 
 // Create inserts a new tag for a content item
@@ -43,36 +46,40 @@ func (r *ContentTagRepository) Create(tag *model.ContentTag) error {
 
 // CreateBatch inserts multiple tags for a content item efficiently
 // This reduces database round trips when adding multiple tags
-func (r *ContentTagRepository) CreateBatch(contentID int64, tags []string) error {
+// Each tag's position in the slice is stored as its ordinal
+// Duplicate tags are dropped and the tag limits applied first (see model.TagLimits)
+// Returns how many tags the limits dropped
+func (r *ContentTagRepository) CreateBatch(contentID int64, tags []string) (int, error) {
+	tags, dropped := r.limits.Apply(tags)
 	if len(tags) == 0 {
-		return nil
+		return dropped, nil
 	}
 
 	// Build query with multiple values
-	query := "INSERT INTO content_tags (content_id, tag) VALUES "
-	args := make([]interface{}, 0, len(tags)*2)
+	query := "INSERT INTO content_tags (content_id, tag, ordinal) VALUES "
+	args := make([]interface{}, 0, len(tags)*3)
 
 	for i, tag := range tags {
 		if i > 0 {
 			query += ", "
 		}
-		query += "(?, ?)"
-		args = append(args, contentID, tag)
+		query += "(?, ?, ?)"
+		args = append(args, contentID, tag, i)
 	}
 
 	_, err := r.db.Exec(query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to create content tags batch: %w", err)
+		return dropped, fmt.Errorf("failed to create content tags batch: %w", err)
 	}
 
-	return nil
+	return dropped, nil
 }
 
 // GetByContentID retrieves all tags for a specific content item
 // Returns an empty slice if no tags exist
 func (r *ContentTagRepository) GetByContentID(contentID int64) ([]*model.ContentTag, error) {
 	query := `
-		SELECT id, content_id, tag, created_at
+		SELECT id, content_id, tag, ordinal, created_at
 		FROM content_tags
 		WHERE content_id = ?
 		ORDER BY tag
@@ -86,7 +93,7 @@ func (r *ContentTagRepository) GetByContentID(contentID int64) ([]*model.Content
 	var tags []*model.ContentTag
 	for rows.Next() {
 		tag := &model.ContentTag{}
-		err := rows.Scan(&tag.ID, &tag.ContentID, &tag.Tag, &tag.CreatedAt)
+		err := rows.Scan(&tag.ID, &tag.ContentID, &tag.Tag, &tag.Ordinal, &tag.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan tag: %w", err)
 		}
@@ -119,44 +126,95 @@ func (r *ContentTagRepository) Delete(contentID int64, tag string) error {
 
 // ReplaceTags replaces all tags for a content item
 // This is a convenience method that deletes old tags and creates new ones
-func (r *ContentTagRepository) ReplaceTags(contentID int64, tags []string) error {
+// Each tag's position in the slice is stored as its ordinal
+// Duplicate tags are dropped and the tag limits applied first (see model.TagLimits)
+// Returns how many tags the limits dropped
+func (r *ContentTagRepository) ReplaceTags(contentID int64, tags []string) (int, error) {
+	tags, dropped := r.limits.Apply(tags)
+
 	// Start transaction for atomicity
 	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return dropped, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	if err := writeTags(context.Background(), tx, contentID, tags); err != nil {
+		return dropped, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return dropped, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return dropped, nil
+}
+
+// writeTags replaces the tags of a content item through db, which should be a transaction
+// Each tag's position in the slice is stored as its ordinal; limits must already be applied
+func writeTags(ctx context.Context, db execer, contentID int64, tags []string) error {
 	// Delete existing tags
 	deleteQuery := `DELETE FROM content_tags WHERE content_id = ?`
-	_, err = tx.Exec(deleteQuery, contentID)
-	if err != nil {
+	if _, err := db.ExecContext(ctx, deleteQuery, contentID); err != nil {
 		return fmt.Errorf("failed to delete existing tags: %w", err)
 	}
 
 	// Insert new tags if any
 	if len(tags) > 0 {
-		insertQuery := "INSERT INTO content_tags (content_id, tag) VALUES "
-		args := make([]interface{}, 0, len(tags)*2)
+		insertQuery := "INSERT INTO content_tags (content_id, tag, ordinal) VALUES "
+		args := make([]interface{}, 0, len(tags)*3)
 
 		for i, tag := range tags {
 			if i > 0 {
 				insertQuery += ", "
 			}
-			insertQuery += "(?, ?)"
-			args = append(args, contentID, tag)
+			insertQuery += "(?, ?, ?)"
+			args = append(args, contentID, tag, i)
 		}
 
-		_, err = tx.Exec(insertQuery, args...)
-		if err != nil {
+		if _, err := db.ExecContext(ctx, insertQuery, args...); err != nil {
 			return fmt.Errorf("failed to insert new tags: %w", err)
 		}
 	}
+	return nil
+}
 
-	// Commit transaction
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+// GetAllWithCounts returns distinct tags with how many contents carry each, most used first
+// prefix, if not empty, keeps only tags starting with it; at most limit tags are returned
+// Tags differing only in case are counted together, like the uk_content_tag collation
+func (r *ContentTagRepository) GetAllWithCounts(ctx context.Context, prefix string, limit int) ([]model.TagCount, error) {
+	query, args := tagCountsQuery(prefix, limit)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, databaseError("get tag counts", err)
 	}
+	defer rows.Close()
 
-	return nil
+	counts := make([]model.TagCount, 0, limit)
+	for rows.Next() {
+		var tc model.TagCount
+		if err := rows.Scan(&tc.Tag, &tc.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag count: %w", err)
+		}
+		counts = append(counts, tc)
+	}
+
+	return counts, rows.Err()
+}
+
+// tagCountsQuery builds the GetAllWithCounts query and its args
+// Ties are broken by tag so the order is stable across calls
+func tagCountsQuery(prefix string, limit int) (string, []interface{}) {
+	where := ""
+	args := []interface{}{}
+	if prefix != "" {
+		where = "WHERE tag LIKE ? "
+		args = append(args, likeEscaper.Replace(prefix)+"%")
+	}
+	args = append(args, limit)
+
+	query := "SELECT tag, COUNT(*) FROM content_tags " + where +
+		"GROUP BY tag ORDER BY COUNT(*) DESC, tag LIMIT ?"
+	return query, args
 }
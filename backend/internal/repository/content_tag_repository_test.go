@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTagCountsQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		prefix    string
+		limit     int
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			"no prefix",
+			"", 50,
+			"SELECT tag, COUNT(*) FROM content_tags GROUP BY tag ORDER BY COUNT(*) DESC, tag LIMIT ?",
+			[]interface{}{50},
+		},
+		{
+			"prefix",
+			"kub", 10,
+			"SELECT tag, COUNT(*) FROM content_tags WHERE tag LIKE ? GROUP BY tag ORDER BY COUNT(*) DESC, tag LIMIT ?",
+			[]interface{}{"kub%", 10},
+		},
+		{
+			"prefix wildcards are literal",
+			"100%_", 5,
+			"SELECT tag, COUNT(*) FROM content_tags WHERE tag LIKE ? GROUP BY tag ORDER BY COUNT(*) DESC, tag LIMIT ?",
+			[]interface{}{`100\%\_%`, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := tagCountsQuery(tt.prefix, tt.limit)
+			if query != tt.wantQuery {
+				t.Errorf("query = %q, want %q", query, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
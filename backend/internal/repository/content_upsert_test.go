@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"search-engine/backend/internal/model"
+	"testing"
+	"time"
+)
+
+// existingContentRow is a stored contents row in contentColumns order
+func existingContentRow(id int64, score float64) []driver.Value {
+	at := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	return []driver.Value{
+		id, int64(1), "v1", "Go Tutorial", "video",
+		int64(100), int64(10), nil,
+		nil, int64(0), int64(0),
+		at, score, nil, at, at,
+	}
+}
+
+func newUpsertContent() *model.Content {
+	return &model.Content{
+		ProviderID:  1,
+		ExternalID:  "v1",
+		Title:       "Go Tutorial",
+		Type:        model.ContentTypeVideo,
+		Views:       150,
+		PublishedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestUpsertWithTagsCreate(t *testing.T) {
+	db := &fakeDB{lastInsertID: 42}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+
+	c := newUpsertContent()
+	id, dropped, err := r.UpsertWithTags(context.Background(), c, []string{"go", "intro"})
+	if err != nil {
+		t.Fatalf("UpsertWithTags: %v", err)
+	}
+	if id != 42 || c.ID != 42 || dropped != 0 {
+		t.Errorf("got id %d (content %d), dropped %d; want 42, 0", id, c.ID, dropped)
+	}
+	if db.commits != 1 {
+		t.Errorf("commits = %d, want 1", db.commits)
+	}
+
+	// The ID comes from the upsert: the only lookup is the one before the write
+	if n := len(db.statementsLike("SELECT")); n != 1 {
+		t.Errorf("ran %d SELECTs, want only the existence check", n)
+	}
+	if n := len(db.statementsLike("ON DUPLICATE KEY UPDATE")); n != 1 {
+		t.Errorf("ran %d content upserts, want 1", n)
+	}
+	tagInserts := db.statementsLike("INSERT INTO content_tags")
+	if len(tagInserts) != 1 || tagInserts[0].args[0] != int64(42) {
+		t.Fatalf("tag inserts = %+v, want one for content 42", tagInserts)
+	}
+}
+
+func TestUpsertWithTagsUpdate(t *testing.T) {
+	// On a duplicate key, LAST_INSERT_ID(id) makes the driver report the existing ID
+	db := &fakeDB{existing: existingContentRow(7, 3.5), lastInsertID: 7}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+
+	c := newUpsertContent()
+	id, _, err := r.UpsertWithTags(context.Background(), c, []string{"go"})
+	if err != nil {
+		t.Fatalf("UpsertWithTags: %v", err)
+	}
+	if id != 7 || c.ID != 7 {
+		t.Errorf("got id %d (content %d), want the existing 7", id, c.ID)
+	}
+	// Providers don't send scores, so the stored one is kept
+	if c.Score != 3.5 {
+		t.Errorf("score = %v, want the stored 3.5", c.Score)
+	}
+
+	// A locking read of a missing row takes a gap lock that parallel syncs deadlock on
+	if locks := db.statementsLike("FOR UPDATE"); len(locks) != 0 {
+		t.Errorf("existence check locked rows: %+v", locks)
+	}
+	if n := len(db.statementsLike("UPDATE contents")); n != 0 {
+		t.Errorf("ran %d separate updates, want the upsert only", n)
+	}
+	upserts := db.statementsLike("ON DUPLICATE KEY UPDATE")
+	if len(upserts) != 1 || upserts[0].args[11] != 3.5 {
+		t.Errorf("upserts = %+v, want one keeping score 3.5", upserts)
+	}
+	tagInserts := db.statementsLike("INSERT INTO content_tags")
+	if len(tagInserts) != 1 || tagInserts[0].args[0] != int64(7) {
+		t.Errorf("tag inserts = %+v, want one for content 7", tagInserts)
+	}
+}
+
+func TestUpsertWithTagsSyncTime(t *testing.T) {
+	syncedAt := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		lastSyncedAt *time.Time
+		want         driver.Value
+	}{
+		{"provider sync", &syncedAt, syncedAt},
+		{"manual ingest", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{existing: existingContentRow(7, 3.5), lastInsertID: 7}
+			r := NewContentRepository(sql.OpenDB(db), 3)
+
+			c := newUpsertContent()
+			c.LastSyncedAt = tt.lastSyncedAt
+			if _, _, err := r.UpsertWithTags(context.Background(), c, nil); err != nil {
+				t.Fatalf("UpsertWithTags: %v", err)
+			}
+			if c.LastSyncedAt != tt.lastSyncedAt {
+				t.Errorf("LastSyncedAt = %v, want %v", c.LastSyncedAt, tt.lastSyncedAt)
+			}
+			// A NULL keeps the stored sync time of a row that was synced before
+			upserts := db.statementsLike("last_synced_at = COALESCE(VALUES(last_synced_at), last_synced_at)")
+			if len(upserts) != 1 || upserts[0].args[12] != tt.want {
+				t.Errorf("upserts = %+v, want one writing last_synced_at %v", upserts, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpsertWithTagsKeepsTagsWhenNoneSent(t *testing.T) {
+	db := &fakeDB{existing: existingContentRow(7, 3.5), lastInsertID: 7}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+
+	if _, _, err := r.UpsertWithTags(context.Background(), newUpsertContent(), nil); err != nil {
+		t.Fatalf("UpsertWithTags: %v", err)
+	}
+	if n := len(db.statementsLike("content_tags")); n != 0 {
+		t.Errorf("ran %d tag statements for an item sent without tags, want 0", n)
+	}
+	if db.commits != 1 {
+		t.Errorf("commits = %d, want 1", db.commits)
+	}
+}
+
+func TestUpsertWithTagsIsAtomic(t *testing.T) {
+	for _, failOn := range []string{"INSERT INTO content_tags", "DELETE FROM content_tags", "INSERT INTO contents", "FROM contents"} {
+		t.Run(failOn, func(t *testing.T) {
+			db := &fakeDB{lastInsertID: 42, failOn: failOn}
+			r := NewContentRepository(sql.OpenDB(db), 3)
+
+			if _, _, err := r.UpsertWithTags(context.Background(), newUpsertContent(), []string{"go"}); err == nil {
+				t.Fatal("UpsertWithTags succeeded despite a failed statement")
+			}
+			if db.commits != 0 || db.rollbacks != 1 {
+				t.Errorf("commits %d, rollbacks %d; want the transaction rolled back", db.commits, db.rollbacks)
+			}
+			// Every write ran inside the rolled back transaction, so none of them stuck
+			for _, s := range db.statements {
+				if !s.inTx {
+					t.Errorf("statement ran outside the transaction: %s", s.query)
+				}
+			}
+		})
+	}
+}
+
+func TestUpsertWithTagsAppliesTagLimits(t *testing.T) {
+	db := &fakeDB{lastInsertID: 5}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+	r.SetTagLimits(model.TagLimits{MaxPerContent: 2})
+
+	_, dropped, err := r.UpsertWithTags(context.Background(), newUpsertContent(), []string{"go", "intro", "extra"})
+	if err != nil {
+		t.Fatalf("UpsertWithTags: %v", err)
+	}
+	if dropped != 1 {
+		t.Errorf("dropped = %d, want 1", dropped)
+	}
+	// Each tag row carries content_id, tag and ordinal
+	if tagInserts := db.statementsLike("INSERT INTO content_tags"); len(tagInserts) != 1 || len(tagInserts[0].args) != 6 {
+		t.Errorf("tag inserts = %+v, want two tags written", tagInserts)
+	}
+}
+
+func TestUpsertWithTagsMarksOnlyRealChanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    []driver.Value
+		views       int
+		wantChanged bool
+	}{
+		{"new item", nil, 150, true},
+		{"views changed", existingContentRow(7, 3.5), 150, true},
+		{"resent unchanged", existingContentRow(7, 3.5), 100, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{existing: tt.existing, lastInsertID: 7}
+			r := NewContentRepository(sql.OpenDB(db), 3)
+
+			c := newUpsertContent()
+			c.Views, c.Likes = tt.views, 10
+			if _, _, err := r.UpsertWithTags(context.Background(), c, nil); err != nil {
+				t.Fatalf("UpsertWithTags: %v", err)
+			}
+			upserts := db.statementsLike("content_changed_at = IF(?")
+			if len(upserts) != 1 {
+				t.Fatalf("upserts = %+v, want one that may move content_changed_at", upserts)
+			}
+			if got := upserts[0].args[len(upserts[0].args)-1]; got != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", got, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestUpsertWithTagsKeepsPublishedAtOfClampedResync(t *testing.T) {
+	// A provider keeps sending the same item dated a month ahead; each sync clamps it to its own time
+	firstSync := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	secondSync := firstSync.Add(time.Hour)
+	clampedAt := func(now time.Time) *model.Content {
+		c := newUpsertContent()
+		c.PublishedAt, c.PublishedAtClamped = now, true
+		return c
+	}
+
+	db := &fakeDB{lastInsertID: 7}
+	r := NewContentRepository(sql.OpenDB(db), 3)
+	if _, _, err := r.UpsertWithTags(context.Background(), clampedAt(firstSync), nil); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if got := db.statementsLike("ON DUPLICATE KEY UPDATE")[0].args[10]; got != firstSync {
+		t.Fatalf("first sync inserted published_at %v, want the sync time %s", got, firstSync)
+	}
+
+	stored := existingContentRow(7, 3.5)
+	stored[11] = firstSync
+	db = &fakeDB{existing: stored, lastInsertID: 7}
+	r = NewContentRepository(sql.OpenDB(db), 3)
+	c := clampedAt(secondSync)
+	if _, _, err := r.UpsertWithTags(context.Background(), c, nil); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if got := db.statementsLike("ON DUPLICATE KEY UPDATE")[0].args[10]; got != firstSync || !c.PublishedAt.Equal(firstSync) {
+		t.Errorf("second sync wrote published_at %v, want the stored %s", got, firstSync)
+	}
+}
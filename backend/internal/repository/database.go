@@ -3,10 +3,13 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"search-engine/backend/internal/config"
+	apperrors "search-engine/backend/internal/errors"
+	"sync/atomic"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql" // MySQL driver - imported for side effects
@@ -34,27 +37,72 @@ func Connect(cfg *config.Config) error {
 
 	// Set connection pool settings
 	// These are important for performance and resource management
+	applyPoolSettings(DB, cfg.Database)
 
+	// Test the connection by pinging the database
+	// This ensures the connection string is correct and database is accessible
+	// The database may still be starting (e.g. under docker-compose), so failed pings are retried
+	if err := pingWithRetry(context.Background(), DB, cfg.Database.ConnectMaxRetries, cfg.Database.ConnectRetryDelay); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	log.Println("Database connection established successfully")
+	return nil
+}
+
+// connPool is the part of *sql.DB that configures its connection pool
+type connPool interface {
+	SetMaxOpenConns(n int)
+	SetMaxIdleConns(n int)
+	SetConnMaxLifetime(d time.Duration)
+}
+
+// applyPoolSettings sizes the connection pool from the database config
+func applyPoolSettings(pool connPool, cfg config.DatabaseConfig) {
 	// SetMaxOpenConns sets the maximum number of open connections to the database
 	// Too high = resource exhaustion, too low = connection starvation
-	DB.SetMaxOpenConns(25)
+	pool.SetMaxOpenConns(cfg.MaxOpenConns)
 
 	// SetMaxIdleConns sets the maximum number of connections in the idle connection pool
 	// Keeping some idle connections ready improves response time
-	DB.SetMaxIdleConns(5)
+	pool.SetMaxIdleConns(cfg.MaxIdleConns)
 
 	// SetConnMaxLifetime sets the maximum amount of time a connection may be reused
 	// This prevents using stale connections that might have been closed by the server
-	DB.SetConnMaxLifetime(5 * time.Minute)
+	pool.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetimeSeconds) * time.Second)
+}
 
-	// Test the connection by pinging the database
-	// This ensures the connection string is correct and database is accessible
-	if err := DB.Ping(); err != nil {
-		return fmt.Errorf("failed to ping database: %w", err)
-	}
+// maxConnectRetryDelay caps the backoff between startup pings
+const maxConnectRetryDelay = 30 * time.Second
 
-	log.Println("Database connection established successfully")
-	return nil
+// pinger is the part of *sql.DB that checks the database is reachable
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// pingWithRetry pings db, retrying up to retries times with exponential backoff from delay
+// Returns the last ping error once the retries run out, or ctx's error if it is done first
+func pingWithRetry(ctx context.Context, db pinger, retries int, delay time.Duration) error {
+	for attempt := 0; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil || attempt >= retries || ctx.Err() != nil {
+			return err
+		}
+
+		wait := delay
+		for i := 0; i < attempt && wait < maxConnectRetryDelay; i++ {
+			wait *= 2
+		}
+		wait = min(wait, maxConnectRetryDelay)
+		log.Printf("Database not reachable (attempt %d of %d), retrying in %s: %v", attempt+1, retries+1, wait, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
 }
 
 // Close closes the database connection
@@ -72,3 +120,38 @@ func Close() error {
 func GetDB() *sql.DB {
 	return DB
 }
+
+// connErrorCooldown is how long a connection failure keeps the instance not ready
+// Stops readiness from flapping while the database is recovering
+const connErrorCooldown = 5 * time.Second
+
+// lastConnErrorAt holds the UnixNano time of the last connection-level failure (0 if none)
+var lastConnErrorAt atomic.Int64
+
+// databaseError wraps a database error as an AppError, recording connection failures
+// Repositories use it instead of apperrors.NewDatabaseError so readiness can react to outages
+func databaseError(operation string, err error) *apperrors.AppError {
+	if apperrors.IsConnectionError(err) {
+		lastConnErrorAt.Store(time.Now().UnixNano())
+	}
+	return apperrors.NewDatabaseError(operation, err)
+}
+
+// Ready reports whether the database can serve requests
+// It fails if the database can't be pinged or a connection failure was seen within
+// the cooldown, so load balancers stop routing here until the database recovers
+func Ready(ctx context.Context) error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if err := DB.PingContext(ctx); err != nil {
+		if ctx.Err() == nil {
+			lastConnErrorAt.Store(time.Now().UnixNano())
+		}
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+	if last := lastConnErrorAt.Load(); last != 0 && time.Since(time.Unix(0, last)) < connErrorCooldown {
+		return fmt.Errorf("database connection failed recently")
+	}
+	return nil
+}
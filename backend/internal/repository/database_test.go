@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"search-engine/backend/internal/config"
+	"testing"
+	"time"
+)
+
+// recordingPool records the pool settings applied to it
+type recordingPool struct {
+	maxOpen, maxIdle int
+	maxLifetime      time.Duration
+}
+
+func (p *recordingPool) SetMaxOpenConns(n int)              { p.maxOpen = n }
+func (p *recordingPool) SetMaxIdleConns(n int)              { p.maxIdle = n }
+func (p *recordingPool) SetConnMaxLifetime(d time.Duration) { p.maxLifetime = d }
+
+func TestApplyPoolSettings(t *testing.T) {
+	pool := &recordingPool{}
+	applyPoolSettings(pool, config.DatabaseConfig{MaxOpenConns: 40, MaxIdleConns: 10, ConnMaxLifetimeSeconds: 90})
+
+	if pool.maxOpen != 40 || pool.maxIdle != 10 || pool.maxLifetime != 90*time.Second {
+		t.Errorf("applied open %d, idle %d, lifetime %s; want 40, 10, 1m30s", pool.maxOpen, pool.maxIdle, pool.maxLifetime)
+	}
+}
+
+func TestApplyPoolSettingsToSQLDB(t *testing.T) {
+	// sql.Open doesn't connect, so the pool can be inspected without a database
+	db, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/db")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	applyPoolSettings(db, config.DatabaseConfig{MaxOpenConns: 7, MaxIdleConns: 2, ConnMaxLifetimeSeconds: 60})
+	if got := db.Stats().MaxOpenConnections; got != 7 {
+		t.Errorf("MaxOpenConnections = %d, want 7", got)
+	}
+}
+
+// flakyPinger fails the first failures pings and counts every ping
+type flakyPinger struct {
+	failures int
+	pings    int
+}
+
+func (p *flakyPinger) PingContext(ctx context.Context) error {
+	p.pings++
+	if p.pings <= p.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestPingWithRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		retries   int
+		wantPings int
+		wantErr   bool
+	}{
+		{"reachable at once", 0, 3, 1, false},
+		{"reachable after retries", 2, 3, 3, false},
+		{"retries run out", 5, 3, 4, true},
+		{"retries disabled", 1, 0, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &flakyPinger{failures: tt.failures}
+			err := pingWithRetry(context.Background(), db, tt.retries, time.Millisecond)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("pingWithRetry() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if db.pings != tt.wantPings {
+				t.Errorf("pinged %d times, want %d", db.pings, tt.wantPings)
+			}
+		})
+	}
+}
+
+func TestPingWithRetryStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	db := &flakyPinger{failures: 10}
+	if err := pingWithRetry(ctx, db, 10, time.Hour); err == nil {
+		t.Fatal("pingWithRetry() succeeded with a cancelled context")
+	}
+	if db.pings != 1 {
+		t.Errorf("pinged %d times after the context was done, want 1", db.pings)
+	}
+}
+
+// countingPinger counts the pings passed on to a real database handle
+type countingPinger struct {
+	db    *sql.DB
+	pings int
+}
+
+func (p *countingPinger) PingContext(ctx context.Context) error {
+	p.pings++
+	return p.db.PingContext(ctx)
+}
+
+func TestPingWithRetryUnreachableDatabase(t *testing.T) {
+	// Nothing listens on port 1, so every ping is refused straight away
+	db, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/db?timeout=1s")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	counter := &countingPinger{db: db}
+	if err := pingWithRetry(context.Background(), counter, 2, time.Millisecond); err == nil {
+		t.Fatal("pingWithRetry() reached an unreachable database")
+	}
+	if counter.pings != 3 {
+		t.Errorf("pinged %d times, want 3 (the first ping and 2 retries)", counter.pings)
+	}
+}
+
+func TestConnectUnreachableDatabase(t *testing.T) {
+	cfg := &config.Config{Database: config.DatabaseConfig{
+		Host: "127.0.0.1", Port: "1", User: "user", Password: "pass", Name: "db",
+		ConnectMaxRetries: 2, ConnectRetryDelay: time.Millisecond,
+	}}
+	defer func() {
+		Close()
+		DB = nil
+	}()
+
+	start := time.Now()
+	if err := Connect(cfg); err == nil {
+		t.Fatal("Connect() succeeded against an unreachable database")
+	}
+	// The two retries back off 1ms then 2ms, so giving up takes moments
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Connect() took %s to give up", elapsed)
+	}
+}
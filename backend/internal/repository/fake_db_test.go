@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// fakeStatement is a statement the fake database ran, and whether a transaction was open
+type fakeStatement struct {
+	query string
+	args  []driver.Value
+	inTx  bool
+}
+
+// fakeDB is a scripted database/sql backend: it records statements instead of running them
+// A query containing a key of results returns those rows; any other query returns existing,
+// a contents row (nil for none). lastInsertID is the ID writes report, and any statement
+// containing failOn fails. latency stands in for a round trip to the database on every statement
+type fakeDB struct {
+	existing     []driver.Value
+	results      map[string][][]driver.Value
+	lastInsertID int64
+	failOn       string
+	latency      time.Duration
+
+	inTx       bool
+	statements []fakeStatement
+	commits    int
+	rollbacks  int
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return nil, errors.New("use the connector") }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.inTx = true
+	return fakeTx{db: c.db}, nil
+}
+
+type fakeTx struct{ db *fakeDB }
+
+func (tx fakeTx) Commit() error {
+	tx.db.inTx = false
+	tx.db.commits++
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.db.inTx = false
+	tx.db.rollbacks++
+	return nil
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) record(args []driver.Value) error {
+	if s.db.latency > 0 {
+		time.Sleep(s.db.latency)
+	}
+	s.db.statements = append(s.db.statements, fakeStatement{query: s.query, args: args, inTx: s.db.inTx})
+	if s.db.failOn != "" && strings.Contains(s.query, s.db.failOn) {
+		return errors.New("lock wait timeout exceeded")
+	}
+	return nil
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if err := s.record(args); err != nil {
+		return nil, err
+	}
+	return fakeResult{lastInsertID: s.db.lastInsertID}, nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if err := s.record(args); err != nil {
+		return nil, err
+	}
+	for fragment, rows := range s.db.results {
+		if strings.Contains(s.query, fragment) {
+			return &fakeRows{rows: rows}, nil
+		}
+	}
+	if s.db.existing == nil {
+		return &fakeRows{}, nil
+	}
+	return &fakeRows{rows: [][]driver.Value{s.db.existing}}, nil
+}
+
+type fakeResult struct{ lastInsertID int64 }
+
+func (r fakeResult) LastInsertId() (int64, error) { return r.lastInsertID, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return 1, nil }
+
+// fakeRows returns rows in order; only the column count matters to database/sql
+type fakeRows struct {
+	rows [][]driver.Value
+	next int
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(r.rows[0]))
+}
+func (r *fakeRows) Close() error { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
+
+// statementsLike returns the recorded statements whose query contains fragment
+func (db *fakeDB) statementsLike(fragment string) []fakeStatement {
+	var matched []fakeStatement
+	for _, s := range db.statements {
+		if strings.Contains(s.query, fragment) {
+			matched = append(matched, s)
+		}
+	}
+	return matched
+}
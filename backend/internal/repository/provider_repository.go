@@ -3,14 +3,37 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	apperrors "search-engine/backend/internal/errors"
 	"search-engine/backend/internal/model"
+	"strings"
 	"time"
+
+	"github.com/go-sql-driver/mysql"
 )
 
+// mysqlErrDuplicateEntry is the MySQL error number for unique key violations
+const mysqlErrDuplicateEntry = 1062
+
+// isDuplicateKeyError reports whether err is a MySQL unique constraint violation
+func isDuplicateKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
+
+// duplicateProviderError maps a unique key violation on providers to a conflict error
+// providers has two unique keys besides the primary key: uk_providers_url and the one on name
+func duplicateProviderError(err error, p *model.Provider) *apperrors.AppError {
+	if strings.Contains(err.Error(), "uk_providers_url") {
+		return apperrors.NewDuplicateProviderURLError(p.URL, "")
+	}
+	return apperrors.NewDuplicateProviderNameError(p.Name)
+}
+
 // ErrProviderNotFound is kept for backward compatibility
 // Use apperrors.ErrProviderNotFound instead
 var ErrProviderNotFound = apperrors.ErrProviderNotFound
@@ -21,6 +44,71 @@ type ProviderRepository struct {
 	db *sql.DB
 }
 
+// providerColumns lists the providers columns in the order scanProvider expects
+const providerColumns = `id, name, url, format, rate_limit_per_minute, enabled,
+		       stale_after_minutes, field_mapping, last_fetched_at, created_at, updated_at`
+
+// scanProvider scans a single providers row selected with providerColumns
+func scanProvider(row rowScanner) (*model.Provider, error) {
+	p := &model.Provider{}
+	var staleAfterMinutes sql.NullInt64
+	var fieldMapping sql.NullString
+	var lastFetchedAt sql.NullTime
+
+	err := row.Scan(
+		&p.ID,
+		&p.Name,
+		&p.URL,
+		&p.Format,
+		&p.RateLimitPerMinute,
+		&p.Enabled,
+		&staleAfterMinutes,
+		&fieldMapping,
+		&lastFetchedAt,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if staleAfterMinutes.Valid {
+		minutes := int(staleAfterMinutes.Int64)
+		p.StaleAfterMinutes = &minutes
+	}
+	if p.FieldMapping, err = decodeFieldMapping(fieldMapping); err != nil {
+		return nil, err
+	}
+	if lastFetchedAt.Valid {
+		p.LastFetchedAt = &lastFetchedAt.Time
+	}
+	return p, nil
+}
+
+// encodeFieldMapping returns the field_mapping column value for m, NULL when m is nil
+func encodeFieldMapping(m *model.FieldMapping) (any, error) {
+	if m == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode field mapping: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// decodeFieldMapping parses a field_mapping column value, nil when the column is NULL
+func decodeFieldMapping(value sql.NullString) (*model.FieldMapping, error) {
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+	m := &model.FieldMapping{}
+	if err := json.Unmarshal([]byte(value.String), m); err != nil {
+		return nil, fmt.Errorf("failed to decode field mapping: %w", err)
+	}
+	return m, nil
+}
+
 // NewProviderRepository creates a new ProviderRepository instance
 // This allows dependency injection of the database connection
 func NewProviderRepository(db *sql.DB) *ProviderRepository {
@@ -29,18 +117,33 @@ func NewProviderRepository(db *sql.DB) *ProviderRepository {
 
 // Create inserts a new provider into the database
 // Returns the created provider with its generated ID
+// New providers always start enabled
 func (r *ProviderRepository) Create(p *model.Provider) error {
-	// Validate provider before inserting
-	if err := model.ValidateProvider(p); err != nil {
+	// Validate provider before inserting; checkURLAvailable refuses any URL already in use
+	if err := model.ValidateProvider(p, nil); err != nil {
 		return apperrors.NewValidationErrorWithDetails("Provider validation failed", err.Error())
 	}
 
+	// Refuse a second provider pointing at the same feed
+	// Syncing both would double-ingest identical content under different provider IDs
+	if err := r.checkURLAvailable(p.URL, 0); err != nil {
+		return err
+	}
+
+	fieldMapping, err := encodeFieldMapping(p.FieldMapping)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO providers (name, url, format, rate_limit_per_minute)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO providers (name, url, format, rate_limit_per_minute, field_mapping)
+		VALUES (?, ?, ?, ?, ?)
 	`
-	result, err := r.db.Exec(query, p.Name, p.URL, p.Format, p.RateLimitPerMinute)
+	result, err := r.db.Exec(query, p.Name, p.URL, p.Format, p.RateLimitPerMinute, fieldMapping)
 	if err != nil {
+		if isDuplicateKeyError(err) {
+			return duplicateProviderError(err, p)
+		}
 		return fmt.Errorf("failed to create provider: %w", err)
 	}
 
@@ -50,6 +153,7 @@ func (r *ProviderRepository) Create(p *model.Provider) error {
 	}
 
 	p.ID = int(id)
+	p.Enabled = true
 	return nil
 }
 
@@ -57,33 +161,16 @@ func (r *ProviderRepository) Create(p *model.Provider) error {
 // Returns sql.ErrNoRows if provider is not found
 func (r *ProviderRepository) GetByID(id int) (*model.Provider, error) {
 	query := `
-		SELECT id, name, url, format, rate_limit_per_minute, 
-		       last_fetched_at, created_at, updated_at
+		SELECT ` + providerColumns + `
 		FROM providers
 		WHERE id = ?
 	`
-	p := &model.Provider{}
-	var lastFetchedAt sql.NullTime
-
-	err := r.db.QueryRow(query, id).Scan(
-		&p.ID,
-		&p.Name,
-		&p.URL,
-		&p.Format,
-		&p.RateLimitPerMinute,
-		&lastFetchedAt,
-		&p.CreatedAt,
-		&p.UpdatedAt,
-	)
+	p, err := scanProvider(r.db.QueryRow(query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, apperrors.ErrProviderNotFound
 		}
-		return nil, apperrors.NewDatabaseError("get provider by id", err)
-	}
-
-	if lastFetchedAt.Valid {
-		p.LastFetchedAt = &lastFetchedAt.Time
+		return nil, databaseError("get provider by id", err)
 	}
 
 	return p, nil
@@ -93,44 +180,60 @@ func (r *ProviderRepository) GetByID(id int) (*model.Provider, error) {
 // Returns sql.ErrNoRows if provider is not found
 func (r *ProviderRepository) GetByName(name string) (*model.Provider, error) {
 	query := `
-		SELECT id, name, url, format, rate_limit_per_minute, 
-		       last_fetched_at, created_at, updated_at
+		SELECT ` + providerColumns + `
 		FROM providers
 		WHERE name = ?
 	`
-	p := &model.Provider{}
-	var lastFetchedAt sql.NullTime
-
-	err := r.db.QueryRow(query, name).Scan(
-		&p.ID,
-		&p.Name,
-		&p.URL,
-		&p.Format,
-		&p.RateLimitPerMinute,
-		&lastFetchedAt,
-		&p.CreatedAt,
-		&p.UpdatedAt,
-	)
+	p, err := scanProvider(r.db.QueryRow(query, name))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, apperrors.ErrProviderNotFound
 		}
-		return nil, apperrors.NewDatabaseError("get provider by name", err)
+		return nil, databaseError("get provider by name", err)
 	}
 
-	if lastFetchedAt.Valid {
-		p.LastFetchedAt = &lastFetchedAt.Time
+	return p, nil
+}
+
+// GetByURL retrieves a provider by its feed URL
+// Used to detect an existing provider before creating a duplicate under a new name
+func (r *ProviderRepository) GetByURL(url string) (*model.Provider, error) {
+	query := `
+		SELECT ` + providerColumns + `
+		FROM providers
+		WHERE url = ?
+	`
+	p, err := scanProvider(r.db.QueryRow(query, url))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrProviderNotFound
+		}
+		return nil, databaseError("get provider by url", err)
 	}
 
 	return p, nil
 }
 
+// checkURLAvailable returns a conflict error if a provider other than exceptID already uses url
+func (r *ProviderRepository) checkURLAvailable(url string, exceptID int) error {
+	existing, err := r.GetByURL(url)
+	if err != nil {
+		if errors.Is(err, ErrProviderNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != exceptID {
+		return apperrors.NewDuplicateProviderURLError(url, existing.Name)
+	}
+	return nil
+}
+
 // GetAll retrieves all providers from the database
 // Returns an empty slice if no providers exist
 func (r *ProviderRepository) GetAll() ([]*model.Provider, error) {
 	query := `
-		SELECT id, name, url, format, rate_limit_per_minute, 
-		       last_fetched_at, created_at, updated_at
+		SELECT ` + providerColumns + `
 		FROM providers
 		ORDER BY name
 	`
@@ -142,33 +245,51 @@ func (r *ProviderRepository) GetAll() ([]*model.Provider, error) {
 
 	var providers []*model.Provider
 	for rows.Next() {
-		p := &model.Provider{}
-		var lastFetchedAt sql.NullTime
-
-		err := rows.Scan(
-			&p.ID,
-			&p.Name,
-			&p.URL,
-			&p.Format,
-			&p.RateLimitPerMinute,
-			&lastFetchedAt,
-			&p.CreatedAt,
-			&p.UpdatedAt,
-		)
+		p, err := scanProvider(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan provider: %w", err)
 		}
+		providers = append(providers, p)
+	}
 
-		if lastFetchedAt.Valid {
-			p.LastFetchedAt = &lastFetchedAt.Time
-		}
+	return providers, rows.Err()
+}
+
+// GetPaginated retrieves one page of providers ordered by name
+func (r *ProviderRepository) GetPaginated(ctx context.Context, limit, offset int) ([]*model.Provider, error) {
+	query := `
+		SELECT ` + providerColumns + `
+		FROM providers
+		ORDER BY name
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, databaseError("get providers page", err)
+	}
+	defer rows.Close()
 
+	providers := make([]*model.Provider, 0, limit)
+	for rows.Next() {
+		p, err := scanProvider(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan provider: %w", err)
+		}
 		providers = append(providers, p)
 	}
 
 	return providers, rows.Err()
 }
 
+// CountAll returns the number of providers
+func (r *ProviderRepository) CountAll(ctx context.Context) (int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM providers`).Scan(&total); err != nil {
+		return 0, databaseError("count providers", err)
+	}
+	return total, nil
+}
+
 // UpdateLastFetched updates the last_fetched_at timestamp for a provider
 // This is used to track when data was last successfully fetched from the provider
 func (r *ProviderRepository) UpdateLastFetched(id int, fetchedAt time.Time) error {
@@ -188,18 +309,31 @@ func (r *ProviderRepository) UpdateLastFetched(id int, fetchedAt time.Time) erro
 // Only updates non-zero fields
 func (r *ProviderRepository) Update(p *model.Provider) error {
 	// Validate provider before updating
-	if err := model.ValidateProvider(p); err != nil {
+	if err := model.ValidateProvider(p, nil); err != nil {
 		return apperrors.NewValidationErrorWithDetails("Provider validation failed", err.Error())
 	}
 
+	if err := r.checkURLAvailable(p.URL, p.ID); err != nil {
+		return err
+	}
+
+	fieldMapping, err := encodeFieldMapping(p.FieldMapping)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE providers
 		SET name = ?, url = ?, format = ?, rate_limit_per_minute = ?,
+		    enabled = ?, stale_after_minutes = ?, field_mapping = ?,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
-	_, err := r.db.Exec(query, p.Name, p.URL, p.Format, p.RateLimitPerMinute, p.ID)
+	_, err = r.db.Exec(query, p.Name, p.URL, p.Format, p.RateLimitPerMinute, p.Enabled, p.StaleAfterMinutes, fieldMapping, p.ID)
 	if err != nil {
+		if isDuplicateKeyError(err) {
+			return duplicateProviderError(err, p)
+		}
 		return fmt.Errorf("failed to update provider: %w", err)
 	}
 	return nil
@@ -207,11 +341,15 @@ func (r *ProviderRepository) Update(p *model.Provider) error {
 
 // Delete removes a provider from the database
 // Note: This will cascade delete all associated contents due to foreign key constraint
+// Returns apperrors.ErrProviderNotFound if no provider has the ID
 func (r *ProviderRepository) Delete(id int) error {
 	query := `DELETE FROM providers WHERE id = ?`
-	_, err := r.db.Exec(query, id)
+	result, err := r.db.Exec(query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete provider: %w", err)
 	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return apperrors.ErrProviderNotFound
+	}
 	return nil
 }
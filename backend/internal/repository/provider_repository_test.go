@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net/http"
+	"reflect"
+	"search-engine/backend/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestFieldMappingColumnRoundTrip(t *testing.T) {
+	mapping := &model.FieldMapping{ItemsPath: "data.items", ID: "uid", Title: "headline", DefaultType: "video", PublishedAt: "released"}
+
+	value, err := encodeFieldMapping(mapping)
+	if err != nil {
+		t.Fatalf("encodeFieldMapping: %v", err)
+	}
+	encoded, ok := value.(string)
+	if !ok {
+		t.Fatalf("encodeFieldMapping() = %T, want string", value)
+	}
+
+	decoded, err := decodeFieldMapping(sql.NullString{String: encoded, Valid: true})
+	if err != nil {
+		t.Fatalf("decodeFieldMapping: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, mapping) {
+		t.Errorf("round trip = %+v, want %+v", decoded, mapping)
+	}
+}
+
+func TestFieldMappingColumnNull(t *testing.T) {
+	if value, err := encodeFieldMapping(nil); err != nil || value != nil {
+		t.Errorf("encodeFieldMapping(nil) = %v, %v, want nil, nil", value, err)
+	}
+	if m, err := decodeFieldMapping(sql.NullString{}); err != nil || m != nil {
+		t.Errorf("decodeFieldMapping(NULL) = %v, %v, want nil, nil", m, err)
+	}
+}
+
+func TestDuplicateProviderError(t *testing.T) {
+	p := &model.Provider{Name: "provider3", URL: "https://example.com/feed"}
+
+	tests := []struct {
+		name        string
+		err         error
+		wantMessage string
+	}{
+		{"name key", &mysql.MySQLError{Number: mysqlErrDuplicateEntry, Message: "Duplicate entry 'provider3' for key 'providers.name'"}, "Provider name already registered"},
+		{"url key", &mysql.MySQLError{Number: mysqlErrDuplicateEntry, Message: "Duplicate entry 'https://example.com/feed' for key 'providers.uk_providers_url'"}, "Provider URL already registered"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !isDuplicateKeyError(tt.err) {
+				t.Fatalf("isDuplicateKeyError(%v) = false", tt.err)
+			}
+			appErr := duplicateProviderError(tt.err, p)
+			if appErr.StatusCode != http.StatusConflict || appErr.Message != tt.wantMessage {
+				t.Errorf("duplicateProviderError() = %d %q, want 409 %q", appErr.StatusCode, appErr.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+// providerRow is a stored providers row in providerColumns order
+func providerRow(id int64, name string) []driver.Value {
+	at := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	return []driver.Value{id, name, "https://example.com/" + name, "json", int64(60), true, nil, nil, nil, at, at}
+}
+
+func TestGetPaginatedProviders(t *testing.T) {
+	db := &fakeDB{results: map[string][][]driver.Value{"FROM providers": {providerRow(3, "alpha"), providerRow(1, "beta")}}}
+	r := NewProviderRepository(sql.OpenDB(db))
+
+	providers, err := r.GetPaginated(context.Background(), 2, 4)
+	if err != nil {
+		t.Fatalf("GetPaginated: %v", err)
+	}
+	if len(providers) != 2 || providers[0].Name != "alpha" || providers[1].ID != 1 {
+		t.Errorf("providers = %+v, want alpha then beta as returned", providers)
+	}
+
+	queries := db.statementsLike("FROM providers")
+	if len(queries) != 1 {
+		t.Fatalf("queries = %+v, want one", queries)
+	}
+	// Pages are ordered by name, so they stay stable as providers are added
+	if query := strings.Join(strings.Fields(queries[0].query), " "); !strings.HasSuffix(query, "FROM providers ORDER BY name LIMIT ? OFFSET ?") {
+		t.Errorf("query = %q, want it ordered by name and paged", query)
+	}
+	if want := []driver.Value{int64(2), int64(4)}; !reflect.DeepEqual(queries[0].args, want) {
+		t.Errorf("args = %v, want limit and offset %v", queries[0].args, want)
+	}
+}
+
+func TestGetPaginatedProvidersPastTheEnd(t *testing.T) {
+	r := NewProviderRepository(sql.OpenDB(&fakeDB{}))
+
+	providers, err := r.GetPaginated(context.Background(), 20, 100)
+	if err != nil {
+		t.Fatalf("GetPaginated: %v", err)
+	}
+	if providers == nil || len(providers) != 0 {
+		t.Errorf("providers = %#v, want an empty, non-nil page", providers)
+	}
+}
+
+func TestCountAllProviders(t *testing.T) {
+	db := &fakeDB{results: map[string][][]driver.Value{"SELECT COUNT(*) FROM providers": {{int64(42)}}}}
+	r := NewProviderRepository(sql.OpenDB(db))
+
+	total, err := r.CountAll(context.Background())
+	if err != nil || total != 42 {
+		t.Errorf("CountAll() = %d, %v; want 42", total, err)
+	}
+
+	db.failOn = "COUNT(*)"
+	if _, err := r.CountAll(context.Background()); err == nil {
+		t.Error("CountAll succeeded despite a failed query")
+	}
+}
@@ -0,0 +1,171 @@
+// search_filter_builder.go - WHERE clause assembly for content searches
+// Keeps clauses and their args together so new filters can't get them out of order
+package repository
+
+import (
+	"search-engine/backend/internal/model"
+	"strings"
+	"time"
+)
+
+// searchFilterBuilder accumulates search conditions and their args
+// Each Add method appends a fixed, parameterized clause; user input only ever
+// travels in args, never in the SQL text
+type searchFilterBuilder struct {
+	clauses []string
+	args    []interface{}
+}
+
+// newSearchFilterBuilder creates an empty searchFilterBuilder
+func newSearchFilterBuilder() *searchFilterBuilder {
+	return &searchFilterBuilder{
+		clauses: []string{},
+		args:    []interface{}{},
+	}
+}
+
+// add appends a clause together with the args for its placeholders
+func (b *searchFilterBuilder) add(clause string, args ...interface{}) *searchFilterBuilder {
+	b.clauses = append(b.clauses, clause)
+	b.args = append(b.args, args...)
+	return b
+}
+
+// AddFullText matches titles against a boolean-mode FULLTEXT query
+func (b *searchFilterBuilder) AddFullText(booleanQuery string) *searchFilterBuilder {
+	return b.add("MATCH(title) AGAINST(? IN BOOLEAN MODE)", booleanQuery)
+}
+
+// AddLike matches titles containing every term of a short query
+func (b *searchFilterBuilder) AddLike(query string) *searchFilterBuilder {
+	clause, args := buildLikeClause(query)
+	return b.add(clause, args...)
+}
+
+// AddTitleFragments matches titles containing any of the fragments
+// Used to gather candidates for fuzzy matching, which is too costly to run in SQL
+func (b *searchFilterBuilder) AddTitleFragments(fragments []string) *searchFilterBuilder {
+	if len(fragments) == 0 {
+		return b
+	}
+	clauses := make([]string, len(fragments))
+	args := make([]interface{}, len(fragments))
+	for i, fragment := range fragments {
+		clauses[i] = "title LIKE ?"
+		args[i] = "%" + likeEscaper.Replace(fragment) + "%"
+	}
+	return b.add("("+strings.Join(clauses, " OR ")+")", args...)
+}
+
+// tagTermMatch says how a keyword term is compared with tags
+type tagTermMatch int
+
+const (
+	tagMatchExact    tagTermMatch = iota // The tag equals the term
+	tagMatchPrefix                       // The tag starts with the term
+	tagMatchContains                     // The tag contains the term
+)
+
+// OrTags widens the last added clause (the keyword match) so contents carrying
+// matching tags match too; with requireAll every term must match some tag
+// The keyword and tag matches are a UNION in a derived table rather than an OR:
+// MySQL can't use the FULLTEXT index for MATCH ORed with another condition and
+// would scan every row, while each UNION branch uses its own index. Matching by
+// id IN keeps a content with several matching tags one row, so COUNT(*) stays accurate
+func (b *searchFilterBuilder) OrTags(terms []string, match tagTermMatch, requireAll bool) *searchFilterBuilder {
+	if len(b.clauses) == 0 || len(terms) == 0 {
+		return b
+	}
+
+	conditions := make([]string, len(terms))
+	for i, term := range terms {
+		switch match {
+		case tagMatchPrefix:
+			conditions[i] = "tag LIKE ?"
+			b.args = append(b.args, likeEscaper.Replace(term)+"%")
+		case tagMatchContains:
+			conditions[i] = "tag LIKE ?"
+			b.args = append(b.args, "%"+likeEscaper.Replace(term)+"%")
+		default:
+			conditions[i] = "tag = ?"
+			b.args = append(b.args, term)
+		}
+	}
+
+	tagMatches := "SELECT content_id FROM content_tags WHERE " + strings.Join(conditions, " OR ")
+	if requireAll {
+		tagMatches = "SELECT content_id FROM content_tags WHERE " + conditions[0]
+		for _, cond := range conditions[1:] {
+			tagMatches += " AND content_id IN (SELECT content_id FROM content_tags WHERE " + cond + ")"
+		}
+	}
+
+	last := len(b.clauses) - 1
+	b.clauses[last] = "id IN (SELECT id FROM (SELECT id FROM contents WHERE " + b.clauses[last] +
+		" UNION " + tagMatches + ") AS keyword_matches)"
+	return b
+}
+
+// AddType restricts results to a content type
+func (b *searchFilterBuilder) AddType(contentType *model.ContentType) *searchFilterBuilder {
+	if contentType == nil {
+		return b
+	}
+	return b.add("type = ?", *contentType)
+}
+
+// AddProvider restricts results to a single provider
+func (b *searchFilterBuilder) AddProvider(providerID *int) *searchFilterBuilder {
+	if providerID == nil {
+		return b
+	}
+	return b.add("provider_id = ?", *providerID)
+}
+
+// AddDateRange restricts published_at to [start, end]; either bound may be nil
+func (b *searchFilterBuilder) AddDateRange(start, end *time.Time) *searchFilterBuilder {
+	if start != nil {
+		b.add("published_at >= ?", *start)
+	}
+	if end != nil {
+		b.add("published_at <= ?", *end)
+	}
+	return b
+}
+
+// AddUpdatedSince restricts content_changed_at to since or later; nil adds nothing
+// updated_at would also match items a resync or score recalculation merely touched
+func (b *searchFilterBuilder) AddUpdatedSince(since *time.Time) *searchFilterBuilder {
+	if since != nil {
+		b.add("content_changed_at >= ?", *since)
+	}
+	return b
+}
+
+// AddMinEngagement applies raw engagement floors, each only to its own content type
+// Views and likes are video metrics, reactions and comments article metrics;
+// content of the other type passes the floor untouched
+func (b *searchFilterBuilder) AddMinEngagement(minViews, minLikes, minReactions, minComments *int) *searchFilterBuilder {
+	b.addTypedMinimum(model.ContentTypeVideo, "views", minViews)
+	b.addTypedMinimum(model.ContentTypeVideo, "likes", minLikes)
+	b.addTypedMinimum(model.ContentTypeArticle, "reactions", minReactions)
+	b.addTypedMinimum(model.ContentTypeArticle, "comments", minComments)
+	return b
+}
+
+// addTypedMinimum requires column >= minValue for rows of contentType
+// column is always a constant from AddMinEngagement, never user input
+func (b *searchFilterBuilder) addTypedMinimum(contentType model.ContentType, column string, minValue *int) {
+	if minValue == nil {
+		return
+	}
+	b.add("(type <> ? OR "+column+" >= ?)", contentType, *minValue)
+}
+
+// Build returns the WHERE clause (empty when there are no conditions) and its args
+func (b *searchFilterBuilder) Build() (string, []interface{}) {
+	if len(b.clauses) == 0 {
+		return "", b.args
+	}
+	return "WHERE " + strings.Join(b.clauses, " AND "), b.args
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"reflect"
+	"search-engine/backend/internal/model"
+	"testing"
+	"time"
+)
+
+func TestSearchFilterBuilderEmpty(t *testing.T) {
+	where, args := newSearchFilterBuilder().
+		AddType(nil).
+		AddProvider(nil).
+		AddDateRange(nil, nil).
+		AddUpdatedSince(nil).
+		AddMinEngagement(nil, nil, nil, nil).
+		AddTitleFragments(nil).
+		Build()
+
+	if where != "" {
+		t.Errorf("expected no WHERE clause, got %q", where)
+	}
+	if len(args) != 0 {
+		t.Errorf("expected no args, got %v", args)
+	}
+}
+
+func TestSearchFilterBuilderKeepsClausesAndArgsAligned(t *testing.T) {
+	videoType := model.ContentTypeVideo
+	providerID := 2
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	minViews := 100
+
+	where, args := newSearchFilterBuilder().
+		AddFullText("golang*").
+		AddType(&videoType).
+		AddProvider(&providerID).
+		AddDateRange(&start, &end).
+		AddMinEngagement(&minViews, nil, nil, nil).
+		Build()
+
+	wantWhere := "WHERE MATCH(title) AGAINST(? IN BOOLEAN MODE) AND type = ? AND provider_id = ?" +
+		" AND published_at >= ? AND published_at <= ? AND (type <> ? OR views >= ?)"
+	if where != wantWhere {
+		t.Errorf("where =\n%s\nwant\n%s", where, wantWhere)
+	}
+
+	wantArgs := []interface{}{"golang*", videoType, providerID, start, end, model.ContentTypeVideo, minViews}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestSearchFilterBuilderKeepsUserInputOutOfSQL(t *testing.T) {
+	injection := "x' OR 1=1 --"
+	where, args := newSearchFilterBuilder().
+		AddLike(injection).
+		AddTitleFragments([]string{injection}).
+		Build()
+
+	if want := "WHERE (title LIKE ? AND title LIKE ? AND title LIKE ? AND title LIKE ?)" +
+		" AND (title LIKE ?)"; where != want {
+		t.Errorf("where = %q, want %q", where, want)
+	}
+	wantArgs := []interface{}{"%x'%", "%OR%", "%1=1%", "%--%", "%" + injection + "%"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestBuildSearchFiltersUsesQueryMode(t *testing.T) {
+	r := NewContentRepository(nil, 3)
+	titlesOnly := false
+
+	tests := []struct {
+		name      string
+		query     string
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{"no query", "", "", []interface{}{}},
+		{"whitespace query", "   ", "", []interface{}{}},
+		{"short query uses LIKE", "go", "WHERE (title LIKE ?)", []interface{}{"%go%"}},
+		{"long query uses FULLTEXT", " golang ", "WHERE MATCH(title) AGAINST(? IN BOOLEAN MODE)", []interface{}{"golang*"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := r.buildSearchFilters(&model.SearchRequest{Query: tt.query, IncludeTags: &titlesOnly})
+			if where != tt.wantWhere {
+				t.Errorf("where = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSearchFilterBuilderMinEngagementIsTypeScoped(t *testing.T) {
+	minViews, minComments := 100, 0
+
+	where, args := newSearchFilterBuilder().
+		AddMinEngagement(&minViews, nil, nil, &minComments).
+		Build()
+
+	wantWhere := "WHERE (type <> ? OR views >= ?) AND (type <> ? OR comments >= ?)"
+	if where != wantWhere {
+		t.Errorf("where = %q, want %q", where, wantWhere)
+	}
+	wantArgs := []interface{}{model.ContentTypeVideo, 100, model.ContentTypeArticle, 0}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestBuildSearchFiltersMatchesTags(t *testing.T) {
+	r := NewContentRepository(nil, 3)
+	exact := false
+	video := model.ContentTypeVideo
+
+	tests := []struct {
+		name      string
+		req       model.SearchRequest
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			"FULLTEXT any mode unions one tag query",
+			model.SearchRequest{Query: "kubernetes docker", MatchMode: model.MatchModeAny},
+			"WHERE id IN (SELECT id FROM (SELECT id FROM contents WHERE MATCH(title) AGAINST(? IN BOOLEAN MODE)" +
+				" UNION SELECT content_id FROM content_tags WHERE tag LIKE ? OR tag LIKE ?) AS keyword_matches)",
+			[]interface{}{"kubernetes* docker*", "kubernetes%", "docker%"},
+		},
+		{
+			"FULLTEXT all mode needs a tag per term",
+			model.SearchRequest{Query: "kubernetes docker", MatchMode: model.MatchModeAll, Prefix: &exact},
+			"WHERE id IN (SELECT id FROM (SELECT id FROM contents WHERE MATCH(title) AGAINST(? IN BOOLEAN MODE)" +
+				" UNION SELECT content_id FROM content_tags WHERE tag = ?" +
+				" AND content_id IN (SELECT content_id FROM content_tags WHERE tag = ?)) AS keyword_matches)",
+			[]interface{}{"+kubernetes +docker", "kubernetes", "docker"},
+		},
+		{
+			"LIKE mode matches tags containing every term",
+			model.SearchRequest{Query: "k8"},
+			"WHERE id IN (SELECT id FROM (SELECT id FROM contents WHERE (title LIKE ?)" +
+				" UNION SELECT content_id FROM content_tags WHERE tag LIKE ?) AS keyword_matches)",
+			[]interface{}{"%k8%", "%k8%"},
+		},
+		{
+			"tag terms skip boolean operators and escape wildcards",
+			model.SearchRequest{Query: "+100%_done"},
+			"WHERE id IN (SELECT id FROM (SELECT id FROM contents WHERE MATCH(title) AGAINST(? IN BOOLEAN MODE)" +
+				" UNION SELECT content_id FROM content_tags WHERE tag LIKE ?) AS keyword_matches)",
+			[]interface{}{"100%_done*", `100\%\_done%`},
+		},
+		{
+			"keyword union comes before the other filters",
+			model.SearchRequest{Query: "kubernetes", Type: &video},
+			"WHERE id IN (SELECT id FROM (SELECT id FROM contents WHERE MATCH(title) AGAINST(? IN BOOLEAN MODE)" +
+				" UNION SELECT content_id FROM content_tags WHERE tag LIKE ?) AS keyword_matches) AND type = ?",
+			[]interface{}{"kubernetes*", "kubernetes%", video},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := r.buildSearchFilters(&tt.req)
+			if where != tt.wantWhere {
+				t.Errorf("where =\n%s\nwant\n%s", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSearchFilterBuilderOrTagsWithoutKeyword(t *testing.T) {
+	where, args := newSearchFilterBuilder().
+		OrTags([]string{"go"}, tagMatchExact, false).
+		Build()
+
+	if where != "" || len(args) != 0 {
+		t.Errorf("OrTags without a keyword clause = %q %v, want nothing", where, args)
+	}
+}
+
+func TestSearchFilterBuilderTitleFragments(t *testing.T) {
+	providerID := 1
+	where, args := newSearchFilterBuilder().
+		AddTitleFragments([]string{"kub", "10%"}).
+		AddProvider(&providerID).
+		Build()
+
+	wantWhere := "WHERE (title LIKE ? OR title LIKE ?) AND provider_id = ?"
+	if where != wantWhere {
+		t.Errorf("where = %q, want %q", where, wantWhere)
+	}
+	wantArgs := []interface{}{"%kub%", `%10\%%`, 1}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+
+	if where, _ := newSearchFilterBuilder().AddTitleFragments(nil).Build(); where != "" {
+		t.Errorf("no fragments: where = %q, want none", where)
+	}
+}
+
+func TestBuildSearchFiltersUpdatedSince(t *testing.T) {
+	r := NewContentRepository(nil, 3)
+	since := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	videoType := model.ContentTypeVideo
+
+	// Search and its COUNT share these filters, so both only see rows changed since then
+	where, args := r.buildSearchFilters(&model.SearchRequest{Type: &videoType, UpdatedSince: &since})
+
+	if want := "WHERE type = ? AND content_changed_at >= ?"; where != want {
+		t.Errorf("where = %q, want %q", where, want)
+	}
+	if wantArgs := []interface{}{videoType, since}; !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+}
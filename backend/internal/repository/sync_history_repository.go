@@ -0,0 +1,117 @@
+// sync_history_repository.go - Database operations for sync history
+// Records provider sync runs and reads back the latest ones
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"search-engine/backend/internal/model"
+)
+
+// SyncHistoryRepository handles all database operations for sync history
+type SyncHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewSyncHistoryRepository creates a new SyncHistoryRepository instance
+func NewSyncHistoryRepository(db *sql.DB) *SyncHistoryRepository {
+	return &SyncHistoryRepository{db: db}
+}
+
+// Create records a sync run and sets its generated ID
+func (r *SyncHistoryRepository) Create(s *model.SyncResult) error {
+	query := `
+		INSERT INTO sync_history (provider_id, started_at, finished_at, items_fetched, items_skipped, items_upserted, tags_dropped, status, error_message)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	var errorMessage sql.NullString
+	if s.ErrorMessage != "" {
+		errorMessage = sql.NullString{String: s.ErrorMessage, Valid: true}
+	}
+
+	result, err := r.db.Exec(query, s.ProviderID, s.StartedAt, s.FinishedAt, s.ItemsFetched, s.ItemsSkipped, s.ItemsUpserted, s.TagsDropped, s.Status, errorMessage)
+	if err != nil {
+		return fmt.Errorf("failed to create sync history: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	s.ID = id
+	return nil
+}
+
+// GetLatestPerProvider returns the most recent sync run of every provider that has one
+func (r *SyncHistoryRepository) GetLatestPerProvider(ctx context.Context) ([]*model.SyncResult, error) {
+	query := `
+		SELECT h.id, h.provider_id, h.started_at, h.finished_at, h.items_fetched, h.items_skipped, h.items_upserted, h.tags_dropped, h.status, h.error_message
+		FROM sync_history h
+		JOIN (
+			SELECT provider_id, MAX(id) AS id
+			FROM sync_history
+			GROUP BY provider_id
+		) latest ON latest.id = h.id
+		ORDER BY h.provider_id
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, databaseError("get latest sync history", err)
+	}
+	defer rows.Close()
+
+	var results []*model.SyncResult
+	for rows.Next() {
+		s, err := scanSyncResult(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sync history: %w", err)
+		}
+		results = append(results, s)
+	}
+
+	return results, rows.Err()
+}
+
+// GetByProvider returns up to limit of a provider's sync runs, newest first
+func (r *SyncHistoryRepository) GetByProvider(ctx context.Context, providerID, limit int) ([]*model.SyncResult, error) {
+	query := `
+		SELECT id, provider_id, started_at, finished_at, items_fetched, items_skipped, items_upserted, tags_dropped, status, error_message
+		FROM sync_history
+		WHERE provider_id = ?
+		ORDER BY started_at DESC, id DESC
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, providerID, limit)
+	if err != nil {
+		return nil, databaseError("get sync history", err)
+	}
+	defer rows.Close()
+
+	results := []*model.SyncResult{}
+	for rows.Next() {
+		s, err := scanSyncResult(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sync history: %w", err)
+		}
+		results = append(results, s)
+	}
+
+	return results, rows.Err()
+}
+
+// scanSyncResult scans a sync_history row selected in column order
+func scanSyncResult(row rowScanner) (*model.SyncResult, error) {
+	s := &model.SyncResult{}
+	var finishedAt sql.NullTime
+	var errorMessage sql.NullString
+	if err := row.Scan(&s.ID, &s.ProviderID, &s.StartedAt, &finishedAt, &s.ItemsFetched, &s.ItemsSkipped, &s.ItemsUpserted, &s.TagsDropped, &s.Status, &errorMessage); err != nil {
+		return nil, err
+	}
+	if finishedAt.Valid {
+		s.FinishedAt = &finishedAt.Time
+	}
+	s.ErrorMessage = errorMessage.String
+	return s, nil
+}
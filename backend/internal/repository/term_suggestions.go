@@ -0,0 +1,133 @@
+// term_suggestions.go - "Did you mean" terms for queries that match nothing
+// Candidates are existing tags sharing a query term's first letters, ranked by edit distance
+package repository
+
+import (
+	"context"
+	"fmt"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/pkg/textdist"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// suggestPrefixLength is how many leading letters a tag must share with a query term
+// Keeps the candidate lookup on the idx_tag index; typos rarely hit the first letters
+const suggestPrefixLength = 2
+
+// suggestCandidateLimit caps how many tags one lookup compares with the query, most used first
+const suggestCandidateLimit = 200
+
+// SuggestTerms returns up to limit existing tags close to the query's terms
+// A tag is close when it is within textdist.MaxEdits of a term or completes it; tags equal
+// to a term are skipped. Terms shorter than 3 letters get no suggestions
+func (r *ContentRepository) SuggestTerms(ctx context.Context, query string, limit int) ([]string, error) {
+	terms := suggestionTerms(query)
+	if len(terms) == 0 || limit <= 0 {
+		return nil, nil
+	}
+
+	q, args := suggestCandidatesQuery(terms)
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, databaseError("suggest terms", err)
+	}
+	defer rows.Close()
+
+	var candidates []model.TagCount
+	for rows.Next() {
+		var tc model.TagCount
+		if err := rows.Scan(&tc.Tag, &tc.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag suggestion: %w", err)
+		}
+		candidates = append(candidates, tc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rankSuggestions(terms, candidates, limit), nil
+}
+
+// suggestionTerms splits a query into lowercased, deduplicated terms long enough to misspell
+func suggestionTerms(query string) [][]rune {
+	var terms [][]rune
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		term := []rune(strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}))
+		if textdist.MaxEdits(len(term)) == 0 {
+			continue
+		}
+		if !slices.ContainsFunc(terms, func(t []rune) bool { return slices.Equal(t, term) }) {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// suggestCandidatesQuery builds the query for tags starting like any of terms, most used first
+func suggestCandidatesQuery(terms [][]rune) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	for _, term := range terms {
+		arg := likeEscaper.Replace(string(term[:suggestPrefixLength])) + "%"
+		if slices.Contains(args, interface{}(arg)) {
+			continue
+		}
+		clauses = append(clauses, "tag LIKE ?")
+		args = append(args, arg)
+	}
+	args = append(args, suggestCandidateLimit)
+
+	query := "SELECT tag, COUNT(*) FROM content_tags WHERE " + strings.Join(clauses, " OR ") +
+		" GROUP BY tag ORDER BY COUNT(*) DESC, tag LIMIT ?"
+	return query, args
+}
+
+// rankSuggestions returns up to limit candidate tags close to any of terms
+// Misspellings come before completions, fewer edits first, then the more used tag
+func rankSuggestions(terms [][]rune, candidates []model.TagCount, limit int) []string {
+	type suggestion struct {
+		tag      string
+		distance int
+		count    int
+	}
+
+	var suggestions []suggestion
+candidates:
+	for _, c := range candidates {
+		tag := []rune(strings.ToLower(c.Tag))
+		best, found := 0, false
+		for _, term := range terms {
+			if slices.Equal(tag, term) {
+				continue candidates
+			}
+			d, ok := textdist.Within(term, tag)
+			if !ok && len(tag) > len(term) && slices.Equal(tag[:len(term)], term) {
+				// A completion ranks after every misspelling of the term
+				d, ok = textdist.MaxEdits(len(term))+1, true
+			}
+			if ok && (!found || d < best) {
+				best, found = d, true
+			}
+		}
+		if found {
+			suggestions = append(suggestions, suggestion{tag: c.Tag, distance: best, count: c.Count})
+		}
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b suggestion) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return b.count - a.count
+	})
+
+	tags := make([]string, 0, min(limit, len(suggestions)))
+	for _, s := range suggestions[:min(limit, len(suggestions))] {
+		tags = append(tags, s.tag)
+	}
+	return tags
+}
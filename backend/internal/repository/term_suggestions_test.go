@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"reflect"
+	"search-engine/backend/internal/model"
+	"testing"
+)
+
+func TestSuggestCandidatesQuery(t *testing.T) {
+	query, args := suggestCandidatesQuery(suggestionTerms("Pyton pytest go 10%_off"))
+
+	wantQuery := "SELECT tag, COUNT(*) FROM content_tags WHERE tag LIKE ? OR tag LIKE ? GROUP BY tag ORDER BY COUNT(*) DESC, tag LIMIT ?"
+	if query != wantQuery {
+		t.Errorf("query = %q, want %q", query, wantQuery)
+	}
+	// "go" is too short to misspell; "pyton" and "pytest" share a prefix
+	wantArgs := []interface{}{"py%", "10%", suggestCandidateLimit}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestRankSuggestions(t *testing.T) {
+	candidates := []model.TagCount{
+		{Tag: "python", Count: 40},
+		{Tag: "pytorch", Count: 30},
+		{Tag: "Pythons", Count: 5},
+		{Tag: "kubernetes", Count: 20},
+		{Tag: "docker", Count: 10},
+		{Tag: "dock", Count: 3},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []string
+	}{
+		{"near miss", "pyton", 3, []string{"python"}},
+		{"fewest edits before most used", "pythns", 3, []string{"Pythons", "python"}},
+		{"completion after misspellings", "kubernets dock", 3, []string{"kubernetes", "docker"}},
+		{"limit", "pyth", 1, []string{"python"}},
+		{"exact tag is not suggested", "docker", 3, []string{"dock"}},
+		{"nothing close", "javascript", 3, []string{}},
+		{"short terms are ignored", "py", 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms := suggestionTerms(tt.query)
+			var got []string
+			if len(terms) > 0 {
+				got = rankSuggestions(terms, candidates, tt.limit)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("suggestions for %q = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,51 +3,68 @@
 package scoring
 
 import (
+	"math"
+	"search-engine/backend/internal/config"
 	"search-engine/backend/internal/model"
 )
 
 // CalculateBaseScore calculates the base score for content
-// Formula:
+// Formula (stock weights, see config.ScoringWeights):
 //
-//	Video: views / 1000 + (likes / 100)
+//	Video: views / 1000 + (likes / 100), or log10(views + 1) + (likes / 100) with UseLogScaling
 //	Article: reading_time + (reactions / 50)
-func CalculateBaseScore(content *model.Content) float64 {
+func CalculateBaseScore(content *model.Content, w config.ScoringWeights) float64 {
 	if content.IsVideo() {
-		return calculateVideoBaseScore(content)
+		return calculateVideoBaseScore(content, w)
 	} else if content.IsArticle() {
-		return calculateArticleBaseScore(content)
+		return calculateArticleBaseScore(content, w)
 	}
 	return 0.0
 }
 
 // calculateVideoBaseScore calculates base score for video content
-// Formula: views / 1000 + (likes / 100)
+// Formula: views / ViewDivisor + (likes / LikeDivisor)
 // This normalizes views and likes to a comparable scale
-func calculateVideoBaseScore(content *model.Content) float64 {
-	viewsScore := float64(content.Views) / 1000.0
-	likesScore := float64(content.Likes) / 100.0
+// With UseLogScaling views contribute log10(views + 1) instead: 0 views scores 0,
+// 1K about 3, 10M about 7, so ordering by views holds but huge counts are dampened
+func calculateVideoBaseScore(content *model.Content, w config.ScoringWeights) float64 {
+	var viewsScore float64
+	if w.UseLogScaling {
+		viewsScore = math.Log10(float64(max(content.Views, 0)) + 1)
+	} else {
+		viewsScore = divideBy(float64(content.Views), w.ViewDivisor)
+	}
+	likesScore := divideBy(float64(content.Likes), w.LikeDivisor)
 	return viewsScore + likesScore
 }
 
 // calculateArticleBaseScore calculates base score for article content
-// Formula: reading_time + (reactions / 50)
+// Formula: reading_time * ReadingTimeWeight + (reactions / ReactionDivisor)
 // Reading time is already in minutes, reactions are normalized
-func calculateArticleBaseScore(content *model.Content) float64 {
+func calculateArticleBaseScore(content *model.Content, w config.ScoringWeights) float64 {
 	readingTimeScore := 0.0
 	if content.ReadingTime != nil {
-		readingTimeScore = float64(*content.ReadingTime)
+		readingTimeScore = float64(*content.ReadingTime) * w.ReadingTimeWeight
 	}
 
-	reactionsScore := float64(content.Reactions) / 50.0
+	reactionsScore := divideBy(float64(content.Reactions), w.ReactionDivisor)
 	return readingTimeScore + reactionsScore
 }
 
+// divideBy returns value / divisor, or 0 when the divisor disables the term
+func divideBy(value, divisor float64) float64 {
+	if divisor <= 0 {
+		return 0.0
+	}
+	return value / divisor
+}
+
 // GetContentTypeCoefficient returns the coefficient for content type
-// Video: 1.5 (videos are weighted higher)
-// Article: 1.0 (articles have standard weight)
-func GetContentTypeCoefficient(contentType model.ContentType) float64 {
+// Video: VideoCoefficient (1.5 by default, videos are weighted higher)
+// Article: ArticleCoefficient (1.0 by default, articles have standard weight)
+func GetContentTypeCoefficient(contentType model.ContentType, w config.ScoringWeights) float64 {
 	if contentType == model.ContentTypeVideo {
-		return 1.5
+		return w.VideoCoefficient
 	}
-	return 1.0
+	return w.ArticleCoefficient
 }
@@ -0,0 +1,69 @@
+package scoring
+
+import (
+	"math"
+	"search-engine/backend/internal/config"
+	"search-engine/backend/internal/model"
+	"testing"
+)
+
+func TestCalculateVideoBaseScoreLogScaling(t *testing.T) {
+	linear := config.DefaultScoringWeights()
+	logScaled := config.DefaultScoringWeights()
+	logScaled.UseLogScaling = true
+
+	tests := []struct {
+		views      int
+		wantLinear float64
+		wantLog    float64
+	}{
+		{0, 0, 0},
+		{9, 0.009, 1},
+		{999, 0.999, 3},
+		{9_999_999, 9999.999, 7},
+	}
+
+	for _, tt := range tests {
+		video := &model.Content{Type: model.ContentTypeVideo, Views: tt.views}
+		if got := CalculateBaseScore(video, linear); math.Abs(got-tt.wantLinear) > 1e-9 {
+			t.Errorf("linear base score for %d views = %v, want %v", tt.views, got, tt.wantLinear)
+		}
+		if got := CalculateBaseScore(video, logScaled); math.Abs(got-tt.wantLog) > 1e-9 {
+			t.Errorf("log base score for %d views = %v, want %v", tt.views, got, tt.wantLog)
+		}
+	}
+}
+
+func TestLogScalingPreservesViewOrdering(t *testing.T) {
+	logScaled := config.DefaultScoringWeights()
+	logScaled.UseLogScaling = true
+
+	// Small to viral view counts, likes held fixed so views alone decide the order
+	views := []int{0, 1, 10, 150, 1_000, 25_000, 1_000_000, 10_000_000}
+	for _, w := range []config.ScoringWeights{config.DefaultScoringWeights(), logScaled} {
+		prev := -1.0
+		for _, v := range views {
+			score := CalculateBaseScore(&model.Content{Type: model.ContentTypeVideo, Views: v, Likes: 50}, w)
+			if score <= prev {
+				t.Errorf("log scaling %t: %d views scored %v, not above the previous %v", w.UseLogScaling, v, score, prev)
+			}
+			prev = score
+		}
+	}
+
+	// Log scaling dampens a viral video's lead over a modest one
+	modest := &model.Content{Type: model.ContentTypeVideo, Views: 10_000, Likes: 50}
+	viral := &model.Content{Type: model.ContentTypeVideo, Views: 10_000_000, Likes: 50}
+	linearGap := CalculateBaseScore(viral, config.DefaultScoringWeights()) - CalculateBaseScore(modest, config.DefaultScoringWeights())
+	logGap := CalculateBaseScore(viral, logScaled) - CalculateBaseScore(modest, logScaled)
+	if logGap >= linearGap || logGap > 3.01 {
+		t.Errorf("log gap = %v, linear gap = %v; want the log gap about 3 and far smaller", logGap, linearGap)
+	}
+}
+
+func TestLogScalingKeptWithZeroWeights(t *testing.T) {
+	w := config.ScoringWeights{UseLogScaling: true}.OrDefault()
+	if !w.UseLogScaling || w.VideoCoefficient != 1.5 {
+		t.Errorf("OrDefault() = %+v, want the defaults with log scaling on", w)
+	}
+}
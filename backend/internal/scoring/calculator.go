@@ -3,15 +3,19 @@
 package scoring
 
 import (
+	"search-engine/backend/internal/config"
 	"search-engine/backend/internal/model"
+	"time"
 )
 
 // CalculateFinalScore calculates the final score for content
 // Formula: (Base Score * Content Type Coefficient) + Freshness Score + Engagement Score
+// The numbers below are the stock weights; cfg.Weights overrides them
 //
 // Base Score:
 //
 //	Video: views / 1000 + (likes / 100)
+//	       log10(views + 1) + (likes / 100) with Weights.UseLogScaling
 //	Article: reading_time + (reactions / 50)
 //
 // Content Type Coefficient:
@@ -25,33 +29,69 @@ import (
 //	1 month or newer: +3
 //	3 months or newer: +1
 //	Older: +0
+//	Decay curve (Weights.FreshnessCurve): 5 * exp(-age_days * ln 2 / 14)
+//	Disabled via ScoringConfig.DisableFreshness: 0
 //
 // Engagement Score:
 //
 //	Video: (likes / views) * 10
 //	Article: (reactions / reading_time) * 5
-func CalculateFinalScore(content *model.Content) float64 {
+func CalculateFinalScore(content *model.Content, cfg config.ScoringConfig) float64 {
+	return CalculateFinalScoreAt(content, cfg, clock())
+}
+
+// CalculateFinalScoreAt calculates the final score with freshness measured against asOf
+// Batch recalculations use this so every item is scored against the same moment
+func CalculateFinalScoreAt(content *model.Content, cfg config.ScoringConfig, asOf time.Time) float64 {
+	return CalculateScoreBreakdown(content, cfg, asOf).FinalScore
+}
+
+// FreshnessNoteDisabled marks a breakdown whose freshness component was turned off by config
+const FreshnessNoteDisabled = "disabled"
+
+// ScoreBreakdown holds every component of a content score
+// It is what CalculateFinalScoreAt sums up, exposed so rankings can be explained
+type ScoreBreakdown struct {
+	BaseScore         float64 `json:"base_score"`
+	TypeCoefficient   float64 `json:"type_coefficient"`
+	WeightedBaseScore float64 `json:"weighted_base_score"`
+	FreshnessScore    float64 `json:"freshness_score"`
+	FreshnessNote     string  `json:"freshness_note,omitempty"`
+	EngagementScore   float64 `json:"engagement_score"`
+	FinalScore        float64 `json:"final_score"`
+}
+
+// CalculateScoreBreakdown calculates each score component for content as of asOf
+// When freshness is disabled in cfg it contributes 0 and is annotated as disabled
+func CalculateScoreBreakdown(content *model.Content, cfg config.ScoringConfig, asOf time.Time) ScoreBreakdown {
+	var b ScoreBreakdown
+	w := cfg.Weights.OrDefault()
+
 	// Step 1: Calculate base score
-	baseScore := CalculateBaseScore(content)
+	b.BaseScore = CalculateBaseScore(content, w)
 
 	// Step 2: Apply content type coefficient
-	coefficient := GetContentTypeCoefficient(content.Type)
-	weightedBaseScore := baseScore * coefficient
+	b.TypeCoefficient = GetContentTypeCoefficient(content.Type, w)
+	b.WeightedBaseScore = b.BaseScore * b.TypeCoefficient
 
-	// Step 3: Calculate freshness score
-	freshnessScore := CalculateFreshnessScore(content.PublishedAt)
+	// Step 3: Calculate freshness score (skipped entirely for evergreen catalogs)
+	if cfg.DisableFreshness {
+		b.FreshnessNote = FreshnessNoteDisabled
+	} else {
+		b.FreshnessScore = CalculateFreshnessScoreWithWeights(content.PublishedAt, asOf, w)
+	}
 
 	// Step 4: Calculate engagement score
-	engagementScore := CalculateEngagementScore(content)
+	b.EngagementScore = CalculateEngagementScore(content, w)
 
 	// Step 5: Combine all scores
-	finalScore := weightedBaseScore + freshnessScore + engagementScore
+	b.FinalScore = b.WeightedBaseScore + b.FreshnessScore + b.EngagementScore
 
-	return finalScore
+	return b
 }
 
 // CalculateAndUpdateScore calculates the final score and updates the content
 // This is a convenience method that both calculates and sets the score
-func CalculateAndUpdateScore(content *model.Content) {
-	content.Score = CalculateFinalScore(content)
+func CalculateAndUpdateScore(content *model.Content, cfg config.ScoringConfig) {
+	content.Score = CalculateFinalScore(content, cfg)
 }
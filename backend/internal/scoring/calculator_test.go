@@ -0,0 +1,93 @@
+package scoring
+
+import (
+	"search-engine/backend/internal/config"
+	"search-engine/backend/internal/model"
+	"testing"
+	"time"
+)
+
+func TestCalculateScoreBreakdownWithFreshnessDisabled(t *testing.T) {
+	publishedAt := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	content := &model.Content{
+		Type:        model.ContentTypeVideo,
+		Views:       10000,
+		Likes:       500,
+		PublishedAt: publishedAt,
+	}
+	cfg := config.ScoringConfig{DisableFreshness: true}
+
+	fresh := CalculateScoreBreakdown(content, cfg, publishedAt.AddDate(0, 0, 1))
+	if fresh.FreshnessScore != 0 {
+		t.Errorf("FreshnessScore = %v, want 0", fresh.FreshnessScore)
+	}
+	if fresh.FreshnessNote != FreshnessNoteDisabled {
+		t.Errorf("FreshnessNote = %q, want %q", fresh.FreshnessNote, FreshnessNoteDisabled)
+	}
+
+	// Without freshness the score must not drift as the content ages
+	old := CalculateScoreBreakdown(content, cfg, publishedAt.AddDate(1, 0, 0))
+	if fresh.FinalScore != old.FinalScore {
+		t.Errorf("FinalScore changed with age: %v vs %v", fresh.FinalScore, old.FinalScore)
+	}
+	if want := fresh.WeightedBaseScore + fresh.EngagementScore; fresh.FinalScore != want {
+		t.Errorf("FinalScore = %v, want base + engagement %v", fresh.FinalScore, want)
+	}
+
+	enabled := CalculateScoreBreakdown(content, config.ScoringConfig{}, publishedAt.AddDate(0, 0, 1))
+	if enabled.FreshnessScore != 5.0 || enabled.FreshnessNote != "" {
+		t.Errorf("enabled freshness = %v (%q), want 5 with no note", enabled.FreshnessScore, enabled.FreshnessNote)
+	}
+}
+
+func TestCalculateScoreBreakdownUsesWeights(t *testing.T) {
+	publishedAt := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	asOf := publishedAt.AddDate(0, 0, 1)
+	readingTime := 10
+	video := &model.Content{Type: model.ContentTypeVideo, Views: 10000, Likes: 500, PublishedAt: publishedAt}
+	article := &model.Content{Type: model.ContentTypeArticle, ReadingTime: &readingTime, Reactions: 100, PublishedAt: publishedAt}
+
+	stock := config.ScoringConfig{Weights: config.DefaultScoringWeights()}
+	if got, want := CalculateFinalScoreAt(video, stock, asOf), CalculateFinalScoreAt(video, config.ScoringConfig{}, asOf); got != want {
+		t.Fatalf("default weights score = %v, want the zero-value score %v", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		content *model.Content
+		tweak   func(w *config.ScoringWeights)
+	}{
+		{"view divisor", video, func(w *config.ScoringWeights) { w.ViewDivisor = 500 }},
+		{"like divisor", video, func(w *config.ScoringWeights) { w.LikeDivisor = 10 }},
+		{"video coefficient", video, func(w *config.ScoringWeights) { w.VideoCoefficient = 2 }},
+		{"video engagement", video, func(w *config.ScoringWeights) { w.VideoEngagementMultiplier = 20 }},
+		{"reading time weight", article, func(w *config.ScoringWeights) { w.ReadingTimeWeight = 2 }},
+		{"reaction divisor", article, func(w *config.ScoringWeights) { w.ReactionDivisor = 25 }},
+		{"article coefficient", article, func(w *config.ScoringWeights) { w.ArticleCoefficient = 1.2 }},
+		{"article engagement", article, func(w *config.ScoringWeights) { w.ArticleEngagementMultiplier = 1 }},
+		{"freshness tiers", article, func(w *config.ScoringWeights) { w.FreshnessTiers = []config.FreshnessTier{{MaxAgeDays: 7, Points: 8}} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := CalculateFinalScoreAt(tt.content, stock, asOf)
+
+			tuned := config.ScoringConfig{Weights: config.DefaultScoringWeights()}
+			tt.tweak(&tuned.Weights)
+			if got := CalculateFinalScoreAt(tt.content, tuned, asOf); got == base {
+				t.Errorf("score = %v with the tweaked weight, want it to differ from %v", got, base)
+			}
+		})
+	}
+}
+
+func TestCalculateScoreBreakdownZeroDivisorDropsTerm(t *testing.T) {
+	content := &model.Content{Type: model.ContentTypeVideo, Views: 10000, Likes: 500}
+	w := config.DefaultScoringWeights()
+	w.ViewDivisor = 0
+
+	b := CalculateScoreBreakdown(content, config.ScoringConfig{Weights: w, DisableFreshness: true}, time.Now())
+	if b.BaseScore != 5.0 {
+		t.Errorf("BaseScore = %v, want likes only (5)", b.BaseScore)
+	}
+}
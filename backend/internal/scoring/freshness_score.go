@@ -3,9 +3,24 @@
 package scoring
 
 import (
+	"math"
+	"search-engine/backend/internal/config"
 	"time"
 )
 
+// clock is the time source for freshness calculations
+// It defaults to time.Now and can be frozen with SetClock for reproducible scoring
+var clock = time.Now
+
+// SetClock overrides the time source used for freshness scoring
+// Passing nil restores the default (time.Now)
+func SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	clock = now
+}
+
 // CalculateFreshnessScore calculates the freshness score based on publication date
 // Formula:
 //
@@ -14,32 +29,57 @@ import (
 //	3 months (90 days) or newer: +1
 //	Older than 3 months: +0
 func CalculateFreshnessScore(publishedAt time.Time) float64 {
-	now := time.Now()
-	age := now.Sub(publishedAt)
+	return CalculateFreshnessScoreAt(publishedAt, clock())
+}
+
+// CalculateFreshnessScoreAt calculates the freshness score relative to asOf
+// instead of the current time, so a batch recalculation can score every
+// item against the same reference point
+func CalculateFreshnessScoreAt(publishedAt, asOf time.Time) float64 {
+	return CalculateFreshnessScoreWithTiers(publishedAt, asOf, config.DefaultFreshnessTiers())
+}
+
+// CalculateFreshnessScoreWithTiers calculates the freshness score relative to asOf
+// using configured tiers, sorted by MaxAgeDays; the first tier the age fits wins
+// Content older than every tier scores 0
+func CalculateFreshnessScoreWithTiers(publishedAt, asOf time.Time, tiers []config.FreshnessTier) float64 {
+	age := asOf.Sub(publishedAt)
 
 	// Calculate age in days
 	days := int(age.Hours() / 24)
 
-	// Apply freshness scoring based on age
-	if days <= 7 {
-		// 1 week or newer: +5 points
-		return 5.0
-	} else if days <= 30 {
-		// 1 month or newer: +3 points
-		return 3.0
-	} else if days <= 90 {
-		// 3 months or newer: +1 point
-		return 1.0
+	for _, tier := range tiers {
+		if days <= tier.MaxAgeDays {
+			return tier.Points
+		}
 	}
-
-	// Older than 3 months: +0 points
 	return 0.0
 }
 
+// CalculateFreshnessScoreWithWeights calculates the freshness score relative to asOf
+// using w's curve: the tiers by default, or exponential decay with FreshnessCurveDecay
+func CalculateFreshnessScoreWithWeights(publishedAt, asOf time.Time, w config.ScoringWeights) float64 {
+	if w.FreshnessCurve == config.FreshnessCurveDecay {
+		return CalculateDecayedFreshnessScore(publishedAt, asOf, w.FreshnessMaxPoints, w.FreshnessHalfLifeDays)
+	}
+	return CalculateFreshnessScoreWithTiers(publishedAt, asOf, w.FreshnessTiers)
+}
+
+// CalculateDecayedFreshnessScore calculates a freshness score that halves every halfLifeDays
+// Formula: maxPoints * exp(-ageDays * ln 2 / halfLifeDays), with fractional days so
+// there are no cliffs between neighbouring ages. Future-dated content gets maxPoints;
+// a half-life of 0 or less scores 0
+func CalculateDecayedFreshnessScore(publishedAt, asOf time.Time, maxPoints, halfLifeDays float64) float64 {
+	if halfLifeDays <= 0 {
+		return 0.0
+	}
+	days := max(asOf.Sub(publishedAt).Hours()/24, 0)
+	return maxPoints * math.Exp(-days*math.Ln2/halfLifeDays)
+}
+
 // GetAgeInDays calculates the age of content in days
 // Helper function for debugging and logging
 func GetAgeInDays(publishedAt time.Time) int {
-	now := time.Now()
-	age := now.Sub(publishedAt)
+	age := clock().Sub(publishedAt)
 	return int(age.Hours() / 24)
 }
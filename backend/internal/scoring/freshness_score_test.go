@@ -0,0 +1,118 @@
+package scoring
+
+import (
+	"math"
+	"search-engine/backend/internal/config"
+	"testing"
+	"time"
+)
+
+func TestCalculateFreshnessScoreWithFrozenClock(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	SetClock(func() time.Time { return now })
+	t.Cleanup(func() { SetClock(nil) })
+
+	tests := []struct {
+		name    string
+		ageDays int
+		want    float64
+	}{
+		{"published now", 0, 5.0},
+		{"one week old", 7, 5.0},
+		{"just over a week", 8, 3.0},
+		{"one month old", 30, 3.0},
+		{"just over a month", 31, 1.0},
+		{"three months old", 90, 1.0},
+		{"just over three months", 91, 0.0},
+		{"one year old", 365, 0.0},
+		{"future dated", -2, 5.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publishedAt := now.AddDate(0, 0, -tt.ageDays)
+			if got := CalculateFreshnessScore(publishedAt); got != tt.want {
+				t.Errorf("CalculateFreshnessScore(%d days old) = %v, want %v", tt.ageDays, got, tt.want)
+			}
+			if got := GetAgeInDays(publishedAt); got != tt.ageDays {
+				t.Errorf("GetAgeInDays = %d, want %d", got, tt.ageDays)
+			}
+		})
+	}
+}
+
+func TestCalculateFreshnessScoreAtUsesReference(t *testing.T) {
+	publishedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := CalculateFreshnessScoreAt(publishedAt, publishedAt.AddDate(0, 0, 3)); got != 5.0 {
+		t.Errorf("expected 5.0 three days after publishing, got %v", got)
+	}
+	if got := CalculateFreshnessScoreAt(publishedAt, publishedAt.AddDate(1, 0, 0)); got != 0.0 {
+		t.Errorf("expected 0.0 a year after publishing, got %v", got)
+	}
+}
+
+func TestFreshnessCurvesAtBoundaryAges(t *testing.T) {
+	asOf := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	step := config.DefaultScoringWeights()
+	decay := config.DefaultScoringWeights()
+	decay.FreshnessCurve = config.FreshnessCurveDecay
+
+	// Decay with the default 5 points halving every 14 days
+	tests := []struct {
+		ageDays   int
+		wantStep  float64
+		wantDecay float64
+	}{
+		{6, 5, 3.7150},
+		{7, 5, 3.5355},
+		{8, 3, 3.3648},
+		{30, 3, 1.1322},
+		{31, 1, 1.0775},
+	}
+
+	scores := make(map[int]float64)
+	for _, tt := range tests {
+		publishedAt := asOf.AddDate(0, 0, -tt.ageDays)
+		if got := CalculateFreshnessScoreWithWeights(publishedAt, asOf, step); got != tt.wantStep {
+			t.Errorf("step curve at %d days = %v, want %v", tt.ageDays, got, tt.wantStep)
+		}
+		got := CalculateFreshnessScoreWithWeights(publishedAt, asOf, decay)
+		if math.Abs(got-tt.wantDecay) > 1e-4 {
+			t.Errorf("decay curve at %d days = %.4f, want %.4f", tt.ageDays, got, tt.wantDecay)
+		}
+		scores[tt.ageDays] = got
+	}
+
+	// The step curve drops 2 points from day 7 to day 8; the decay curve barely moves
+	for _, pair := range [][2]int{{7, 8}, {30, 31}} {
+		if drop := scores[pair[0]] - scores[pair[1]]; drop <= 0 || drop > 0.2 {
+			t.Errorf("decay from day %d to %d = %.4f, want a small positive drop", pair[0], pair[1], drop)
+		}
+	}
+}
+
+func TestCalculateDecayedFreshnessScore(t *testing.T) {
+	asOf := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		publishedAt  time.Time
+		halfLifeDays float64
+		want         float64
+	}{
+		{"published now", asOf, 14, 5},
+		{"one half-life", asOf.AddDate(0, 0, -14), 14, 2.5},
+		{"two half-lives", asOf.AddDate(0, 0, -28), 14, 1.25},
+		{"half a day", asOf.Add(-12 * time.Hour), 0.5, 2.5},
+		{"future dated", asOf.AddDate(0, 0, 3), 14, 5},
+		{"no half-life", asOf, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateDecayedFreshnessScore(tt.publishedAt, asOf, 5, tt.halfLifeDays); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("CalculateDecayedFreshnessScore = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
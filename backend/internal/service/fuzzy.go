@@ -0,0 +1,123 @@
+// fuzzy.go - Typo-tolerant search fallback
+// When a keyword search matches nothing, finds titles with words spelled close to the query terms
+package service
+
+import (
+	"context"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/pkg/textdist"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// fuzzyCandidateLimit caps how many titles one fallback compares with the query
+// Candidates come highest score first, so a very common fragment still yields the best items
+const fuzzyCandidateLimit = 500
+
+// fuzzyFragmentLength is how many leading letters of a term a candidate title must contain
+// Typos rarely hit the start of a word, and requiring the fragment keeps the candidate set small
+const fuzzyFragmentLength = 3
+
+// fuzzyCandidateFinder is the part of ContentRepository the fuzzy fallback needs
+type fuzzyCandidateFinder interface {
+	FuzzyCandidates(ctx context.Context, req *model.SearchRequest, fragments []string, limit int) ([]*model.Content, error)
+}
+
+// fuzzySearch runs the fallback for a request whose keyword matched nothing
+// Matches are ordered closest first rather than by sort_by; returns the page and the total
+func fuzzySearch(ctx context.Context, finder fuzzyCandidateFinder, req *model.SearchRequest) ([]*model.Content, int, error) {
+	terms := highlightTerms(req.Query)
+	candidates, err := finder.FuzzyCandidates(ctx, req, fuzzyFragments(terms), fuzzyCandidateLimit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matches := rankFuzzyMatches(candidates, terms, req.MatchMode == model.MatchModeAll)
+	start := min(req.GetOffset(), len(matches))
+	end := min(start+req.PerPage, len(matches))
+	return matches[start:end], len(matches), nil
+}
+
+// fuzzyFragments returns the leading letters of each term, deduplicated
+func fuzzyFragments(terms [][]rune) []string {
+	var fragments []string
+	for _, term := range terms {
+		fragment := string(term[:min(len(term), fuzzyFragmentLength)])
+		if !slices.Contains(fragments, fragment) {
+			fragments = append(fragments, fragment)
+		}
+	}
+	return fragments
+}
+
+// rankFuzzyMatches keeps the candidates whose title has a word close enough to the terms
+// With requireAll every term needs a close word, otherwise one is enough. Candidates
+// matching more terms come first, then those with fewer edits, then by score
+func rankFuzzyMatches(candidates []*model.Content, terms [][]rune, requireAll bool) []*model.Content {
+	type fuzzyMatch struct {
+		content  *model.Content
+		matched  int
+		distance int
+	}
+
+	var matches []fuzzyMatch
+	for _, c := range candidates {
+		words := titleWords(c.Title)
+		m := fuzzyMatch{content: c}
+		for _, term := range terms {
+			if d, ok := closestWord(term, words); ok {
+				m.matched++
+				m.distance += d
+			}
+		}
+		if m.matched == 0 || (requireAll && m.matched < len(terms)) {
+			continue
+		}
+		matches = append(matches, m)
+	}
+
+	slices.SortStableFunc(matches, func(a, b fuzzyMatch) int {
+		switch {
+		case a.matched != b.matched:
+			return b.matched - a.matched
+		case a.distance != b.distance:
+			return a.distance - b.distance
+		case a.content.Score != b.content.Score:
+			if a.content.Score > b.content.Score {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+
+	contents := make([]*model.Content, len(matches))
+	for i, m := range matches {
+		contents[i] = m.content
+	}
+	return contents
+}
+
+// closestWord returns the fewest edits between term and any of words, if within textdist.MaxEdits
+func closestWord(term []rune, words [][]rune) (int, bool) {
+	best, found := 0, false
+	for _, word := range words {
+		if d, ok := textdist.Within(term, word); ok && (!found || d < best) {
+			best, found = d, true
+		}
+	}
+	return best, found
+}
+
+// titleWords splits a title into lowercased words of letters and digits
+func titleWords(title string) [][]rune {
+	fields := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := make([][]rune, len(fields))
+	for i, f := range fields {
+		words[i] = []rune(f)
+	}
+	return words
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"search-engine/backend/internal/model"
+	"strings"
+	"testing"
+)
+
+// fakeFuzzyFinder returns the seeded contents whose title contains a fragment, like the LIKE query
+type fakeFuzzyFinder struct {
+	contents     []*model.Content
+	gotFragments []string
+	gotLimit     int
+}
+
+func (f *fakeFuzzyFinder) FuzzyCandidates(_ context.Context, _ *model.SearchRequest, fragments []string, limit int) ([]*model.Content, error) {
+	f.gotFragments, f.gotLimit = fragments, limit
+	var found []*model.Content
+	for _, c := range f.contents {
+		for _, fragment := range fragments {
+			if strings.Contains(strings.ToLower(c.Title), fragment) {
+				found = append(found, c)
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+func seededFuzzyFinder() *fakeFuzzyFinder {
+	return &fakeFuzzyFinder{contents: []*model.Content{
+		{ID: 1, Title: "Kubernetes in Production", Score: 40},
+		{ID: 2, Title: "Docker and Kubernetes Basics", Score: 80},
+		{ID: 3, Title: "Kubeflow Pipelines", Score: 90},
+		{ID: 4, Title: "Docker Compose Guide", Score: 70},
+		{ID: 5, Title: "Go Concurrency Patterns", Score: 60},
+	}}
+}
+
+func fuzzyIDs(contents []*model.Content) []int64 {
+	ids := make([]int64, len(contents))
+	for i, c := range contents {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+func TestFuzzySearchFindsMisspelledTerms(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       model.SearchRequest
+		wantIDs   []int64
+		wantTotal int
+	}{
+		// Kubeflow shares the fragment but is too many edits away
+		{"misspelled word", model.SearchRequest{Query: "kubernets"}, []int64{2, 1}, 2},
+		{"transposed letters", model.SearchRequest{Query: "dockre"}, []int64{2, 4}, 2},
+		// Candidates must share the first letters, so an early typo goes unmatched
+		{"typo in the first letters", model.SearchRequest{Query: "dokcer"}, nil, 0},
+		{"any term", model.SearchRequest{Query: "dockr kubernets"}, []int64{2, 4, 1}, 3},
+		{"all terms", model.SearchRequest{Query: "dockr kubernets", MatchMode: model.MatchModeAll}, []int64{2}, 1},
+		{"closer match first", model.SearchRequest{Query: "kubernetess"}, []int64{2, 1}, 2},
+		{"short terms must match exactly", model.SearchRequest{Query: "gp"}, nil, 0},
+		{"nothing close", model.SearchRequest{Query: "terraform"}, nil, 0},
+		{"second page", model.SearchRequest{Query: "dockr kubernets", Page: 2, PerPage: 2}, []int64{1}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Validate()
+			contents, total, err := fuzzySearch(context.Background(), seededFuzzyFinder(), &tt.req)
+			if err != nil {
+				t.Fatalf("fuzzySearch() error = %v", err)
+			}
+			if got := fuzzyIDs(contents); total != tt.wantTotal || (len(got) > 0 || len(tt.wantIDs) > 0) && !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("fuzzySearch(%q) = %v of %d, want %v of %d", tt.req.Query, got, total, tt.wantIDs, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestFuzzySearchCandidateQuery(t *testing.T) {
+	finder := seededFuzzyFinder()
+	req := &model.SearchRequest{Query: `+Kubernets "kubectl" go`}
+	req.Validate()
+	if _, _, err := fuzzySearch(context.Background(), finder, req); err != nil {
+		t.Fatalf("fuzzySearch() error = %v", err)
+	}
+
+	if want := []string{"kub", "go"}; !reflect.DeepEqual(finder.gotFragments, want) {
+		t.Errorf("fragments = %q, want %q", finder.gotFragments, want)
+	}
+	if finder.gotLimit != fuzzyCandidateLimit {
+		t.Errorf("limit = %d, want %d", finder.gotLimit, fuzzyCandidateLimit)
+	}
+}
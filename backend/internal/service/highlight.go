@@ -0,0 +1,117 @@
+// highlight.go - Search result highlighting
+// Marks the parts of a title that match the query terms
+package service
+
+import (
+	"html"
+	"search-engine/backend/internal/model"
+	"strings"
+	"unicode"
+)
+
+// Default delimiters wrapped around matched terms
+const (
+	DefaultHighlightPreTag  = "<mark>"
+	DefaultHighlightPostTag = "</mark>"
+)
+
+// titleHighlighter wraps query term matches in a title with preTag and postTag
+type titleHighlighter struct {
+	preTag  string
+	postTag string
+}
+
+// highlightTerms splits a query into lowercased terms for highlighting
+// Boolean operators and quotes around a term are dropped, as the search ignores them too
+func highlightTerms(query string) [][]rune {
+	var terms [][]rune
+	seen := make(map[string]bool)
+	for _, field := range strings.Fields(query) {
+		term := strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if term == "" {
+			continue
+		}
+		lowered := []rune(strings.ToLower(term))
+		if key := string(lowered); !seen[key] {
+			seen[key] = true
+			terms = append(terms, lowered)
+		}
+	}
+	return terms
+}
+
+// highlight returns title as HTML with every case-insensitive match of terms marked
+// Overlapping and adjacent matches merge into one marked run, and the rest of the
+// title is HTML-escaped, so the result is safe to render as HTML
+func (h titleHighlighter) highlight(title string, terms [][]rune) string {
+	runes := []rune(title)
+	lowered := make([]rune, len(runes))
+	for i, r := range runes {
+		lowered[i] = unicode.ToLower(r)
+	}
+
+	marked := make([]bool, len(runes))
+	for _, term := range terms {
+		for start := 0; start+len(term) <= len(lowered); start++ {
+			if runesEqual(lowered[start:start+len(term)], term) {
+				for i := start; i < start+len(term); i++ {
+					marked[i] = true
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		end := i
+		for end < len(runes) && marked[end] == marked[i] {
+			end++
+		}
+		text := html.EscapeString(string(runes[i:end]))
+		if marked[i] {
+			b.WriteString(h.preTag)
+			b.WriteString(text)
+			b.WriteString(h.postTag)
+		} else {
+			b.WriteString(text)
+		}
+		i = end
+	}
+	return b.String()
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// withHighlights returns resp with HighlightedTitle set on every result for query, grouped or not
+// It fills a copy so cached responses, shared with unhighlighted searches, stay clean
+func (h titleHighlighter) withHighlights(resp *model.SearchResponse, query string) *model.SearchResponse {
+	terms := highlightTerms(query)
+	highlighted := *resp
+	highlighted.Results = make([]model.Content, len(resp.Results))
+	for i, content := range resp.Results {
+		content.HighlightedTitle = h.highlight(content.Title, terms)
+		highlighted.Results[i] = content
+	}
+	if resp.Groups != nil {
+		highlighted.Groups = make([]model.ProviderGroup, len(resp.Groups))
+		for i, group := range resp.Groups {
+			results := make([]model.Content, len(group.Results))
+			for j, content := range group.Results {
+				content.HighlightedTitle = h.highlight(content.Title, terms)
+				results[j] = content
+			}
+			group.Results = results
+			highlighted.Groups[i] = group
+		}
+	}
+	return &highlighted
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"search-engine/backend/internal/model"
+	"testing"
+)
+
+func TestHighlightTitle(t *testing.T) {
+	mark := titleHighlighter{preTag: DefaultHighlightPreTag, postTag: DefaultHighlightPostTag}
+
+	tests := []struct {
+		name  string
+		query string
+		title string
+		want  string
+	}{
+		{"single term", "go", "Go Tutorial", "<mark>Go</mark> Tutorial"},
+		{"every occurrence", "go", "Go go GO", "<mark>Go</mark> <mark>go</mark> <mark>GO</mark>"},
+		{"inside a word", "go", "Learn Golang", "Learn <mark>Go</mark>lang"},
+		{"multiple terms", "docker guide", "The Docker Beginner Guide", "The <mark>Docker</mark> Beginner <mark>Guide</mark>"},
+		{"overlapping terms", "gol lang", "Golang", "<mark>Golang</mark>"},
+		{"nested terms", "go golang", "golang basics", "<mark>golang</mark> basics"},
+		{"repeated letters", "aa", "aaa", "<mark>aaa</mark>"},
+		{"no match", "rust", "Go Tutorial", "Go Tutorial"},
+		{"no query", "", "Go Tutorial", "Go Tutorial"},
+		{"boolean operators ignored", `+go -"tutorial"`, "Go Tutorial", "<mark>Go</mark> <mark>Tutorial</mark>"},
+		{"non-ASCII", "über", "Über alles", "<mark>Über</mark> alles"},
+		{"surrounding text escaped", "go", `<script>alert("go")</script>`, "&lt;script&gt;alert(&#34;<mark>go</mark>&#34;)&lt;/script&gt;"},
+		{"matched text escaped", "a&b", "Tips a&b", "Tips <mark>a&amp;b</mark>"},
+		{"markup in the query", "<b>", "Use <b> tags", "Use &lt;<mark>b</mark>&gt; tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mark.highlight(tt.title, highlightTerms(tt.query)); got != tt.want {
+				t.Errorf("highlight(%q, %q) = %q, want %q", tt.title, tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightCustomDelimiters(t *testing.T) {
+	h := titleHighlighter{preTag: "[[", postTag: "]]"}
+	if got := h.highlight("Go & Rust", highlightTerms("rust go")); got != "[[Go]] &amp; [[Rust]]" {
+		t.Errorf("highlight() = %q", got)
+	}
+}
+
+func TestWithHighlightsLeavesResponseUntouched(t *testing.T) {
+	h := titleHighlighter{preTag: DefaultHighlightPreTag, postTag: DefaultHighlightPostTag}
+	cached := &model.SearchResponse{Results: []model.Content{{ID: 1, Title: "Go Tutorial"}}, Total: 1}
+
+	got := h.withHighlights(cached, "go")
+	if got.Results[0].HighlightedTitle != "<mark>Go</mark> Tutorial" {
+		t.Errorf("HighlightedTitle = %q", got.Results[0].HighlightedTitle)
+	}
+	if cached.Results[0].HighlightedTitle != "" {
+		t.Error("highlighting modified the cached response")
+	}
+}
@@ -6,20 +6,41 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"search-engine/backend/internal/config"
+	"search-engine/backend/internal/model"
 	"search-engine/backend/internal/repository"
 	"search-engine/backend/internal/scoring"
+	"search-engine/backend/internal/trace"
+	"time"
 )
 
+// scoreUpdateRetryBackoff is the base delay between score update attempts
+const scoreUpdateRetryBackoff = 100 * time.Millisecond
+
+// scoreBatchSize is how many content items a recalculation loads per query
+const scoreBatchSize = 100
+
+// scoreStore is the part of the content repository scoring reads and writes
+type scoreStore interface {
+	GetByID(ctx context.Context, id int64) (*model.Content, error)
+	GetByProviderID(ctx context.Context, providerID int, limit, offset int) ([]*model.Content, error)
+	GetAfterID(ctx context.Context, afterID int64, limit int) ([]*model.Content, error)
+	UpdateScore(ctx context.Context, id int64, score float64) error
+	UpdateScoresBatch(ctx context.Context, updates map[int64]float64) error
+}
+
 // ScoringService handles scoring operations for content
 // This service orchestrates scoring calculations and database updates
 type ScoringService struct {
-	contentRepo *repository.ContentRepository
+	contentRepo scoreStore
+	scoringCfg  config.ScoringConfig
 }
 
 // NewScoringService creates a new ScoringService instance
-func NewScoringService(contentRepo *repository.ContentRepository) *ScoringService {
+func NewScoringService(contentRepo *repository.ContentRepository, scoringCfg config.ScoringConfig) *ScoringService {
 	return &ScoringService{
 		contentRepo: contentRepo,
+		scoringCfg:  scoringCfg,
 	}
 }
 
@@ -36,10 +57,10 @@ func (s *ScoringService) CalculateScoreForContent(contentID int64) error {
 	}
 
 	// Calculate final score
-	score := scoring.CalculateFinalScore(content)
+	score := scoring.CalculateFinalScore(content, s.scoringCfg)
 
 	// Update score in database
-	if err := s.contentRepo.UpdateScore(contentID, score); err != nil {
+	if err := s.contentRepo.UpdateScore(ctx, contentID, score); err != nil {
 		return fmt.Errorf("failed to update score: %w", err)
 	}
 
@@ -49,74 +70,72 @@ func (s *ScoringService) CalculateScoreForContent(contentID int64) error {
 
 // RecalculateAllScores recalculates scores for all content items
 // This is useful when the scoring algorithm changes or for maintenance
-func (s *ScoringService) RecalculateAllScores() error {
+// Content is walked in id order in batches, so every item is scored once
+// whatever providers exist. Cancelling ctx stops the walk before the next batch
+func (s *ScoringService) RecalculateAllScores(ctx context.Context) error {
 	log.Println("Starting score recalculation for all content...")
 
-	// Get all content items in batches to avoid memory issues
-	// We'll use a simple approach: get all providers and iterate through them
-	// For now, we'll use a workaround by getting content with a high limit
-	// In production, you might want to add a GetAllContent method to repository
-	batchSize := 100
-	offset := 0
+	failed := 0
+	total := 0
+	var lastID int64
 
-	// Use a large provider ID range or implement a better method
-	// For simplicity, we'll fetch from provider 1 first, then 2, etc.
-	// This is a temporary solution - in production, add GetAllContent() method
-	for providerID := 1; providerID <= 10; providerID++ {
-		for {
-			// Fetch a batch of content items
-			contents, err := s.contentRepo.GetByProviderID(providerID, batchSize, offset)
-			if err != nil {
-				// Provider might not exist, skip to next
-				break
-			}
+	// Score every item against the same reference time so the run is consistent
+	asOf := time.Now()
 
-			// If no more content, we're done
-			if len(contents) == 0 {
-				break
-			}
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("score recalculation cancelled after id %d: %w", lastID, err)
+		}
 
-			// Calculate and update scores for this batch
-			updated := 0
-			for _, content := range contents {
-				score := scoring.CalculateFinalScore(content)
-				if err := s.contentRepo.UpdateScore(content.ID, score); err != nil {
-					log.Printf("Failed to update score for content %d: %v", content.ID, err)
-					continue
-				}
-				updated++
-			}
+		contents, err := s.contentRepo.GetAfterID(ctx, lastID, scoreBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get content batch after id %d: %w", lastID, err)
+		}
+		if len(contents) == 0 {
+			break
+		}
 
-			log.Printf("Updated scores for %d content items (offset: %d)", updated, offset)
+		updated, pageFailed := s.scorePage(ctx, contents, asOf)
+		failed += pageFailed
+		total += updated
+		lastID = contents[len(contents)-1].ID
 
-			// Move to next batch
-			offset += batchSize
+		trace.Logf(ctx, "Updated scores for %d content items (through id %d)", updated, lastID)
 
-			// If we got fewer items than batch size, move to next provider
-			if len(contents) < batchSize {
-				offset = 0
-				break
-			}
-			offset += batchSize
+		// A short batch is the last one
+		if len(contents) < scoreBatchSize {
+			break
 		}
-		offset = 0 // Reset for next provider
 	}
 
-	log.Println("Score recalculation completed")
+	if err := s.checkFailureBudget(ctx, failed, "all content"); err != nil {
+		return err
+	}
+
+	trace.Logf(ctx, "Score recalculation completed for %d content items", total)
 	return nil
 }
 
 // RecalculateScoresForProvider recalculates scores for all content from a specific provider
 // This is useful after syncing data from a provider
-func (s *ScoringService) RecalculateScoresForProvider(providerID int) error {
-	log.Printf("Starting score recalculation for provider %d...", providerID)
+// Cancelling ctx stops the recalculation before the next batch
+func (s *ScoringService) RecalculateScoresForProvider(ctx context.Context, providerID int) error {
+	trace.Logf(ctx, "Starting score recalculation for provider %d...", providerID)
 
-	batchSize := 100
+	batchSize := scoreBatchSize
 	offset := 0
+	failed := 0
+
+	// Score every item against the same reference time so the run is consistent
+	asOf := time.Now()
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("score recalculation for provider %d cancelled at offset %d: %w", providerID, offset, err)
+		}
+
 		// Fetch a batch of content items for this provider
-		contents, err := s.contentRepo.GetByProviderID(providerID, batchSize, offset)
+		contents, err := s.contentRepo.GetByProviderID(ctx, providerID, batchSize, offset)
 		if err != nil {
 			return fmt.Errorf("failed to get content batch: %w", err)
 		}
@@ -127,17 +146,10 @@ func (s *ScoringService) RecalculateScoresForProvider(providerID int) error {
 		}
 
 		// Calculate and update scores for this batch
-		updated := 0
-		for _, content := range contents {
-			score := scoring.CalculateFinalScore(content)
-			if err := s.contentRepo.UpdateScore(content.ID, score); err != nil {
-				log.Printf("Failed to update score for content %d: %v", content.ID, err)
-				continue
-			}
-			updated++
-		}
+		updated, pageFailed := s.scorePage(ctx, contents, asOf)
+		failed += pageFailed
 
-		log.Printf("Updated scores for %d content items from provider %d (offset: %d)", updated, providerID, offset)
+		trace.Logf(ctx, "Updated scores for %d content items from provider %d (offset: %d)", updated, providerID, offset)
 
 		// Move to next batch
 		offset += batchSize
@@ -148,6 +160,108 @@ func (s *ScoringService) RecalculateScoresForProvider(providerID int) error {
 		}
 	}
 
-	log.Printf("Score recalculation completed for provider %d", providerID)
+	if err := s.checkFailureBudget(ctx, failed, fmt.Sprintf("provider %d", providerID)); err != nil {
+		return err
+	}
+
+	trace.Logf(ctx, "Score recalculation completed for provider %d", providerID)
+	return nil
+}
+
+// scorePage scores a page of content and writes the scores in one batch
+// If the batch still fails after retries, which leaves every score unwritten,
+// each score is written on its own so one bad row can't cost the whole page
+// The fallback gives up the batch's atomicity: rows that fail, or a cancellation
+// partway through, leave the page partly rescored, with the rest keeping old scores
+// Returns how many scores were written and how many failed
+func (s *ScoringService) scorePage(ctx context.Context, contents []*model.Content, asOf time.Time) (updated, failed int) {
+	scores := make(map[int64]float64, len(contents))
+	for _, content := range contents {
+		scores[content.ID] = scoring.CalculateFinalScoreAt(content, s.scoringCfg, asOf)
+	}
+
+	err := s.updateScoresBatchWithRetry(ctx, scores)
+	if err == nil {
+		return len(scores), 0
+	}
+	if ctx.Err() != nil {
+		// Cancelled: writing row by row would only fail the same way
+		return 0, len(scores)
+	}
+	trace.Logf(ctx, "Batch score update for %d content items failed, writing them one by one: %v", len(scores), err)
+
+	for _, content := range contents {
+		if err := s.updateScoreWithRetry(ctx, content.ID, scores[content.ID]); err != nil {
+			trace.Logf(ctx, "Failed to update score for content %d: %v", content.ID, err)
+			failed++
+			continue
+		}
+		updated++
+	}
+	return updated, failed
+}
+
+// updateScoresBatchWithRetry writes a batch of scores, retrying transient failures
+// Uses the same attempts and backoff as updateScoreWithRetry
+func (s *ScoringService) updateScoresBatchWithRetry(ctx context.Context, scores map[int64]float64) error {
+	var err error
+	for attempt := range s.updateAttempts() {
+		if attempt > 0 {
+			if waitErr := sleepContext(ctx, time.Duration(attempt)*scoreUpdateRetryBackoff); waitErr != nil {
+				return waitErr
+			}
+		}
+		if err = s.contentRepo.UpdateScoresBatch(ctx, scores); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// updateScoreWithRetry writes a score, retrying transient failures
+// Makes up to 1 + UpdateRetries attempts with a short linear backoff
+func (s *ScoringService) updateScoreWithRetry(ctx context.Context, contentID int64, score float64) error {
+	var err error
+	for attempt := range s.updateAttempts() {
+		if attempt > 0 {
+			if waitErr := sleepContext(ctx, time.Duration(attempt)*scoreUpdateRetryBackoff); waitErr != nil {
+				return waitErr
+			}
+		}
+		if err = s.contentRepo.UpdateScore(ctx, contentID, score); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// updateAttempts is how many times a score write is tried: once, plus UpdateRetries
+// A negative UpdateRetries counts as none, so the write is still made
+func (s *ScoringService) updateAttempts() int {
+	return 1 + max(0, s.scoringCfg.UpdateRetries)
+}
+
+// sleepContext waits for d, returning ctx's error early if it is cancelled first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// checkFailureBudget returns an error when more rows failed than MaxUpdateFailures allows
+// so callers know the recalculation was incomplete and rankings may be inconsistent
+func (s *ScoringService) checkFailureBudget(ctx context.Context, failed int, scope string) error {
+	if failed == 0 {
+		return nil
+	}
+	if failed > s.scoringCfg.MaxUpdateFailures {
+		return fmt.Errorf("score recalculation incomplete for %s: %d updates failed (budget %d)", scope, failed, s.scoringCfg.MaxUpdateFailures)
+	}
+	trace.Logf(ctx, "Score recalculation for %s finished with %d failed updates (within budget %d)", scope, failed, s.scoringCfg.MaxUpdateFailures)
 	return nil
 }
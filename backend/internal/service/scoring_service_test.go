@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"search-engine/backend/internal/config"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/internal/scoring"
+	"sort"
+	"testing"
+	"time"
+)
+
+// fakeScoreStore is an in-memory scoreStore that counts score updates per content ID
+// A failing batch writes nothing, like the transaction behind UpdateScoresBatch
+type fakeScoreStore struct {
+	contents  map[int64]*model.Content
+	updates   map[int64]int
+	batches   int // UpdateScoresBatch calls, failed ones included
+	singles   int // UpdateScore calls
+	failBatch bool
+	onBatch   func() // Called after each successful batch
+}
+
+func (f *fakeScoreStore) GetByID(_ context.Context, id int64) (*model.Content, error) {
+	c, ok := f.contents[id]
+	if !ok {
+		return nil, fmt.Errorf("content %d not found", id)
+	}
+	return c, nil
+}
+
+func (f *fakeScoreStore) GetByProviderID(_ context.Context, providerID int, limit, offset int) ([]*model.Content, error) {
+	var matched []*model.Content
+	for _, c := range f.sorted() {
+		if c.ProviderID == providerID {
+			matched = append(matched, c)
+		}
+	}
+	if offset >= len(matched) {
+		return nil, nil
+	}
+	return matched[offset:min(offset+limit, len(matched))], nil
+}
+
+func (f *fakeScoreStore) GetAfterID(_ context.Context, afterID int64, limit int) ([]*model.Content, error) {
+	var page []*model.Content
+	for _, c := range f.sorted() {
+		if c.ID > afterID && len(page) < limit {
+			page = append(page, c)
+		}
+	}
+	return page, nil
+}
+
+func (f *fakeScoreStore) UpdateScoresBatch(_ context.Context, updates map[int64]float64) error {
+	f.batches++
+	if f.failBatch {
+		return fmt.Errorf("lock wait timeout exceeded")
+	}
+	for id, score := range updates {
+		f.updates[id]++
+		f.contents[id].Score = score
+	}
+	if f.onBatch != nil {
+		f.onBatch()
+	}
+	return nil
+}
+
+func (f *fakeScoreStore) UpdateScore(_ context.Context, id int64, score float64) error {
+	f.singles++
+	f.updates[id]++
+	f.contents[id].Score = score
+	return nil
+}
+
+func (f *fakeScoreStore) sorted() []*model.Content {
+	all := make([]*model.Content, 0, len(f.contents))
+	for _, c := range f.contents {
+		all = append(all, c)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all
+}
+
+func TestRecalculateAllScoresCoversEveryProvider(t *testing.T) {
+	// More items than a batch, with gaps in both content and provider IDs,
+	// including providers beyond the old hard-coded 1-10 range
+	store := &fakeScoreStore{contents: map[int64]*model.Content{}, updates: map[int64]int{}}
+	providerIDs := []int{3, 17, 42}
+	published := time.Now().Add(-48 * time.Hour)
+	for i := 0; i < 2*scoreBatchSize+7; i++ {
+		id := int64(i*3 + 5)
+		store.contents[id] = &model.Content{
+			ID:          id,
+			ProviderID:  providerIDs[i%len(providerIDs)],
+			Type:        model.ContentTypeVideo,
+			Views:       1000,
+			Likes:       10,
+			PublishedAt: published,
+		}
+	}
+
+	s := &ScoringService{contentRepo: store, scoringCfg: config.ScoringConfig{}}
+	if err := s.RecalculateAllScores(context.Background()); err != nil {
+		t.Fatalf("RecalculateAllScores: %v", err)
+	}
+
+	assertScoredOnce(t, store)
+	if store.batches != 3 || store.singles != 0 {
+		t.Errorf("batches = %d, single updates = %d; want 3 batches and no single updates", store.batches, store.singles)
+	}
+}
+
+// newProviderScoreStore seeds n video items of one provider
+func newProviderScoreStore(providerID, n int) *fakeScoreStore {
+	store := &fakeScoreStore{contents: map[int64]*model.Content{}, updates: map[int64]int{}}
+	published := time.Now().Add(-48 * time.Hour)
+	for i := 1; i <= n; i++ {
+		store.contents[int64(i)] = &model.Content{
+			ID: int64(i), ProviderID: providerID, Type: model.ContentTypeVideo, Views: 100 * i, PublishedAt: published,
+		}
+	}
+	return store
+}
+
+// assertScoredOnce checks that every item got exactly one positive score
+func assertScoredOnce(t *testing.T, store *fakeScoreStore) {
+	t.Helper()
+	for id, c := range store.contents {
+		if got := store.updates[id]; got != 1 {
+			t.Errorf("content %d (provider %d) scored %d times, want 1", id, c.ProviderID, got)
+		}
+		if c.Score <= 0 {
+			t.Errorf("content %d score = %v, want a positive score", id, c.Score)
+		}
+	}
+}
+
+func TestRecalculateScoresForProviderBatchesPerPage(t *testing.T) {
+	store := newProviderScoreStore(7, scoreBatchSize+20)
+	s := &ScoringService{contentRepo: store}
+
+	if err := s.RecalculateScoresForProvider(context.Background(), 7); err != nil {
+		t.Fatalf("RecalculateScoresForProvider: %v", err)
+	}
+
+	assertScoredOnce(t, store)
+	if store.batches != 2 || store.singles != 0 {
+		t.Errorf("batches = %d, single updates = %d; want 2 batches and no single updates", store.batches, store.singles)
+	}
+
+	want := scoring.CalculateFinalScore(store.contents[5], config.ScoringConfig{})
+	if got := store.contents[5].Score; math.Abs(got-want) > 1e-6 {
+		t.Errorf("content 5 score = %v, want %v", got, want)
+	}
+}
+
+func TestRecalculateScoresFallsBackWhenBatchFails(t *testing.T) {
+	store := newProviderScoreStore(7, 30)
+	store.failBatch = true
+	s := &ScoringService{contentRepo: store, scoringCfg: config.ScoringConfig{UpdateRetries: 1}}
+
+	if err := s.RecalculateScoresForProvider(context.Background(), 7); err != nil {
+		t.Fatalf("RecalculateScoresForProvider: %v", err)
+	}
+
+	// The failed batch wrote nothing, so each item is written exactly once by the fallback
+	assertScoredOnce(t, store)
+	if store.batches != 2 || store.singles != 30 {
+		t.Errorf("batches = %d, single updates = %d; want 2 batch attempts and 30 single updates", store.batches, store.singles)
+	}
+}
+
+func TestRecalculateScoresWithNegativeRetriesStillWrites(t *testing.T) {
+	store := newProviderScoreStore(7, 30)
+	store.failBatch = true
+	s := &ScoringService{contentRepo: store, scoringCfg: config.ScoringConfig{UpdateRetries: -1}}
+
+	if err := s.RecalculateScoresForProvider(context.Background(), 7); err != nil {
+		t.Fatalf("RecalculateScoresForProvider: %v", err)
+	}
+	assertScoredOnce(t, store)
+	if store.batches != 1 || store.singles != 30 {
+		t.Errorf("batches = %d, single updates = %d; want 1 batch attempt and 30 single updates", store.batches, store.singles)
+	}
+}
+
+func TestRecalculateAllScoresWithoutContent(t *testing.T) {
+	store := &fakeScoreStore{contents: map[int64]*model.Content{}, updates: map[int64]int{}}
+	s := &ScoringService{contentRepo: store}
+	if err := s.RecalculateAllScores(context.Background()); err != nil {
+		t.Errorf("RecalculateAllScores() on empty content = %v, want nil", err)
+	}
+}
+
+func TestRecalculationStopsWhenCancelled(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(s *ScoringService, ctx context.Context) error
+	}{
+		{"all content", func(s *ScoringService, ctx context.Context) error { return s.RecalculateAllScores(ctx) }},
+		{"one provider", func(s *ScoringService, ctx context.Context) error { return s.RecalculateScoresForProvider(ctx, 7) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newProviderScoreStore(7, 3*scoreBatchSize)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			store.onBatch = cancel // Cancel once the first page is written
+			s := &ScoringService{contentRepo: store}
+
+			err := tt.run(s, ctx)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("error = %v, want context.Canceled", err)
+			}
+			if store.batches != 1 || len(store.updates) != scoreBatchSize {
+				t.Errorf("batches = %d, scored = %d; want the recalculation to stop after 1 batch of %d", store.batches, len(store.updates), scoreBatchSize)
+			}
+		})
+	}
+}
+
+func TestRecalculationWithCancelledContextWritesNothing(t *testing.T) {
+	store := newProviderScoreStore(7, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := &ScoringService{contentRepo: store}
+
+	if err := s.RecalculateAllScores(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("RecalculateAllScores() = %v, want context.Canceled", err)
+	}
+	if store.batches != 0 || store.singles != 0 {
+		t.Errorf("batches = %d, single updates = %d; want no writes", store.batches, store.singles)
+	}
+}
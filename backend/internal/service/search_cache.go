@@ -0,0 +1,79 @@
+// search_cache.go - Typed cache for search responses
+// Keeps serialization out of SearchService while working with any cache.Cache
+package service
+
+import (
+	"log"
+	"search-engine/backend/internal/config"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/pkg/cache"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// searchCacheNamespace is the cache generation namespace of search responses
+const searchCacheNamespace = "search"
+
+// InvalidateSearchCache bumps the search cache generation so every cached search
+// response is bypassed from now on; a nil cache is a no-op
+// Called after anything that changes content or scores, e.g. a sync
+func InvalidateSearchCache(c cache.Cache) {
+	if c == nil {
+		return
+	}
+	if s, ok := c.(*SearchResponseCache); ok && s == nil {
+		return
+	}
+	gen := c.BumpGeneration(searchCacheNamespace)
+	if gen == 0 {
+		log.Printf("Warning: search cache not invalidated; cached searches stay until their TTL")
+		return
+	}
+	log.Printf("Search cache invalidated (generation %d)", gen)
+}
+
+// InvalidateSharedSearchCache invalidates the API's search cache from another process,
+// such as the sync and seed commands after they change content and scores
+// Only a Redis cache is shared: the API's in-memory cache can't be reached, and its
+// entries expire with their TTL
+func InvalidateSharedSearchCache(cfg config.RedisConfig) {
+	if !cfg.Enabled {
+		log.Println("Search cache is in-memory in the API process; cached searches expire with their TTL")
+		return
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	defer client.Close()
+	InvalidateSearchCache(&cache.RedisCacheWrapper{Client: client})
+}
+
+// SearchResponseCache stores search responses in a cache.Cache as JSON
+// It embeds the Cache, so it still works anywhere the generic interface does
+type SearchResponseCache struct {
+	cache.Cache
+}
+
+// NewSearchResponseCache wraps c; a nil c gives a nil cache, which disables caching
+func NewSearchResponseCache(c cache.Cache) *SearchResponseCache {
+	if c == nil {
+		return nil
+	}
+	return &SearchResponseCache{Cache: c}
+}
+
+// GetSearchResponse returns the response cached under key
+func (c *SearchResponseCache) GetSearchResponse(key string) (*model.SearchResponse, bool) {
+	return cache.GetJSON[model.SearchResponse](c.Cache, key)
+}
+
+// SetSearchResponse caches resp under key for ttl
+// A response that fails to encode is logged and left uncached
+func (c *SearchResponseCache) SetSearchResponse(key string, resp *model.SearchResponse, ttl time.Duration) {
+	if err := cache.SetJSON(c.Cache, key, resp, ttl); err != nil {
+		log.Printf("Warning: search response not cached: %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"search-engine/backend/internal/config"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/internal/repository"
+	"search-engine/backend/pkg/cache"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestSearchResponseCacheRoundTrip(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	backends := map[string]cache.Cache{
+		"in-memory": cache.NewInMemoryCache(time.Minute, 0),
+		"redis":     &cache.RedisCacheWrapper{Client: client},
+	}
+
+	published := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	want := &model.SearchResponse{
+		Results: []model.Content{
+			{ID: 1, Title: "Go Tutorial", Type: model.ContentTypeVideo, Views: 10, PublishedAt: published, Tags: []string{"go"}},
+		},
+		Total:          1,
+		Page:           1,
+		PerPage:        10,
+		TotalPages:     1,
+		ResultChecksum: "abc",
+	}
+
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			c := NewSearchResponseCache(backend)
+			c.SetSearchResponse("search:q=go", want, time.Minute)
+
+			got, ok := c.GetSearchResponse("search:q=go")
+			if !ok {
+				t.Fatal("GetSearchResponse missed a stored response")
+			}
+			if got.Total != want.Total || got.ResultChecksum != want.ResultChecksum || len(got.Results) != 1 {
+				t.Fatalf("GetSearchResponse = %+v, want %+v", got, want)
+			}
+			r := got.Results[0]
+			if r.ID != 1 || r.Title != "Go Tutorial" || r.Views != 10 || !r.PublishedAt.Equal(published) || len(r.Tags) != 1 {
+				t.Errorf("result = %+v, want %+v", r, want.Results[0])
+			}
+
+			if _, ok := c.GetSearchResponse("search:q=other"); ok {
+				t.Error("GetSearchResponse hit for a missing key")
+			}
+
+			// The wrapper still satisfies the generic interface
+			if _, ok := c.TTL("search:q=go"); !ok {
+				t.Error("TTL through the wrapper missed the stored key")
+			}
+		})
+	}
+}
+
+func TestNewSearchResponseCacheNil(t *testing.T) {
+	if c := NewSearchResponseCache(nil); c != nil {
+		t.Errorf("NewSearchResponseCache(nil) = %v, want nil", c)
+	}
+}
+
+func TestInvalidateCacheBypassesStaleEntries(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	backends := map[string]cache.Cache{
+		"in-memory": cache.NewInMemoryCache(time.Minute, 0),
+		"redis":     &cache.RedisCacheWrapper{Client: client},
+	}
+
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			svc := NewSearchService(nil, backend, SearchServiceOptions{})
+			req := model.SearchRequest{Query: "go"}
+
+			staleKey := svc.CacheKey(&req)
+			svc.cache.SetSearchResponse(staleKey, &model.SearchResponse{Total: 1}, time.Minute)
+			if _, ok := svc.cache.GetSearchResponse(svc.CacheKey(&req)); !ok {
+				t.Fatal("cached response missed before invalidation")
+			}
+
+			svc.InvalidateCache()
+
+			freshKey := svc.CacheKey(&req)
+			if freshKey == staleKey {
+				t.Fatalf("CacheKey() = %q after invalidation, want a new key", freshKey)
+			}
+			if _, ok := svc.cache.GetSearchResponse(freshKey); ok {
+				t.Error("stale response served after invalidation")
+			}
+		})
+	}
+}
+
+func TestInvalidateSearchCacheNil(t *testing.T) {
+	// Must not panic when caching is disabled
+	InvalidateSearchCache(nil)
+	NewSearchService(nil, nil, SearchServiceOptions{}).InvalidateCache()
+}
+
+func TestSearchServiceCountsCacheHitsAndMisses(t *testing.T) {
+	s := NewSearchService(nil, cache.NewInMemoryCache(time.Minute, 0), SearchServiceOptions{})
+	key := buildSearchCacheKey(&model.SearchRequest{Query: "go"}, 0)
+
+	if _, ok := s.cachedResponse(key); ok {
+		t.Fatal("cachedResponse hit before anything was cached")
+	}
+	s.cache.SetSearchResponse(key, &model.SearchResponse{Total: 1}, time.Minute)
+	if _, ok := s.cachedResponse(key); !ok {
+		t.Fatal("cachedResponse missed a cached response")
+	}
+
+	want := model.CacheStats{Hits: 1, Misses: 1, HitRatio: 0.5}
+	if got := s.CacheStats(); got != want {
+		t.Errorf("CacheStats() = %+v, want %+v", got, want)
+	}
+	if got := s.ResetCacheStats(); got != want {
+		t.Errorf("ResetCacheStats() = %+v, want the previous %+v", got, want)
+	}
+	if got := s.CacheStats(); got != (model.CacheStats{}) {
+		t.Errorf("CacheStats() after reset = %+v, want zeros", got)
+	}
+}
+
+func TestSearchCacheKeyUpdatedSince(t *testing.T) {
+	since := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	later := since.Add(time.Minute)
+
+	all := buildSearchCacheKey(&model.SearchRequest{Query: "go"}, 0)
+	recent := buildSearchCacheKey(&model.SearchRequest{Query: "go", UpdatedSince: &since}, 0)
+	moreRecent := buildSearchCacheKey(&model.SearchRequest{Query: "go", UpdatedSince: &later}, 0)
+
+	if all == recent || recent == moreRecent {
+		t.Errorf("keys %q, %q and %q should all differ", all, recent, moreRecent)
+	}
+}
+
+func TestSearchCacheKeyGroupByProvider(t *testing.T) {
+	flat := buildSearchCacheKey(&model.SearchRequest{Query: "go"}, 0)
+	grouped := buildSearchCacheKey(&model.SearchRequest{Query: "go", GroupByProvider: true}, 0)
+	if flat == grouped {
+		t.Errorf("grouped and flat searches share the key %q", flat)
+	}
+}
+
+func TestSearchCacheKeyIncludeFacets(t *testing.T) {
+	plain := buildSearchCacheKey(&model.SearchRequest{Query: "go"}, 0)
+	faceted := buildSearchCacheKey(&model.SearchRequest{Query: "go", IncludeFacets: true}, 0)
+	if plain == faceted {
+		t.Errorf("faceted and plain searches share the key %q", plain)
+	}
+}
+
+// unreadableGenerationCache is a cache whose generation can't be read, like Redis going away
+type unreadableGenerationCache struct {
+	cache.Cache
+}
+
+func (unreadableGenerationCache) Generation(string) (int64, bool) { return 0, false }
+
+func TestSearchBypassesCacheWithoutGeneration(t *testing.T) {
+	// Nothing listens on port 1, so a search that reaches the database fails fast
+	db, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/search?timeout=1s")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	backend := unreadableGenerationCache{Cache: cache.NewInMemoryCache(time.Minute, 0)}
+	s := NewSearchService(repository.NewContentRepository(db, 3), backend, SearchServiceOptions{})
+
+	// An entry cached before the first bump, under generation 0
+	req := model.SearchRequest{Query: "golang"}
+	s.cache.SetSearchResponse(s.CacheKey(&req), &model.SearchResponse{Total: 1}, time.Minute)
+
+	if _, err := s.Search(context.Background(), &model.SearchRequest{Query: "golang"}); err == nil {
+		t.Error("search was served from an entry cached under a guessed generation")
+	}
+	if stats := s.CacheStats(); stats.Hits != 0 || stats.Misses != 0 {
+		t.Errorf("cache stats = %+v, want the cache bypassed", stats)
+	}
+}
+
+func TestInvalidateSharedSearchCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	InvalidateSharedSearchCache(config.RedisConfig{Enabled: true, Addr: mr.Addr()})
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	if gen, ok := (&cache.RedisCacheWrapper{Client: client}).Generation(searchCacheNamespace); !ok || gen != 1 {
+		t.Errorf("generation after invalidating = %d, %t; want 1", gen, ok)
+	}
+}
@@ -4,12 +4,15 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"search-engine/backend/internal/errors"
 	"search-engine/backend/internal/model"
 	"search-engine/backend/internal/repository"
 	"search-engine/backend/pkg/cache"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,33 +20,87 @@ import (
 // This service orchestrates search queries, result processing, and response formatting
 type SearchService struct {
 	contentRepo        *repository.ContentRepository
-	cache              cache.Cache
+	cache              *SearchResponseCache // nil disables caching
 	cacheTTL           time.Duration
 	queryTimeout       time.Duration
+	maxQueryTimeout    time.Duration
 	simpleQueryTimeout time.Duration
+	maxResultWindow    int
+	maxPerPage         int
+	prefixMatch        bool
+	emptyResultHints   bool
+	highlighter        titleHighlighter
+	scoringDegraded    atomic.Bool // Score ranking disabled; toggled at runtime by operators
+	cacheHits          atomic.Int64
+	cacheMisses        atomic.Int64
+}
+
+// SearchServiceOptions holds the tunable settings of a SearchService
+// Zero values fall back to the defaults noted on each field
+type SearchServiceOptions struct {
+	CacheTTL           time.Duration // How long search responses stay cached (default: 1m)
+	QueryTimeout       time.Duration // Timeout for search queries (default: 15s)
+	MaxQueryTimeout    time.Duration // Caps per-request timeout overrides (default: QueryTimeout)
+	SimpleQueryTimeout time.Duration // Timeout for simple queries like tag loading (default: 5s)
+	MaxResultWindow    int           // Caps page * per_page (0 disables the check)
+	MaxPerPage         int           // Caps per_page (default: model.DefaultMaxPerPage)
+	PrefixMatch        bool          // Whether FULLTEXT terms prefix-match when a request doesn't say
+	EmptyResultHints   bool          // Attach a hint explaining empty result sets
+	HighlightPreTag    string        // Inserted before matched terms in highlighted titles (default: DefaultHighlightPreTag)
+	HighlightPostTag   string        // Inserted after matched terms in highlighted titles (default: DefaultHighlightPostTag)
+	ScoringDegraded    bool          // Start with score-based ranking disabled
 }
 
 // NewSearchService creates a new SearchService instance
 // cache can be nil to disable caching.
-// queryTimeout is the timeout for search queries (default: 15s)
-// simpleQueryTimeout is the timeout for simple queries like GetByID (default: 5s)
-func NewSearchService(contentRepo *repository.ContentRepository, cache cache.Cache, cacheTTL, queryTimeout, simpleQueryTimeout time.Duration) *SearchService {
-	if cacheTTL <= 0 {
-		cacheTTL = time.Minute
+func NewSearchService(contentRepo *repository.ContentRepository, cache cache.Cache, opts SearchServiceOptions) *SearchService {
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = time.Minute
+	}
+	if opts.QueryTimeout <= 0 {
+		opts.QueryTimeout = 15 * time.Second
+	}
+	if opts.MaxQueryTimeout < opts.QueryTimeout {
+		opts.MaxQueryTimeout = opts.QueryTimeout
 	}
-	if queryTimeout <= 0 {
-		queryTimeout = 15 * time.Second
+	if opts.SimpleQueryTimeout <= 0 {
+		opts.SimpleQueryTimeout = 5 * time.Second
 	}
-	if simpleQueryTimeout <= 0 {
-		simpleQueryTimeout = 5 * time.Second
+	if opts.MaxPerPage <= 0 {
+		opts.MaxPerPage = model.DefaultMaxPerPage
 	}
-	return &SearchService{
+	if opts.HighlightPreTag == "" && opts.HighlightPostTag == "" {
+		opts.HighlightPreTag, opts.HighlightPostTag = DefaultHighlightPreTag, DefaultHighlightPostTag
+	}
+	s := &SearchService{
 		contentRepo:        contentRepo,
-		cache:              cache,
-		cacheTTL:           cacheTTL,
-		queryTimeout:       queryTimeout,
-		simpleQueryTimeout: simpleQueryTimeout,
+		cache:              NewSearchResponseCache(cache),
+		cacheTTL:           opts.CacheTTL,
+		queryTimeout:       opts.QueryTimeout,
+		maxQueryTimeout:    opts.MaxQueryTimeout,
+		simpleQueryTimeout: opts.SimpleQueryTimeout,
+		maxResultWindow:    opts.MaxResultWindow,
+		maxPerPage:         opts.MaxPerPage,
+		prefixMatch:        opts.PrefixMatch,
+		emptyResultHints:   opts.EmptyResultHints,
+		highlighter:        titleHighlighter{preTag: opts.HighlightPreTag, postTag: opts.HighlightPostTag},
 	}
+	s.scoringDegraded.Store(opts.ScoringDegraded)
+	return s
+}
+
+// RankingDisabledNotice is attached to search responses whose score ordering was replaced
+const RankingDisabledNotice = "score-based ranking is temporarily disabled; results are ordered by published_at"
+
+// SetScoringDegraded turns degraded mode on or off
+// While on, searches that would order by score are ordered by published_at DESC instead
+func (s *SearchService) SetScoringDegraded(degraded bool) {
+	s.scoringDegraded.Store(degraded)
+}
+
+// ScoringDegraded reports whether score-based ranking is currently disabled
+func (s *SearchService) ScoringDegraded() bool {
+	return s.scoringDegraded.Load()
 }
 
 // Search performs a search query and returns formatted results
@@ -54,50 +111,108 @@ func (s *SearchService) Search(ctx context.Context, req *model.SearchRequest) (*
 	// Validate and set default values for the request
 	// This ensures we have valid parameters even if client doesn't provide them
 	req.Validate()
+	rankingDisabled := s.applyDefaults(req)
+
+	// Reject requests that reach too deep into the result set
+	// Large pulls should go through cursor/export APIs instead
+	if err := req.CheckResultWindow(s.maxResultWindow); err != nil {
+		return nil, errors.NewValidationErrorWithDetails("Result window is too large", err.Error())
+	}
+
+	// Check the cursor against the final sort, which degraded mode may have changed
+	if _, err := req.Cursor(); err != nil {
+		return nil, errors.NewFieldValidationError("Invalid cursor", map[string]string{"after": err.Error()})
+	}
+	if req.GroupByProvider && req.DistinctTitles {
+		return nil, errors.NewFieldValidationError("Invalid search mode", map[string]string{
+			"group_by_provider": "group_by_provider can't be combined with distinct_titles",
+		})
+	}
 
+	// NoCache skips the read only; the fresh result below still refreshes the entry
+	// An unreadable generation leaves cacheKey empty, bypassing the cache both ways
 	cacheKey := ""
 	if s.cache != nil {
-		cacheKey = buildSearchCacheKey(req)
-		if cached, ok := s.cache.Get(cacheKey); ok {
-			switch v := cached.(type) {
-			case *model.SearchResponse:
-				return v, nil
-			case []byte:
-				var resp model.SearchResponse
-				if err := json.Unmarshal(v, &resp); err == nil {
-					return &resp, nil
-				}
-			}
+		if generation, ok := s.cache.Generation(searchCacheNamespace); ok {
+			cacheKey = buildSearchCacheKey(req, generation)
+		}
+	}
+	if cacheKey != "" && !req.NoCache {
+		if cached, ok := s.cachedResponse(cacheKey); ok {
+			return s.finishResponse(cached, req, rankingDisabled), nil
 		}
 	}
 
 	// Apply timeout for search query (longer timeout for complex searches)
-	searchCtx, cancel := context.WithTimeout(ctx, s.queryTimeout)
+	// Clients may override it per request, bounded by maxQueryTimeout
+	searchCtx, cancel := errors.WithQueryTimeout(ctx, s.effectiveQueryTimeout(req))
 	defer cancel()
 
 	// Perform the search using the repository
 	// The repository handles the actual database query with filtering and sorting
-	contents, total, err := s.contentRepo.Search(searchCtx, req)
+	var contents []*model.Content
+	var providerCounts map[int]int
+	var facets *model.SearchFacets
+	var total, collapsed int
+	var err error
+	switch {
+	case req.GroupByProvider:
+		contents, providerCounts, total, err = s.contentRepo.SearchByProvider(searchCtx, req)
+	case req.DistinctTitles:
+		contents, total, collapsed, err = s.contentRepo.SearchDistinctTitles(searchCtx, req)
+	case req.IncludeFacets:
+		contents, total, facets, err = s.contentRepo.SearchWithFacets(searchCtx, req)
+	default:
+		contents, total, err = s.contentRepo.Search(searchCtx, req)
+	}
+
+	// The other search modes count their facets separately, over the same filters
+	if err == nil && req.IncludeFacets && (req.GroupByProvider || req.DistinctTitles) {
+		facets, err = s.contentRepo.Facets(searchCtx, req)
+	}
 	if err != nil {
 		// Check if it's already an AppError
 		if appErr := errors.AsAppError(err); appErr != nil {
 			return nil, appErr
 		}
 
-		if searchCtx.Err() == context.DeadlineExceeded {
-			return nil, errors.NewQueryTimeoutError("search")
+		// A timeout or a client that went away, told apart by FromContext
+		if appErr := errors.FromContext(searchCtx, "search"); appErr != nil {
+			return nil, appErr
 		}
 		return nil, errors.NewServiceError("search content", err)
 	}
 
+	// Nothing matched the keyword as typed; with fuzzy=true look for near spellings
+	// A failed fallback leaves the empty result as it was
+	fuzzy := false
+	if req.Fuzzy && !req.GroupByProvider && total == 0 && strings.TrimSpace(req.Query) != "" && (req.After == nil || *req.After == "") {
+		fuzzyContents, fuzzyTotal, err := fuzzySearch(searchCtx, s.contentRepo, req)
+		switch {
+		case err != nil:
+			fmt.Printf("Warning: fuzzy search failed: %v\n", err)
+		case fuzzyTotal > 0:
+			contents, total, fuzzy = fuzzyContents, fuzzyTotal, true
+		}
+	}
+
+	// A timed-out COUNT leaves the total unknown; estimate it so pagination still works
+	totalIsEstimate := total < 0
+	if totalIsEstimate {
+		total = estimateTotal(ctx, s.contentRepo, req, len(contents), s.simpleQueryTimeout)
+	}
+
 	// Load tags for all content items in batch
 	// This is more efficient than loading tags one by one
 	// Use shorter timeout for tag loading (simpler query)
+	tagsPartial := false
 	if len(contents) > 0 {
 		tagCtx, tagCancel := context.WithTimeout(ctx, s.simpleQueryTimeout)
-		if err := s.contentRepo.LoadTagsBatch(tagCtx, contents); err != nil {
+		if err := s.contentRepo.LoadTagsBatch(tagCtx, contents, req.TagOrder); err != nil {
 			// Log error but don't fail the entire search
-			// Tags are optional metadata
+			// Tags are optional metadata, but flag the response so clients
+			// don't mistake dropped tags for untagged content
+			tagsPartial = true
 			if tagCtx.Err() == context.DeadlineExceeded {
 				fmt.Printf("Warning: tag loading timeout after %v\n", s.simpleQueryTimeout)
 			} else {
@@ -109,41 +224,311 @@ func (s *SearchService) Search(ctx context.Context, req *model.SearchRequest) (*
 
 	// Convert repository results to response format
 	// We need to convert []*model.Content to []model.Content for JSON serialization
-	results := make([]model.Content, len(contents))
-	for i, content := range contents {
-		results[i] = *content
+	// Grouped searches return their rows in Groups and leave Results empty
+	var groups []model.ProviderGroup
+	results := make([]model.Content, 0, len(contents))
+	if req.GroupByProvider {
+		groups = groupByProvider(contents, providerCounts)
+	} else {
+		for _, content := range contents {
+			results = append(results, *content)
+		}
 	}
 
 	// Build the search response
 	response := &model.SearchResponse{
-		Results: results,
-		Total:   total,
-		Page:    req.Page,
-		PerPage: req.PerPage,
+		Results:     results,
+		Total:       total,
+		Page:        req.Page,
+		PerPage:     req.PerPage,
+		TagsPartial: tagsPartial,
+
+		CollapsedCount:  collapsed,
+		TotalIsEstimate: totalIsEstimate,
+		Fuzzy:           fuzzy,
+		Groups:          groups,
+		Facets:          facets,
 	}
 
 	// Calculate total pages for pagination metadata
 	// This helps clients build pagination UI
 	response.CalculateTotalPages()
+	response.CalculateResultChecksum()
+
+	// A full page may have more rows after it; the cursor picks up from its last row
+	// Fuzzy pages aren't in score order, so they are paged by offset only
+	if req.SupportsCursor() && !fuzzy && len(contents) > 0 && len(contents) == req.PerPage {
+		response.NextCursor = model.CursorOf(contents[len(contents)-1]).Encode()
+	}
+
+	// Explain empty results so clients can tell why nothing matched
+	if len(contents) == 0 && s.emptyResultHints {
+		response.Hint = s.buildEmptyResultHint(req, total, response.TotalPages)
+	}
+
+	// When nothing matched at all, offer existing tags spelled like the query
+	response.Suggestions = suggestionsFor(ctx, s.contentRepo, req.Query, total, s.simpleQueryTimeout)
 
 	// Store in cache for subsequent requests
-	if s.cache != nil && cacheKey != "" {
-		// For RedisCache we pass JSON bytes; InMemoryCache will also accept []byte.
-		if b, err := json.Marshal(response); err == nil {
-			s.cache.Set(cacheKey, b, s.cacheTTL)
-		} else {
-			// Fallback: store as pointer for in-memory cache if JSON fails.
-			s.cache.Set(cacheKey, response, s.cacheTTL)
+	// Responses with partial tags, an estimated total or timed-out facets are not
+	// cached so the degraded result doesn't outlive the DB pressure that caused it
+	facetsMissing := req.IncludeFacets && facets == nil
+	if s.cache != nil && cacheKey != "" && !tagsPartial && !totalIsEstimate && !facetsMissing {
+		s.cache.SetSearchResponse(cacheKey, response, s.cacheTTL)
+	}
+
+	return s.finishResponse(response, req, rankingDisabled), nil
+}
+
+// finishResponse applies the per-request parts of a response, fresh or cached
+func (s *SearchService) finishResponse(resp *model.SearchResponse, req *model.SearchRequest, rankingDisabled bool) *model.SearchResponse {
+	if req.Highlight {
+		resp = s.highlighter.withHighlights(resp, req.Query)
+	}
+	return withRankingNotice(resp, rankingDisabled)
+}
+
+// groupByProvider buckets contents by provider, keeping their order within each bucket
+// Buckets come in order of each provider's first row; counts holds the bucket totals
+func groupByProvider(contents []*model.Content, counts map[int]int) []model.ProviderGroup {
+	groups := make([]model.ProviderGroup, 0)
+	index := make(map[int]int)
+	for _, content := range contents {
+		i, ok := index[content.ProviderID]
+		if !ok {
+			i = len(groups)
+			index[content.ProviderID] = i
+			groups = append(groups, model.ProviderGroup{ProviderID: content.ProviderID, Count: counts[content.ProviderID]})
 		}
+		groups[i].Results = append(groups[i].Results, *content)
 	}
+	return groups
+}
 
-	return response, nil
+// rowEstimator is the part of the content repository that estimates result counts
+type rowEstimator interface {
+	EstimateSearchResults(ctx context.Context, req *model.SearchRequest) (int, error)
+}
+
+// estimateTotal stands in for a search total whose COUNT timed out
+// It never reports fewer rows than the client has already paged through, and a full
+// page counts one extra row so total_pages still offers the next page
+func estimateTotal(ctx context.Context, estimator rowEstimator, req *model.SearchRequest, pageLen int, timeout time.Duration) int {
+	lowerBound := pageLen
+	if req.After == nil || *req.After == "" {
+		lowerBound += req.GetOffset()
+	}
+	if pageLen == req.PerPage {
+		lowerBound++
+	}
+
+	estimateCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	estimate, err := estimator.EstimateSearchResults(estimateCtx, req)
+	if err != nil {
+		if !stderrors.Is(err, repository.ErrNoRowEstimate) {
+			fmt.Printf("Warning: failed to estimate search total: %v\n", err)
+		}
+		return lowerBound
+	}
+	return max(estimate, lowerBound)
+}
+
+// Count returns the number of results a search would produce, without fetching rows
+// It applies the same filters as Search; a count timeout yields total -1 with TimedOut set
+func (s *SearchService) Count(ctx context.Context, req *model.SearchRequest) (*model.SearchCountResponse, error) {
+	req.Validate()
+	s.applyDefaults(req)
+
+	countCtx, cancel := errors.WithQueryTimeout(ctx, s.effectiveQueryTimeout(req))
+	defer cancel()
+
+	total, err := s.contentRepo.Count(countCtx, req)
+	if err != nil {
+		if appErr := errors.AsAppError(err); appErr != nil {
+			return nil, appErr
+		}
+		if appErr := errors.FromContext(countCtx, "count"); appErr != nil {
+			return nil, appErr
+		}
+		return nil, errors.NewServiceError("count content", err)
+	}
+
+	return &model.SearchCountResponse{
+		Total:    total,
+		TimedOut: total < 0,
+	}, nil
+}
+
+// CacheKey returns the cache key a search request would be stored under
+// Applies the same normalization as Search so operators can look up real entries
+func (s *SearchService) CacheKey(req *model.SearchRequest) string {
+	req.Validate()
+	s.applyDefaults(req)
+	var generation int64
+	if s.cache != nil {
+		generation, _ = s.cache.Generation(searchCacheNamespace)
+	}
+	return buildSearchCacheKey(req, generation)
+}
+
+// cachedResponse reads a search response from the cache, counting the hit or miss
+func (s *SearchService) cachedResponse(cacheKey string) (*model.SearchResponse, bool) {
+	cached, ok := s.cache.GetSearchResponse(cacheKey)
+	if ok {
+		s.cacheHits.Add(1)
+	} else {
+		s.cacheMisses.Add(1)
+	}
+	return cached, ok
+}
+
+// MaxPerPage returns the configured per_page cap
+func (s *SearchService) MaxPerPage() int {
+	return s.maxPerPage
+}
+
+// CacheStats reports search cache hits and misses since startup or the last reset
+// Reads skipped with nocache count as neither
+func (s *SearchService) CacheStats() model.CacheStats {
+	return model.NewCacheStats(s.cacheHits.Load(), s.cacheMisses.Load())
+}
+
+// ResetCacheStats zeroes the hit and miss counters and returns the values they had
+func (s *SearchService) ResetCacheStats() model.CacheStats {
+	return model.NewCacheStats(s.cacheHits.Swap(0), s.cacheMisses.Swap(0))
+}
+
+// InvalidateCache makes every cached search response stale at once
+func (s *SearchService) InvalidateCache() {
+	InvalidateSearchCache(s.cache)
+}
+
+// buildEmptyResultHint describes why a search returned no results and what to try next
+func (s *SearchService) buildEmptyResultHint(req *model.SearchRequest, total, totalPages int) *model.SearchHint {
+	mode, effectiveQuery := s.contentRepo.DescribeQuery(req)
+	hint := &model.SearchHint{
+		Mode:           mode,
+		EffectiveQuery: effectiveQuery,
+		ActiveFilters:  activeFilters(req),
+	}
+
+	switch {
+	case total > 0:
+		hint.Suggestion = fmt.Sprintf("page %d is past the last page (%d); request an earlier page", req.Page, totalPages)
+	case len(hint.ActiveFilters) > 0:
+		hint.Suggestion = "no content matched; try removing filters: " + strings.Join(hint.ActiveFilters, ", ")
+	case mode == model.SearchModeLike:
+		hint.Suggestion = "the query is short, so only a substring match on titles was used; try a longer or different keyword"
+	case mode == model.SearchModeFullText:
+		hint.Suggestion = "no titles matched these words; try fewer or more general keywords"
+	default:
+		hint.Suggestion = "there is no content yet; sync providers first"
+	}
+
+	return hint
+}
+
+// activeFilters lists the filter parameters set on a request
+func activeFilters(req *model.SearchRequest) []string {
+	var filters []string
+	if req.Type != nil {
+		filters = append(filters, "type")
+	}
+	if req.ProviderID != nil {
+		filters = append(filters, "provider_id")
+	}
+	if req.StartDate != nil {
+		filters = append(filters, "start_date")
+	}
+	if req.EndDate != nil {
+		filters = append(filters, "end_date")
+	}
+	if req.UpdatedSince != nil {
+		filters = append(filters, "updated_since")
+	}
+	if req.MinViews != nil {
+		filters = append(filters, "min_views")
+	}
+	if req.MinLikes != nil {
+		filters = append(filters, "min_likes")
+	}
+	if req.MinReactions != nil {
+		filters = append(filters, "min_reactions")
+	}
+	if req.MinComments != nil {
+		filters = append(filters, "min_comments")
+	}
+	return filters
+}
+
+// applyDefaults fills request options left unset by the client with server defaults
+// and caps per_page at the configured maximum
+// Done before building the cache key so explicit and implicit defaults share an entry
+// In degraded mode score ordering becomes published_at DESC; the return value
+// reports whether that happened so the response can say so
+func (s *SearchService) applyDefaults(req *model.SearchRequest) bool {
+	req.LimitPerPage(s.maxPerPage)
+
+	if req.Prefix == nil {
+		prefix := s.prefixMatch
+		req.Prefix = &prefix
+	}
+
+	if s.ScoringDegraded() && req.SortBy == "score" {
+		req.SortBy = "published_at"
+		req.SortOrder = "desc"
+		return true
+	}
+	return false
+}
+
+// withRankingNotice returns resp annotated with RankingDisabledNotice when ranking was disabled
+// It annotates a copy so cached responses, shared with plain published_at searches, stay clean
+func withRankingNotice(resp *model.SearchResponse, rankingDisabled bool) *model.SearchResponse {
+	if !rankingDisabled {
+		return resp
+	}
+	annotated := *resp
+	annotated.Notice = RankingDisabledNotice
+	return &annotated
+}
+
+// effectiveQueryTimeout returns the query timeout for a request
+// A timeout_ms override is honored but clamped to maxQueryTimeout so clients
+// can't hold connections open indefinitely
+func (s *SearchService) effectiveQueryTimeout(req *model.SearchRequest) time.Duration {
+	if req.TimeoutMs <= 0 {
+		return s.queryTimeout
+	}
+	timeout := time.Duration(req.TimeoutMs) * time.Millisecond
+	if timeout > s.maxQueryTimeout {
+		return s.maxQueryTimeout
+	}
+	return timeout
+}
+
+// optionalInt formats an optional int for cache keys; unset is empty, distinct from 0
+func optionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func optionalString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
 }
 
 // buildSearchCacheKey builds a cache key that uniquely identifies a search request.
-func buildSearchCacheKey(r *model.SearchRequest) string {
+// generation is the search cache generation; bumping it retires every earlier key
+func buildSearchCacheKey(r *model.SearchRequest, generation int64) string {
 	// We keep it simple and explicit instead of generic JSON serialization.
-	key := fmt.Sprintf("q=%s|t=%s|p=%d|prov=%v|sd=%v|ed=%v|sort=%s|ord=%s|pp=%d",
+	key := fmt.Sprintf("g=%d|q=%s|t=%s|p=%d|prov=%v|sd=%v|ed=%v|us=%v|sort=%s|ord=%s|pp=%d|to=%s|px=%t|mm=%s|tg=%t|dt=%t|gp=%t|fc=%t|mv=%s|ml=%s|mr=%s|mc=%s|af=%s|fz=%t",
+		generation,
 		r.Query,
 		func() string {
 			if r.Type == nil {
@@ -160,9 +545,23 @@ func buildSearchCacheKey(r *model.SearchRequest) string {
 		}(),
 		r.StartDate,
 		r.EndDate,
+		r.UpdatedSince,
 		r.SortBy,
 		r.SortOrder,
 		r.PerPage,
+		r.TagOrder,
+		r.Prefix != nil && *r.Prefix,
+		r.MatchMode,
+		r.IncludesTags(),
+		r.DistinctTitles,
+		r.GroupByProvider,
+		r.IncludeFacets,
+		optionalInt(r.MinViews),
+		optionalInt(r.MinLikes),
+		optionalInt(r.MinReactions),
+		optionalInt(r.MinComments),
+		optionalString(r.After),
+		r.Fuzzy,
 	)
 	return key
 }
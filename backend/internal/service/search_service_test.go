@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	apperrors "search-engine/backend/internal/errors"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/internal/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeEstimator returns a fixed estimate or error
+type fakeEstimator struct {
+	estimate int
+	err      error
+	calls    int
+}
+
+func (f *fakeEstimator) EstimateSearchResults(ctx context.Context, req *model.SearchRequest) (int, error) {
+	f.calls++
+	return f.estimate, f.err
+}
+
+func TestEstimateTotalAfterCountTimeout(t *testing.T) {
+	after := model.SearchCursor{Score: 1, ID: 5}.Encode()
+
+	tests := []struct {
+		name      string
+		estimator *fakeEstimator
+		req       model.SearchRequest
+		pageLen   int
+		want      int
+	}{
+		{"planner estimate", &fakeEstimator{estimate: 5000}, model.SearchRequest{Page: 2, PerPage: 10}, 10, 5000},
+		{"estimate below rows already seen", &fakeEstimator{estimate: 3}, model.SearchRequest{Page: 4, PerPage: 10}, 10, 41},
+		{"no estimate on a full page", &fakeEstimator{err: repository.ErrNoRowEstimate}, model.SearchRequest{Page: 3, PerPage: 10}, 10, 31},
+		{"no estimate on the last page", &fakeEstimator{err: repository.ErrNoRowEstimate}, model.SearchRequest{Page: 3, PerPage: 10}, 4, 24},
+		{"estimate query failed", &fakeEstimator{err: errors.New("connection reset")}, model.SearchRequest{Page: 1, PerPage: 10}, 10, 11},
+		{"cursor page ignores page number", &fakeEstimator{err: repository.ErrNoRowEstimate}, model.SearchRequest{Page: 9, PerPage: 10, After: &after}, 10, 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := estimateTotal(context.Background(), tt.estimator, &tt.req, tt.pageLen, time.Second)
+			if got != tt.want {
+				t.Errorf("estimateTotal() = %d, want %d", got, tt.want)
+			}
+			if tt.estimator.calls != 1 {
+				t.Errorf("estimator called %d times, want 1", tt.estimator.calls)
+			}
+		})
+	}
+}
+
+func TestEstimatedTotalKeepsPaginationUsable(t *testing.T) {
+	// COUNT timed out: the repository reports -1 and the service substitutes an estimate
+	req := &model.SearchRequest{Page: 1, PerPage: 10}
+	total := estimateTotal(context.Background(), &fakeEstimator{err: repository.ErrNoRowEstimate}, req, 10, time.Second)
+
+	response := &model.SearchResponse{Total: total, Page: req.Page, PerPage: req.PerPage, TotalIsEstimate: true}
+	response.CalculateTotalPages()
+	if response.TotalPages < 2 {
+		t.Errorf("total_pages = %d with a full first page, want a next page", response.TotalPages)
+	}
+	if !response.TotalIsEstimate {
+		t.Error("total_is_estimate not set")
+	}
+}
+
+func TestSearchMaxPerPage(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxPerPage int
+		perPage    int
+		want       int
+	}{
+		{"default cap", 0, 500, model.DefaultMaxPerPage},
+		{"within default cap", 0, 50, 50},
+		{"raised cap", 500, 250, 250},
+		{"raised cap exceeded", 500, 1000, 500},
+		{"tightened cap", 20, 50, 20},
+		{"unset per_page under a tight cap", 5, 0, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSearchService(nil, nil, SearchServiceOptions{MaxPerPage: tt.maxPerPage})
+			req := &model.SearchRequest{PerPage: tt.perPage}
+			key := s.CacheKey(req)
+			if req.PerPage != tt.want {
+				t.Errorf("per_page = %d, want %d", req.PerPage, tt.want)
+			}
+			// The capped value is what gets cached, so over-cap requests share the entry
+			if want := fmt.Sprintf("|pp=%d|", tt.want); !strings.Contains(key, want) {
+				t.Errorf("cache key %q does not contain %q", key, want)
+			}
+		})
+	}
+}
+
+func TestGroupByProvider(t *testing.T) {
+	// Rows as SearchByProvider returns them: by provider, then rank
+	contents := []*model.Content{
+		{ID: 4, ProviderID: 1, Score: 9},
+		{ID: 2, ProviderID: 1, Score: 7},
+		{ID: 9, ProviderID: 2, Score: 8},
+		{ID: 1, ProviderID: 3, Score: 5},
+		{ID: 3, ProviderID: 3, Score: 2},
+	}
+	counts := map[int]int{1: 6, 2: 1, 3: 2}
+
+	groups := groupByProvider(contents, counts)
+	want := []struct {
+		providerID, count int
+		ids               []int64
+	}{
+		{1, 6, []int64{4, 2}},
+		{2, 1, []int64{9}},
+		{3, 2, []int64{1, 3}},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(want))
+	}
+	for i, w := range want {
+		g := groups[i]
+		if g.ProviderID != w.providerID || g.Count != w.count {
+			t.Errorf("group %d = provider %d count %d, want provider %d count %d", i, g.ProviderID, g.Count, w.providerID, w.count)
+		}
+		var ids []int64
+		for _, c := range g.Results {
+			ids = append(ids, c.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(w.ids) {
+			t.Errorf("provider %d results = %v, want %v", g.ProviderID, ids, w.ids)
+		}
+	}
+
+	if groups := groupByProvider(nil, nil); groups == nil || len(groups) != 0 {
+		t.Errorf("groupByProvider(nil) = %#v, want an empty slice", groups)
+	}
+}
+
+func TestSearchRejectsGroupedDistinctTitles(t *testing.T) {
+	s := NewSearchService(nil, nil, SearchServiceOptions{})
+	_, err := s.Search(context.Background(), &model.SearchRequest{GroupByProvider: true, DistinctTitles: true})
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Search() error = %v, want a 400", err)
+	}
+	if _, ok := appErr.Fields["group_by_provider"]; !ok {
+		t.Errorf("fields = %v, want a group_by_provider message", appErr.Fields)
+	}
+}
@@ -0,0 +1,36 @@
+// suggestions.go - "Did you mean" suggestions
+// Offers existing tags spelled close to a query that matched nothing
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxSuggestions caps the terms suggested for one empty search
+const maxSuggestions = 3
+
+// termSuggester is the part of ContentRepository that finds terms close to a query
+type termSuggester interface {
+	SuggestTerms(ctx context.Context, query string, limit int) ([]string, error)
+}
+
+// suggestionsFor returns "did you mean" terms for a search that found total results
+// Only searches that matched nothing get suggestions; a failed lookup is logged and
+// leaves them out rather than failing the search
+func suggestionsFor(ctx context.Context, suggester termSuggester, query string, total int, timeout time.Duration) []string {
+	if total != 0 || strings.TrimSpace(query) == "" {
+		return nil
+	}
+	suggestCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	suggestions, err := suggester.SuggestTerms(suggestCtx, query, maxSuggestions)
+	if err != nil {
+		fmt.Printf("Warning: failed to load search suggestions: %v\n", err)
+		return nil
+	}
+	return suggestions
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeSuggester suggests fixed terms and counts lookups
+type fakeSuggester struct {
+	terms    []string
+	err      error
+	calls    int
+	gotLimit int
+}
+
+func (f *fakeSuggester) SuggestTerms(_ context.Context, query string, limit int) ([]string, error) {
+	f.calls++
+	f.gotLimit = limit
+	return f.terms, f.err
+}
+
+func TestSuggestionsForNearMiss(t *testing.T) {
+	suggester := &fakeSuggester{terms: []string{"python", "pytorch"}}
+
+	got := suggestionsFor(context.Background(), suggester, "pyton", 0, time.Second)
+	if want := []string{"python", "pytorch"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("suggestions = %v, want %v", got, want)
+	}
+	if suggester.gotLimit != maxSuggestions {
+		t.Errorf("limit = %d, want %d", suggester.gotLimit, maxSuggestions)
+	}
+}
+
+func TestSuggestionsForSkipped(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		total     int
+		err       error
+		wantCalls int
+	}{
+		{"results exist", "python", 12, nil, 0},
+		{"no query", "  ", 0, nil, 0},
+		{"lookup fails", "pyton", 0, errors.New("connection refused"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggester := &fakeSuggester{terms: []string{"python"}, err: tt.err}
+			if got := suggestionsFor(context.Background(), suggester, tt.query, tt.total, time.Second); got != nil {
+				t.Errorf("suggestions = %v, want none", got)
+			}
+			if suggester.calls != tt.wantCalls {
+				t.Errorf("SuggestTerms calls = %d, want %d", suggester.calls, tt.wantCalls)
+			}
+		})
+	}
+}
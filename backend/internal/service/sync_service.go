@@ -0,0 +1,146 @@
+// sync_service.go - Provider sync orchestration
+// Runs a full sync (register configured providers, fetch all, recalculate scores)
+// for the startup sync and the on-demand sync endpoint
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"search-engine/backend/internal/config"
+	"search-engine/backend/internal/model"
+	"search-engine/backend/internal/provider"
+	"search-engine/backend/internal/repository"
+	"search-engine/backend/internal/trace"
+	"search-engine/backend/pkg/cache"
+	"time"
+)
+
+// SyncService runs full provider syncs
+type SyncService struct {
+	providerRepo *repository.ProviderRepository
+	contentRepo  *repository.ContentRepository
+	syncRepo     *repository.SyncHistoryRepository
+	searchCache  cache.Cache
+	cfg          *config.Config
+}
+
+// NewSyncService creates a new SyncService instance
+// contentRepo should have history enabled if syncs are to record content history,
+// and the tag limits set that synced tags must respect
+// searchCache, if not nil, is invalidated after each run so searches see the synced data
+func NewSyncService(
+	providerRepo *repository.ProviderRepository,
+	contentRepo *repository.ContentRepository,
+	syncRepo *repository.SyncHistoryRepository,
+	searchCache cache.Cache,
+	cfg *config.Config,
+) *SyncService {
+	return &SyncService{
+		providerRepo: providerRepo,
+		contentRepo:  contentRepo,
+		syncRepo:     syncRepo,
+		searchCache:  searchCache,
+		cfg:          cfg,
+	}
+}
+
+// Run syncs every configured and field-mapped provider, then recalculates scores
+// Scores are recalculated even when some providers failed, so the ones that
+// synced are ranked on fresh data. Callers guard against overlapping runs
+// with provider.TryStartSync
+// ctx carries the trace ID the run logs under; runs are detached from any request
+// (see SyncHandler.TriggerSync), so it shouldn't be cancelled when a request ends
+func (s *SyncService) Run(ctx context.Context) *model.SyncSummary {
+	startedAt := time.Now()
+
+	manager := provider.NewManager(s.providerRepo, s.contentRepo, s.syncRepo)
+	manager.SetFutureDatePolicy(provider.FutureDatePolicyFromConfig(s.cfg.Provider.FutureDates))
+	s.registerProviders(manager)
+	retry := provider.RetryPolicyFromConfig(s.cfg.Provider.Retry)
+	if err := manager.RegisterMappedProviders(provider.HTTPTimeoutsFromConfig(s.cfg.Provider.MappedTimeouts), retry); err != nil {
+		trace.Logf(ctx, "Warning: Failed to register mapped providers: %v", err)
+	}
+
+	summary := &model.SyncSummary{
+		StartedAt: startedAt,
+		Providers: manager.SyncAll(ctx),
+	}
+
+	scoringService := NewScoringService(s.contentRepo, s.cfg.Scoring)
+	allProviders, err := s.providerRepo.GetAll()
+	if err != nil {
+		summary.ScoreErrors = append(summary.ScoreErrors, fmt.Sprintf("list providers: %v", err))
+	}
+	for _, p := range allProviders {
+		if err := scoringService.RecalculateScoresForProvider(ctx, p.ID); err != nil {
+			trace.Logf(ctx, "Warning: Failed to recalculate scores for provider %s: %v", p.Name, err)
+			summary.ScoreErrors = append(summary.ScoreErrors, fmt.Sprintf("%s: %v", p.Name, err))
+		}
+	}
+
+	// Content and scores changed under every cached search response
+	InvalidateSearchCache(s.searchCache)
+
+	summary.DurationMs = time.Since(startedAt).Milliseconds()
+	return summary
+}
+
+// registerProviders ensures the configured providers exist in the database and registers them
+// Rows are only created when missing: an existing row may carry edits made through the
+// provider admin endpoints, so it is synced as stored rather than reset to the configuration
+func (s *SyncService) registerProviders(manager *provider.Manager) {
+	providers := []struct {
+		name, url   string
+		format      model.ProviderFormat
+		timeouts    config.ProviderTimeoutConfig
+		dateLayouts []string
+	}{
+		{"provider1", s.cfg.Provider.Provider1URL, model.ProviderFormatJSON, s.cfg.Provider.Provider1Timeouts, s.cfg.Provider.Provider1DateLayouts},
+		{"provider2", s.cfg.Provider.Provider2URL, model.ProviderFormatXML, s.cfg.Provider.Provider2Timeouts, s.cfg.Provider.Provider2DateLayouts},
+	}
+
+	for _, p := range providers {
+		configured := &model.Provider{Name: p.name, URL: p.url, Format: p.format, RateLimitPerMinute: 60}
+		stored, err := s.providerRepo.GetByName(p.name)
+		if errors.Is(err, repository.ErrProviderNotFound) {
+			stored, err = s.createProvider(configured)
+		}
+		if err != nil {
+			log.Printf("Warning: Not syncing provider %s: %v", p.name, err)
+			continue
+		}
+
+		timeouts := provider.HTTPTimeoutsFromConfig(p.timeouts)
+		retry := provider.RetryPolicyFromConfig(s.cfg.Provider.Retry)
+		pagination := provider.PaginationPolicyFromConfig(s.cfg.Provider.Pagination)
+		if stored.Format == model.ProviderFormatJSON {
+			manager.RegisterProvider(provider.NewJSONProvider(stored.Name, stored.URL, timeouts, p.dateLayouts, retry, pagination))
+		} else {
+			manager.RegisterProvider(provider.NewXMLProvider(stored.Name, stored.URL, timeouts, p.dateLayouts, retry, pagination))
+		}
+	}
+}
+
+// createProvider stores a configured provider that has no row yet and returns the row to sync
+// A provider already registered for the same URL under another name is reused rather
+// than double-ingesting the same feed, as long as it reads the feed in the same format
+func (s *SyncService) createProvider(configured *model.Provider) (*model.Provider, error) {
+	byURL, err := s.providerRepo.GetByURL(configured.URL)
+	switch {
+	case err == nil:
+		if err := model.ValidateProvider(configured, byURL); err != nil {
+			return nil, err
+		}
+		log.Printf("Provider %s shares its URL with existing provider %s, reusing it", configured.Name, byURL.Name)
+		return byURL, nil
+	case !errors.Is(err, repository.ErrProviderNotFound):
+		return nil, err
+	}
+
+	if err := s.providerRepo.Create(configured); err != nil {
+		return nil, fmt.Errorf("create provider: %w", err)
+	}
+	return configured, nil
+}
@@ -0,0 +1,41 @@
+// trace.go - Trace ID propagation through context
+// Carries a request's trace ID into background work such as provider syncs and
+// score recalculation, so their log lines can be tied back to the request
+package trace
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// ctxKey is the context key the trace ID is stored under
+type ctxKey struct{}
+
+// NewID generates a new trace ID
+func NewID() string {
+	return uuid.New().String()
+}
+
+// WithID returns a copy of ctx carrying the trace ID id
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// ID returns the trace ID carried by ctx, or "" if it has none
+func ID(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// Logf logs like log.Printf, prefixed with the trace ID carried by ctx
+// The prefix matches the request log's trace=<id>, so one grep finds both
+func Logf(ctx context.Context, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if id := ID(ctx); id != "" {
+		msg = "trace=" + id + " | " + msg
+	}
+	log.Print(msg)
+}
@@ -0,0 +1,48 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestIDRoundTrip(t *testing.T) {
+	if got := ID(context.Background()); got != "" {
+		t.Errorf("ID() without a trace ID = %q, want empty", got)
+	}
+
+	ctx := WithID(context.Background(), "abc")
+	if got := ID(ctx); got != "abc" {
+		t.Errorf("ID() = %q, want abc", got)
+	}
+
+	// Derived contexts, including ones detached from cancellation, keep the ID
+	derived, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	if got := ID(derived); got != "abc" {
+		t.Errorf("ID() of a derived context = %q, want abc", got)
+	}
+}
+
+func TestLogf(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	Logf(WithID(context.Background(), "abc"), "synced %d items", 3)
+	Logf(context.Background(), "no trace")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d log lines, want 2", len(lines))
+	}
+	if !strings.HasSuffix(lines[0], "trace=abc | synced 3 items") {
+		t.Errorf("line = %q, want the trace prefix", lines[0])
+	}
+	if strings.Contains(lines[1], "trace=") || !strings.HasSuffix(lines[1], "no trace") {
+		t.Errorf("line = %q, want no trace prefix", lines[1])
+	}
+}
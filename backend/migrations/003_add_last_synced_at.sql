@@ -0,0 +1,15 @@
+-- 003_add_last_synced_at.sql - Track when a provider sync last wrote each content row
+-- updated_at changes on any write (including score recalculation), so mirrors
+-- can't use it to tell provider-driven changes from internal ones
+-- Note: MySQL doesn't support IF NOT EXISTS for ADD COLUMN, so we check existence first
+
+SET @column_exists = (SELECT COUNT(*) FROM information_schema.columns 
+    WHERE table_schema = DATABASE() 
+    AND table_name = 'contents' 
+    AND column_name = 'last_synced_at');
+SET @sql = IF(@column_exists = 0, 
+    'ALTER TABLE contents ADD COLUMN last_synced_at TIMESTAMP NULL COMMENT ''Last time a provider sync wrote this row'' AFTER score', 
+    'SELECT ''Column last_synced_at already exists''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,14 @@
+-- 004_add_content_tag_ordinal.sql - Preserve the provider's original tag order
+-- Tags are returned alphabetically by default; the ordinal lets clients
+-- request the order the provider supplied them in instead
+
+SET @column_exists = (SELECT COUNT(*) FROM information_schema.columns 
+    WHERE table_schema = DATABASE() 
+    AND table_name = 'content_tags' 
+    AND column_name = 'ordinal');
+SET @sql = IF(@column_exists = 0, 
+    'ALTER TABLE content_tags ADD COLUMN ordinal INT NOT NULL DEFAULT 0 COMMENT ''Position of the tag in the provider list'' AFTER tag', 
+    'SELECT ''Column ordinal already exists''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,15 @@
+-- 005_add_provider_url_unique.sql - Prevent two providers from pointing at the same feed
+-- Providers are keyed by name during sync, so the same URL registered under two
+-- names would double-ingest identical content under different provider IDs
+-- Note: existing duplicate URLs must be merged before this migration can apply
+
+SET @index_exists = (SELECT COUNT(*) FROM information_schema.statistics 
+    WHERE table_schema = DATABASE() 
+    AND table_name = 'providers' 
+    AND index_name = 'uk_providers_url');
+SET @sql = IF(@index_exists = 0, 
+    'CREATE UNIQUE INDEX uk_providers_url ON providers(url)', 
+    'SELECT ''Index uk_providers_url already exists''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,17 @@
+-- 006_create_sync_history.sql - Record the outcome of every provider sync run
+-- last_fetched_at only says when a provider last synced successfully; this keeps
+-- each run's timing, item count and error so operators can see what a sync did
+
+CREATE TABLE IF NOT EXISTS sync_history (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    provider_id INT NOT NULL COMMENT 'Provider that was synced',
+    started_at TIMESTAMP NOT NULL COMMENT 'When the fetch started',
+    finished_at TIMESTAMP NULL COMMENT 'When the fetch and persistence finished',
+    items_fetched INT NOT NULL DEFAULT 0 COMMENT 'Items returned by the provider',
+    status ENUM('success', 'failed') NOT NULL COMMENT 'Outcome of the run',
+    error_message TEXT NULL COMMENT 'Failure reason when status is failed',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (provider_id) REFERENCES providers(id) ON DELETE CASCADE,
+    INDEX idx_provider_started (provider_id, started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
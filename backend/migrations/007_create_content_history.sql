@@ -0,0 +1,17 @@
+-- 007_create_content_history.sql - Keep snapshots of content metrics over time
+-- contents only holds the current values; a snapshot is written whenever a sync
+-- changes a significant field so metric and score trends can be shown
+
+CREATE TABLE IF NOT EXISTS content_history (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    content_id BIGINT NOT NULL COMMENT 'Content the snapshot belongs to',
+    views INT NOT NULL DEFAULT 0,
+    likes INT NOT NULL DEFAULT 0,
+    reactions INT NOT NULL DEFAULT 0,
+    comments INT NOT NULL DEFAULT 0,
+    score DECIMAL(10, 4) NOT NULL DEFAULT 0.0000,
+    recorded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT 'When the change was written',
+
+    FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE,
+    INDEX idx_content_recorded (content_id, recorded_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
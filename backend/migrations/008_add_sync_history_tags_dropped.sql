@@ -0,0 +1,15 @@
+-- 008_add_sync_history_tags_dropped.sql - Record tags rejected by the tag limits per sync run
+-- Over-long tags and tags past the per-item cap are dropped before insertion;
+-- keeping the count with the run shows when a provider starts sending bad tags
+-- Note: MySQL doesn't support IF NOT EXISTS for ADD COLUMN, so we check existence first
+
+SET @column_exists = (SELECT COUNT(*) FROM information_schema.columns 
+    WHERE table_schema = DATABASE() 
+    AND table_name = 'sync_history' 
+    AND column_name = 'tags_dropped');
+SET @sql = IF(@column_exists = 0, 
+    'ALTER TABLE sync_history ADD COLUMN tags_dropped INT NOT NULL DEFAULT 0 COMMENT ''Tags rejected by the tag limits'' AFTER items_fetched', 
+    'SELECT ''Column tags_dropped already exists''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,26 @@
+-- 009_add_provider_enabled_and_staleness.sql - Let providers be disabled and carry their own staleness threshold
+-- Syncs skip disabled providers; the status endpoint flags a provider as stale
+-- once last_fetched_at is older than stale_after_minutes (server default when NULL)
+-- Note: MySQL doesn't support IF NOT EXISTS for ADD COLUMN, so we check existence first
+
+SET @column_exists = (SELECT COUNT(*) FROM information_schema.columns 
+    WHERE table_schema = DATABASE() 
+    AND table_name = 'providers' 
+    AND column_name = 'enabled');
+SET @sql = IF(@column_exists = 0, 
+    'ALTER TABLE providers ADD COLUMN enabled BOOLEAN NOT NULL DEFAULT TRUE COMMENT ''Disabled providers are skipped by syncs'' AFTER rate_limit_per_minute', 
+    'SELECT ''Column enabled already exists''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @column_exists = (SELECT COUNT(*) FROM information_schema.columns 
+    WHERE table_schema = DATABASE() 
+    AND table_name = 'providers' 
+    AND column_name = 'stale_after_minutes');
+SET @sql = IF(@column_exists = 0, 
+    'ALTER TABLE providers ADD COLUMN stale_after_minutes INT NULL COMMENT ''Minutes since last fetch before the provider counts as stale'' AFTER enabled', 
+    'SELECT ''Column stale_after_minutes already exists''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,15 @@
+-- 010_add_sync_history_items_skipped.sql - Record provider items skipped during transformation
+-- Items whose date matches none of the provider's layouts (or that fail to parse otherwise)
+-- are dropped before saving; counting them per run makes silent item loss visible
+-- Note: MySQL doesn't support IF NOT EXISTS for ADD COLUMN, so we check existence first
+
+SET @column_exists = (SELECT COUNT(*) FROM information_schema.columns 
+    WHERE table_schema = DATABASE() 
+    AND table_name = 'sync_history' 
+    AND column_name = 'items_skipped');
+SET @sql = IF(@column_exists = 0, 
+    'ALTER TABLE sync_history ADD COLUMN items_skipped INT NOT NULL DEFAULT 0 COMMENT ''Provider items that could not be transformed'' AFTER items_fetched', 
+    'SELECT ''Column items_skipped already exists''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
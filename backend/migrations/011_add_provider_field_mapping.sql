@@ -0,0 +1,15 @@
+-- 011_add_provider_field_mapping.sql - Store a field mapping for configurable providers
+-- Providers with a field mapping are synced by the generic configurable provider,
+-- which reads each content field from the JSON path the mapping names
+-- Note: MySQL doesn't support IF NOT EXISTS for ADD COLUMN, so we check existence first
+
+SET @column_exists = (SELECT COUNT(*) FROM information_schema.columns 
+    WHERE table_schema = DATABASE() 
+    AND table_name = 'providers' 
+    AND column_name = 'field_mapping');
+SET @sql = IF(@column_exists = 0, 
+    'ALTER TABLE providers ADD COLUMN field_mapping JSON NULL COMMENT ''JSON paths of each content field; NULL for built-in providers'' AFTER stale_after_minutes', 
+    'SELECT ''Column field_mapping already exists''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
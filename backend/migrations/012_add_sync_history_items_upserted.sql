@@ -0,0 +1,15 @@
+-- 012_add_sync_history_items_upserted.sql - Record how many provider items a sync run saved
+-- Items can be fetched but fail to save; comparing items_upserted with items_fetched
+-- shows a run that lost items on the way to the database
+-- Note: MySQL doesn't support IF NOT EXISTS for ADD COLUMN, so we check existence first
+
+SET @column_exists = (SELECT COUNT(*) FROM information_schema.columns 
+    WHERE table_schema = DATABASE() 
+    AND table_name = 'sync_history' 
+    AND column_name = 'items_upserted');
+SET @sql = IF(@column_exists = 0, 
+    'ALTER TABLE sync_history ADD COLUMN items_upserted INT NOT NULL DEFAULT 0 COMMENT ''Fetched items saved to the database'' AFTER items_skipped', 
+    'SELECT ''Column items_upserted already exists''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
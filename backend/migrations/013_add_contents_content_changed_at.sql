@@ -0,0 +1,35 @@
+-- 013_add_contents_content_changed_at.sql - Track when a content item last really changed
+-- updated_at moves on every sync write and score recalculation, so counting or polling
+-- changes with it reports almost every item; content_changed_at only moves on insert,
+-- on a sync that changes the item's fields, and on an edit
+-- Existing rows start from updated_at, the best estimate available
+-- Note: MySQL doesn't support IF NOT EXISTS for ADD COLUMN, so we check existence first
+
+SET @column_exists = (SELECT COUNT(*) FROM information_schema.columns 
+    WHERE table_schema = DATABASE() 
+    AND table_name = 'contents' 
+    AND column_name = 'content_changed_at');
+SET @sql = IF(@column_exists = 0, 
+    'ALTER TABLE contents ADD COLUMN content_changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT ''Last insert, field change or edit; unlike updated_at not moved by resyncs or score recalcs'' AFTER last_synced_at', 
+    'SELECT ''Column content_changed_at already exists''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @sql = IF(@column_exists = 0, 
+    'UPDATE contents SET content_changed_at = updated_at', 
+    'SELECT ''Column content_changed_at already backfilled''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @index_exists = (SELECT COUNT(*) FROM information_schema.statistics 
+    WHERE table_schema = DATABASE() 
+    AND table_name = 'contents' 
+    AND index_name = 'idx_provider_content_changed_at');
+SET @sql = IF(@index_exists = 0, 
+    'CREATE INDEX idx_provider_content_changed_at ON contents(provider_id, content_changed_at)', 
+    'SELECT ''Index idx_provider_content_changed_at already exists''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,15 @@
+-- 014_index_contents_content_changed_at.sql - Index contents by last real change
+-- Speeds up searches with updated_since, which clients poll for recent changes; it filters
+-- on content_changed_at because updated_at moves on every resync and score recalculation
+-- Note: MySQL doesn't support IF NOT EXISTS for CREATE INDEX, so we check existence first
+
+SET @index_exists = (SELECT COUNT(*) FROM information_schema.statistics 
+    WHERE table_schema = DATABASE() 
+    AND table_name = 'contents' 
+    AND index_name = 'idx_content_changed_at');
+SET @sql = IF(@index_exists = 0, 
+    'CREATE INDEX idx_content_changed_at ON contents(content_changed_at)', 
+    'SELECT ''Index idx_content_changed_at already exists''');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
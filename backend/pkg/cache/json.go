@@ -0,0 +1,42 @@
+// json.go - Typed JSON helpers on top of Cache
+// Lets callers cache structs without each one repeating the serialization
+package cache
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// GetJSON returns the value stored under key by SetJSON, decoded into a T
+// Entries are JSON bytes, which is what Redis stores; a *T placed in an
+// in-memory cache directly is returned as is. Undecodable entries count as misses
+func GetJSON[T any](c Cache, key string) (*T, bool) {
+	cached, ok := c.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	switch v := cached.(type) {
+	case *T:
+		return v, true
+	case []byte:
+		var out T
+		if err := json.Unmarshal(v, &out); err != nil {
+			return nil, false
+		}
+		return &out, true
+	default:
+		return nil, false
+	}
+}
+
+// SetJSON stores value under key as JSON bytes, which every Cache implementation accepts
+// Returns the encoding error, if any; the value is not cached then
+func SetJSON(c Cache, key string, value interface{}, ttl time.Duration) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.Set(key, b, ttl)
+	return nil
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type cachedThing struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags"`
+}
+
+// testCaches returns an in-memory cache and a Redis wrapper backed by miniredis
+func testCaches(t *testing.T) map[string]Cache {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return map[string]Cache{
+		"in-memory": NewInMemoryCache(time.Minute, 0),
+		"redis":     &RedisCacheWrapper{Client: client},
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for name, c := range testCaches(t) {
+		t.Run(name, func(t *testing.T) {
+			want := cachedThing{Name: "go", Count: 3, Tags: []string{"a", "b"}}
+			if err := SetJSON(c, "thing", want, time.Minute); err != nil {
+				t.Fatalf("SetJSON: %v", err)
+			}
+
+			got, ok := GetJSON[cachedThing](c, "thing")
+			if !ok {
+				t.Fatal("GetJSON missed a stored value")
+			}
+			if got.Name != want.Name || got.Count != want.Count || len(got.Tags) != 2 {
+				t.Errorf("GetJSON = %+v, want %+v", got, want)
+			}
+
+			if _, ok := GetJSON[cachedThing](c, "missing"); ok {
+				t.Error("GetJSON hit for a missing key")
+			}
+		})
+	}
+}
+
+func TestGetJSONTreatsUndecodableEntriesAsMisses(t *testing.T) {
+	for name, c := range testCaches(t) {
+		t.Run(name, func(t *testing.T) {
+			c.Set("bad", []byte("not json"), time.Minute)
+			if _, ok := GetJSON[cachedThing](c, "bad"); ok {
+				t.Error("GetJSON hit for an undecodable entry")
+			}
+		})
+	}
+}
+
+func TestGetJSONReturnsStoredPointer(t *testing.T) {
+	c := NewInMemoryCache(time.Minute, 0)
+	stored := &cachedThing{Name: "direct"}
+	c.Set("thing", stored, time.Minute)
+
+	got, ok := GetJSON[cachedThing](c, "thing")
+	if !ok || got != stored {
+		t.Errorf("GetJSON = %v, %t, want the stored pointer", got, ok)
+	}
+}
+
+func TestGeneration(t *testing.T) {
+	for name, c := range testCaches(t) {
+		t.Run(name, func(t *testing.T) {
+			if got, ok := c.Generation("search"); !ok || got != 0 {
+				t.Fatalf("Generation() before any bump = %d, want 0", got)
+			}
+			if got := c.BumpGeneration("search"); got != 1 {
+				t.Errorf("BumpGeneration() = %d, want 1", got)
+			}
+			if got := c.BumpGeneration("search"); got != 2 {
+				t.Errorf("BumpGeneration() = %d, want 2", got)
+			}
+			if got, ok := c.Generation("search"); !ok || got != 2 {
+				t.Errorf("Generation() = %d, want 2", got)
+			}
+			if got, ok := c.Generation("stats"); !ok || got != 0 {
+				t.Errorf("Generation() of another namespace = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestRedisGenerationUnreadable(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	c := &RedisCacheWrapper{Client: client}
+
+	if gen, ok := c.Generation("search"); !ok || gen != 0 {
+		t.Fatalf("Generation() of an unset counter = %d, %t; want 0, true", gen, ok)
+	}
+	mr.Close()
+	// Reading 0 here would match entries cached before the first bump
+	if _, ok := c.Generation("search"); ok {
+		t.Error("Generation() with Redis down reported ok")
+	}
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestInMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewInMemoryCache(time.Minute, 3)
+	c.Set("a", 1, 0)
+	c.Set("b", 2, 0)
+	c.Set("c", 3, 0)
+
+	// Reading a makes b the least recently used entry
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("Get(a) missed before the cache was full")
+	}
+	c.Set("d", 4, 0)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b should have been evicted as the least recently used entry")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("Get(%s) missed, want it kept", key)
+		}
+	}
+	if got := c.Len(); got != 3 {
+		t.Errorf("Len() = %d, want 3", got)
+	}
+}
+
+func TestInMemoryCacheFloodKeepsNewestEntries(t *testing.T) {
+	const limit = 50
+	c := NewInMemoryCache(time.Minute, limit)
+	for i := 0; i < 10*limit; i++ {
+		c.Set(fmt.Sprintf("query-%d", i), i, 0)
+	}
+
+	if got := c.Len(); got != limit {
+		t.Fatalf("Len() = %d, want %d", got, limit)
+	}
+	if _, ok := c.Get("query-0"); ok {
+		t.Error("the oldest key survived the flood")
+	}
+	for i := 9 * limit; i < 10*limit; i++ {
+		if v, ok := c.Get(fmt.Sprintf("query-%d", i)); !ok || v != i {
+			t.Errorf("Get(query-%d) = %v, %t, want %d", i, v, ok, i)
+		}
+	}
+}
+
+func TestInMemoryCacheOverwriteDoesNotEvict(t *testing.T) {
+	c := NewInMemoryCache(time.Minute, 2)
+	c.Set("a", 1, 0)
+	c.Set("b", 2, 0)
+	c.Set("a", 10, 0)
+
+	if got := c.Len(); got != 2 {
+		t.Fatalf("Len() = %d, want 2", got)
+	}
+	if v, ok := c.Get("a"); !ok || v != 10 {
+		t.Errorf("Get(a) = %v, %t, want the overwritten value 10", v, ok)
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Error("overwriting a key evicted another entry")
+	}
+}
+
+func TestInMemoryCacheUnboundedAndExpiry(t *testing.T) {
+	c := NewInMemoryCache(time.Minute, 0)
+	for i := 0; i < 1000; i++ {
+		c.Set(fmt.Sprintf("k%d", i), i, 0)
+	}
+	if got := c.Len(); got != 1000 {
+		t.Errorf("Len() without a limit = %d, want 1000", got)
+	}
+
+	c.Set("short", "v", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get("short"); ok {
+		t.Error("Get returned an expired entry")
+	}
+	if got := c.Len(); got != 1000 {
+		t.Errorf("Len() after the expired entry was read = %d, want 1000", got)
+	}
+}
@@ -3,7 +3,9 @@
 package cache
 
 import (
+	"container/list"
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -14,29 +16,59 @@ import (
 type Cache interface {
 	Get(key string) (interface{}, bool)
 	Set(key string, value interface{}, ttl time.Duration)
+
+	// TTL returns the remaining lifetime of key and whether it exists.
+	// A negative duration means the key exists but never expires.
+	TTL(key string) (time.Duration, bool)
+
+	// Delete removes key and reports whether it existed.
+	Delete(key string) bool
+
+	// Generation returns the current generation of namespace, 0 until first bumped.
+	// Callers fold it into their keys, so bumping it invalidates every entry of
+	// the namespace at once without scanning keys; old entries just expire.
+	// ok is false when the generation can't be read: callers must then bypass the
+	// cache, as keys built from a guessed generation could serve pre-bump entries.
+	Generation(namespace string) (gen int64, ok bool)
+
+	// BumpGeneration advances namespace to a new generation and returns it.
+	BumpGeneration(namespace string) int64
 }
 
 type item struct {
+	key        string
 	value      interface{}
 	expiration time.Time
 }
 
 // InMemoryCache is a threadsafe in-memory implementation of Cache.
 // It is good enough for this case study and can be replaced with Redis later.
+// With a maxEntries limit, Set evicts the least recently used entry once the
+// cache is full, so a flood of unique keys can't grow it before cleanup runs.
 type InMemoryCache struct {
-	mu         sync.RWMutex
-	items      map[string]item
-	defaultTTL time.Duration
+	mu          sync.Mutex
+	items       map[string]*list.Element // Elements hold *item
+	lru         *list.List               // Front is the most recently used
+	generations map[string]int64
+	defaultTTL  time.Duration
+	maxEntries  int // 0 means unbounded
 }
 
 // NewInMemoryCache creates a new in-memory cache with a default TTL.
-func NewInMemoryCache(defaultTTL time.Duration) *InMemoryCache {
+// maxEntries caps the number of entries; 0 or less leaves the cache unbounded.
+func NewInMemoryCache(defaultTTL time.Duration, maxEntries int) *InMemoryCache {
 	if defaultTTL <= 0 {
 		defaultTTL = time.Minute
 	}
+	if maxEntries < 0 {
+		maxEntries = 0
+	}
 	c := &InMemoryCache{
-		items:      make(map[string]item),
-		defaultTTL: defaultTTL,
+		items:       make(map[string]*list.Element),
+		lru:         list.New(),
+		generations: make(map[string]int64),
+		defaultTTL:  defaultTTL,
+		maxEntries:  maxEntries,
 	}
 
 	// Background cleanup goroutine.
@@ -51,49 +83,120 @@ func NewInMemoryCache(defaultTTL time.Duration) *InMemoryCache {
 	return c
 }
 
-// Get returns a value if present and not expired.
+// Get returns a value if present and not expired, marking it recently used.
 func (c *InMemoryCache) Get(key string) (interface{}, bool) {
-	c.mu.RLock()
-	it, ok := c.items[key]
-	c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
 	if !ok {
 		return nil, false
 	}
+	it := el.Value.(*item)
 	if time.Now().After(it.expiration) {
 		// Lazy delete expired item.
-		c.mu.Lock()
-		delete(c.items, key)
-		c.mu.Unlock()
+		c.removeElement(el)
 		return nil, false
 	}
+	c.lru.MoveToFront(el)
 	return it.value, true
 }
 
 // Set stores a value with an optional TTL (0 = use default TTL).
+// A new key in a full cache evicts the least recently used entry.
 func (c *InMemoryCache) Set(key string, value interface{}, ttl time.Duration) {
 	if ttl <= 0 {
 		ttl = c.defaultTTL
 	}
+	expiration := time.Now().Add(ttl)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		it := el.Value.(*item)
+		it.value = value
+		it.expiration = expiration
+		c.lru.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.lru.PushFront(&item{key: key, value: value, expiration: expiration})
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+// Len returns the number of entries, including expired ones not yet cleaned up.
+func (c *InMemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// TTL returns the time left before key expires.
+func (c *InMemoryCache) TTL(key string) (time.Duration, bool) {
 	c.mu.Lock()
-	c.items[key] = item{
-		value:      value,
-		expiration: time.Now().Add(ttl),
+	el, ok := c.items[key]
+	var expiration time.Time
+	if ok {
+		expiration = el.Value.(*item).expiration
 	}
 	c.mu.Unlock()
+	if !ok {
+		return 0, false
+	}
+	remaining := time.Until(expiration)
+	if remaining <= 0 {
+		return 0, false
+	}
+	return remaining, true
+}
+
+// Delete removes key from the cache.
+func (c *InMemoryCache) Delete(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.removeElement(el)
+	return time.Now().Before(el.Value.(*item).expiration)
+}
+
+// Generation returns the current generation of namespace.
+func (c *InMemoryCache) Generation(namespace string) (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generations[namespace], true
+}
+
+// BumpGeneration advances namespace to a new generation.
+func (c *InMemoryCache) BumpGeneration(namespace string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations[namespace]++
+	return c.generations[namespace]
 }
 
 // cleanup removes expired items.
 func (c *InMemoryCache) cleanup() {
 	now := time.Now()
 	c.mu.Lock()
-	for k, it := range c.items {
-		if now.After(it.expiration) {
-			delete(c.items, k)
+	for _, el := range c.items {
+		if now.After(el.Value.(*item).expiration) {
+			c.removeElement(el)
 		}
 	}
 	c.mu.Unlock()
 }
 
+// removeElement drops an entry from both the map and the LRU list.
+// The caller must hold c.mu.
+func (c *InMemoryCache) removeElement(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.items, el.Value.(*item).key)
+}
+
 // RedisCache is a Redis-backed implementation of Cache.
 // It stores values as gob-encoded bytes under the given key.
 type RedisCache struct {
@@ -160,6 +263,26 @@ func (r *RedisCache) Set(key string, value interface{}, ttl time.Duration) {
 	_ = r.client.Set(ctx, key, b, ttl).Err()
 }
 
+// TTL returns the remaining lifetime of key using Redis TTL.
+func (r *RedisCache) TTL(key string) (time.Duration, bool) {
+	return redisTTL(r.client, key)
+}
+
+// Delete removes key using Redis DEL.
+func (r *RedisCache) Delete(key string) bool {
+	return redisDelete(r.client, key)
+}
+
+// Generation returns the generation of namespace stored in Redis.
+func (r *RedisCache) Generation(namespace string) (int64, bool) {
+	return redisGeneration(r.client, namespace)
+}
+
+// BumpGeneration increments the generation of namespace in Redis.
+func (r *RedisCache) BumpGeneration(namespace string) int64 {
+	return redisBumpGeneration(r.client, namespace)
+}
+
 // Get implements Cache interface for RedisCacheWrapper
 func (r *RedisCacheWrapper) Get(key string) (interface{}, bool) {
 	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
@@ -190,3 +313,83 @@ func (r *RedisCacheWrapper) Set(key string, value interface{}, ttl time.Duration
 	}
 	_ = r.Client.Set(ctx, key, b, ttl).Err()
 }
+
+// TTL implements Cache interface for RedisCacheWrapper
+func (r *RedisCacheWrapper) TTL(key string) (time.Duration, bool) {
+	return redisTTL(r.Client, key)
+}
+
+// Delete implements Cache interface for RedisCacheWrapper
+func (r *RedisCacheWrapper) Delete(key string) bool {
+	return redisDelete(r.Client, key)
+}
+
+// Generation implements Cache interface for RedisCacheWrapper
+func (r *RedisCacheWrapper) Generation(namespace string) (int64, bool) {
+	return redisGeneration(r.Client, namespace)
+}
+
+// BumpGeneration implements Cache interface for RedisCacheWrapper
+func (r *RedisCacheWrapper) BumpGeneration(namespace string) int64 {
+	return redisBumpGeneration(r.Client, namespace)
+}
+
+// generationKeyPrefix namespaces generation counters in Redis.
+// Counters have no TTL so a bump is never undone by expiry.
+const generationKeyPrefix = "cache-generation:"
+
+// redisGeneration reads a generation counter; a missing counter reads as 0.
+// Any other error is reported as not ok rather than 0, which would match
+// entries cached before the first bump.
+// Being shared in Redis, a bump by any process invalidates entries for all of them.
+func redisGeneration(client *redis.Client, namespace string) (int64, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	gen, err := client.Get(ctx, generationKeyPrefix+namespace).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, true
+	}
+	if err != nil {
+		return 0, false
+	}
+	return gen, true
+}
+
+// redisBumpGeneration atomically increments a generation counter with INCR.
+// It returns 0, never a real generation, when the increment failed.
+func redisBumpGeneration(client *redis.Client, namespace string) int64 {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	gen, err := client.Incr(ctx, generationKeyPrefix+namespace).Result()
+	if err != nil {
+		return 0
+	}
+	return gen
+}
+
+// redisTTL maps Redis TTL replies onto the Cache TTL contract.
+// Redis returns -2 for a missing key and -1 for a key without expiry.
+func redisTTL(client *redis.Client, key string) (time.Duration, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	ttl, err := client.TTL(ctx, key).Result()
+	if err != nil || ttl == -2 {
+		return 0, false
+	}
+	if ttl < 0 {
+		return -1, true
+	}
+	return ttl, true
+}
+
+// redisDelete deletes key and reports whether Redis removed anything.
+func redisDelete(client *redis.Client, key string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	n, err := client.Del(ctx, key).Result()
+	return err == nil && n > 0
+}
@@ -0,0 +1,49 @@
+// levenshtein.go - Edit distance between words
+// Used to match misspelled query terms against titles and tags
+package textdist
+
+// Levenshtein returns the number of single-letter insertions, deletions and
+// substitutions that turn a into b
+func Levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// MaxEdits returns how many edits a term of n letters may be from a word and still match
+// Like Elasticsearch's AUTO fuzziness: exact up to 2 letters, 1 edit up to 5, then 2
+func MaxEdits(n int) int {
+	switch {
+	case n <= 2:
+		return 0
+	case n <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Within reports whether a and b are at most MaxEdits(len(a)) edits apart, and how many
+// Words whose length alone puts them out of reach are rejected without computing the distance
+func Within(a, b []rune) (int, bool) {
+	limit := MaxEdits(len(a))
+	if diff := len(b) - len(a); diff > limit || -diff > limit {
+		return 0, false
+	}
+	d := Levenshtein(a, b)
+	return d, d <= limit
+}
@@ -0,0 +1,61 @@
+package textdist
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"go", "", 2},
+		{"kubernets", "kubernetes", 1},
+		{"dokcer", "docker", 2},
+		{"kitten", "sitting", 3},
+		{"über", "uber", 1},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMaxEdits(t *testing.T) {
+	tests := []struct {
+		n, want int
+	}{
+		{0, 0},
+		{2, 0},
+		{3, 1},
+		{5, 1},
+		{6, 2},
+		{12, 2},
+	}
+	for _, tt := range tests {
+		if got := MaxEdits(tt.n); got != tt.want {
+			t.Errorf("MaxEdits(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestWithin(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		wantDist int
+		wantOK   bool
+	}{
+		{"go", "go", 0, true},
+		{"go", "gp", 0, false},
+		{"dokcer", "docker", 2, true},
+		{"pyton", "python", 1, true},
+		{"pyton", "pythons", 0, false},
+		{"kubernets", "kubernetes", 1, true},
+	}
+	for _, tt := range tests {
+		d, ok := Within([]rune(tt.a), []rune(tt.b))
+		if ok != tt.wantOK || (ok && d != tt.wantDist) {
+			t.Errorf("Within(%q, %q) = %d, %t; want %d, %t", tt.a, tt.b, d, ok, tt.wantDist, tt.wantOK)
+		}
+	}
+}
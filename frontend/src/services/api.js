@@ -76,13 +76,14 @@ export const searchContent = async (params) => {
 };
 
 /**
- * Get all providers
+ * Get providers (first page of up to 100, ordered by name)
  * @returns {Promise} List of providers
  */
 export const getProviders = async () => {
   try {
-    const response = await apiClient.get('/providers');
-    return response.data.data || [];
+    const response = await apiClient.get('/providers', { params: { per_page: 100 } });
+    // Backend returns: { data: { providers, total, page, per_page, total_pages }, trace_id }
+    return response.data.data?.providers || [];
   } catch (error) {
     throw error;
   }